@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+// budgetNone is passed instead of a budget to unset it.
+const budgetNone = "none"
+
+var (
+	errCouldntSetBudget         = errors.New("couldn't set budget")
+	errBudgetStatsNotApplicable = errors.New("--budget cannot be used")
+)
+
+func getBudget(value string) (*int, error) {
+	if strings.TrimSpace(value) == budgetNone {
+		return nil, nil
+	}
+
+	secs, err := types.ParseBudget(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secs, nil
+}
+
+func describeBudget(budgetSecs *int) string {
+	if budgetSecs == nil {
+		return budgetNone
+	}
+
+	return types.HumanizeDuration(*budgetSecs)
+}
+
+func setTaskBudget(db *sql.DB, writer io.Writer, taskQuery, budgetValue string) error {
+	budgetSecs, err := getBudget(budgetValue)
+	if err != nil {
+		return err
+	}
+
+	task, err := resolveAnyTask(db, taskQuery)
+	if err != nil {
+		return err
+	}
+
+	err = pers.SetTaskBudget(db, task.ID, budgetSecs)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntSetBudget, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Set budget of task #%d (%q): %s\n", task.ID, task.Summary, describeBudget(budgetSecs))
+
+	return nil
+}
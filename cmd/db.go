@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+)
+
+var (
+	errKeepIsNegative             = errors.New("--keep cannot be negative")
+	errKeepNeedsBackupDirectory   = errors.New("--keep can only be used when backing up to a directory")
+	errBackupAlreadyExists        = errors.New("backup file already exists")
+	errCouldntCreateBackupDir     = errors.New("couldn't create directory for backups")
+	errCouldntRotateBackups       = errors.New("couldn't remove old backups")
+	errBackupFileDoesntExist      = errors.New("backup file doesn't exist")
+	errBackupIsTheLiveDB          = errors.New("backup file is the same as the database being restored")
+	errCouldntSnapshotLiveDB      = errors.New("couldn't back up the current database before restoring")
+	errCouldntRestoreDB           = errors.New("couldn't restore database")
+	errCouldntCheckBackupFilePath = errors.New("couldn't check backup file path")
+	errDBDoesntExist              = errors.New("database doesn't exist")
+	errCouldntCreateScratchDir    = errors.New("couldn't create temporary directory for the dry run")
+	errMigrationDryRunFailed      = errors.New("migrations failed against a copy of the database; the database wasn't changed")
+)
+
+// getBackupFileName returns a timestamped name for a backup of the database at
+// dbPath; names sort in the order the backups were made.
+func getBackupFileName(dbPath string, now time.Time) string {
+	stem := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath))
+	return fmt.Sprintf("%s-%s.db", stem, now.Format(pers.BackupTimestampFormat))
+}
+
+// getBackupsToRemove returns the timestamped backups of the database at
+// dbPath (out of fileNames) that fall outside the newest keep ones.
+func getBackupsToRemove(dbPath string, fileNames []string, keep int) []string {
+	stem := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath))
+	backupRegex := regexp.MustCompile(fmt.Sprintf(`^%s-\d{8}T\d{6}\.db$`, regexp.QuoteMeta(stem)))
+
+	var backups []string
+	for _, name := range fileNames {
+		if backupRegex.MatchString(name) {
+			backups = append(backups, name)
+		}
+	}
+
+	if len(backups) <= keep {
+		return nil
+	}
+
+	slices.Sort(backups)
+	return backups[:len(backups)-keep]
+}
+
+func backupDB(db *sql.DB, writer io.Writer, dbPath, target string, keep int, now time.Time) error {
+	if keep < 0 {
+		return errKeepIsNegative
+	}
+
+	var backupPath string
+	if filepath.Ext(target) == ".db" {
+		if keep > 0 {
+			return errKeepNeedsBackupDirectory
+		}
+		backupPath = target
+	} else {
+		backupDir := target
+		if backupDir == "" {
+			backupDir = filepath.Join(filepath.Dir(dbPath), pers.BackupDirName)
+		}
+		backupPath = filepath.Join(backupDir, getBackupFileName(dbPath, now))
+	}
+
+	_, err := os.Stat(backupPath)
+	if err == nil {
+		return fmt.Errorf("%w: %s", errBackupAlreadyExists, backupPath)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", errCouldntCheckBackupFilePath, err.Error())
+	}
+
+	backupDir := filepath.Dir(backupPath)
+	err = os.MkdirAll(backupDir, 0o755)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntCreateBackupDir, err.Error())
+	}
+
+	err = pers.BackupDB(db, backupPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(writer, "Backed up database to %s\n", backupPath)
+
+	if keep == 0 {
+		return nil
+	}
+
+	dirEntries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntRotateBackups, err.Error())
+	}
+
+	var fileNames []string
+	for _, entry := range dirEntries {
+		if entry.Type().IsRegular() {
+			fileNames = append(fileNames, entry.Name())
+		}
+	}
+
+	for _, name := range getBackupsToRemove(dbPath, fileNames, keep) {
+		oldBackupPath := filepath.Join(backupDir, name)
+		err = os.Remove(oldBackupPath)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntRotateBackups, err.Error())
+		}
+		fmt.Fprintf(writer, "Removed old backup %s\n", oldBackupPath)
+	}
+
+	return nil
+}
+
+func restoreDB(writer io.Writer, dbPath, backupPath string, now time.Time) error {
+	backupInfo, err := os.Stat(backupPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", errBackupFileDoesntExist, backupPath)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntCheckBackupFilePath, err.Error())
+	}
+
+	// the live database might not exist yet; that's fine, restoring will create it
+	liveInfo, err := os.Stat(dbPath)
+	liveDBExists := err == nil
+	if liveDBExists && os.SameFile(backupInfo, liveInfo) {
+		return errBackupIsTheLiveDB
+	}
+
+	version, err := pers.CheckRestorable(backupPath)
+	if err != nil {
+		return err
+	}
+
+	dbDir := filepath.Dir(dbPath)
+	err = os.MkdirAll(dbDir, 0o755)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntCreateDBDirectory, err.Error())
+	}
+
+	var snapshotPath string
+	if liveDBExists {
+		stem := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath))
+		snapshotPath = filepath.Join(dbDir, pers.BackupDirName,
+			fmt.Sprintf("%s-%s-pre-restore.db", stem, now.Format(pers.BackupTimestampFormat)))
+
+		err = snapshotDB(dbPath, snapshotPath)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntSnapshotLiveDB, err.Error())
+		}
+	}
+
+	// the backup is copied next to the live database first, so that replacing
+	// the live database is a single rename
+	tempPath := filepath.Join(dbDir, fmt.Sprintf(".%s.restore-%s", filepath.Base(dbPath), now.Format(pers.BackupTimestampFormat)))
+	err = snapshotDB(backupPath, tempPath)
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("%w: %s", errCouldntRestoreDB, err.Error())
+	}
+
+	err = os.Rename(tempPath, dbPath)
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("%w: %s", errCouldntRestoreDB, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Restored database from %s\n", backupPath)
+	if snapshotPath != "" {
+		fmt.Fprintf(writer, "The previous database was saved to %s\n", snapshotPath)
+	}
+	if version < pers.LatestDBVersion() {
+		fmt.Fprintln(writer, "The restored database is from an older version of hours; it'll be upgraded the next time hours opens it")
+	}
+
+	return nil
+}
+
+func snapshotDB(srcPath, destPath string) error {
+	err := os.MkdirAll(filepath.Dir(destPath), 0o755)
+	if err != nil {
+		return err
+	}
+
+	db, err := pers.GetDB(srcPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return pers.BackupDB(db, destPath)
+}
+
+func migrateDB(writer io.Writer, dbPath string, dryRun bool) error {
+	_, err := os.Stat(dbPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", errDBDoesntExist, dbPath)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntOpenDB, err.Error())
+	}
+
+	db, err := pers.GetDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntOpenDB, err.Error())
+	}
+	defer db.Close()
+
+	version, pending, err := pers.PendingMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		fmt.Fprintf(writer, "Database is at the latest version (%d); there are no pending migrations\n", version)
+		return nil
+	}
+
+	fmt.Fprintf(writer, "Database is at version %d; pending migrations:\n", version)
+	for _, migration := range pending {
+		fmt.Fprintf(writer, "\n-- version %d\n%s\n", migration.Version, strings.TrimSpace(migration.Query))
+	}
+	fmt.Fprintln(writer)
+
+	if !dryRun {
+		err = pers.UpgradeDB(db, version)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(writer, "Migrated database to version %d\n", pending[len(pending)-1].Version)
+		return nil
+	}
+
+	scratchDir, err := os.MkdirTemp("", "hours-migrate-")
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntCreateScratchDir, err.Error())
+	}
+	defer os.RemoveAll(scratchDir)
+
+	err = pers.DryRunMigrations(db, filepath.Join(scratchDir, filepath.Base(dbPath)))
+	if err != nil {
+		return fmt.Errorf("%w: %s", errMigrationDryRunFailed, err.Error())
+	}
+
+	fmt.Fprintln(writer, "Migrations ran successfully against a copy of the database; the database wasn't changed")
+
+	return nil
+}
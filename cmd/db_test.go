@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBackupFileName(t *testing.T) {
+	// GIVEN
+	now := time.Date(2025, time.October, 24, 9, 5, 30, 0, time.UTC)
+
+	// WHEN
+	got := getBackupFileName("/home/user/work.db", now)
+
+	// THEN
+	assert.Equal(t, "work-20251024T090530.db", got)
+}
+
+func TestGetBackupsToRemove(t *testing.T) {
+	fileNames := []string{
+		"hours-20251022T090000.db",
+		"hours-20251024T090000.db",
+		"hours-20251023T090000.db",
+		"hours-20251021T090000-pre-restore.db",
+		"hours-20251020T090000.db",
+		"work-20251019T090000.db",
+		"notes.txt",
+	}
+
+	testCases := []struct {
+		name     string
+		keep     int
+		expected []string
+	}{
+		{
+			name:     "oldest backups are removed",
+			keep:     2,
+			expected: []string{"hours-20251020T090000.db", "hours-20251022T090000.db"},
+		},
+		{
+			name: "nothing is removed if there are fewer backups than to keep",
+			keep: 4,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getBackupsToRemove("/home/user/hours.db", fileNames, tt.keep)
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+var (
+	errDBHasProblems             = errors.New("database has problems (see above)")
+	errDBHasUnfixableProblems    = errors.New("some problems can't be fixed automatically (see above)")
+	errCouldntDiagnoseDBAfterFix = errors.New("couldn't check database after fixing it")
+)
+
+func describeDiagnosedTL(tl pers.DiagnosedTL) string {
+	taskID := "none"
+	if tl.TaskID != nil {
+		taskID = fmt.Sprintf("#%d", *tl.TaskID)
+	}
+
+	if tl.Active {
+		return fmt.Sprintf("task log entry #%d (task: %s): active since %s", tl.ID, taskID, tl.BeginTS.Format(timeFormat))
+	}
+
+	end := "(no end)"
+	if tl.EndTS != nil {
+		end = tl.EndTS.Format(timeFormat)
+	}
+
+	return fmt.Sprintf("task log entry #%d (task: %s): %s ... %s", tl.ID, taskID, tl.BeginTS.Format(timeFormat), end)
+}
+
+// writeDiagnosis writes a report of the problems in a diagnosis, and returns
+// the number of problems found, and how many of them can be fixed.
+func writeDiagnosis(writer io.Writer, diagnosis pers.DBDiagnosis) (int, int) {
+	var numProblems, numFixable int
+
+	writeSection := func(title string, lines []string, fixable bool, hint string) {
+		if len(lines) == 0 {
+			fmt.Fprintf(writer, "%s: ok\n", title)
+			return
+		}
+
+		numProblems += len(lines)
+		if fixable {
+			numFixable += len(lines)
+		}
+
+		fmt.Fprintf(writer, "%s: %d problem(s)\n", title, len(lines))
+		for _, line := range lines {
+			fmt.Fprintf(writer, "  - %s\n", line)
+		}
+		fmt.Fprintf(writer, "  %s\n", hint)
+	}
+
+	writeSection("Integrity check", diagnosis.IntegrityErrors, false,
+		`Can't be fixed automatically; restore a backup using "hours db restore"`)
+
+	var driftLines []string
+	for _, drift := range diagnosis.RollupDrifts {
+		driftLines = append(driftLines, fmt.Sprintf("task #%d (%q): recorded %s, task log entries add up to %s",
+			drift.TaskID,
+			drift.TaskSummary,
+			types.HumanizeDuration(drift.RecordedSecs),
+			types.HumanizeDuration(drift.ActualSecs),
+		))
+	}
+	writeSection("Time spent on tasks", driftLines, true,
+		"Fixable; the time spent on these tasks will be recomputed from their task log entries")
+
+	var orphanedLines []string
+	for _, tl := range diagnosis.OrphanedTLs {
+		orphanedLines = append(orphanedLines, describeDiagnosedTL(tl))
+	}
+	writeSection("Task log entries without a task", orphanedLines, true,
+		fmt.Sprintf("Fixable; these entries will be moved to a new task, %q", pers.OrphanedTLsTaskSummary))
+
+	var invalidLines []string
+	for _, tl := range diagnosis.InvalidTLs {
+		invalidLines = append(invalidLines, describeDiagnosedTL(tl))
+	}
+	writeSection("Task log entries that don't end after they begin", invalidLines, false,
+		`Can't be fixed automatically; edit or delete these entries using "hours log edit" or "hours log rm"`)
+
+	var activeLines []string
+	if len(diagnosis.ActiveTLs) > 1 {
+		for _, tl := range diagnosis.ActiveTLs {
+			activeLines = append(activeLines, describeDiagnosedTL(tl))
+		}
+	}
+	writeSection("Active task log entries", activeLines, false,
+		`Can't be fixed automatically, since it's not known when the stale entries ended; restore a backup using "hours db restore"`)
+
+	return numProblems, numFixable
+}
+
+func runDoctor(db *sql.DB, writer io.Writer, fix bool) error {
+	diagnosis, err := pers.DiagnoseDB(db)
+	if err != nil {
+		return err
+	}
+
+	numProblems, numFixable := writeDiagnosis(writer, diagnosis)
+
+	if numProblems == 0 {
+		fmt.Fprintln(writer, "\nNo problems found")
+		return nil
+	}
+
+	if !fix {
+		if numFixable > 0 {
+			fmt.Fprintf(writer, "\nFound %d problem(s), %d of which can be fixed by running \"hours doctor --fix\"\n", numProblems, numFixable)
+		}
+		return errDBHasProblems
+	}
+
+	if numFixable > 0 {
+		result, err := pers.RepairDB(db, diagnosis)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(writer)
+		if result.NumTLsReassigned > 0 {
+			fmt.Fprintf(writer, "Moved %d task log entries to task #%d (%q)\n",
+				result.NumTLsReassigned,
+				result.OrphanedTLsTaskID,
+				pers.OrphanedTLsTaskSummary,
+			)
+		}
+		fmt.Fprintf(writer, "Recomputed time spent on %d task(s)\n", result.NumTasksRecomputed)
+	}
+
+	// the fixes are verified by checking again
+	diagnosis, err = pers.DiagnoseDB(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntDiagnoseDBAfterFix, err.Error())
+	}
+
+	if len(diagnosis.RollupDrifts) > 0 || len(diagnosis.OrphanedTLs) > 0 {
+		return fmt.Errorf("%w: %s", pers.ErrCouldntRepairDB, msgReportIssue)
+	}
+
+	if numProblems > numFixable {
+		return errDBHasUnfixableProblems
+	}
+
+	return nil
+}
@@ -59,3 +59,27 @@ Fun fact: There are 16,777,216 true color choices. Go nuts.
 		return
 	}
 }
+
+const (
+	exitCodeGeneric           = 1
+	exitCodeNoTaskActive      = 2
+	exitCodeNoTaskMatched     = 3
+	exitCodeMultipleTasks     = 4
+	exitCodeTaskAlreadyActive = 5
+)
+
+// ExitCode returns the code hours should exit with for a given error.
+func ExitCode(err error) int {
+	switch {
+	case errors.Is(err, errNoTaskActive):
+		return exitCodeNoTaskActive
+	case errors.Is(err, errNoTaskMatched):
+		return exitCodeNoTaskMatched
+	case errors.Is(err, errMultipleTasksMatch):
+		return exitCodeMultipleTasks
+	case errors.Is(err, errTaskAlreadyBeingTracked):
+		return exitCodeTaskAlreadyActive
+	default:
+		return exitCodeGeneric
+	}
+}
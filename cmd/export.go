@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+var (
+	errCouldntFetchTLs  = errors.New("couldn't fetch task log entries")
+	errCouldntExportTLs = errors.New("couldn't export task log entries")
+)
+
+func exportTLs(db *sql.DB,
+	writer io.Writer,
+	write func(io.Writer, []domain.TaskLogEntry) error,
+	dateRange types.DateRange,
+	filter types.TaskFilter,
+) error {
+	entries, err := pers.FetchTLEntriesBetweenTS(db, dateRange.Start, dateRange.End, filter, pers.NoLimit)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchTLs, err.Error())
+	}
+
+	err = write(writer, entries)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntExportTLs, err.Error())
+	}
+
+	return nil
+}
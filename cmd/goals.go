@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+// goalNone is passed instead of a goal to unset it.
+const goalNone = "none"
+
+const (
+	dailyGoalLimitSecs  = 24 * 60 * 60
+	weeklyGoalLimitSecs = 7 * dailyGoalLimitSecs
+)
+
+var (
+	errNoGoalProvided = errors.New("a goal needs to be provided via --daily and/or --weekly")
+	errGoalTooLarge   = errors.New("goal is too large")
+	errCouldntSetGoal = errors.New("couldn't set goal")
+)
+
+func getGoal(value string, limitSecs int) (*int, error) {
+	if strings.TrimSpace(value) == goalNone {
+		return nil, nil
+	}
+
+	secs, err := types.ParseGoal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if secs > limitSecs {
+		return nil, fmt.Errorf("%w: %q (limit: %s)", errGoalTooLarge, value, types.HumanizeDuration(limitSecs))
+	}
+
+	return &secs, nil
+}
+
+// setGoals sets the daily and/or weekly goal; goals that are passed as empty
+// strings are left as they are.
+func setGoals(db *sql.DB, writer io.Writer, dailyValue, weeklyValue string) error {
+	if dailyValue == "" && weeklyValue == "" {
+		return errNoGoalProvided
+	}
+
+	type goalChange struct {
+		kind string
+		secs *int
+	}
+
+	var changes []goalChange
+	for _, g := range []struct {
+		kind      string
+		value     string
+		limitSecs int
+	}{
+		{domain.GoalKindDaily, dailyValue, dailyGoalLimitSecs},
+		{domain.GoalKindWeekly, weeklyValue, weeklyGoalLimitSecs},
+	} {
+		if g.value == "" {
+			continue
+		}
+
+		secs, err := getGoal(g.value, g.limitSecs)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, goalChange{g.kind, secs})
+	}
+
+	for _, change := range changes {
+		err := pers.SetGoal(db, change.kind, change.secs)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntSetGoal, err.Error())
+		}
+
+		if change.secs == nil {
+			fmt.Fprintf(writer, "Unset %s goal\n", change.kind)
+		} else {
+			fmt.Fprintf(writer, "Set %s goal: %s\n", change.kind, types.HumanizeDuration(*change.secs))
+		}
+	}
+
+	return nil
+}
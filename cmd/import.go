@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dhth/hours/internal/importer"
+)
+
+const (
+	togglFormatCSV  = "csv"
+	togglFormatJSON = "json"
+)
+
+var validTogglFormatValues = []string{togglFormatCSV, togglFormatJSON}
+
+var (
+	errCouldntOpenImportFile = errors.New("couldn't open file to import")
+	errCouldntParseImport    = errors.New("couldn't parse file to import")
+	errCouldntPlanImport     = errors.New("couldn't check entries to import")
+	errCouldntImport         = errors.New("couldn't import entries")
+	errImportHasInvalidRows  = errors.New("some rows are invalid (see above); nothing was imported")
+	errTogglFormatInvalid    = errors.New("incorrect format provided")
+	errDelimiterInvalid      = errors.New("delimiter must be a single character")
+
+	errICSNeedsRulesOrCreateTasks = errors.New("events need to be mapped to tasks; provide rules via --rule, or use --create-tasks")
+)
+
+func parseDelimiter(value string) (rune, error) {
+	switch value {
+	case `\t`, "tab":
+		return '\t', nil
+	}
+
+	if utf8.RuneCountInString(value) != 1 {
+		return 0, fmt.Errorf("%w: %q", errDelimiterInvalid, value)
+	}
+
+	r, _ := utf8.DecodeRuneInString(value)
+	return r, nil
+}
+
+func importCSV(db *sql.DB,
+	writer io.Writer,
+	filePath string,
+	mapping importer.ColumnMapping,
+	delimiter rune,
+	dryRun bool,
+) error {
+	file, err := openImportFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entries, rowErrors, err := importer.ParseCSV(file, mapping, delimiter)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntParseImport, err)
+	}
+
+	return importEntries(db, writer, importer.SourceCSV, entries, rowErrors, dryRun)
+}
+
+func importTimew(db *sql.DB,
+	writer io.Writer,
+	filePath string,
+	summarySource importer.TimewSummarySource,
+	dryRun bool,
+) error {
+	file, err := openImportFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entries, rowErrors, err := importer.ParseTimew(file, summarySource)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntParseImport, err)
+	}
+
+	return importEntries(db, writer, importer.SourceTimew, entries, rowErrors, dryRun)
+}
+
+// getTogglFormat returns the format of a Toggl export; if one isn't provided,
+// it's inferred from the file's extension.
+func getTogglFormat(format, filePath string) (string, error) {
+	switch format {
+	case togglFormatCSV, togglFormatJSON:
+		return format, nil
+	case "":
+		if strings.EqualFold(filepath.Ext(filePath), ".json") {
+			return togglFormatJSON, nil
+		}
+		return togglFormatCSV, nil
+	default:
+		return "", fmt.Errorf("%w: %q (possible values: %q)", errTogglFormatInvalid, format, validTogglFormatValues)
+	}
+}
+
+func importToggl(db *sql.DB,
+	writer io.Writer,
+	filePath string,
+	format string,
+	dryRun bool,
+) error {
+	format, err := getTogglFormat(format, filePath)
+	if err != nil {
+		return err
+	}
+
+	file, err := openImportFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var entries []importer.Entry
+	var rowErrors []importer.RowError
+	switch format {
+	case togglFormatJSON:
+		entries, rowErrors, err = importer.ParseTogglJSON(file)
+	default:
+		entries, rowErrors, err = importer.ParseTogglCSV(file)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntParseImport, err)
+	}
+
+	return importEntries(db, writer, importer.SourceToggl, entries, rowErrors, dryRun)
+}
+
+func importICS(db *sql.DB,
+	writer io.Writer,
+	filePath string,
+	opts importer.ICSOptions,
+	allowOverlap bool,
+	dryRun bool,
+) error {
+	if len(opts.Rules) == 0 && !opts.CreateTasks {
+		return errICSNeedsRulesOrCreateTasks
+	}
+
+	file, err := openImportFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entries, rowErrors, skipped, err := importer.ParseICS(file, opts)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntParseImport, err)
+	}
+
+	plan, err := importer.NewPlan(db, importer.SourceICS, entries, rowErrors)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntPlanImport, err.Error())
+	}
+
+	plan.Skip(skipped)
+	if !allowOverlap {
+		err = plan.SkipOverlapping(db)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntPlanImport, err.Error())
+		}
+	}
+
+	return runImport(db, writer, plan, dryRun)
+}
+
+// openImportFile opens the file at filePath, or stdin if it's "-".
+func openImportFile(filePath string) (io.ReadCloser, error) {
+	if filePath == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCouldntOpenImportFile, err.Error())
+	}
+
+	return file, nil
+}
+
+func importEntries(db *sql.DB,
+	writer io.Writer,
+	source string,
+	entries []importer.Entry,
+	rowErrors []importer.RowError,
+	dryRun bool,
+) error {
+	plan, err := importer.NewPlan(db, source, entries, rowErrors)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntPlanImport, err.Error())
+	}
+
+	return runImport(db, writer, plan, dryRun)
+}
+
+func runImport(db *sql.DB, writer io.Writer, plan importer.Plan, dryRun bool) error {
+	plan.WriteReport(writer)
+
+	if dryRun {
+		return nil
+	}
+
+	if plan.HasInvalidRows() {
+		return errImportHasInvalidRows
+	}
+
+	if len(plan.ToImport) == 0 {
+		fmt.Fprintln(writer, "\nnothing to import")
+		return nil
+	}
+
+	result, err := plan.Apply(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntImport, err.Error())
+	}
+
+	fmt.Fprintf(writer, "\nimported %d task log entries (%d new tasks created)\n", result.NumTLsInserted, result.NumTasksCreated)
+	if result.ActiveTLID != 0 {
+		fmt.Fprintln(writer, "the open entry is now being tracked")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+const projectNameLengthLimit = 50
+
+var (
+	errProjectNameEmpty      = errors.New("project name cannot be empty")
+	errProjectNameTooLong    = errors.New("project name is too long")
+	errClientNameEmpty       = errors.New("client name cannot be empty")
+	errClientNameTooLong     = errors.New("client name is too long")
+	errProjectAlreadyExists  = errors.New("project already exists")
+	errProjectNotFound       = errors.New("project not found")
+	errCouldntAddProject     = errors.New("couldn't add project")
+	errCouldntFetchProjects  = errors.New("couldn't fetch projects")
+	errCouldntSetTaskProject = errors.New("couldn't set project of task")
+)
+
+func validateName(name string, errEmpty, errTooLong error) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errEmpty
+	}
+
+	if len(name) > projectNameLengthLimit {
+		return "", fmt.Errorf("%w (limit: %d characters)", errTooLong, projectNameLengthLimit)
+	}
+
+	return name, nil
+}
+
+// resolveProject finds a project by its name (case-insensitive).
+func resolveProject(db *sql.DB, name string) (domain.Project, error) {
+	projects, err := pers.FetchProjects(db)
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("%w: %s", errCouldntFetchProjects, err.Error())
+	}
+
+	name = strings.TrimSpace(name)
+	for _, project := range projects {
+		if strings.EqualFold(project.Name, name) {
+			return project, nil
+		}
+	}
+
+	return domain.Project{}, fmt.Errorf(`%w: %q; run "hours project list" to list projects`, errProjectNotFound, name)
+}
+
+func addProject(db *sql.DB, writer io.Writer, name, client string) error {
+	name, err := validateName(name, errProjectNameEmpty, errProjectNameTooLong)
+	if err != nil {
+		return err
+	}
+
+	client, err = validateName(client, errClientNameEmpty, errClientNameTooLong)
+	if err != nil {
+		return err
+	}
+
+	id, err := pers.InsertProject(db, name, client)
+	if errors.Is(err, pers.ErrProjectAlreadyExists) {
+		return fmt.Errorf("%w: %q", errProjectAlreadyExists, name)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntAddProject, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Added project #%d: %q (client: %s)\n", id, name, client)
+
+	return nil
+}
+
+// setTaskProject moves a task (active or inactive) to a project.
+func setTaskProject(db *sql.DB, writer io.Writer, taskQuery, projectName string) error {
+	project, err := resolveProject(db, projectName)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := pers.FetchTasksWithStatus(db, types.TaskStatusAny, taskLookupLimit)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchTasks, err.Error())
+	}
+
+	task, err := findTask(tasks, taskQuery)
+	if err != nil {
+		return err
+	}
+
+	err = pers.SetTaskProject(db, task.ID, project.ID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntSetTaskProject, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Moved task #%d (%q) to project %q (client: %s)\n", task.ID, task.Summary, project.Name, project.Client)
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+// rateNone is passed instead of a rate to unset it.
+const rateNone = "none"
+
+var (
+	errCurrencyNeeded      = errors.New(`a currency needs to be provided via --currency (unless the rate is "none")`)
+	errClientNotFound      = errors.New("client not found")
+	errCouldntSetRate      = errors.New("couldn't set rate")
+	errCouldntSetBillable  = errors.New("couldn't update billable status of task")
+	errInvoiceClientNeeded = errors.New("a client needs to be provided via --client")
+)
+
+func getRate(value, currencyValue string) (*domain.Rate, error) {
+	if strings.TrimSpace(value) == rateNone {
+		return nil, nil
+	}
+
+	hundredths, err := types.ParseRate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(currencyValue) == "" {
+		return nil, errCurrencyNeeded
+	}
+
+	currency, err := types.ParseCurrency(currencyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Rate{Hundredths: hundredths, Currency: currency}, nil
+}
+
+func describeRate(rate *domain.Rate) string {
+	if rate == nil {
+		return rateNone
+	}
+
+	return rate.String() + "/hour"
+}
+
+func setProjectRate(db *sql.DB, writer io.Writer, projectName, rateValue, currency string) error {
+	rate, err := getRate(rateValue, currency)
+	if err != nil {
+		return err
+	}
+
+	project, err := resolveProject(db, projectName)
+	if err != nil {
+		return err
+	}
+
+	err = pers.SetProjectRate(db, project.ID, rate)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntSetRate, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Set rate of project %q: %s\n", project.Name, describeRate(rate))
+
+	return nil
+}
+
+// resolveAnyTask finds a task among both active and inactive tasks.
+func resolveAnyTask(db *sql.DB, query string) (domain.Task, error) {
+	tasks, err := pers.FetchTasksWithStatus(db, types.TaskStatusAny, taskLookupLimit)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%w: %s", errCouldntFetchTasks, err.Error())
+	}
+
+	return findTask(tasks, query)
+}
+
+func setTaskRate(db *sql.DB, writer io.Writer, taskQuery, rateValue, currency string) error {
+	rate, err := getRate(rateValue, currency)
+	if err != nil {
+		return err
+	}
+
+	task, err := resolveAnyTask(db, taskQuery)
+	if err != nil {
+		return err
+	}
+
+	err = pers.SetTaskRate(db, task.ID, rate)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntSetRate, err.Error())
+	}
+
+	if rate == nil {
+		fmt.Fprintf(writer, "Unset rate of task #%d (%q); the rate of its project applies\n", task.ID, task.Summary)
+	} else {
+		fmt.Fprintf(writer, "Set rate of task #%d (%q): %s\n", task.ID, task.Summary, describeRate(rate))
+	}
+
+	return nil
+}
+
+func setTaskBillable(db *sql.DB, writer io.Writer, taskQuery string, billable bool) error {
+	task, err := resolveAnyTask(db, taskQuery)
+	if err != nil {
+		return err
+	}
+
+	err = pers.SetTaskBillable(db, task.ID, billable)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntSetBillable, err.Error())
+	}
+
+	if billable {
+		fmt.Fprintf(writer, "Marked task #%d (%q) as billable\n", task.ID, task.Summary)
+	} else {
+		fmt.Fprintf(writer, "Marked task #%d (%q) as non-billable\n", task.ID, task.Summary)
+	}
+
+	return nil
+}
+
+// resolveClient finds a client by its name (case-insensitive).
+func resolveClient(db *sql.DB, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errInvoiceClientNeeded
+	}
+
+	projects, err := pers.FetchProjects(db)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntFetchProjects, err.Error())
+	}
+
+	for _, project := range projects {
+		if strings.EqualFold(project.Client, name) {
+			return project.Client, nil
+		}
+	}
+
+	return "", fmt.Errorf(`%w: %q; run "hours project list" to list projects and their clients`, errClientNotFound, name)
+}
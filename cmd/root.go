@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"math/rand"
 	"os"
@@ -17,6 +18,9 @@ import (
 
 	"charm.land/lipgloss/v2"
 	c "github.com/dhth/hours/internal/common"
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/export"
+	"github.com/dhth/hours/internal/importer"
 	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/types"
 	"github.com/dhth/hours/internal/ui"
@@ -33,6 +37,7 @@ const (
 	genNumDaysUpperLimit   = 30
 	genNumTasksUpperLimit  = 20
 	reportNumDaysThreshold = 7
+	timeFormat             = "2006/01/02 15:04"
 
 	envVarTheme      = "HOURS_THEME"
 	envVarNow        = "HOURS_NOW"
@@ -62,6 +67,7 @@ var (
 	errCouldntMarshalTheme       = errors.New("couldn't marshal theme")
 	errNowInvalid                = errors.New("invalid HOURS_NOW")
 	errGenSeedInvalid            = errors.New("invalid HOURS_GEN_SEED")
+	errTimeboxInvalid            = errors.New("invalid timebox settings")
 
 	msgReportIssue = fmt.Sprintf("This isn't supposed to happen; let %s know about this error via \n%s.", c.Author, c.RepoIssuesURL)
 )
@@ -109,7 +115,7 @@ func setupDB(dbPathFull string) (*sql.DB, error) {
 		if err != nil {
 			return nil, fmt.Errorf("%w: %s", errCouldntInitializeDB, err.Error())
 		}
-		err = pers.UpgradeDB(db, 1)
+		err = pers.UpgradeNewDB(db)
 		if err != nil {
 			return nil, err
 		}
@@ -149,11 +155,53 @@ func NewRootCommand() (*cobra.Command, error) {
 		reportAgg           bool
 		recordsInteractive  bool
 		recordsOutputPlain  bool
+		recordsWithComments bool
+		statsBudget         bool
 		taskStatusStr       string
+		filterTags          []string
+		filterExcludeTags   []string
+		groupByStr          string
+		taskTags            []string
+		taskProject         string
+		taskBudget          string
+		projectClient       string
+		rateCurrency        string
+		invoiceClient       string
+		invoiceRoundTo      time.Duration
+		invoiceRoundingStr  string
+		invoiceOutputStr    string
+		goalDaily           string
+		goalWeekly          string
+		timeboxDuration     time.Duration
+		breakDuration       time.Duration
+		recordBreaks        bool
+		rejectOverlaps      bool
+		timeboxAlertStr     string
 		activeTemplate      string
+		trackingComment     string
+		trackingAt          string
+		taskListStatusStr   string
+		tlBeginStr          string
+		tlEndStr            string
+		tlComment           string
+		tlSplitAtStr        string
+		tlSplitTask         string
+		outputFormatStr     string
 		genNumDays          uint8
 		genNumTasks         uint8
 		genSkipConfirmation bool
+		importMapping       importer.ColumnMapping
+		importDelimiter     string
+		importDryRun        bool
+		timewSummarySrcStr  string
+		togglFormat         string
+		icsRuleStrs         []string
+		icsCreateTasks      bool
+		icsAllowOverlap     bool
+		backupKeep          int
+		migrateDryRun       bool
+		doctorFix           bool
+		purgeOlderThan      int
 	)
 
 	preRun := func(cmd *cobra.Command, _ []string) error {
@@ -197,14 +245,22 @@ Clean up error: %s
 			fmt.Fprintf(os.Stderr, `Looks like you downgraded hours. You should either delete hours' database file (you
 will lose data by doing that), or upgrade hours to the latest version.
 
+`)
+		case errors.Is(err, pers.ErrCouldntBackUpBeforeMigration):
+			fmt.Fprintf(os.Stderr, `hours needs to migrate its database, but couldn't back it up first; the database
+wasn't changed. Make sure the directory the database is in is writable.
+
 `)
 		case errors.Is(err, pers.ErrDBMigrationFailed):
 			fmt.Fprintf(os.Stderr, `Something went wrong migrating hours' database.
 
-You can try running hours by passing it a custom database file path (using
---db-path; this will create a new database) to see if that fixes things. If that
-works, you can either delete the previous database, or keep using this new
-database (both are not ideal).
+The database was backed up before the migration was attempted (see the error
+below for its location); you can get back to it using "hours db restore". Run
+"hours db migrate --dry-run" to check if the migration works against a copy of
+the database.
+
+You can also try running hours by passing it a custom database file path (using
+--dbpath; this will create a new database) to see if that fixes things.
 
 %s
 Sorry for breaking the upgrade step!
@@ -243,7 +299,25 @@ summary statistics for your tracked time.
 		SilenceUsage: true,
 		PreRunE:      preRun,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return ui.RenderUI(db, style, types.RealTimeProvider{})
+			if timeboxDuration < time.Minute {
+				return fmt.Errorf("%w: --timebox needs to be at least a minute long", errTimeboxInvalid)
+			}
+
+			if breakDuration < 0 {
+				return fmt.Errorf("%w: --break cannot be negative", errTimeboxInvalid)
+			}
+
+			alert, err := types.ParseTimeboxAlert(timeboxAlertStr)
+			if err != nil {
+				return err
+			}
+
+			return ui.RenderUI(db, style, types.RealTimeProvider{}, ui.PomodoroConfig{
+				TimeboxDuration: timeboxDuration,
+				BreakDuration:   breakDuration,
+				RecordBreaks:    recordBreaks,
+				Alert:           alert,
+			}, rejectOverlaps)
 		},
 	}
 
@@ -355,6 +429,21 @@ will be reported on the day it ends.
 				return err
 			}
 
+			filter, err := types.NewTaskFilter(taskStatus, filterTags, filterExcludeTags)
+			if err != nil {
+				return err
+			}
+
+			groupBy, err := types.ParseGroupBy(groupByStr)
+			if err != nil {
+				return err
+			}
+
+			outputFormat, err := types.ParseRecordsOutputFormat(outputFormatStr)
+			if err != nil {
+				return err
+			}
+
 			var period string
 			if len(args) == 0 {
 				period = "3d"
@@ -378,7 +467,7 @@ will be reported on the day it ends.
 				return err
 			}
 
-			return ui.RenderReport(db, style, os.Stdout, recordsOutputPlain, dateRange, period, taskStatus, reportAgg, recordsInteractive)
+			return ui.RenderReport(db, style, os.Stdout, recordsOutputPlain, outputFormat, dateRange, period, filter, reportAgg, groupBy, recordsInteractive, recordsWithComments)
 		},
 	}
 
@@ -407,6 +496,16 @@ appear in the log for the day it ends.
 				return err
 			}
 
+			filter, err := types.NewTaskFilter(taskStatus, filterTags, filterExcludeTags)
+			if err != nil {
+				return err
+			}
+
+			outputFormat, err := types.ParseRecordsOutputFormat(outputFormatStr)
+			if err != nil {
+				return err
+			}
+
 			var period string
 			if len(args) == 0 {
 				period = types.TimePeriodToday
@@ -424,7 +523,226 @@ appear in the log for the day it ends.
 				return err
 			}
 
-			return ui.RenderTaskLog(db, style, os.Stdout, recordsOutputPlain, dateRange, period, taskStatus, recordsInteractive)
+			return ui.RenderTaskLog(db, style, os.Stdout, recordsOutputPlain, outputFormat, dateRange, period, filter, recordsInteractive, recordsWithComments)
+		},
+	}
+
+	addTLCmd := &cobra.Command{
+		Use:   "add <TASK>",
+		Short: "Add a task log entry",
+		Long: `Add a task log entry for an active task.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one active task.
+
+--begin and --end accept either "YYYY/MM/DD HH:MM" or "HH:MM" (for today).
+
+A warning is printed if the entry overlaps saved entries; pass
+--reject-overlaps to not save it in that case.
+`,
+		Example: `hours log add 3 --begin 09:00 --end 10:30 --comment "outline"
+hours log add "write blog" --begin "2025/10/23 14:00" --end "2025/10/23 15:00"`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			return addTL(db, os.Stdout, args[0], tlBeginStr, tlEndStr, getComment(tlComment), rejectOverlaps, now)
+		},
+	}
+
+	editTLCmd := &cobra.Command{
+		Use:   "edit <ID>",
+		Short: "Edit a task log entry",
+		Long: `Edit a task log entry.
+
+Only the attributes provided via flags are changed. --begin and --end accept
+either "YYYY/MM/DD HH:MM" or "HH:MM" (for today). Passing an empty comment
+clears it.
+
+A warning is printed if the entry ends up overlapping other saved entries; pass
+--reject-overlaps to not save the change in that case.
+
+Run "hours log" to find the ID of the entry to edit.
+`,
+		Example: `hours log edit 42 --end 11:15
+hours log edit 42 --comment ""`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseTLID(args[0])
+			if err != nil {
+				return err
+			}
+
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			var update tlUpdate
+			if cmd.Flags().Changed("begin") {
+				update.begin = &tlBeginStr
+			}
+			if cmd.Flags().Changed("end") {
+				update.end = &tlEndStr
+			}
+			if cmd.Flags().Changed("comment") {
+				update.commentProvided = true
+				update.comment = getComment(tlComment)
+			}
+
+			return editTL(db, os.Stdout, id, update, rejectOverlaps, now)
+		},
+	}
+
+	splitTLCmd := &cobra.Command{
+		Use:   "split <ID>",
+		Short: "Split a task log entry in two",
+		Long: `Split a task log entry in two at a timestamp.
+
+The second entry is for the same task, unless another active task is provided
+via --task (either by its ID, or by a prefix of its summary). Both entries keep
+the comment of the original one.
+
+--at accepts either "YYYY/MM/DD HH:MM" or "HH:MM" (for today).
+`,
+		Example: `hours log split 42 --at 10:15
+hours log split 42 --at 10:15 --task "review PRs"`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			id, err := parseTLID(args[0])
+			if err != nil {
+				return err
+			}
+
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			return splitTL(db, os.Stdout, id, tlSplitAtStr, tlSplitTask, now)
+		},
+	}
+
+	mergeTLsCmd := &cobra.Command{
+		Use:   "merge <ID> <ID>",
+		Short: "Merge two adjacent task log entries",
+		Long: `Merge two adjacent task log entries of the same task into one.
+
+Entries are adjacent if no other entry lies between them. The gap between them
+(if any) doesn't count towards the time spent on the merged entry. Comments of
+both entries are kept.
+`,
+		Example: `hours log merge 42 43`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			id, err := parseTLID(args[0])
+			if err != nil {
+				return err
+			}
+
+			otherID, err := parseTLID(args[1])
+			if err != nil {
+				return err
+			}
+
+			return mergeTLs(db, os.Stdout, id, otherID)
+		},
+	}
+
+	moveTLCmd := &cobra.Command{
+		Use:   "move <ID> <TASK>",
+		Short: "Move a task log entry to another task",
+		Long: `Move a task log entry to another active task.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one active task.
+`,
+		Example: `hours log move 42 "review PRs"`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			id, err := parseTLID(args[0])
+			if err != nil {
+				return err
+			}
+
+			return moveTL(db, os.Stdout, id, args[1])
+		},
+	}
+
+	overlapsTLCmd := &cobra.Command{
+		Use:   "overlaps [PERIOD]",
+		Short: "Show task log entries that overlap",
+		Long: `Show pairs of task log entries that overlap, along with the period they
+overlap for.
+
+Accepts an argument, which can be one of the following:
+
+  today      show overlaps for today
+  yest       show overlaps for yesterday
+  3d         show overlaps for the last 3 days
+  week       show overlaps for the current week (default)
+  date       show overlaps for a specific date (eg. "2024/06/08")
+  range      show overlaps for a date range (eg. "2024/06/08...2024/06/12", "2024/06/08...today")
+`,
+		Example: `hours log overlaps
+hours log overlaps 2024/06/01...2024/06/30 --output json`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			outputFormat, err := types.ParseOutputFormat(outputFormatStr)
+			if err != nil {
+				return err
+			}
+
+			period := types.TimePeriodWeek
+			if len(args) > 0 {
+				period = args[0]
+			}
+
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			dateRange, err := types.GetDateRangeFromPeriod(period, now, false, nil)
+			if err != nil {
+				return err
+			}
+
+			return ui.RenderOverlaps(db, style, os.Stdout, recordsOutputPlain, outputFormat, dateRange)
+		},
+	}
+
+	deleteTLCmd := &cobra.Command{
+		Use:   "rm <ID>",
+		Short: "Move a task log entry to the trash",
+		Long: `Move a task log entry to the trash.
+
+Run "hours log" to find the ID of the entry to delete. Entries in the trash
+don't count towards reports, and can be restored via "hours trash restore".
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			id, err := parseTLID(args[0])
+			if err != nil {
+				return err
+			}
+
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			return deleteTL(db, os.Stdout, id, now)
 		},
 	}
 
@@ -454,6 +772,21 @@ be considered in the stats for the day it ends.
 				return err
 			}
 
+			filter, err := types.NewTaskFilter(taskStatus, filterTags, filterExcludeTags)
+			if err != nil {
+				return err
+			}
+
+			groupBy, err := types.ParseGroupBy(groupByStr)
+			if err != nil {
+				return err
+			}
+
+			outputFormat, err := types.ParseRecordsOutputFormat(outputFormatStr)
+			if err != nil {
+				return err
+			}
+
 			var period string
 			if len(args) == 0 {
 				period = "3d"
@@ -480,90 +813,461 @@ be considered in the stats for the day it ends.
 				dateRange = &dr
 			}
 
-			return ui.RenderStats(db, style, os.Stdout, recordsOutputPlain, dateRange, period, taskStatus, recordsInteractive)
+			if statsBudget {
+				switch {
+				case recordsInteractive:
+					return fmt.Errorf("%w in interactive mode", errBudgetStatsNotApplicable)
+				case groupBy != types.GroupByTask:
+					return fmt.Errorf("%w with --group-by %s", errBudgetStatsNotApplicable, groupBy)
+				case recordsWithComments:
+					return fmt.Errorf("%w with --with-comments", errBudgetStatsNotApplicable)
+				}
+
+				return ui.RenderBudgetStats(db, style, os.Stdout, recordsOutputPlain, outputFormat, dateRange, filter)
+			}
+
+			return ui.RenderStats(db, style, os.Stdout, recordsOutputPlain, outputFormat, dateRange, period, filter, groupBy, recordsInteractive, recordsWithComments)
 		},
 	}
 
-	themesCmd := &cobra.Command{
-		Use:   "themes",
-		Short: "Generate or view hours' themes",
-	}
+	invoiceCmd := &cobra.Command{
+		Use:   "invoice [PERIOD]",
+		Short: "Output an invoice for a client",
+		Long: `Output an invoice for the billable time spent on a client's tasks.
 
-	addThemeCmd := &cobra.Command{
-		Use:   "add <THEME_NAME>",
-		Short: "Add a UI theme for hours",
-		Args:  cobra.ExactArgs(1),
+The invoice has a line item for every billable task of the client that was
+worked on in the period. Time spent on a task is rounded as per --round-to and
+--rounding, and billed at the task's hourly rate if it has one, and at its
+project's otherwise.
+
+Accepts an argument, which can be one of the following:
+
+  today      for today's invoice
+  yest       for yesterday's invoice
+  3d         for an invoice for the last 3 days
+  week       for an invoice for the current week (default)
+  date       for an invoice for a specific date (eg. "2024/06/08")
+  range      for an invoice for a date range (eg. "2024/06/01...2024/06/30", "2024/06/01...today")
+`,
+		Example: `hours invoice 2024/06/01...2024/06/30 --client acme
+hours invoice week --client acme --round-to 15m --rounding up --output html`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: preRun,
 		RunE: func(_ *cobra.Command, args []string) error {
-			themeToCreate := args[0]
-			themePath := filepath.Join(themesDir, fmt.Sprintf("%s.json", themeToCreate))
-			fileInfo, err := os.Stat(themePath)
-			if err == nil && !fileInfo.IsDir() {
-				return fmt.Errorf("%w (at %q)", errThemeAlreadyExists, themePath)
-			} else if !errors.Is(err, fs.ErrNotExist) {
-				return errCouldntCheckIfThemeExists
+			outputFormat, err := types.ParseRecordsOutputFormat(invoiceOutputStr)
+			if err != nil {
+				return err
 			}
 
-			themeFilePath, err := addTheme(themeToCreate, themesDir)
+			roundingMode, err := types.ParseRoundingMode(invoiceRoundingStr)
 			if err != nil {
 				return err
 			}
 
-			fmt.Printf(`Theme file with default values created at: %s
-
-You can edit it as per your liking.
-`, themeFilePath)
-
-			return nil
-		},
-	}
+			client, err := resolveClient(db, invoiceClient)
+			if err != nil {
+				return err
+			}
 
-	listThemesCmd := &cobra.Command{
-		Use:   "list",
-		Short: "List built-in and custom themes set up for hours",
-		RunE: func(_ *cobra.Command, _ []string) error {
-			themes := theme.BuiltIn()
-			walkErr := filepath.Walk(themesDir, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					if errors.Is(err, fs.ErrNotExist) {
-						return nil
-					}
+			period := types.TimePeriodWeek
+			if len(args) > 0 {
+				period = args[0]
+			}
 
-					return err
-				}
-				ext := filepath.Ext(path)
-				if !info.IsDir() && ext == ".json" {
-					base := filepath.Base(path)
-					themes = append(themes, fmt.Sprintf("%s%s", theme.CustomThemePrefix, strings.TrimSuffix(base, ext)))
-				}
-				return nil
-			})
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
 
-			if walkErr != nil {
-				return fmt.Errorf("%w: %s", errCouldntListThemes, walkErr.Error())
+			dateRange, err := types.GetDateRangeFromPeriod(period, now, false, nil)
+			if err != nil {
+				return err
 			}
 
-			fmt.Printf("%s\n", strings.Join(themes, "\n"))
-			return nil
+			return ui.RenderInvoice(db, style, os.Stdout, outputFormat, dateRange, client, invoiceRoundTo, roundingMode)
 		},
 	}
 
-	sampleThemeCmd := &cobra.Command{
-		Use:   "show-sample",
-		Short: "Show a sample theme config",
-		Run: func(_ *cobra.Command, _ []string) {
-			fmt.Printf(`A sample theme config looks like the following.
-Colors codes can be provided in ANSI 16, ANSI 256, or HEX formats.
+	goalsCmd := &cobra.Command{
+		Use:   "goals [PERIOD]",
+		Short: "Show which days met the daily goal",
+		Long: `Show which days in a period met the daily goal for the time to be tracked,
+along with the current streak of days that met it.
 
-You can choose to provide only the attributes you want to change.
+Goals are set via "hours goals set". Progress towards them is also shown in the
+TUI's footer.
 
-%s
+Accepts an argument, which can be one of the following:
+
+  today      show goals for today
+  yest       show goals for yesterday
+  3d         show goals for the last 3 days
+  week       show goals for the current week (default)
+  date       show goals for a specific date (eg. "2024/06/08")
+  range      show goals for a date range (eg. "2024/06/08...2024/06/12", "2024/06/08...today")
 `,
-				sampleThemeConfig)
-		},
-	}
+		Example: `hours goals
+hours goals 2024/06/01...2024/06/30 --output json`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			outputFormat, err := types.ParseOutputFormat(outputFormatStr)
+			if err != nil {
+				return err
+			}
 
-	showThemeConfigCmd := &cobra.Command{
-		Use:     "show-config",
+			period := types.TimePeriodWeek
+			if len(args) > 0 {
+				period = args[0]
+			}
+
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			dateRange, err := types.GetDateRangeFromPeriod(period, now, false, nil)
+			if err != nil {
+				return err
+			}
+
+			return ui.RenderGoals(db, style, os.Stdout, recordsOutputPlain, outputFormat, dateRange, now)
+		},
+	}
+
+	setGoalsCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the daily and/or weekly goal",
+		Long: `Set the daily and/or weekly goal for the time to be tracked.
+
+Goals are durations like "6h", or "7h30m"; pass "none" to unset a goal.
+`,
+		Example: `hours goals set --daily 6h
+hours goals set --daily 7h30m --weekly 35h
+hours goals set --weekly none`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return setGoals(db, os.Stdout, goalDaily, goalWeekly)
+		},
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export task log entries",
+	}
+
+	newExportCmd := func(format, contents string, write func(io.Writer, []domain.TaskLogEntry) error) *cobra.Command {
+		return &cobra.Command{
+			Use:   fmt.Sprintf("%s [PERIOD]", format),
+			Short: fmt.Sprintf("Export task log entries as %s", strings.ToUpper(format)),
+			Long: fmt.Sprintf(`Export task log entries as %s.
+
+%s
+
+Accepts an argument, which can be one of the following:
+
+  today      for log entries from today (default)
+  yest       for log entries from yesterday
+  3d         for log entries from the last 3 days
+  week       for log entries from the current week
+  date       for log entries from a specific date (eg. "2024/06/08")
+  range      for log entries for a date range (eg. "2024/06/08...2024/06/12", "2024/06/08...today", "2024/06/08...")
+
+Note: If a task log continues past midnight in your local timezone, it'll
+be exported for the day it ends.
+`, strings.ToUpper(format), contents),
+			Example: fmt.Sprintf(`hours export %s week > timesheet.%s`, format, format),
+			Args:    cobra.MaximumNArgs(1),
+			PreRunE: preRun,
+			RunE: func(_ *cobra.Command, args []string) error {
+				taskStatus, err := types.ParseTaskStatus(taskStatusStr)
+				if err != nil {
+					return err
+				}
+
+				period := types.TimePeriodToday
+				if len(args) > 0 {
+					period = args[0]
+				}
+
+				now, err := getNow()
+				if err != nil {
+					return err
+				}
+
+				dateRange, err := types.GetDateRangeFromPeriod(period, now, false, nil)
+				if err != nil {
+					return err
+				}
+
+				return exportTLs(db, os.Stdout, write, dateRange, types.TaskFilter{Status: taskStatus})
+			},
+		}
+	}
+
+	delimitedContents := `One row is written per saved task log entry, with the following columns:
+id, task_id, task_summary, begin, end, secs_spent, comment.`
+	exportCSVCmd := newExportCmd("csv", delimitedContents, export.WriteCSV)
+	exportTSVCmd := newExportCmd("tsv", delimitedContents, export.WriteTSV)
+	exportICSCmd := newExportCmd("ics", `An iCalendar file is written, with one event per saved task log entry; its
+task's summary is the event's summary, and its comment is the event's
+description. Events are identified by the task log entry's ID, so importing
+them into a calendar again updates them instead of duplicating them.`,
+		func(w io.Writer, entries []domain.TaskLogEntry) error {
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			return export.WriteICS(w, entries, now)
+		},
+	)
+
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import task log entries from other sources",
+	}
+
+	importCSVCmd := &cobra.Command{
+		Use:   "csv <FILE>",
+		Short: "Import task log entries from a CSV file",
+		Long: `Import task log entries from a CSV file.
+
+The file needs a header row; columns are referred to by their names in it. By
+default, the columns written by "hours export csv" are expected (task_summary,
+begin, end, comment), so exported data can be imported as is. Other layouts
+can be imported by mapping columns using the --*-col flags.
+
+Tasks are matched by their summary, and created if they don't exist yet. Rows
+that duplicate existing task log entries (same task, begin, and end) are
+skipped. If any row is invalid (eg. has an unparseable timestamp, or a
+duration shorter than a minute), nothing is imported.
+
+All entries are imported in a single transaction. Use --dry-run to see what
+would be imported without changing anything.
+`,
+		Example: `hours import csv timesheet.csv --dry-run
+hours import csv timesheet.tsv --delimiter tab
+hours import csv entries.csv --task-col Project --begin-col Start --end-col End \
+    --comment-col Description --time-format '2006-01-02T15:04:05Z07:00'`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			delimiter, err := parseDelimiter(importDelimiter)
+			if err != nil {
+				return err
+			}
+
+			return importCSV(db, os.Stdout, args[0], importMapping, delimiter, importDryRun)
+		},
+	}
+
+	importTimewCmd := &cobra.Command{
+		Use:   "timew <FILE>",
+		Short: "Import task log entries from Timewarrior",
+		Long: `Import task log entries from the output of "timew export" (use "-" to read
+it from stdin).
+
+Each interval's task summary is derived from its tags (by default, the first
+one), or its annotation (see --task-from). The annotation becomes the comment
+of the task log entry, unless it's used as the task summary.
+
+An interval that's still open is imported as the active task log entry, as
+long as no task is being tracked in hours already; finished intervals can be
+imported either way.
+
+Tasks are matched by their summary, and created if they don't exist yet.
+Intervals that duplicate existing task log entries are skipped. If any
+interval is invalid, nothing is imported.
+`,
+		Example: `timew export | hours import timew - --dry-run
+timew export :year | hours import timew - --task-from tags`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			summarySource, err := importer.ParseTimewSummarySource(timewSummarySrcStr)
+			if err != nil {
+				return err
+			}
+
+			return importTimew(db, os.Stdout, args[0], summarySource, importDryRun)
+		},
+	}
+
+	importTogglCmd := &cobra.Command{
+		Use:   "toggl <FILE>",
+		Short: "Import task log entries from Toggl Track",
+		Long: `Import task log entries from a CSV or JSON export of Toggl Track's detailed
+report (use "-" to read it from stdin).
+
+An entry's task summary is derived from its project and description (as
+"project: description"), and its description becomes the comment of the task
+log entry. The end of an entry is taken from its stop time, or computed from its
+duration if that's not present. Running entries are not imported.
+
+Imported entries are recorded, so running an import again skips entries that
+were imported before, even if their task log entries were changed since.
+Entries are recognized by their IDs in JSON exports; since CSV exports don't
+include IDs, entries in them are recognized by their user, start, project, and
+description.
+`,
+		Example: `hours import toggl Toggl_time_entries.csv --dry-run
+hours import toggl detailed-report.json`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return importToggl(db, os.Stdout, args[0], togglFormat, importDryRun)
+		},
+	}
+
+	importICSCmd := &cobra.Command{
+		Use:   "ics <FILE> [PERIOD]",
+		Short: "Import calendar events from an iCalendar file",
+		Long: `Import calendar events from an iCalendar (.ics) file as task log entries (use
+"-" to read it from stdin).
+
+Only events that end within the period are imported; the period defaults to
+today, and accepts the same values as "hours log". Recurring events are expanded
+within the period, leaving out excluded and modified instances (modified
+instances are imported as they are). All-day, cancelled, and unfinished events,
+and events shorter than a minute are skipped.
+
+Events are mapped to tasks via rules of the format "REGEX=TASK", which are
+tried in order against an event's summary; the first one to match decides the
+task, and the event's summary becomes the comment of the task log entry. The
+task can refer to the regex's submatches (eg. "$1"). With --create-tasks, events
+that match no rule are imported under tasks named after their summaries;
+otherwise they're skipped. Tasks that don't exist are created.
+
+Events that overlap with existing task log entries (or with each other) are
+skipped, unless --allow-overlap is used.
+
+Imported events are recorded, so running an import again skips events that were
+imported before. Events are recognized by their UIDs (and the original start of
+an instance, for recurring events).
+`,
+		Example: `hours import ics calendar.ics --create-tasks --dry-run
+hours import ics calendar.ics week --rule '(?i)standup=meetings' --rule '^1:1 with (.+)$=1:1s with $1'
+hours import ics calendar.ics 2025/10/01...2025/10/15 --rule '.*=meetings' --allow-overlap`,
+		Args:    cobra.RangeArgs(1, 2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			rules := make([]importer.ICSRule, len(icsRuleStrs))
+			for i, ruleStr := range icsRuleStrs {
+				rule, err := importer.ParseICSRule(ruleStr)
+				if err != nil {
+					return err
+				}
+				rules[i] = rule
+			}
+
+			period := types.TimePeriodToday
+			if len(args) > 1 {
+				period = args[1]
+			}
+
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			dateRange, err := types.GetDateRangeFromPeriod(period, now, false, nil)
+			if err != nil {
+				return err
+			}
+
+			opts := importer.ICSOptions{
+				Rules:       rules,
+				CreateTasks: icsCreateTasks,
+				DateRange:   dateRange,
+				Now:         now,
+			}
+
+			return importICS(db, os.Stdout, args[0], opts, icsAllowOverlap, importDryRun)
+		},
+	}
+
+	themesCmd := &cobra.Command{
+		Use:   "themes",
+		Short: "Generate or view hours' themes",
+	}
+
+	addThemeCmd := &cobra.Command{
+		Use:   "add <THEME_NAME>",
+		Short: "Add a UI theme for hours",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			themeToCreate := args[0]
+			themePath := filepath.Join(themesDir, fmt.Sprintf("%s.json", themeToCreate))
+			fileInfo, err := os.Stat(themePath)
+			if err == nil && !fileInfo.IsDir() {
+				return fmt.Errorf("%w (at %q)", errThemeAlreadyExists, themePath)
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return errCouldntCheckIfThemeExists
+			}
+
+			themeFilePath, err := addTheme(themeToCreate, themesDir)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf(`Theme file with default values created at: %s
+
+You can edit it as per your liking.
+`, themeFilePath)
+
+			return nil
+		},
+	}
+
+	listThemesCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List built-in and custom themes set up for hours",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			themes := theme.BuiltIn()
+			walkErr := filepath.Walk(themesDir, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					if errors.Is(err, fs.ErrNotExist) {
+						return nil
+					}
+
+					return err
+				}
+				ext := filepath.Ext(path)
+				if !info.IsDir() && ext == ".json" {
+					base := filepath.Base(path)
+					themes = append(themes, fmt.Sprintf("%s%s", theme.CustomThemePrefix, strings.TrimSuffix(base, ext)))
+				}
+				return nil
+			})
+
+			if walkErr != nil {
+				return fmt.Errorf("%w: %s", errCouldntListThemes, walkErr.Error())
+			}
+
+			fmt.Printf("%s\n", strings.Join(themes, "\n"))
+			return nil
+		},
+	}
+
+	sampleThemeCmd := &cobra.Command{
+		Use:   "show-sample",
+		Short: "Show a sample theme config",
+		Run: func(_ *cobra.Command, _ []string) {
+			fmt.Printf(`A sample theme config looks like the following.
+Colors codes can be provided in ANSI 16, ANSI 256, or HEX formats.
+
+You can choose to provide only the attributes you want to change.
+
+%s
+`,
+				sampleThemeConfig)
+		},
+	}
+
+	showThemeConfigCmd := &cobra.Command{
+		Use:     "show-config",
 		Short:   "Show JSON configuration for a theme",
 		Example: strings.TrimSuffix(showThemeConfigExamples, "\n"),
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -574,38 +1278,681 @@ You can choose to provide only the attributes you want to change.
 				}
 			}
 
-			thm, err := theme.Get(themeName, themesDir)
+			thm, err := theme.Get(themeName, themesDir)
+			if err != nil {
+				return err
+			}
+
+			themeBytes, err := json.MarshalIndent(thm, "", "  ")
+			if err != nil {
+				return fmt.Errorf("%w: %w", errCouldntMarshalTheme, err)
+			}
+
+			fmt.Printf("%s\n", themeBytes)
+
+			return nil
+		},
+	}
+
+	activeCmd := &cobra.Command{
+		Use:   "active",
+		Short: "Show the task being actively tracked by \"hours\"",
+		Long: `Show the task being actively tracked by "hours".
+
+You can pass in a template using the --template/-t flag, which supports the
+following placeholders:
+
+  {{task}}:  for the task summary
+  {{time}}:  for the time spent so far on the active log entry (followed by
+             ", paused" if it's paused)
+
+eg. hours active -t ' {{task}} ({{time}}) '
+`,
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			outputFormat, err := types.ParseOutputFormat(outputFormatStr)
+			if err != nil {
+				return err
+			}
+
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			return ui.ShowActiveTask(db, os.Stdout, activeTemplate, outputFormat, now)
+		},
+	}
+
+	trackingExitCodes := fmt.Sprintf(`Exit codes:
+
+  %d  no task is being tracked
+  %d  no task matched the provided ID or summary prefix
+  %d  more than one task matched the provided summary prefix
+  %d  a task is already being tracked
+`, exitCodeNoTaskActive, exitCodeNoTaskMatched, exitCodeMultipleTasks, exitCodeTaskAlreadyActive)
+
+	startCmd := &cobra.Command{
+		Use:   "start <TASK>",
+		Short: "Start tracking time on a task",
+		Long: fmt.Sprintf(`Start tracking time on a task.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one active task.
+
+The begin timestamp can be backdated using --at, which accepts either
+"YYYY/MM/DD HH:MM" or "HH:MM" (for today).
+
+%s`, trackingExitCodes),
+		Example: `hours start 3
+hours start "write blog" --comment "outline" --at 09:30`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			beginTS, err := getTrackingTS(trackingAt, now)
+			if err != nil {
+				return err
+			}
+
+			return startTracking(db, os.Stdout, args[0], beginTS, now, getComment(trackingComment))
+		},
+	}
+
+	stopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop tracking time on the active task",
+		Long: fmt.Sprintf(`Stop tracking time on the active task, and save the task log entry.
+
+The end timestamp can be backdated using --at, which accepts either
+"YYYY/MM/DD HH:MM" or "HH:MM" (for today).
+
+The comment set when tracking started is kept, unless overridden via
+--comment (passing an empty comment clears it).
+
+%s`, trackingExitCodes),
+		Example: `hours stop
+hours stop --comment "finished the outline" --at 11:45`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			endTS, err := getTrackingTS(trackingAt, now)
+			if err != nil {
+				return err
+			}
+
+			return stopTracking(db, os.Stdout, endTS, now, getComment(trackingComment), cmd.Flags().Changed("comment"))
+		},
+	}
+
+	switchCmd := &cobra.Command{
+		Use:   "switch <TASK>",
+		Short: "Stop tracking the active task, and start tracking another one",
+		Long: fmt.Sprintf(`Stop tracking the active task, and start tracking another one.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one active task.
+
+The switch timestamp can be backdated using --at, which accepts either
+"YYYY/MM/DD HH:MM" or "HH:MM" (for today). The comment passed via --comment is
+set on the new task log entry.
+
+%s`, trackingExitCodes),
+		Example: `hours switch 4
+hours switch review --at 14:00`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			ts, err := getTrackingTS(trackingAt, now)
+			if err != nil {
+				return err
+			}
+
+			return switchTracking(db, os.Stdout, args[0], ts, now, getComment(trackingComment))
+		},
+	}
+
+	pauseCmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause the active task log",
+		Long: fmt.Sprintf(`Pause the active task log, without finishing it.
+
+The time the task log stays paused for doesn't count towards the time spent on
+it. Tracking can be resumed via "hours resume".
+
+The pause timestamp can be backdated using --at, which accepts either
+"YYYY/MM/DD HH:MM" or "HH:MM" (for today).
+
+%s`, trackingExitCodes),
+		Example: `hours pause
+hours pause --at 12:30`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			now, err := getNow()
 			if err != nil {
 				return err
 			}
 
-			themeBytes, err := json.MarshalIndent(thm, "", "  ")
+			ts, err := getTrackingTS(trackingAt, now)
 			if err != nil {
-				return fmt.Errorf("%w: %w", errCouldntMarshalTheme, err)
+				return err
 			}
 
-			fmt.Printf("%s\n", themeBytes)
+			return pauseTracking(db, os.Stdout, ts, now)
+		},
+	}
 
-			return nil
+	resumeCmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume the paused task log",
+		Long: fmt.Sprintf(`Resume the task log paused via "hours pause".
+
+The resume timestamp can be backdated using --at, which accepts either
+"YYYY/MM/DD HH:MM" or "HH:MM" (for today).
+
+%s`, trackingExitCodes),
+		Example: `hours resume
+hours resume --at 13:15`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			ts, err := getTrackingTS(trackingAt, now)
+			if err != nil {
+				return err
+			}
+
+			return resumeTracking(db, os.Stdout, ts, now)
 		},
 	}
 
-	activeCmd := &cobra.Command{
-		Use:   "active",
-		Short: "Show the task being actively tracked by \"hours\"",
-		Long: `Show the task being actively tracked by "hours".
+	taskCmd := &cobra.Command{
+		Use:   "task",
+		Short: "Manage tasks",
+	}
 
-You can pass in a template using the --template/-t flag, which supports the
-following placeholders:
+	addTaskCmd := &cobra.Command{
+		Use:   "add <SUMMARY>",
+		Short: "Add a task",
+		Example: `hours task add "write blog post"
+hours task add "write blog post" --tag writing,personal
+hours task add "fix login bug" --project website
+hours task add "migrate to postgres" --budget 20h`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return addTask(db, os.Stdout, args[0], taskProject, taskTags, taskBudget)
+		},
+	}
 
-  {{task}}:  for the task summary
-  {{time}}:  for the time spent so far on the active log entry
+	listTasksCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tasks",
+		Long: `List tasks, along with their status, the total time tracked on them, and when
+they were last updated.
 
-eg. hours active -t ' {{task}} ({{time}}) '
+Only active tasks are listed by default; use --task-status to change that.
+`,
+		Example: `hours task list
+hours task list --task-status any --output json`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			taskStatus, err := types.ParseTaskStatus(taskListStatusStr)
+			if err != nil {
+				return err
+			}
+
+			outputFormat, err := types.ParseOutputFormat(outputFormatStr)
+			if err != nil {
+				return err
+			}
+
+			return ui.RenderTasks(db, style, os.Stdout, recordsOutputPlain, outputFormat, taskStatus)
+		},
+	}
+
+	renameTaskCmd := &cobra.Command{
+		Use:   "rename <TASK> <SUMMARY>",
+		Short: "Rename an active task",
+		Long: `Rename an active task.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one active task.
+`,
+		Example: `hours task rename 3 "write blog post on sqlite"`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return renameTask(db, os.Stdout, args[0], args[1])
+		},
+	}
+
+	deactivateTaskCmd := &cobra.Command{
+		Use:   "deactivate <TASK>",
+		Short: "Deactivate an active task",
+		Long: `Deactivate an active task.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one active task. A task being tracked
+cannot be deactivated.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return updateTaskActiveStatus(db, os.Stdout, args[0], false)
+		},
+	}
+
+	activateTaskCmd := &cobra.Command{
+		Use:   "activate <TASK>",
+		Short: "Activate an inactive task",
+		Long: `Activate an inactive task.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one inactive task.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return updateTaskActiveStatus(db, os.Stdout, args[0], true)
+		},
+	}
+
+	tagTaskCmd := &cobra.Command{
+		Use:   "tag <TASK> <TAG>...",
+		Short: "Add tags to a task",
+		Long: `Add tags to a task.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one task. Tags are lowercased, and can
+contain letters, digits, and the characters "_./:-".
+`,
+		Example: `hours task tag 3 writing personal`,
+		Args:    cobra.MinimumNArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return updateTaskTags(db, os.Stdout, args[0], args[1:], false)
+		},
+	}
+
+	untagTaskCmd := &cobra.Command{
+		Use:   "untag <TASK> <TAG>...",
+		Short: "Remove tags from a task",
+		Long: `Remove tags from a task.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one task.
+`,
+		Example: `hours task untag 3 personal`,
+		Args:    cobra.MinimumNArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return updateTaskTags(db, os.Stdout, args[0], args[1:], true)
+		},
+	}
+
+	setTaskProjectCmd := &cobra.Command{
+		Use:   "set-project <TASK> <PROJECT>",
+		Short: "Move a task to a project",
+		Long: `Move a task to a project.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one task. Use the project "unassigned"
+to take a task out of its project.
+`,
+		Example: `hours task set-project 3 website`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return setTaskProject(db, os.Stdout, args[0], args[1])
+		},
+	}
+
+	setTaskRateCmd := &cobra.Command{
+		Use:   "set-rate <TASK> <RATE>",
+		Short: "Set the hourly rate of a task",
+		Long: `Set the hourly rate of a task, which takes precedence over the rate of its
+project.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one task. Pass "none" as the rate to
+unset it.
+`,
+		Example: `hours task set-rate 3 150 --currency EUR
+hours task set-rate 3 none`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return setTaskRate(db, os.Stdout, args[0], args[1], rateCurrency)
+		},
+	}
+
+	setTaskBudgetCmd := &cobra.Command{
+		Use:   "set-budget <TASK> <DURATION>",
+		Short: "Set the time budget of a task",
+		Long: `Set the time budget of a task, ie, how much time is expected to be spent on
+it. Progress against the budget is shown in the TUI, and via "hours stats
+--budget".
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one task. The budget is a duration like
+"10h", or "1h30m"; pass "none" to unset it.
+`,
+		Example: `hours task set-budget 3 10h
+hours task set-budget "write blog" 1h30m
+hours task set-budget 3 none`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return setTaskBudget(db, os.Stdout, args[0], args[1])
+		},
+	}
+
+	billableTaskCmd := &cobra.Command{
+		Use:   "billable <TASK>",
+		Short: "Mark a task as billable",
+		Long: `Mark a task as billable (which tasks are by default).
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one task.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return setTaskBillable(db, os.Stdout, args[0], true)
+		},
+	}
+
+	nonBillableTaskCmd := &cobra.Command{
+		Use:   "non-billable <TASK>",
+		Short: "Mark a task as non-billable",
+		Long: `Mark a task as non-billable; time spent on it is left out of invoices.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one task.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return setTaskBillable(db, os.Stdout, args[0], false)
+		},
+	}
+
+	deleteTaskCmd := &cobra.Command{
+		Use:   "rm <TASK>",
+		Short: "Move a task to the trash",
+		Long: `Move a task to the trash, along with its task log entries.
+
+The task can be specified either by its ID, or by a prefix of its summary
+(case-insensitive) that matches exactly one task. A task being tracked cannot
+be deleted. Tasks in the trash can be restored via "hours trash restore".
+`,
+		Example: `hours task rm 3`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			return trashTask(db, os.Stdout, args[0], now)
+		},
+	}
+
+	projectCmd := &cobra.Command{
+		Use:   "project",
+		Short: "Manage projects",
+		Long: `Manage projects.
+
+Tasks belong to a project, and projects belong to a client. Tasks that haven't
+been moved to a project belong to the project "unassigned" (whose client is
+also "unassigned").
+`,
+	}
+
+	addProjectCmd := &cobra.Command{
+		Use:     "add <NAME>",
+		Short:   "Add a project",
+		Example: `hours project add website --client acme`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return addProject(db, os.Stdout, args[0], projectClient)
+		},
+	}
+
+	listProjectsCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List projects",
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			outputFormat, err := types.ParseOutputFormat(outputFormatStr)
+			if err != nil {
+				return err
+			}
+
+			return ui.RenderProjects(db, style, os.Stdout, recordsOutputPlain, outputFormat)
+		},
+	}
+
+	setProjectRateCmd := &cobra.Command{
+		Use:   "set-rate <PROJECT> <RATE>",
+		Short: "Set the hourly rate of a project",
+		Long: `Set the hourly rate of a project.
+
+The rate applies to all tasks of the project that don't have a rate of their
+own. Pass "none" as the rate to unset it.
+`,
+		Example: `hours project set-rate website 120.50 --currency EUR
+hours project set-rate website none`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return setProjectRate(db, os.Stdout, args[0], args[1], rateCurrency)
+		},
+	}
+
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage hours' database",
+	}
+
+	backupDBCmd := &cobra.Command{
+		Use:   "backup [PATH]",
+		Short: "Back up hours' database",
+		Long: `Back up hours' database.
+
+The backup is a consistent snapshot of the database, and can be made while hours
+is running.
+
+If PATH ends with ".db", the backup is written to it. Otherwise, PATH is
+treated as a directory, and the backup is written to a timestamped file in it.
+By default, backups are written to a directory named "hours-backups" next to
+the database.
+
+Use --keep to only keep the newest N timestamped backups in the directory.
+`,
+		Example: `hours db backup
+hours db backup ~/backups --keep 7
+hours db backup ~/backups/hours-before-cleanup.db`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			var target string
+			if len(args) > 0 {
+				target = expandTilde(args[0], userHomeDir)
+			}
+
+			return backupDB(db, os.Stdout, dbPathFull, target, backupKeep, now)
+		},
+	}
+
+	restoreDBCmd := &cobra.Command{
+		Use:   "restore <FILE>",
+		Short: "Restore hours' database from a backup",
+		Long: `Restore hours' database from a backup.
+
+The backup is checked before anything is replaced: it needs to be an hours
+database that this version of hours can work with, and it needs to pass
+SQLite's integrity check. The current database is backed up to the
+"hours-backups" directory next to it before it's replaced.
+
+Make sure hours isn't running while a backup is being restored.
+`,
+		Example: `hours db restore ~/hours-backups/hours-20251024T120000.db`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			dbPathFull = expandTilde(dbPath, userHomeDir)
+			if filepath.Ext(dbPathFull) != ".db" {
+				return errDBFileExtIncorrect
+			}
+
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			return restoreDB(os.Stdout, dbPathFull, expandTilde(args[0], userHomeDir), now)
+		},
+	}
+
+	migrateDBCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate hours' database to the latest version",
+		Long: `Migrate hours' database to the latest version.
+
+hours migrates its database automatically when a new version of hours opens it
+for the first time; this command lists the pending migrations, and lets you
+check whether they work (using --dry-run) before that happens. The database is
+backed up to the "hours-backups" directory next to it before it's migrated.
+`,
+		Example: `hours db migrate --dry-run`,
+		Args:    cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			dbPathFull = expandTilde(dbPath, userHomeDir)
+			if filepath.Ext(dbPathFull) != ".db" {
+				return errDBFileExtIncorrect
+			}
+
+			return migrateDB(os.Stdout, dbPathFull, migrateDryRun)
+		},
+	}
+
+	trashCmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Manage deleted tasks and task log entries",
+		Long: `Manage deleted tasks and task log entries.
+
+Deleting a task or a task log entry moves it to the trash, from where it can be
+restored, until the trash is purged. Items in the trash are left out of
+reports, stats, and everything else.
+`,
+	}
+
+	listTrashCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the items in the trash",
+		Example: `hours trash list
+hours trash list --output json`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			outputFormat, err := types.ParseOutputFormat(outputFormatStr)
+			if err != nil {
+				return err
+			}
+
+			return ui.RenderTrash(db, style, os.Stdout, recordsOutputPlain, outputFormat)
+		},
+	}
+
+	restoreFromTrashCmd := &cobra.Command{
+		Use:   "restore <log|task> <ID>",
+		Short: "Restore an item from the trash",
+		Long: `Restore a task log entry, or a task, from the trash.
+
+Run "hours trash list" to find the ID of the item to restore. A task log entry
+of a task in the trash can only be restored once the task is.
+`,
+		Example: `hours trash restore log 42
+hours trash restore task 3`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return restoreFromTrash(db, os.Stdout, args[0], args[1])
+		},
+	}
+
+	purgeTrashCmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Permanently delete the items in the trash",
+		Long: `Permanently delete the items in the trash; this cannot be undone.
+
+Purging a task also purges all of its task log entries. Use --older-than to
+only purge items that were deleted more than N days ago.
+`,
+		Example: `hours trash purge
+hours trash purge --older-than 30`,
+		Args:    cobra.NoArgs,
+		PreRunE: preRun,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			now, err := getNow()
+			if err != nil {
+				return err
+			}
+
+			return purgeTrash(db, os.Stdout, purgeOlderThan, now)
+		},
+	}
+
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check hours' database for problems",
+		Long: `Check hours' database for problems.
+
+The following checks are run:
+- SQLite's integrity check
+- the time spent on each task matches the sum of its task log entries
+- every task log entry belongs to a task that exists
+- saved task log entries end after they begin
+- at most one task log entry is active
+
+Use --fix to repair the problems that can be fixed automatically; this happens
+in a single transaction. It's a good idea to run "hours db backup" first.
 `,
+		Example: `hours doctor
+hours doctor --fix`,
+		Args:    cobra.NoArgs,
 		PreRunE: preRun,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return ui.ShowActiveTask(db, os.Stdout, activeTemplate)
+			return runDoctor(db, os.Stdout, doctorFix)
 		},
 	}
 
@@ -625,6 +1972,11 @@ eg. hours active -t ' {{task}} ({{time}}) '
 	defaultDBPath := filepath.Join(userHomeDir, defaultDBName)
 	rootCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
 	rootCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
+	rootCmd.Flags().DurationVar(&timeboxDuration, "timebox", 25*time.Minute, "length of timeboxes (started with P in the TUI)")
+	rootCmd.Flags().DurationVar(&breakDuration, "break", 5*time.Minute, "length of the break after a timebox; 0 turns off breaks")
+	rootCmd.Flags().BoolVar(&recordBreaks, "record-breaks", false, `whether to track breaks on a task called "break"`)
+	rootCmd.Flags().BoolVar(&rejectOverlaps, "reject-overlaps", false, "whether to refuse to save task log entries that overlap saved ones")
+	rootCmd.Flags().StringVar(&timeboxAlertStr, "timebox-alert", types.TAValueBell, fmt.Sprintf("how to signal the end of a timebox or a break [possible values: %q]", types.ValidTimeboxAlertValues))
 
 	generateCmd.Flags().Uint8Var(&genNumDays, "num-days", 30, "number of days to generate fake data for")
 	generateCmd.Flags().Uint8Var(&genNumTasks, "num-tasks", 10, "number of tasks to generate fake data for")
@@ -634,38 +1986,258 @@ eg. hours active -t ' {{task}} ({{time}}) '
 	reportCmd.Flags().BoolVarP(&reportAgg, "agg", "a", false, "whether to aggregate data by task for each day in report")
 	reportCmd.Flags().BoolVarP(&recordsInteractive, "interactive", "i", false, "whether to view report interactively")
 	reportCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output report without any formatting")
+	reportCmd.Flags().StringVarP(&outputFormatStr, "output", "o", types.OFValueTable, fmt.Sprintf("output format [possible values: %q]", types.ValidRecordsOutputFormatValues))
+	reportCmd.Flags().BoolVar(&recordsWithComments, "with-comments", false, "whether to include a collapsible section with the comments of task log entries (HTML output only)")
 	reportCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
 	reportCmd.Flags().StringVarP(&taskStatusStr, "task-status", "s", "any", fmt.Sprintf("only show data for tasks with this status [possible values: %q]", types.ValidTaskStatusValues))
+	reportCmd.Flags().StringSliceVar(&filterTags, "tag", nil, "only show data for tasks with any of these tags (can be repeated, or comma separated)")
+	reportCmd.Flags().StringSliceVar(&filterExcludeTags, "exclude-tag", nil, "don't show data for tasks with any of these tags (can be repeated, or comma separated)")
+	reportCmd.Flags().StringVar(&groupByStr, "group-by", types.GBValueTask, fmt.Sprintf("what to total time spent on each day by; entries are aggregated unless this is \"task\" [possible values: %q]", []string{types.GBValueTask, types.GBValueProject, types.GBValueClient}))
 	reportCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
 
 	logCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output logs without any formatting")
 	logCmd.Flags().BoolVarP(&recordsInteractive, "interactive", "i", false, "whether to view logs interactively")
+	logCmd.Flags().StringVarP(&outputFormatStr, "output", "o", types.OFValueTable, fmt.Sprintf("output format [possible values: %q]", types.ValidRecordsOutputFormatValues))
+	logCmd.Flags().BoolVar(&recordsWithComments, "with-comments", false, "whether to include a collapsible section with the comments of task log entries (HTML output only)")
 	logCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
 	logCmd.Flags().StringVarP(&taskStatusStr, "task-status", "s", "any", fmt.Sprintf("only show data for tasks with this status [possible values: %q]", types.ValidTaskStatusValues))
+	logCmd.Flags().StringSliceVar(&filterTags, "tag", nil, "only show data for tasks with any of these tags (can be repeated, or comma separated)")
+	logCmd.Flags().StringSliceVar(&filterExcludeTags, "exclude-tag", nil, "don't show data for tasks with any of these tags (can be repeated, or comma separated)")
 	logCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
 
+	addTLCmd.Flags().StringVar(&tlBeginStr, "begin", "", "begin timestamp (\"YYYY/MM/DD HH:MM\" or \"HH:MM\")")
+	addTLCmd.Flags().StringVar(&tlEndStr, "end", "", "end timestamp (\"YYYY/MM/DD HH:MM\" or \"HH:MM\")")
+	addTLCmd.Flags().StringVarP(&tlComment, "comment", "c", "", "comment for the task log entry")
+	addTLCmd.Flags().BoolVar(&rejectOverlaps, "reject-overlaps", false, "whether to refuse to add the entry if it overlaps saved ones")
+	addTLCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	_ = addTLCmd.MarkFlagRequired("begin")
+	_ = addTLCmd.MarkFlagRequired("end")
+
+	editTLCmd.Flags().StringVar(&tlBeginStr, "begin", "", "new begin timestamp (\"YYYY/MM/DD HH:MM\" or \"HH:MM\")")
+	editTLCmd.Flags().StringVar(&tlEndStr, "end", "", "new end timestamp (\"YYYY/MM/DD HH:MM\" or \"HH:MM\")")
+	editTLCmd.Flags().StringVarP(&tlComment, "comment", "c", "", "new comment for the task log entry")
+	editTLCmd.Flags().BoolVar(&rejectOverlaps, "reject-overlaps", false, "whether to refuse to save the change if the entry ends up overlapping saved ones")
+	editTLCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	splitTLCmd.Flags().StringVar(&tlSplitAtStr, "at", "", "timestamp to split the entry at (\"YYYY/MM/DD HH:MM\" or \"HH:MM\")")
+	splitTLCmd.Flags().StringVar(&tlSplitTask, "task", "", "task for the second entry (defaults to the task of the entry being split)")
+	splitTLCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	_ = splitTLCmd.MarkFlagRequired("at")
+
+	mergeTLsCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	moveTLCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	overlapsTLCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output overlaps without any formatting")
+	overlapsTLCmd.Flags().StringVarP(&outputFormatStr, "output", "o", types.OFValueTable, fmt.Sprintf("output format [possible values: %q]", types.ValidOutputFormatValues))
+	overlapsTLCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	overlapsTLCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
+
+	deleteTLCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
 	statsCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output stats without any formatting")
 	statsCmd.Flags().BoolVarP(&recordsInteractive, "interactive", "i", false, "whether to view stats interactively")
+	statsCmd.Flags().StringVarP(&outputFormatStr, "output", "o", types.OFValueTable, fmt.Sprintf("output format [possible values: %q]", types.ValidRecordsOutputFormatValues))
+	statsCmd.Flags().BoolVar(&statsBudget, "budget", false, "whether to show how much of their time budgets tasks have used up, instead of time spent")
+	statsCmd.Flags().BoolVar(&recordsWithComments, "with-comments", false, "whether to include a collapsible section with the comments of task log entries (HTML output only)")
 	statsCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
 	statsCmd.Flags().StringVarP(&taskStatusStr, "task-status", "s", "any", fmt.Sprintf("only show data for tasks with this status [possible values: %q]", types.ValidTaskStatusValues))
+	statsCmd.Flags().StringSliceVar(&filterTags, "tag", nil, "only show data for tasks with any of these tags (can be repeated, or comma separated)")
+	statsCmd.Flags().StringSliceVar(&filterExcludeTags, "exclude-tag", nil, "don't show data for tasks with any of these tags (can be repeated, or comma separated)")
+	statsCmd.Flags().StringVar(&groupByStr, "group-by", types.GBValueTask, fmt.Sprintf("what to total time spent by [possible values: %q]", types.ValidGroupByValues))
 	statsCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
 
 	activeCmd.Flags().StringVarP(&activeTemplate, "template", "t", ui.ActiveTaskPlaceholder, "string template to use for outputting active task")
+	activeCmd.Flags().StringVarP(&outputFormatStr, "output", "o", types.OFValueTable, fmt.Sprintf("output format [possible values: %q]; the template is ignored for JSON output", types.ValidOutputFormatValues))
 	activeCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
 
+	for _, cmd := range []*cobra.Command{exportCSVCmd, exportTSVCmd, exportICSCmd} {
+		cmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+		cmd.Flags().StringVarP(&taskStatusStr, "task-status", "s", "any", fmt.Sprintf("only export data for tasks with this status [possible values: %q]", types.ValidTaskStatusValues))
+	}
+
+	defaultImportMapping := importer.DefaultColumnMapping()
+	importCSVCmd.Flags().StringVar(&importMapping.Task, "task-col", defaultImportMapping.Task, "column holding the task summary")
+	importCSVCmd.Flags().StringVar(&importMapping.Begin, "begin-col", defaultImportMapping.Begin, "column holding the begin timestamp")
+	importCSVCmd.Flags().StringVar(&importMapping.End, "end-col", defaultImportMapping.End, "column holding the end timestamp")
+	importCSVCmd.Flags().StringVar(&importMapping.Comment, "comment-col", defaultImportMapping.Comment, "column holding the comment (optional; pass an empty value to ignore comments)")
+	importCSVCmd.Flags().StringVar(&importMapping.TimeFormat, "time-format", defaultImportMapping.TimeFormat, "layout of timestamps, in Go's time format; the local timezone is used unless the layout includes one")
+	importCSVCmd.Flags().StringVar(&importDelimiter, "delimiter", ",", `field delimiter (use "tab" for tab-separated files)`)
+	importCSVCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "report what would be imported without changing anything")
+	importCSVCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	importTimewCmd.Flags().StringVar(&timewSummarySrcStr, "task-from", importer.TSSValueFirstTag, fmt.Sprintf("what to derive task summaries from [possible values: %q]", importer.ValidTimewSummarySourceValues))
+	importTimewCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "report what would be imported without changing anything")
+	importTimewCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	importTogglCmd.Flags().StringVar(&togglFormat, "format", "", fmt.Sprintf("format of the export [possible values: %q]; inferred from the file extension by default", validTogglFormatValues))
+	importTogglCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "report what would be imported without changing anything")
+	importTogglCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	importICSCmd.Flags().StringArrayVar(&icsRuleStrs, "rule", nil, `rule mapping events to tasks, of the format "REGEX=TASK" (can be repeated)`)
+	importICSCmd.Flags().BoolVar(&icsCreateTasks, "create-tasks", false, "import events that match no rule under tasks named after their summaries")
+	importICSCmd.Flags().BoolVar(&icsAllowOverlap, "allow-overlap", false, "import events even if they overlap with existing task log entries")
+	importICSCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "report what would be imported without changing anything")
+	importICSCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	startCmd.Flags().StringVarP(&trackingComment, "comment", "c", "", "comment for the task log entry")
+	startCmd.Flags().StringVar(&trackingAt, "at", "", "begin timestamp (\"YYYY/MM/DD HH:MM\" or \"HH:MM\"); defaults to now")
+	startCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	stopCmd.Flags().StringVarP(&trackingComment, "comment", "c", "", "comment for the task log entry (overrides the existing one)")
+	stopCmd.Flags().StringVar(&trackingAt, "at", "", "end timestamp (\"YYYY/MM/DD HH:MM\" or \"HH:MM\"); defaults to now")
+	stopCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	switchCmd.Flags().StringVarP(&trackingComment, "comment", "c", "", "comment for the new task log entry")
+	switchCmd.Flags().StringVar(&trackingAt, "at", "", "switch timestamp (\"YYYY/MM/DD HH:MM\" or \"HH:MM\"); defaults to now")
+	switchCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	pauseCmd.Flags().StringVar(&trackingAt, "at", "", "pause timestamp (\"YYYY/MM/DD HH:MM\" or \"HH:MM\"); defaults to now")
+	pauseCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	resumeCmd.Flags().StringVar(&trackingAt, "at", "", "resume timestamp (\"YYYY/MM/DD HH:MM\" or \"HH:MM\"); defaults to now")
+	resumeCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	addTaskCmd.Flags().StringSliceVar(&taskTags, "tag", nil, "tags for the task (can be repeated, or comma separated)")
+	addTaskCmd.Flags().StringVar(&taskProject, "project", "", `project for the task (run "hours project list" for allowed values)`)
+	addTaskCmd.Flags().StringVar(&taskBudget, "budget", "", `time budget for the task (eg. "10h", or "1h30m")`)
+	addTaskCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	listTasksCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output tasks without any formatting")
+	listTasksCmd.Flags().StringVarP(&outputFormatStr, "output", "o", types.OFValueTable, fmt.Sprintf("output format [possible values: %q]", types.ValidOutputFormatValues))
+	listTasksCmd.Flags().StringVarP(&taskListStatusStr, "task-status", "s", types.TSValueActive, fmt.Sprintf("only show tasks with this status [possible values: %q]", types.ValidTaskStatusValues))
+	listTasksCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	listTasksCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
+
+	renameTaskCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	deactivateTaskCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	activateTaskCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	tagTaskCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	untagTaskCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	setTaskProjectCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	setTaskRateCmd.Flags().StringVar(&rateCurrency, "currency", "", `currency of the rate, as a 3 letter code (eg. "EUR")`)
+	setTaskRateCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	setTaskBudgetCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	billableTaskCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	nonBillableTaskCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	deleteTaskCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	addProjectCmd.Flags().StringVar(&projectClient, "client", pers.UnassignedName, "client the project is for")
+	addProjectCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	listProjectsCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output projects without any formatting")
+	listProjectsCmd.Flags().StringVarP(&outputFormatStr, "output", "o", types.OFValueTable, fmt.Sprintf("output format [possible values: %q]", types.ValidOutputFormatValues))
+	listProjectsCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	listProjectsCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
+
+	setProjectRateCmd.Flags().StringVar(&rateCurrency, "currency", "", `currency of the rate, as a 3 letter code (eg. "EUR")`)
+	setProjectRateCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	goalsCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output goals without any formatting")
+	goalsCmd.Flags().StringVarP(&outputFormatStr, "output", "o", types.OFValueTable, fmt.Sprintf("output format [possible values: %q]", types.ValidOutputFormatValues))
+	goalsCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	goalsCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
+
+	setGoalsCmd.Flags().StringVar(&goalDaily, "daily", "", `daily goal (eg. "6h"), or "none" to unset it`)
+	setGoalsCmd.Flags().StringVar(&goalWeekly, "weekly", "", `weekly goal (eg. "30h"), or "none" to unset it`)
+	setGoalsCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	invoiceCmd.Flags().StringVar(&invoiceClient, "client", "", "client to invoice")
+	invoiceCmd.Flags().DurationVar(&invoiceRoundTo, "round-to", 0, `round the time spent on each task to a multiple of this duration (eg. "15m"); 0 turns off rounding`)
+	invoiceCmd.Flags().StringVar(&invoiceRoundingStr, "rounding", types.RMValueNearest, fmt.Sprintf("how to round time spent [possible values: %q]", types.ValidRoundingModeValues))
+	invoiceCmd.Flags().StringVarP(&invoiceOutputStr, "output", "o", types.OFValueMarkdown, fmt.Sprintf("output format [possible values: %q]", []string{types.OFValueMarkdown, types.OFValueHTML, types.OFValueJSON}))
+	invoiceCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	invoiceCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
+
+	backupDBCmd.Flags().IntVar(&backupKeep, "keep", 0, "number of timestamped backups to keep in the backup directory (0 keeps all of them)")
+	backupDBCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	restoreDBCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	migrateDBCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "run pending migrations against a temporary copy of the database, without changing it")
+	migrateDBCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	listTrashCmd.Flags().BoolVarP(&recordsOutputPlain, "plain", "p", false, "whether to output the trash without any formatting")
+	listTrashCmd.Flags().StringVarP(&outputFormatStr, "output", "o", types.OFValueTable, fmt.Sprintf("output format [possible values: %q]", types.ValidOutputFormatValues))
+	listTrashCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	listTrashCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to use (run "hours themes list" for allowed values)`)
+	restoreFromTrashCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+	purgeTrashCmd.Flags().IntVar(&purgeOlderThan, "older-than", 0, "only purge items deleted more than this many days ago (0 purges everything)")
+	purgeTrashCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "repair the problems that can be fixed automatically")
+	doctorCmd.Flags().StringVarP(&dbPath, "dbpath", "d", defaultDBPath, "location of hours' database file")
+
 	showThemeConfigCmd.Flags().StringVarP(&themeName, "theme", "t", defaultThemeName, `UI theme to show (run "hours themes list" for allowed values)`)
 
+	exportCmd.AddCommand(exportCSVCmd)
+	exportCmd.AddCommand(exportTSVCmd)
+	exportCmd.AddCommand(exportICSCmd)
+
+	importCmd.AddCommand(importCSVCmd)
+	importCmd.AddCommand(importTimewCmd)
+	importCmd.AddCommand(importTogglCmd)
+	importCmd.AddCommand(importICSCmd)
+
+	dbCmd.AddCommand(backupDBCmd)
+	dbCmd.AddCommand(restoreDBCmd)
+	dbCmd.AddCommand(migrateDBCmd)
+
 	themesCmd.AddCommand(addThemeCmd)
 	themesCmd.AddCommand(listThemesCmd)
 	themesCmd.AddCommand(sampleThemeCmd)
 	themesCmd.AddCommand(showThemeConfigCmd)
 
+	logCmd.AddCommand(addTLCmd)
+	logCmd.AddCommand(editTLCmd)
+	logCmd.AddCommand(deleteTLCmd)
+	logCmd.AddCommand(splitTLCmd)
+	logCmd.AddCommand(mergeTLsCmd)
+	logCmd.AddCommand(moveTLCmd)
+	logCmd.AddCommand(overlapsTLCmd)
+
+	taskCmd.AddCommand(addTaskCmd)
+	taskCmd.AddCommand(listTasksCmd)
+	taskCmd.AddCommand(renameTaskCmd)
+	taskCmd.AddCommand(deactivateTaskCmd)
+	taskCmd.AddCommand(activateTaskCmd)
+	taskCmd.AddCommand(tagTaskCmd)
+	taskCmd.AddCommand(untagTaskCmd)
+	taskCmd.AddCommand(setTaskProjectCmd)
+	taskCmd.AddCommand(setTaskRateCmd)
+	taskCmd.AddCommand(setTaskBudgetCmd)
+	taskCmd.AddCommand(billableTaskCmd)
+	taskCmd.AddCommand(nonBillableTaskCmd)
+	taskCmd.AddCommand(deleteTaskCmd)
+
+	projectCmd.AddCommand(addProjectCmd)
+	projectCmd.AddCommand(listProjectsCmd)
+	projectCmd.AddCommand(setProjectRateCmd)
+
+	goalsCmd.AddCommand(setGoalsCmd)
+
+	trashCmd.AddCommand(listTrashCmd)
+	trashCmd.AddCommand(restoreFromTrashCmd)
+	trashCmd.AddCommand(purgeTrashCmd)
+
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(reportCmd)
 	rootCmd.AddCommand(logCmd)
 	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(invoiceCmd)
+	rootCmd.AddCommand(goalsCmd)
 	rootCmd.AddCommand(activeCmd)
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(switchCmd)
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(taskCmd)
+	rootCmd.AddCommand(projectCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
 	rootCmd.AddCommand(themesCmd)
+	rootCmd.AddCommand(trashCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(doctorCmd)
 
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+var (
+	errBeginTSInvalid       = errors.New("begin timestamp is invalid")
+	errEndTSInvalid         = errors.New("end timestamp is invalid")
+	errNothingToUpdate      = errors.New("nothing to update; provide at least one of --begin, --end, or --comment")
+	errCouldntAddTL         = errors.New("couldn't add task log entry")
+	errCouldntFetchTL       = errors.New("couldn't fetch task log entry")
+	errCouldntEditTL        = errors.New("couldn't edit task log entry")
+	errCouldntDeleteTL      = errors.New("couldn't delete task log entry")
+	errTaskLogIDIsInvalid   = errors.New("task log ID is invalid")
+	errCouldntFetchOverlaps = errors.New("couldn't fetch overlapping task log entries")
+	errSplitTSInvalid       = errors.New("split timestamp is invalid")
+	errCouldntSplitTL       = errors.New("couldn't split task log entry")
+	errCouldntMergeTLs      = errors.New("couldn't merge task log entries")
+	errCouldntMoveTL        = errors.New("couldn't move task log entry")
+)
+
+type tlUpdate struct {
+	begin   *string
+	end     *string
+	comment *string
+	// whether comment was provided at all; an empty comment clears it
+	commentProvided bool
+}
+
+func parseTLTimes(beginStr, endStr string, now time.Time) (time.Time, time.Time, error) {
+	var zero time.Time
+	beginTS, err := types.ParseTimestamp(beginStr, now)
+	if err != nil {
+		return zero, zero, fmt.Errorf("%w: %w", errBeginTSInvalid, err)
+	}
+
+	endTS, err := types.ParseTimestamp(endStr, now)
+	if err != nil {
+		return zero, zero, fmt.Errorf("%w: %w", errEndTSInvalid, err)
+	}
+
+	err = validateTLDuration(beginTS, endTS)
+	if err != nil {
+		return zero, zero, err
+	}
+
+	return beginTS, endTS, nil
+}
+
+func validateTLDuration(beginTS, endTS time.Time) error {
+	err := types.IsTaskLogDurationValid(beginTS, endTS)
+	if err != nil {
+		return fmt.Errorf("%w (%s ... %s): %w", errTaskLogDurationIsInvalid, beginTS.Format(timeFormat), endTS.Format(timeFormat), err)
+	}
+
+	return nil
+}
+
+func parseTLID(value string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: %q", errTaskLogIDIsInvalid, value)
+	}
+
+	return id, nil
+}
+
+func getComment(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func addTL(db *sql.DB, writer io.Writer, taskQuery, beginStr, endStr string, comment *string, rejectOverlaps bool, now time.Time) error {
+	beginTS, endTS, err := parseTLTimes(beginStr, endStr, now)
+	if err != nil {
+		return err
+	}
+
+	task, err := resolveTask(db, taskQuery, true)
+	if err != nil {
+		return err
+	}
+
+	id, err := pers.InsertManualTL(db, task.ID, beginTS, endTS, comment, rejectOverlaps)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntAddTL, err)
+	}
+
+	fmt.Fprintf(writer, "Added task log entry #%d for %q (task #%d): %s (%s ... %s)\n",
+		id,
+		task.Summary,
+		task.ID,
+		types.HumanizeDuration(int(endTS.Sub(beginTS).Seconds())),
+		beginTS.Format(timeFormat),
+		endTS.Format(timeFormat),
+	)
+
+	warnAboutOverlaps(db, writer, id, beginTS, endTS)
+
+	return nil
+}
+
+func editTL(db *sql.DB, writer io.Writer, id int, update tlUpdate, rejectOverlaps bool, now time.Time) error {
+	if update.begin == nil && update.end == nil && !update.commentProvided {
+		return errNothingToUpdate
+	}
+
+	tl, err := pers.FetchSavedTLByID(db, id)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntFetchTL, err)
+	}
+
+	beginTS := tl.BeginTS
+	if update.begin != nil {
+		beginTS, err = types.ParseTimestamp(*update.begin, now)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errBeginTSInvalid, err)
+		}
+	}
+
+	endTS := tl.EndTS
+	if update.end != nil {
+		endTS, err = types.ParseTimestamp(*update.end, now)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errEndTSInvalid, err)
+		}
+	}
+
+	err = validateTLDuration(beginTS, endTS)
+	if err != nil {
+		return err
+	}
+
+	comment := tl.Comment
+	if update.commentProvided {
+		comment = update.comment
+	}
+
+	_, err = pers.EditSavedTL(db, tl.ID, beginTS, endTS, comment, rejectOverlaps)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntEditTL, err)
+	}
+
+	fmt.Fprintf(writer, "Updated task log entry #%d for %q (task #%d): %s (%s ... %s)\n",
+		tl.ID,
+		tl.TaskSummary,
+		tl.TaskID,
+		types.HumanizeDuration(int(endTS.Sub(beginTS).Seconds())),
+		beginTS.Format(timeFormat),
+		endTS.Format(timeFormat),
+	)
+
+	warnAboutOverlaps(db, writer, tl.ID, beginTS, endTS)
+
+	return nil
+}
+
+// warnAboutOverlaps lets the user know about the task log entries (saved ones,
+// and the active one) that an entry overlaps (overlaps are only rejected if
+// asked for). The entry has been saved by the time this runs, so failing to
+// check for overlaps is only reported.
+func warnAboutOverlaps(db *sql.DB, writer io.Writer, tlID int, beginTS, endTS time.Time) {
+	overlapping, err := pers.FetchOverlappingTLs(db, beginTS, endTS, tlID, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", errCouldntFetchOverlaps.Error(), err.Error())
+		return
+	}
+
+	for _, entry := range overlapping {
+		if entry.EndTS.IsZero() {
+			fmt.Fprintf(writer, "Warning: overlaps active task log entry #%d for %q (being tracked since %s)\n",
+				entry.ID,
+				entry.TaskSummary,
+				entry.BeginTS.Format(timeFormat),
+			)
+			continue
+		}
+
+		fmt.Fprintf(writer, "Warning: overlaps task log entry #%d for %q (%s ... %s)\n",
+			entry.ID,
+			entry.TaskSummary,
+			entry.BeginTS.Format(timeFormat),
+			entry.EndTS.Format(timeFormat),
+		)
+	}
+}
+
+func deleteTL(db *sql.DB, writer io.Writer, id int, now time.Time) error {
+	tl, err := pers.FetchSavedTLByID(db, id)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntFetchTL, err)
+	}
+
+	err = pers.DeleteTL(db, &tl, now)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntDeleteTL, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Moved task log entry %s to the trash\n", describeTL(tl))
+
+	return nil
+}
+
+func describeTL(tl domain.TaskLogEntry) string {
+	return fmt.Sprintf("#%d for %q (task #%d): %s (%s ... %s)",
+		tl.ID,
+		tl.TaskSummary,
+		tl.TaskID,
+		types.HumanizeDuration(tl.SecsSpent),
+		tl.BeginTS.Format(timeFormat),
+		tl.EndTS.Format(timeFormat),
+	)
+}
+
+// splitTL splits a task log entry in two at splitStr; the second entry is for
+// the task matching taskQuery, if provided.
+func splitTL(db *sql.DB, writer io.Writer, id int, splitStr, taskQuery string, now time.Time) error {
+	tl, err := pers.FetchSavedTLByID(db, id)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntFetchTL, err)
+	}
+
+	splitTS, err := types.ParseTimestamp(splitStr, now)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSplitTSInvalid, err)
+	}
+
+	err = validateTLDuration(tl.BeginTS, splitTS)
+	if err != nil {
+		return err
+	}
+
+	err = validateTLDuration(splitTS, tl.EndTS)
+	if err != nil {
+		return err
+	}
+
+	taskID := tl.TaskID
+	if taskQuery != "" {
+		task, err := resolveTask(db, taskQuery, true)
+		if err != nil {
+			return err
+		}
+		taskID = task.ID
+	}
+
+	newID, err := pers.SplitTL(db, tl.ID, splitTS, taskID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntSplitTL, err)
+	}
+
+	first, err := pers.FetchSavedTLByID(db, tl.ID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntFetchTL, err)
+	}
+
+	second, err := pers.FetchSavedTLByID(db, newID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntFetchTL, err)
+	}
+
+	fmt.Fprintf(writer, "Split task log entry #%d at %s:\n  %s\n  %s\n",
+		tl.ID,
+		splitTS.Format(timeFormat),
+		describeTL(first),
+		describeTL(second),
+	)
+
+	return nil
+}
+
+// mergeTLs merges two task log entries, and reports the gap between them (if
+// any), since it's recorded as a pause in the merged entry.
+func mergeTLs(db *sql.DB, writer io.Writer, id, otherID int) error {
+	tl, err := pers.FetchSavedTLByID(db, id)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntFetchTL, err)
+	}
+
+	otherTL, err := pers.FetchSavedTLByID(db, otherID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntFetchTL, err)
+	}
+
+	gap := otherTL.BeginTS.Sub(tl.EndTS)
+	if otherTL.BeginTS.Before(tl.BeginTS) {
+		gap = tl.BeginTS.Sub(otherTL.EndTS)
+	}
+
+	mergedID, err := pers.MergeTLs(db, id, otherID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntMergeTLs, err)
+	}
+
+	merged, err := pers.FetchSavedTLByID(db, mergedID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntFetchTL, err)
+	}
+
+	fmt.Fprintf(writer, "Merged task log entries #%d and #%d into %s\n", id, otherID, describeTL(merged))
+	if gap > 0 {
+		fmt.Fprintf(writer, "The gap of %s between them is recorded as a pause\n", types.HumanizeDuration(int(gap.Seconds())))
+	}
+
+	return nil
+}
+
+func moveTL(db *sql.DB, writer io.Writer, id int, taskQuery string) error {
+	tl, err := pers.FetchSavedTLByID(db, id)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntFetchTL, err)
+	}
+
+	task, err := resolveTask(db, taskQuery, true)
+	if err != nil {
+		return err
+	}
+
+	err = pers.MoveTL(db, tl.ID, task.ID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntMoveTL, err)
+	}
+
+	fmt.Fprintf(writer, "Moved task log entry #%d (%s ... %s) from %q (task #%d) to %q (task #%d)\n",
+		tl.ID,
+		tl.BeginTS.Format(timeFormat),
+		tl.EndTS.Format(timeFormat),
+		tl.TaskSummary,
+		tl.TaskID,
+		task.Summary,
+		task.ID,
+	)
+
+	return nil
+}
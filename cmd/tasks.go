@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+const (
+	taskLookupLimit        = 10000
+	taskSummaryLengthLimit = 100
+)
+
+var (
+	errTaskQueryEmpty              = errors.New("task ID or summary prefix is empty")
+	errNoTaskMatched               = errors.New("no task matched")
+	errMultipleTasksMatch          = errors.New("more than one task matched")
+	errCouldntFetchTasks           = errors.New("couldn't fetch tasks")
+	errTaskSummaryEmpty            = errors.New("task summary cannot be empty")
+	errTaskSummaryTooLong          = errors.New("task summary is too long")
+	errCouldntAddTask              = errors.New("couldn't add task")
+	errCouldntUpdateTask           = errors.New("couldn't update task")
+	errCannotDeactivateTrackedTask = errors.New("cannot deactivate a task being tracked; stop tracking and try again")
+	errCouldntUpdateTaskTags       = errors.New("couldn't update tags of task")
+)
+
+func findTask(tasks []domain.Task, query string) (domain.Task, error) {
+	var zero domain.Task
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return zero, errTaskQueryEmpty
+	}
+
+	if id, err := strconv.Atoi(query); err == nil {
+		for _, task := range tasks {
+			if task.ID == id {
+				return task, nil
+			}
+		}
+	}
+
+	queryLower := strings.ToLower(query)
+	var exactMatches, prefixMatches []domain.Task
+	for _, task := range tasks {
+		summaryLower := strings.ToLower(task.Summary)
+		switch {
+		case summaryLower == queryLower:
+			exactMatches = append(exactMatches, task)
+		case strings.HasPrefix(summaryLower, queryLower):
+			prefixMatches = append(prefixMatches, task)
+		}
+	}
+
+	matches := exactMatches
+	if len(matches) == 0 {
+		matches = prefixMatches
+	}
+
+	switch len(matches) {
+	case 0:
+		return zero, fmt.Errorf("%w %q", errNoTaskMatched, query)
+	case 1:
+		return matches[0], nil
+	default:
+		candidates := make([]string, len(matches))
+		for i, task := range matches {
+			candidates[i] = fmt.Sprintf("#%d %q", task.ID, task.Summary)
+		}
+		return zero, fmt.Errorf("%w %q (%s); use the task ID instead", errMultipleTasksMatch, query, strings.Join(candidates, ", "))
+	}
+}
+
+func resolveTask(db *sql.DB, query string, active bool) (domain.Task, error) {
+	tasks, err := pers.FetchTasks(db, active, taskLookupLimit)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%w: %s", errCouldntFetchTasks, err.Error())
+	}
+
+	return findTask(tasks, query)
+}
+
+func validateTaskSummary(summary string) (string, error) {
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", errTaskSummaryEmpty
+	}
+
+	if len(summary) > taskSummaryLengthLimit {
+		return "", fmt.Errorf("%w (limit: %d characters)", errTaskSummaryTooLong, taskSummaryLengthLimit)
+	}
+
+	return summary, nil
+}
+
+func describeTags(tags []string) string {
+	if len(tags) == 0 {
+		return "none"
+	}
+
+	return strings.Join(tags, ", ")
+}
+
+func addTask(db *sql.DB, writer io.Writer, summary, projectName string, tagValues []string, budgetValue string) error {
+	summary, err := validateTaskSummary(summary)
+	if err != nil {
+		return err
+	}
+
+	var budgetSecs *int
+	if budgetValue != "" {
+		budgetSecs, err = getBudget(budgetValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	tags, err := types.ParseTags(tagValues)
+	if err != nil {
+		return err
+	}
+
+	project := domain.Project{ID: pers.UnassignedProjectID}
+	if projectName != "" {
+		project, err = resolveProject(db, projectName)
+		if err != nil {
+			return err
+		}
+	}
+
+	id, err := pers.InsertTaskWithDetails(db, summary, project.ID, tags)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntAddTask, err.Error())
+	}
+
+	if budgetSecs != nil {
+		err = pers.SetTaskBudget(db, id, budgetSecs)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntSetBudget, err.Error())
+		}
+	}
+
+	var details []string
+	if project.ID != pers.UnassignedProjectID {
+		details = append(details, "project: "+project.Name)
+	}
+	if len(tags) > 0 {
+		details = append(details, "tags: "+describeTags(tags))
+	}
+	if budgetSecs != nil {
+		details = append(details, "budget: "+describeBudget(budgetSecs))
+	}
+
+	if len(details) > 0 {
+		fmt.Fprintf(writer, "Added task #%d: %q (%s)\n", id, summary, strings.Join(details, "; "))
+	} else {
+		fmt.Fprintf(writer, "Added task #%d: %q\n", id, summary)
+	}
+
+	return nil
+}
+
+// updateTaskTags adds tags to (or removes them from) a task; unlike the other
+// task commands, it works on both active and inactive tasks, since tags
+// matter for reports on past work too.
+func updateTaskTags(db *sql.DB, writer io.Writer, taskQuery string, tagValues []string, remove bool) error {
+	changes, err := types.ParseTags(tagValues)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := pers.FetchTasksWithStatus(db, types.TaskStatusAny, taskLookupLimit)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchTasks, err.Error())
+	}
+
+	task, err := findTask(tasks, taskQuery)
+	if err != nil {
+		return err
+	}
+
+	var tags []string
+	if remove {
+		for _, tag := range task.Tags {
+			if !slices.Contains(changes, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	} else {
+		tags, err = types.ParseTags(append(slices.Clone(task.Tags), changes...))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = pers.SetTaskTags(db, task.ID, tags)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntUpdateTaskTags, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Updated tags of task #%d (%q): %s\n", task.ID, task.Summary, describeTags(tags))
+
+	return nil
+}
+
+func renameTask(db *sql.DB, writer io.Writer, taskQuery, summary string) error {
+	summary, err := validateTaskSummary(summary)
+	if err != nil {
+		return err
+	}
+
+	task, err := resolveTask(db, taskQuery, true)
+	if err != nil {
+		return err
+	}
+
+	err = pers.UpdateTask(db, task.ID, summary)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntUpdateTask, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Renamed task #%d: %q -> %q\n", task.ID, task.Summary, summary)
+
+	return nil
+}
+
+func updateTaskActiveStatus(db *sql.DB, writer io.Writer, taskQuery string, active bool) error {
+	// tasks can only be activated if they're inactive, and vice versa
+	task, err := resolveTask(db, taskQuery, !active)
+	if err != nil {
+		return err
+	}
+
+	if !active {
+		activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntFetchActiveTask, err.Error())
+		}
+
+		if activeTaskDetails.TaskID == task.ID {
+			return errCannotDeactivateTrackedTask
+		}
+	}
+
+	err = pers.UpdateTaskActiveStatus(db, task.ID, active)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntUpdateTask, err.Error())
+	}
+
+	if active {
+		fmt.Fprintf(writer, "Activated task #%d: %q\n", task.ID, task.Summary)
+	} else {
+		fmt.Fprintf(writer, "Deactivated task #%d: %q\n", task.ID, task.Summary)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTask(t *testing.T) {
+	tasks := []domain.Task{
+		{ID: 1, Summary: "write blog post"},
+		{ID: 2, Summary: "write tests"},
+		{ID: 3, Summary: "review"},
+		{ID: 4, Summary: "review PRs"},
+		{ID: 42, Summary: "7 habits"},
+	}
+
+	testCases := []struct {
+		name       string
+		query      string
+		expectedID int
+		err        error
+	}{
+		{name: "matches by ID", query: "2", expectedID: 2},
+		{name: "matches by unique prefix", query: "write b", expectedID: 1},
+		{name: "prefix matching is case-insensitive", query: "WRITE T", expectedID: 2},
+		{name: "exact match takes precedence over prefix matches", query: "review", expectedID: 3},
+		{name: "numeric query falls back to summary", query: "7", expectedID: 42},
+		{name: "query is trimmed", query: "  review p ", expectedID: 4},
+		{name: "ambiguous prefix", query: "write", err: errMultipleTasksMatch},
+		{name: "no match", query: "deploy", err: errNoTaskMatched},
+		{name: "empty query", query: "  ", err: errTaskQueryEmpty},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findTask(tasks, tt.query)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedID, got.ID)
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+var (
+	errNoTaskActive             = errors.New("no task is being tracked")
+	errTaskAlreadyBeingTracked  = errors.New("a task is already being tracked")
+	errTaskIsAlreadyActive      = errors.New("task is already being tracked")
+	errBeginTSInTheFuture       = errors.New("begin timestamp cannot be in the future")
+	errEndTSInTheFuture         = errors.New("end timestamp cannot be in the future")
+	errSwitchTSBeforeBeginTS    = errors.New("switch timestamp is before the active task log's begin timestamp")
+	errCouldntFetchActiveTask   = errors.New("couldn't fetch active task details")
+	errCouldntStartTracking     = errors.New("couldn't start tracking")
+	errCouldntStopTracking      = errors.New("couldn't stop tracking")
+	errCouldntSwitchTracking    = errors.New("couldn't switch tracking")
+	errTaskLogDurationIsInvalid = errors.New("task log duration is invalid")
+	errTSInTheFuture            = errors.New("timestamp cannot be in the future")
+	errTLAlreadyPaused          = errors.New("the active task log is already paused")
+	errTLNotPaused              = errors.New("the active task log isn't paused")
+	errPauseTSTooEarly          = errors.New("pause timestamp is before the active task log's begin timestamp, or the end of its last pause")
+	errResumeTSBeforePauseTS    = errors.New("resume timestamp is before the time the active task log was paused at")
+	errCouldntPauseTracking     = errors.New("couldn't pause tracking")
+	errCouldntResumeTracking    = errors.New("couldn't resume tracking")
+	errTimeSpentTooShort        = errors.New("time spent needs to be at least a minute, leaving out the time tracking was paused for")
+)
+
+func getTrackingTS(at string, now time.Time) (time.Time, error) {
+	if at == "" {
+		return now.Truncate(time.Second), nil
+	}
+
+	return types.ParseTimestamp(at, now)
+}
+
+func startTracking(db *sql.DB, writer io.Writer, taskQuery string, beginTS, now time.Time, comment *string) error {
+	if beginTS.After(now) {
+		return errBeginTSInTheFuture
+	}
+
+	activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchActiveTask, err.Error())
+	}
+
+	if activeTaskDetails.TaskID != -1 {
+		return fmt.Errorf("%w (%q, since %s); stop it first, or use \"hours switch\"",
+			errTaskAlreadyBeingTracked,
+			activeTaskDetails.TaskSummary,
+			activeTaskDetails.CurrentLogBeginTS.Format(timeFormat),
+		)
+	}
+
+	task, err := resolveTask(db, taskQuery, true)
+	if err != nil {
+		return err
+	}
+
+	_, err = pers.InsertNewTL(db, task.ID, beginTS, comment)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntStartTracking, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Started tracking %q (task #%d) at %s\n", task.Summary, task.ID, beginTS.Format(timeFormat))
+
+	return nil
+}
+
+func stopTracking(db *sql.DB, writer io.Writer, endTS, now time.Time, comment *string, commentProvided bool) error {
+	if endTS.After(now) {
+		return errEndTSInTheFuture
+	}
+
+	activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchActiveTask, err.Error())
+	}
+
+	if activeTaskDetails.TaskID == -1 {
+		return errNoTaskActive
+	}
+
+	beginTS := activeTaskDetails.CurrentLogBeginTS
+	err = validateTLDuration(beginTS, endTS)
+	if err != nil {
+		return err
+	}
+
+	// the time tracking was paused for isn't recorded, so what's left needs to
+	// be long enough as well
+	pausedSecs := domain.PausedSeconds(activeTaskDetails.CurrentLogPauses, beginTS, endTS)
+	if endTS.Sub(beginTS)-time.Duration(pausedSecs)*time.Second < time.Minute {
+		return fmt.Errorf("%w (%s ... %s, paused for %s): %w",
+			errTaskLogDurationIsInvalid,
+			beginTS.Format(timeFormat),
+			endTS.Format(timeFormat),
+			types.HumanizeDuration(pausedSecs),
+			errTimeSpentTooShort,
+		)
+	}
+
+	if !commentProvided {
+		comment = activeTaskDetails.CurrentLogComment
+	}
+
+	secsSpent, err := pers.FinishActiveTL(db,
+		activeTaskDetails.CurrentLogID,
+		activeTaskDetails.TaskID,
+		beginTS,
+		endTS,
+		comment,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntStopTracking, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Stopped tracking %q (task #%d); recorded %s (%s ... %s)\n",
+		activeTaskDetails.TaskSummary,
+		activeTaskDetails.TaskID,
+		types.HumanizeDuration(secsSpent),
+		beginTS.Format(timeFormat),
+		endTS.Format(timeFormat),
+	)
+
+	return nil
+}
+
+func switchTracking(db *sql.DB, writer io.Writer, taskQuery string, ts, now time.Time, comment *string) error {
+	if ts.After(now) {
+		return errBeginTSInTheFuture
+	}
+
+	activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchActiveTask, err.Error())
+	}
+
+	if activeTaskDetails.TaskID == -1 {
+		return errNoTaskActive
+	}
+
+	if ts.Before(activeTaskDetails.CurrentLogBeginTS) {
+		return fmt.Errorf("%w (%s)", errSwitchTSBeforeBeginTS, activeTaskDetails.CurrentLogBeginTS.Format(timeFormat))
+	}
+
+	task, err := resolveTask(db, taskQuery, true)
+	if err != nil {
+		return err
+	}
+
+	if task.ID == activeTaskDetails.TaskID {
+		return fmt.Errorf("%w: %q", errTaskIsAlreadyActive, task.Summary)
+	}
+
+	_, err = pers.QuickSwitchActiveTL(db, task.ID, ts, comment)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntSwitchTracking, err)
+	}
+
+	fmt.Fprintf(writer, "Switched tracking from %q (task #%d) to %q (task #%d) at %s\n",
+		activeTaskDetails.TaskSummary,
+		activeTaskDetails.TaskID,
+		task.Summary,
+		task.ID,
+		ts.Format(timeFormat),
+	)
+
+	return nil
+}
+
+func pauseTracking(db *sql.DB, writer io.Writer, ts, now time.Time) error {
+	if ts.After(now) {
+		return errTSInTheFuture
+	}
+
+	activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchActiveTask, err.Error())
+	}
+
+	if activeTaskDetails.TaskID == -1 {
+		return errNoTaskActive
+	}
+
+	if pausedAt := activeTaskDetails.PausedAt(); pausedAt != nil {
+		return fmt.Errorf("%w (since %s)", errTLAlreadyPaused, pausedAt.Format(timeFormat))
+	}
+
+	earliestTS := activeTaskDetails.CurrentLogBeginTS
+	for _, pause := range activeTaskDetails.CurrentLogPauses {
+		if pause.EndTS != nil && pause.EndTS.After(earliestTS) {
+			earliestTS = *pause.EndTS
+		}
+	}
+
+	if ts.Before(earliestTS) {
+		return fmt.Errorf("%w (%s)", errPauseTSTooEarly, earliestTS.Format(timeFormat))
+	}
+
+	err = pers.PauseActiveTL(db, ts)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntPauseTracking, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Paused tracking %q (task #%d) at %s\n",
+		activeTaskDetails.TaskSummary,
+		activeTaskDetails.TaskID,
+		ts.Format(timeFormat),
+	)
+
+	return nil
+}
+
+func resumeTracking(db *sql.DB, writer io.Writer, ts, now time.Time) error {
+	if ts.After(now) {
+		return errTSInTheFuture
+	}
+
+	activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchActiveTask, err.Error())
+	}
+
+	if activeTaskDetails.TaskID == -1 {
+		return errNoTaskActive
+	}
+
+	pausedAt := activeTaskDetails.PausedAt()
+	if pausedAt == nil {
+		return errTLNotPaused
+	}
+
+	if ts.Before(*pausedAt) {
+		return fmt.Errorf("%w (%s)", errResumeTSBeforePauseTS, pausedAt.Format(timeFormat))
+	}
+
+	err = pers.ResumeActiveTL(db, ts)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntResumeTracking, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Resumed tracking %q (task #%d) at %s; paused for %s\n",
+		activeTaskDetails.TaskSummary,
+		activeTaskDetails.TaskID,
+		ts.Format(timeFormat),
+		types.HumanizeDuration(int(ts.Sub(*pausedAt).Seconds())),
+	)
+
+	return nil
+}
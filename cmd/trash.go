@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+var (
+	errCouldntTrashTask         = errors.New("couldn't move task to the trash")
+	errCannotTrashTrackedTask   = errors.New("cannot delete a task being tracked; stop tracking and try again")
+	errTrashedItemKindIncorrect = errors.New("kind of item needs to be one of: log, task")
+	errTrashedItemIDIsInvalid   = errors.New("ID of item in the trash is invalid")
+	errCouldntRestoreFromTrash  = errors.New("couldn't restore from the trash")
+	errCouldntPurgeTrash        = errors.New("couldn't purge the trash")
+	errPurgeOlderThanNegative   = errors.New("number of days cannot be negative")
+)
+
+func trashTask(db *sql.DB, writer io.Writer, taskQuery string, now time.Time) error {
+	task, err := resolveAnyTask(db, taskQuery)
+	if err != nil {
+		return err
+	}
+
+	err = pers.TrashTask(db, task.ID, now)
+	if errors.Is(err, pers.ErrCannotTrashTrackedTask) {
+		return errCannotTrashTrackedTask
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntTrashTask, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Moved task #%d to the trash: %q\n", task.ID, task.Summary)
+
+	return nil
+}
+
+func parseTrashedItemKind(value string) (pers.TrashedItemKind, error) {
+	switch kind := pers.TrashedItemKind(strings.ToLower(strings.TrimSpace(value))); kind {
+	case pers.TrashedTL, pers.TrashedTask:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("%w; got %q", errTrashedItemKindIncorrect, value)
+	}
+}
+
+func restoreFromTrash(db *sql.DB, writer io.Writer, kindValue, idValue string) error {
+	kind, err := parseTrashedItemKind(kindValue)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.Atoi(strings.TrimSpace(idValue))
+	if err != nil || id <= 0 {
+		return fmt.Errorf("%w: %q", errTrashedItemIDIsInvalid, idValue)
+	}
+
+	item, err := pers.RestoreFromTrash(db, kind, id)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntRestoreFromTrash, err.Error())
+	}
+
+	switch kind {
+	case pers.TrashedTask:
+		fmt.Fprintf(writer, "Restored task #%d: %q\n", item.ID, item.TaskSummary)
+	default:
+		fmt.Fprintf(writer, "Restored task log entry #%d for %q (task #%d): %s (%s ... %s)\n",
+			item.ID,
+			item.TaskSummary,
+			item.TaskID,
+			types.HumanizeDuration(item.SecsSpent),
+			item.BeginTS.Format(timeFormat),
+			item.EndTS.Format(timeFormat),
+		)
+	}
+
+	return nil
+}
+
+func purgeTrash(db *sql.DB, writer io.Writer, olderThanDays int, now time.Time) error {
+	if olderThanDays < 0 {
+		return fmt.Errorf("%w (--older-than)", errPurgeOlderThanNegative)
+	}
+
+	var deletedBefore *time.Time
+	if olderThanDays > 0 {
+		cutoff := now.AddDate(0, 0, -olderThanDays)
+		deletedBefore = &cutoff
+	}
+
+	result, err := pers.PurgeTrash(db, deletedBefore)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntPurgeTrash, err.Error())
+	}
+
+	fmt.Fprintf(writer, "Purged %d task(s) and %d task log entries from the trash\n", result.NumTasks, result.NumTLs)
+
+	return nil
+}
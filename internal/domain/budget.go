@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+)
+
+// BudgetReportEntry shows how much of a task's budget has been used up.
+type BudgetReportEntry struct {
+	TaskID      int    `json:"task_id"`
+	TaskSummary string `json:"task_summary"`
+	BudgetSecs  int    `json:"budget_secs"`
+	SecsSpent   int    `json:"secs_spent"`
+	// SecsSpentInPeriod is the part of SecsSpent that was spent in the period
+	// being reported on
+	SecsSpentInPeriod int `json:"secs_spent_in_period"`
+	// SecsRemaining is negative for tasks that are over budget
+	SecsRemaining int `json:"secs_remaining"`
+	PercentUsed   int `json:"percent_used"`
+}
+
+func (e BudgetReportEntry) OverBudget() bool {
+	return e.SecsRemaining < 0
+}
+
+// GetBudgetReport returns entries for the tasks that have a budget, ordered by
+// how much of it has been used up.
+func GetBudgetReport(tasks []Task, secsSpentInPeriod map[int]int) []BudgetReportEntry {
+	entries := []BudgetReportEntry{}
+	for _, task := range tasks {
+		if task.BudgetSecs == nil || *task.BudgetSecs <= 0 {
+			continue
+		}
+
+		budget := *task.BudgetSecs
+		entries = append(entries, BudgetReportEntry{
+			TaskID:            task.ID,
+			TaskSummary:       task.Summary,
+			BudgetSecs:        budget,
+			SecsSpent:         task.SecsSpent,
+			SecsSpentInPeriod: secsSpentInPeriod[task.ID],
+			SecsRemaining:     budget - task.SecsSpent,
+			PercentUsed:       task.SecsSpent * 100 / budget,
+		})
+	}
+
+	slices.SortStableFunc(entries, func(a, b BudgetReportEntry) int {
+		return cmp.Or(
+			cmp.Compare(b.PercentUsed, a.PercentUsed),
+			cmp.Compare(a.TaskSummary, b.TaskSummary),
+		)
+	})
+
+	return entries
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBudgetReport(t *testing.T) {
+	// GIVEN
+	budget := func(secs int) *int { return &secs }
+	tasks := []Task{
+		{ID: 1, Summary: "write docs", SecsSpent: 1800, BudgetSecs: budget(3600)},
+		{ID: 2, Summary: "fix login bug", SecsSpent: 9000, BudgetSecs: budget(7200)},
+		{ID: 3, Summary: "team lunch", SecsSpent: 3600},
+		{ID: 4, Summary: "add tests", SecsSpent: 1800, BudgetSecs: budget(3600)},
+	}
+	secsSpentInPeriod := map[int]int{2: 3600, 3: 3600}
+
+	// WHEN
+	got := GetBudgetReport(tasks, secsSpentInPeriod)
+
+	// THEN
+	expected := []BudgetReportEntry{
+		{TaskID: 2, TaskSummary: "fix login bug", BudgetSecs: 7200, SecsSpent: 9000, SecsSpentInPeriod: 3600, SecsRemaining: -1800, PercentUsed: 125},
+		{TaskID: 4, TaskSummary: "add tests", BudgetSecs: 3600, SecsSpent: 1800, SecsRemaining: 1800, PercentUsed: 50},
+		{TaskID: 1, TaskSummary: "write docs", BudgetSecs: 3600, SecsSpent: 1800, SecsRemaining: 1800, PercentUsed: 50},
+	}
+	assert.Equal(t, expected, got)
+	assert.True(t, got[0].OverBudget())
+	assert.False(t, got[1].OverBudget())
+}
+
+func TestGetBudgetReportWithoutBudgets(t *testing.T) {
+	// GIVEN
+	tasks := []Task{{ID: 1, Summary: "team lunch", SecsSpent: 3600}}
+
+	// WHEN
+	got := GetBudgetReport(tasks, nil)
+
+	// THEN
+	assert.Empty(t, got)
+	assert.NotNil(t, got)
+}
@@ -7,6 +7,7 @@ import (
 func SecondsTrackedToday(
 	finishedTaskLogs []TaskLogEntry,
 	activeTaskLogBeginTS *time.Time,
+	activeTaskLogPauses []TaskLogPause,
 	now time.Time,
 ) int {
 	startOfDay := time.Date(
@@ -17,28 +18,86 @@ func SecondsTrackedToday(
 		now.Location(),
 	)
 
+	return SecondsTrackedSince(finishedTaskLogs, activeTaskLogBeginTS, activeTaskLogPauses, startOfDay, now)
+}
+
+// SecondsTrackedSince returns the time tracked between since and now,
+// including the time tracked so far on the active task log, if any. The time
+// task logs were paused for is left out.
+func SecondsTrackedSince(
+	finishedTaskLogs []TaskLogEntry,
+	activeTaskLogBeginTS *time.Time,
+	activeTaskLogPauses []TaskLogPause,
+	since time.Time,
+	now time.Time,
+) int {
 	var trackedSeconds int
 	for _, entry := range finishedTaskLogs {
-		trackedSeconds += overlappingSeconds(
-			entry.BeginTS,
-			entry.EndTS,
-			startOfDay,
-			now,
-		)
+		trackedSeconds += trackedSecondsWithin(entry, since, now)
 	}
 
 	if activeTaskLogBeginTS != nil && !activeTaskLogBeginTS.IsZero() {
 		trackedSeconds += overlappingSeconds(
 			*activeTaskLogBeginTS,
 			now,
-			startOfDay,
+			since,
 			now,
 		)
+		trackedSeconds -= PausedSeconds(activeTaskLogPauses, latest(*activeTaskLogBeginTS, since), now)
 	}
 
 	return trackedSeconds
 }
 
+// PausedSeconds returns how long a task log was paused for between begin and
+// end; a pause that hasn't ended yet lasts till end.
+func PausedSeconds(pauses []TaskLogPause, begin, end time.Time) int {
+	var secs int
+	for _, pause := range pauses {
+		pauseEnd := end
+		if pause.EndTS != nil {
+			pauseEnd = *pause.EndTS
+		}
+
+		secs += overlappingSeconds(pause.BeginTS, pauseEnd, begin, end)
+	}
+
+	return secs
+}
+
+// PausedAt returns when a task log was paused, if it's paused right now.
+func PausedAt(pauses []TaskLogPause) *time.Time {
+	for _, pause := range pauses {
+		if pause.EndTS == nil {
+			return &pause.BeginTS
+		}
+	}
+
+	return nil
+}
+
+// trackedSecondsWithin returns the part of a finished task log's time spent
+// that falls within a range; the time it was paused for (if any) is taken to
+// be spread evenly across it.
+func trackedSecondsWithin(entry TaskLogEntry, rangeStart, rangeEnd time.Time) int {
+	secs := overlappingSeconds(entry.BeginTS, entry.EndTS, rangeStart, rangeEnd)
+
+	duration := int(entry.EndTS.Sub(entry.BeginTS).Seconds())
+	if entry.SecsSpent <= 0 || entry.SecsSpent >= duration {
+		return secs
+	}
+
+	return secs * entry.SecsSpent / duration
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
 func overlappingSeconds(begin, end, rangeStart, rangeEnd time.Time) int {
 	if !end.After(begin) || !rangeEnd.After(rangeStart) {
 		return 0
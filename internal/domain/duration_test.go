@@ -112,14 +112,32 @@ func TestSecondsTrackedToday(t *testing.T) {
 			EndTS:   timestamp(t, "2026-01-16T00:45:00+01:00"),
 		},
 	}
+	pausedTaskLogs := []TaskLogEntry{
+		{
+			BeginTS:   timestamp(t, "2026-01-16T09:00:00+01:00"),
+			EndTS:     timestamp(t, "2026-01-16T11:00:00+01:00"),
+			SecsSpent: 90 * 60,
+		},
+	}
 	zeroTimestamp := time.Time{}
 	activeTaskLogBeginTSWithinDay := timestamp(t, "2026-01-16T13:00:00+01:00")
 	activeTaskLogBeginTSBeforeMidnight := timestamp(t, "2026-01-15T23:15:00+01:00")
+	pauseEndTS := timestamp(t, "2026-01-16T13:20:00+01:00")
+	activeTaskLogPauses := []TaskLogPause{
+		{
+			BeginTS: timestamp(t, "2026-01-16T13:10:00+01:00"),
+			EndTS:   &pauseEndTS,
+		},
+		{
+			BeginTS: timestamp(t, "2026-01-16T13:45:00+01:00"),
+		},
+	}
 
 	testCases := []struct {
 		name                 string
 		finishedTaskLogs     []TaskLogEntry
 		activeTaskLogBeginTS *time.Time
+		activeTaskLogPauses  []TaskLogPause
 		expectedSeconds      int
 	}{
 		{
@@ -152,6 +170,17 @@ func TestSecondsTrackedToday(t *testing.T) {
 			activeTaskLogBeginTS: &activeTaskLogBeginTSBeforeMidnight,
 			expectedSeconds:      14 * 60 * 60,
 		},
+		{
+			name:             "task log that was paused",
+			finishedTaskLogs: pausedTaskLogs,
+			expectedSeconds:  90 * 60,
+		},
+		{
+			name:                 "active task log that is paused",
+			activeTaskLogBeginTS: &activeTaskLogBeginTSWithinDay,
+			activeTaskLogPauses:  activeTaskLogPauses,
+			expectedSeconds:      (60 - 10 - 15) * 60,
+		},
 	}
 
 	for _, tt := range testCases {
@@ -161,6 +190,7 @@ func TestSecondsTrackedToday(t *testing.T) {
 			got := SecondsTrackedToday(
 				tt.finishedTaskLogs,
 				tt.activeTaskLogBeginTS,
+				tt.activeTaskLogPauses,
 				now,
 			)
 
@@ -178,3 +208,32 @@ func timestamp(t *testing.T, value string) time.Time {
 
 	return timestamp
 }
+
+func TestPausedSeconds(t *testing.T) {
+	begin := timestamp(t, "2026-01-16T09:00:00+01:00")
+	end := timestamp(t, "2026-01-16T12:00:00+01:00")
+	firstPauseEndTS := timestamp(t, "2026-01-16T08:45:00+01:00")
+	secondPauseEndTS := timestamp(t, "2026-01-16T10:30:00+01:00")
+	pauses := []TaskLogPause{
+		{
+			BeginTS: timestamp(t, "2026-01-16T08:30:00+01:00"),
+			EndTS:   &firstPauseEndTS,
+		},
+		{
+			BeginTS: timestamp(t, "2026-01-16T10:00:00+01:00"),
+			EndTS:   &secondPauseEndTS,
+		},
+		{
+			BeginTS: timestamp(t, "2026-01-16T11:40:00+01:00"),
+		},
+	}
+
+	// GIVEN
+	// WHEN
+	got := PausedSeconds(pauses, begin, end)
+
+	// THEN
+	assert.Equal(t, (30+20)*60, got)
+	assert.Equal(t, timestamp(t, "2026-01-16T11:40:00+01:00"), *PausedAt(pauses))
+	assert.Nil(t, PausedAt(pauses[:2]))
+}
@@ -0,0 +1,94 @@
+package domain
+
+import "time"
+
+const (
+	GoalKindDaily  = "daily"
+	GoalKindWeekly = "weekly"
+)
+
+// Goals are how much time is meant to be tracked every day, and every week;
+// either of them can be unset.
+type Goals struct {
+	DailySecs  *int `json:"daily_secs,omitempty"`
+	WeeklySecs *int `json:"weekly_secs,omitempty"`
+}
+
+type GoalDay struct {
+	Date        string `json:"date"`
+	SecsTracked int    `json:"secs_tracked"`
+	GoalMet     bool   `json:"goal_met"`
+}
+
+// GoalReport shows which days in a period met the daily goal.
+type GoalReport struct {
+	DailyGoalSecs int       `json:"daily_goal_secs"`
+	Days          []GoalDay `json:"days"`
+	DaysMet       int       `json:"days_met"`
+	// LongestStreak is the largest number of consecutive days in the period
+	// that met the goal
+	LongestStreak int `json:"longest_streak"`
+	// CurrentStreak is the number of consecutive days, up to today, that met
+	// the goal; today only breaks the streak once it's over
+	CurrentStreak int `json:"current_streak"`
+}
+
+// SecondsTrackedPerDay splits the time tracked in task log entries over the
+// days starting at start; entries that span midnight count towards both days.
+func SecondsTrackedPerDay(entries []TaskLogEntry, start time.Time, numDays int) []int {
+	secsPerDay := make([]int, numDays)
+	for i := range numDays {
+		dayStart := start.AddDate(0, 0, i)
+		dayEnd := start.AddDate(0, 0, i+1)
+		for _, entry := range entries {
+			secsPerDay[i] += trackedSecondsWithin(entry, dayStart, dayEnd)
+		}
+	}
+
+	return secsPerDay
+}
+
+// GetGoalReport returns a report for the days starting at start; the current
+// streak is left for the caller to fill in, since it can go back further than
+// the period.
+func GetGoalReport(dailyGoalSecs int, start time.Time, secsPerDay []int) GoalReport {
+	report := GoalReport{
+		DailyGoalSecs: dailyGoalSecs,
+		Days:          make([]GoalDay, len(secsPerDay)),
+	}
+
+	var streak int
+	for i, secs := range secsPerDay {
+		met := secs >= dailyGoalSecs
+		report.Days[i] = GoalDay{
+			Date:        start.AddDate(0, 0, i).Format(time.DateOnly),
+			SecsTracked: secs,
+			GoalMet:     met,
+		}
+
+		if !met {
+			streak = 0
+			continue
+		}
+
+		report.DaysMet++
+		streak++
+		report.LongestStreak = max(report.LongestStreak, streak)
+	}
+
+	return report
+}
+
+// CountStreak counts the consecutive days, going back from the last one, that
+// met the daily goal; unbroken is set if all of them did, in which case the
+// streak might go back further.
+func CountStreak(secsPerDay []int, dailyGoalSecs int) (streak int, unbroken bool) {
+	for i := len(secsPerDay) - 1; i >= 0; i-- {
+		if secsPerDay[i] < dailyGoalSecs {
+			return streak, false
+		}
+		streak++
+	}
+
+	return streak, true
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecondsTrackedPerDay(t *testing.T) {
+	// GIVEN
+	start := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	entries := []TaskLogEntry{
+		{BeginTS: start.Add(9 * time.Hour), EndTS: start.Add(11 * time.Hour)},
+		{BeginTS: start.Add(23 * time.Hour), EndTS: start.Add(25 * time.Hour)},
+		{BeginTS: start.Add(-2 * time.Hour), EndTS: start.Add(-1 * time.Hour)},
+	}
+
+	// WHEN
+	got := SecondsTrackedPerDay(entries, start, 3)
+
+	// THEN
+	assert.Equal(t, []int{3 * 3600, 3600, 0}, got)
+}
+
+func TestGetGoalReport(t *testing.T) {
+	// GIVEN
+	start := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	secsPerDay := []int{7200, 3600, 7200, 9000, 7200, 0}
+
+	// WHEN
+	got := GetGoalReport(7200, start, secsPerDay)
+
+	// THEN
+	assert.Equal(t, 7200, got.DailyGoalSecs)
+	assert.Equal(t, 4, got.DaysMet)
+	assert.Equal(t, 3, got.LongestStreak)
+	assert.Equal(t, 0, got.CurrentStreak)
+	assert.Len(t, got.Days, 6)
+	assert.Equal(t, GoalDay{Date: "2025-10-20", SecsTracked: 7200, GoalMet: true}, got.Days[0])
+	assert.Equal(t, GoalDay{Date: "2025-10-21", SecsTracked: 3600, GoalMet: false}, got.Days[1])
+}
+
+func TestCountStreak(t *testing.T) {
+	testCases := []struct {
+		name             string
+		secsPerDay       []int
+		expectedStreak   int
+		expectedUnbroken bool
+	}{
+		{name: "no days", secsPerDay: nil, expectedStreak: 0, expectedUnbroken: true},
+		{name: "last day missed", secsPerDay: []int{3600, 3600, 0}, expectedStreak: 0},
+		{name: "streak broken earlier", secsPerDay: []int{3600, 0, 3600, 5400}, expectedStreak: 2},
+		{name: "all days met", secsPerDay: []int{3600, 3600}, expectedStreak: 2, expectedUnbroken: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			streak, unbroken := CountStreak(tt.secsPerDay, 3600)
+
+			assert.Equal(t, tt.expectedStreak, streak)
+			assert.Equal(t, tt.expectedUnbroken, unbroken)
+		})
+	}
+}
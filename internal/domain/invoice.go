@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dhth/hours/internal/types"
+)
+
+var ErrNoRateForTask = errors.New("no hourly rate set for billable tasks")
+
+type InvoiceLineItem struct {
+	TaskID      int    `json:"task_id"`
+	TaskSummary string `json:"task_summary"`
+	Project     string `json:"project"`
+	NumEntries  int    `json:"num_entries"`
+	SecsSpent   int    `json:"secs_spent"`
+	SecsBilled  int    `json:"secs_billed"`
+	Rate        Rate   `json:"rate"`
+	// Amount is in hundredths of the rate's currency
+	Amount int `json:"amount_hundredths"`
+}
+
+type InvoiceTotal struct {
+	Currency   string `json:"currency"`
+	SecsBilled int    `json:"secs_billed"`
+	Amount     int    `json:"amount_hundredths"`
+}
+
+type Invoice struct {
+	Client    string            `json:"client"`
+	LineItems []InvoiceLineItem `json:"line_items"`
+	// Totals has an entry for every currency used by the line items
+	Totals          []InvoiceTotal `json:"totals"`
+	NonBillableSecs int            `json:"non_billable_secs"`
+}
+
+// RoundSecs rounds seconds to a multiple of a duration; durations shorter than
+// a second leave the seconds as they are.
+func RoundSecs(secs int, to time.Duration, mode types.RoundingMode) int {
+	unit := int(to / time.Second)
+	if unit <= 0 {
+		return secs
+	}
+
+	switch mode {
+	case types.RoundingUp:
+		return (secs + unit - 1) / unit * unit
+	case types.RoundingDown:
+		return secs / unit * unit
+	default:
+		return (secs + unit/2) / unit * unit
+	}
+}
+
+// BuildInvoice turns the time spent on a client's tasks into line items, one
+// per billable task. Time is rounded per line item, and billed at the task's
+// rate if it has one, and at its project's otherwise.
+func BuildInvoice(
+	client string,
+	entries []TaskReportEntry,
+	tasks map[int]Task,
+	projects map[int]Project,
+	roundTo time.Duration,
+	roundingMode types.RoundingMode,
+) (Invoice, error) {
+	invoice := Invoice{
+		Client:    client,
+		LineItems: []InvoiceLineItem{},
+		Totals:    []InvoiceTotal{},
+	}
+
+	var tasksWithoutRate []string
+	totals := make(map[string]*InvoiceTotal)
+	for _, entry := range entries {
+		task, ok := tasks[entry.TaskID]
+		if !ok || task.Client != client {
+			continue
+		}
+
+		if !task.Billable {
+			invoice.NonBillableSecs += entry.SecsSpent
+			continue
+		}
+
+		rate := task.Rate
+		if rate == nil {
+			rate = projects[task.ProjectID].Rate
+		}
+		if rate == nil {
+			tasksWithoutRate = append(tasksWithoutRate, fmt.Sprintf("#%d %q", task.ID, task.Summary))
+			continue
+		}
+
+		secsBilled := RoundSecs(entry.SecsSpent, roundTo, roundingMode)
+		item := InvoiceLineItem{
+			TaskID:      task.ID,
+			TaskSummary: task.Summary,
+			Project:     task.Project,
+			NumEntries:  entry.NumEntries,
+			SecsSpent:   entry.SecsSpent,
+			SecsBilled:  secsBilled,
+			Rate:        *rate,
+			Amount:      (secsBilled*rate.Hundredths + 1800) / 3600,
+		}
+		invoice.LineItems = append(invoice.LineItems, item)
+
+		total, ok := totals[rate.Currency]
+		if !ok {
+			total = &InvoiceTotal{Currency: rate.Currency}
+			totals[rate.Currency] = total
+		}
+		total.SecsBilled += item.SecsBilled
+		total.Amount += item.Amount
+	}
+
+	if len(tasksWithoutRate) > 0 {
+		return invoice, fmt.Errorf("%w: %s", ErrNoRateForTask, strings.Join(tasksWithoutRate, ", "))
+	}
+
+	slices.SortStableFunc(invoice.LineItems, func(a, b InvoiceLineItem) int {
+		return cmp.Compare(a.Project, b.Project)
+	})
+
+	for _, total := range totals {
+		invoice.Totals = append(invoice.Totals, *total)
+	}
+	slices.SortFunc(invoice.Totals, func(a, b InvoiceTotal) int {
+		return cmp.Compare(a.Currency, b.Currency)
+	})
+
+	return invoice, nil
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundSecs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		secs     int
+		roundTo  time.Duration
+		mode     types.RoundingMode
+		expected int
+	}{
+		{name: "no rounding", secs: 1000, roundTo: 0, mode: types.RoundingUp, expected: 1000},
+		{name: "up", secs: 1000, roundTo: 15 * time.Minute, mode: types.RoundingUp, expected: 1800},
+		{name: "up for an exact multiple", secs: 1800, roundTo: 15 * time.Minute, mode: types.RoundingUp, expected: 1800},
+		{name: "down", secs: 1700, roundTo: 15 * time.Minute, mode: types.RoundingDown, expected: 900},
+		{name: "nearest rounds down", secs: 1340, roundTo: 15 * time.Minute, mode: types.RoundingNearest, expected: 900},
+		{name: "nearest rounds halves up", secs: 1350, roundTo: 15 * time.Minute, mode: types.RoundingNearest, expected: 1800},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RoundSecs(tt.secs, tt.roundTo, tt.mode)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func getInvoiceTestData() ([]TaskReportEntry, map[int]Task, map[int]Project) {
+	entries := []TaskReportEntry{
+		{TaskID: 1, TaskSummary: "fix login bug", NumEntries: 3, SecsSpent: 5000},
+		{TaskID: 2, TaskSummary: "design review", NumEntries: 1, SecsSpent: 3600},
+		{TaskID: 3, TaskSummary: "team lunch", NumEntries: 1, SecsSpent: 2700},
+		{TaskID: 4, TaskSummary: "write blog post", NumEntries: 2, SecsSpent: 7200},
+	}
+	tasks := map[int]Task{
+		1: {ID: 1, Summary: "fix login bug", ProjectID: 2, Project: "website", Client: "acme", Billable: true},
+		2: {ID: 2, Summary: "design review", ProjectID: 3, Project: "app", Client: "acme", Billable: true,
+			Rate: &Rate{Hundredths: 15000, Currency: "USD"}},
+		3: {ID: 3, Summary: "team lunch", ProjectID: 2, Project: "website", Client: "acme", Billable: false},
+		4: {ID: 4, Summary: "write blog post", ProjectID: 1, Project: "unassigned", Client: "unassigned", Billable: true},
+	}
+	projects := map[int]Project{
+		1: {ID: 1, Name: "unassigned", Client: "unassigned"},
+		2: {ID: 2, Name: "website", Client: "acme", Rate: &Rate{Hundredths: 12050, Currency: "EUR"}},
+		3: {ID: 3, Name: "app", Client: "acme"},
+	}
+
+	return entries, tasks, projects
+}
+
+func TestBuildInvoice(t *testing.T) {
+	// GIVEN
+	entries, tasks, projects := getInvoiceTestData()
+
+	// WHEN
+	got, err := BuildInvoice("acme", entries, tasks, projects, 15*time.Minute, types.RoundingUp)
+
+	// THEN
+	require.NoError(t, err)
+	expected := Invoice{
+		Client: "acme",
+		LineItems: []InvoiceLineItem{
+			{
+				TaskID:      2,
+				TaskSummary: "design review",
+				Project:     "app",
+				NumEntries:  1,
+				SecsSpent:   3600,
+				SecsBilled:  3600,
+				Rate:        Rate{Hundredths: 15000, Currency: "USD"},
+				Amount:      15000,
+			},
+			{
+				TaskID:      1,
+				TaskSummary: "fix login bug",
+				Project:     "website",
+				NumEntries:  3,
+				SecsSpent:   5000,
+				SecsBilled:  5400,
+				Rate:        Rate{Hundredths: 12050, Currency: "EUR"},
+				Amount:      18075,
+			},
+		},
+		Totals: []InvoiceTotal{
+			{Currency: "EUR", SecsBilled: 5400, Amount: 18075},
+			{Currency: "USD", SecsBilled: 3600, Amount: 15000},
+		},
+		NonBillableSecs: 2700,
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestBuildInvoiceFailsForTasksWithoutRate(t *testing.T) {
+	// GIVEN
+	entries, tasks, projects := getInvoiceTestData()
+	tasks[2] = Task{ID: 2, Summary: "design review", ProjectID: 3, Project: "app", Client: "acme", Billable: true}
+
+	// WHEN
+	_, err := BuildInvoice("acme", entries, tasks, projects, 0, types.RoundingNearest)
+
+	// THEN
+	assert.ErrorIs(t, err, ErrNoRateForTask)
+	assert.ErrorContains(t, err, `#2 "design review"`)
+}
+
+func TestBuildInvoiceWithNoEntries(t *testing.T) {
+	// GIVEN
+	_, tasks, projects := getInvoiceTestData()
+
+	// WHEN
+	got, err := BuildInvoice("acme", nil, tasks, projects, 0, types.RoundingNearest)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Empty(t, got.LineItems)
+	assert.Empty(t, got.Totals)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// TaskLogOverlap is a pair of task log entries that overlap, along with the
+// period they overlap for.
+type TaskLogOverlap struct {
+	Entry      TaskLogEntry `json:"entry"`
+	OtherEntry TaskLogEntry `json:"other_entry"`
+	BeginTS    time.Time    `json:"begin_ts"`
+	EndTS      time.Time    `json:"end_ts"`
+	Secs       int          `json:"secs"`
+}
+
+// FindOverlaps returns the pairs of task log entries that overlap; entries
+// need to be sorted by their begin timestamps.
+func FindOverlaps(entries []TaskLogEntry) []TaskLogOverlap {
+	var overlaps []TaskLogOverlap
+	for i, entry := range entries {
+		for _, other := range entries[i+1:] {
+			if !other.BeginTS.Before(entry.EndTS) {
+				break
+			}
+
+			endTS := entry.EndTS
+			if other.EndTS.Before(endTS) {
+				endTS = other.EndTS
+			}
+
+			overlaps = append(overlaps, TaskLogOverlap{
+				Entry:      entry,
+				OtherEntry: other,
+				BeginTS:    other.BeginTS,
+				EndTS:      endTS,
+				Secs:       int(endTS.Sub(other.BeginTS).Seconds()),
+			})
+		}
+	}
+
+	return overlaps
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	// GIVEN
+	entries := []TaskLogEntry{
+		{
+			ID:      1,
+			BeginTS: timestamp(t, "2026-01-16T09:00:00+01:00"),
+			EndTS:   timestamp(t, "2026-01-16T11:00:00+01:00"),
+		},
+		{
+			ID:      2,
+			BeginTS: timestamp(t, "2026-01-16T09:30:00+01:00"),
+			EndTS:   timestamp(t, "2026-01-16T10:00:00+01:00"),
+		},
+		{
+			ID:      3,
+			BeginTS: timestamp(t, "2026-01-16T10:45:00+01:00"),
+			EndTS:   timestamp(t, "2026-01-16T12:00:00+01:00"),
+		},
+		{
+			ID:      4,
+			BeginTS: timestamp(t, "2026-01-16T12:00:00+01:00"),
+			EndTS:   timestamp(t, "2026-01-16T13:00:00+01:00"),
+		},
+	}
+
+	// WHEN
+	got := FindOverlaps(entries)
+
+	// THEN
+	require.Len(t, got, 2)
+
+	assert.Equal(t, 1, got[0].Entry.ID)
+	assert.Equal(t, 2, got[0].OtherEntry.ID)
+	assert.Equal(t, 30*60, got[0].Secs)
+
+	assert.Equal(t, 1, got[1].Entry.ID)
+	assert.Equal(t, 3, got[1].OtherEntry.ID)
+	assert.Equal(t, timestamp(t, "2026-01-16T10:45:00+01:00"), got[1].BeginTS)
+	assert.Equal(t, timestamp(t, "2026-01-16T11:00:00+01:00"), got[1].EndTS)
+	assert.Equal(t, 15*60, got[1].Secs)
+}
@@ -1,12 +1,52 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type Task struct {
-	ID        int
-	Summary   string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	SecsSpent int
-	Active    bool
+	ID         int       `json:"id"`
+	Summary    string    `json:"summary"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	SecsSpent  int       `json:"secs_spent"`
+	Active     bool      `json:"active"`
+	ProjectID  int       `json:"project_id"`
+	Project    string    `json:"project"`
+	Client     string    `json:"client"`
+	Rate       *Rate     `json:"rate"`
+	Billable   bool      `json:"billable"`
+	BudgetSecs *int      `json:"budget_secs"`
+	Tags       []string  `json:"tags"`
+}
+
+type Project struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ClientID int    `json:"client_id"`
+	Client   string `json:"client"`
+	Rate     *Rate  `json:"rate"`
+}
+
+// Rate is an hourly rate, in hundredths of a currency's unit (eg. cents).
+type Rate struct {
+	Hundredths int    `json:"hundredths"`
+	Currency   string `json:"currency"`
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%s %s", FormatHundredths(r.Hundredths), r.Currency)
+}
+
+// FormatHundredths formats an amount in hundredths of a currency's unit as a
+// decimal number, eg. 12050 as "120.50".
+func FormatHundredths(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
 }
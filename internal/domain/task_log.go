@@ -1,27 +1,106 @@
 package domain
 
-import "time"
+import (
+	"cmp"
+	"slices"
+	"time"
+)
 
 type TaskLogEntry struct {
-	ID          int
-	TaskID      int
-	TaskSummary string
-	BeginTS     time.Time
-	EndTS       time.Time
-	SecsSpent   int
-	Comment     *string
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id"`
+	TaskSummary string    `json:"task_summary"`
+	BeginTS     time.Time `json:"begin_ts"`
+	EndTS       time.Time `json:"end_ts"`
+	SecsSpent   int       `json:"secs_spent"`
+	Comment     *string   `json:"comment"`
 }
 
 type ActiveTaskDetails struct {
+	CurrentLogID      int
 	TaskID            int
 	TaskSummary       string
 	CurrentLogBeginTS time.Time
 	CurrentLogComment *string
+	CurrentLogPauses  []TaskLogPause
+}
+
+// PausedAt returns when the active task log was paused, if it's paused right
+// now.
+func (d ActiveTaskDetails) PausedAt() *time.Time {
+	return PausedAt(d.CurrentLogPauses)
+}
+
+// SecsSpent returns the time tracked on the active task log till now,
+// excluding the time it was paused for.
+func (d ActiveTaskDetails) SecsSpent(now time.Time) int {
+	return int(now.Sub(d.CurrentLogBeginTS).Seconds()) - PausedSeconds(d.CurrentLogPauses, d.CurrentLogBeginTS, now)
+}
+
+// TaskLogPause is a period during which tracking on a task log was paused.
+type TaskLogPause struct {
+	ID      int
+	BeginTS time.Time
+	// EndTS is nil while the task log is still paused
+	EndTS *time.Time
 }
 
 type TaskReportEntry struct {
-	TaskID      int
-	TaskSummary string
-	NumEntries  int
-	SecsSpent   int
+	TaskID      int    `json:"task_id"`
+	TaskSummary string `json:"task_summary"`
+	NumEntries  int    `json:"num_entries"`
+	SecsSpent   int    `json:"secs_spent"`
+}
+
+// GroupReportEntry holds the time spent on a group of tasks (eg. the tasks of
+// a project, or the ones with a tag).
+type GroupReportEntry struct {
+	Group      string `json:"group"`
+	NumEntries int    `json:"num_entries"`
+	SecsSpent  int    `json:"secs_spent"`
+}
+
+// RollUpReportEntries adds up report entries by the groups their tasks belong
+// to. A task can belong to several groups (in which case it counts towards
+// each of them); tasks without any group are rolled up into a group with an
+// empty name. Groups are sorted by time spent, in descending order.
+func RollUpReportEntries(entries []TaskReportEntry, groupsByTask map[int][]string) []GroupReportEntry {
+	var groupEntries []GroupReportEntry
+	indexes := make(map[string]int)
+
+	for _, entry := range entries {
+		groups := groupsByTask[entry.TaskID]
+		if len(groups) == 0 {
+			groups = []string{""}
+		}
+
+		for _, group := range groups {
+			index, ok := indexes[group]
+			if !ok {
+				index = len(groupEntries)
+				indexes[group] = index
+				groupEntries = append(groupEntries, GroupReportEntry{Group: group})
+			}
+			groupEntries[index].NumEntries += entry.NumEntries
+			groupEntries[index].SecsSpent += entry.SecsSpent
+		}
+	}
+
+	slices.SortStableFunc(groupEntries, func(a, b GroupReportEntry) int {
+		if a.SecsSpent != b.SecsSpent {
+			return cmp.Compare(b.SecsSpent, a.SecsSpent)
+		}
+
+		// tasks without a group go after the others
+		if (a.Group == "") != (b.Group == "") {
+			if a.Group == "" {
+				return 1
+			}
+			return -1
+		}
+
+		return cmp.Compare(a.Group, b.Group)
+	})
+
+	return groupEntries
 }
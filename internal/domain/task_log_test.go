@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollUpReportEntries(t *testing.T) {
+	// GIVEN
+	entries := []TaskReportEntry{
+		{TaskID: 1, TaskSummary: "write blog post", NumEntries: 2, SecsSpent: 3600},
+		{TaskID: 2, TaskSummary: "write docs", NumEntries: 1, SecsSpent: 1800},
+		{TaskID: 3, TaskSummary: "review PRs", NumEntries: 3, SecsSpent: 5400},
+		{TaskID: 4, TaskSummary: "untagged task", NumEntries: 1, SecsSpent: 1800},
+	}
+	groupsByTask := map[int][]string{
+		1: {"personal", "writing"},
+		2: {"writing"},
+		3: {"work"},
+	}
+
+	// WHEN
+	got := RollUpReportEntries(entries, groupsByTask)
+
+	// THEN
+	expected := []GroupReportEntry{
+		{Group: "work", NumEntries: 3, SecsSpent: 5400},
+		{Group: "writing", NumEntries: 3, SecsSpent: 5400},
+		{Group: "personal", NumEntries: 2, SecsSpent: 3600},
+		{Group: "", NumEntries: 1, SecsSpent: 1800},
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestRollUpReportEntriesWithNoEntries(t *testing.T) {
+	// GIVEN
+	// WHEN
+	got := RollUpReportEntries(nil, map[int][]string{1: {"work"}})
+
+	// THEN
+	assert.Empty(t, got)
+}
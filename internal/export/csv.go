@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dhth/hours/internal/domain"
+)
+
+const timestampFormat = "2006-01-02 15:04:05"
+
+var errCouldntWriteRecord = errors.New("couldn't write record")
+
+var taskLogHeader = []string{
+	"id",
+	"task_id",
+	"task_summary",
+	"begin",
+	"end",
+	"secs_spent",
+	"comment",
+}
+
+// WriteCSV writes task log entries as comma-separated values, preceded by a
+// header row.
+func WriteCSV(writer io.Writer, entries []domain.TaskLogEntry) error {
+	return writeDelimited(writer, entries, ',')
+}
+
+// WriteTSV writes task log entries as tab-separated values, preceded by a
+// header row.
+func WriteTSV(writer io.Writer, entries []domain.TaskLogEntry) error {
+	return writeDelimited(writer, entries, '\t')
+}
+
+func writeDelimited(writer io.Writer, entries []domain.TaskLogEntry, delimiter rune) error {
+	w := csv.NewWriter(writer)
+	w.Comma = delimiter
+
+	if err := w.Write(taskLogHeader); err != nil {
+		return fmt.Errorf("%w: %s", errCouldntWriteRecord, err.Error())
+	}
+
+	for _, entry := range entries {
+		var comment string
+		if entry.Comment != nil {
+			comment = *entry.Comment
+		}
+
+		record := []string{
+			strconv.Itoa(entry.ID),
+			strconv.Itoa(entry.TaskID),
+			entry.TaskSummary,
+			entry.BeginTS.Format(timestampFormat),
+			entry.EndTS.Format(timestampFormat),
+			strconv.Itoa(entry.SecsSpent),
+			comment,
+		}
+
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("%w: %s", errCouldntWriteRecord, err.Error())
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("%w: %s", errCouldntWriteRecord, err.Error())
+	}
+
+	return nil
+}
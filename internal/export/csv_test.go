@@ -0,0 +1,98 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestEntries() []domain.TaskLogEntry {
+	referenceTS := time.Date(2025, time.October, 24, 9, 0, 0, 0, time.UTC)
+	comment := "first line, with a comma\n\"quoted\"\tand a tab"
+
+	return []domain.TaskLogEntry{
+		{
+			ID:          1,
+			TaskID:      2,
+			TaskSummary: "write blog post",
+			BeginTS:     referenceTS,
+			EndTS:       referenceTS.Add(90 * time.Minute),
+			SecsSpent:   90 * 60,
+			Comment:     &comment,
+		},
+		{
+			ID:          3,
+			TaskID:      4,
+			TaskSummary: "review PRs",
+			BeginTS:     referenceTS.Add(2 * time.Hour),
+			EndTS:       referenceTS.Add(2*time.Hour + 30*time.Second),
+			SecsSpent:   30,
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	// GIVEN
+	buf := bytes.Buffer{}
+
+	// WHEN
+	err := WriteCSV(&buf, getTestEntries())
+
+	// THEN
+	require.NoError(t, err)
+	expected := `id,task_id,task_summary,begin,end,secs_spent,comment
+1,2,write blog post,2025-10-24 09:00:00,2025-10-24 10:30:00,5400,"first line, with a comma
+""quoted""	and a tab"
+3,4,review PRs,2025-10-24 11:00:00,2025-10-24 11:00:30,30,
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteTSV(t *testing.T) {
+	// GIVEN
+	buf := bytes.Buffer{}
+
+	// WHEN
+	err := WriteTSV(&buf, getTestEntries())
+
+	// THEN
+	require.NoError(t, err)
+	expected := "id\ttask_id\ttask_summary\tbegin\tend\tsecs_spent\tcomment\n" +
+		"1\t2\twrite blog post\t2025-10-24 09:00:00\t2025-10-24 10:30:00\t5400\t\"first line, with a comma\n\"\"quoted\"\"\tand a tab\"\n" +
+		"3\t4\treview PRs\t2025-10-24 11:00:00\t2025-10-24 11:00:30\t30\t\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteCSVOutputCanBeReadBack(t *testing.T) {
+	// GIVEN
+	buf := bytes.Buffer{}
+	entries := getTestEntries()
+	err := WriteCSV(&buf, entries)
+	require.NoError(t, err)
+
+	// WHEN
+	records, err := csv.NewReader(&buf).ReadAll()
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, records, len(entries)+1)
+	assert.Equal(t, *entries[0].Comment, records[1][6])
+	assert.Empty(t, records[2][6])
+}
+
+func TestWriteCSVWithNoEntries(t *testing.T) {
+	// GIVEN
+	buf := bytes.Buffer{}
+
+	// WHEN
+	err := WriteCSV(&buf, nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "id,task_id,task_summary,begin,end,secs_spent,comment\n", buf.String())
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dhth/hours/internal/domain"
+)
+
+const (
+	icsTimestampFormat = "20060102T150405Z"
+	icsProdID          = "-//dhth//hours//EN"
+	// lines longer than this many octets need to be folded (RFC 5545, 3.1)
+	icsLineLengthLimit = 75
+)
+
+var icsTextEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// getICSUID returns the UID of the event for a task log entry. It only depends
+// on the entry's ID, so that calendar apps update events on subsequent imports
+// instead of duplicating them.
+func getICSUID(taskLogID int) string {
+	return fmt.Sprintf("hours-task-log-%d", taskLogID)
+}
+
+// WriteICS writes task log entries as an iCalendar (RFC 5545) calendar, with
+// one event per entry. Timestamps are written in UTC; now is used as the
+// DTSTAMP of each event.
+func WriteICS(writer io.Writer, entries []domain.TaskLogEntry, now time.Time) error {
+	w := bufio.NewWriter(writer)
+	dtStamp := now.UTC().Format(icsTimestampFormat)
+
+	writeICSLine(w, "BEGIN:VCALENDAR")
+	writeICSLine(w, "VERSION:2.0")
+	writeICSLine(w, "PRODID:"+icsProdID)
+	writeICSLine(w, "CALSCALE:GREGORIAN")
+
+	for _, entry := range entries {
+		writeICSLine(w, "BEGIN:VEVENT")
+		writeICSLine(w, "UID:"+getICSUID(entry.ID))
+		writeICSLine(w, "DTSTAMP:"+dtStamp)
+		writeICSLine(w, "DTSTART:"+entry.BeginTS.UTC().Format(icsTimestampFormat))
+		writeICSLine(w, "DTEND:"+entry.EndTS.UTC().Format(icsTimestampFormat))
+		writeICSLine(w, "SUMMARY:"+icsTextEscaper.Replace(entry.TaskSummary))
+		if entry.Comment != nil {
+			writeICSLine(w, "DESCRIPTION:"+icsTextEscaper.Replace(*entry.Comment))
+		}
+		writeICSLine(w, "END:VEVENT")
+	}
+
+	writeICSLine(w, "END:VCALENDAR")
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("%w: %s", errCouldntWriteRecord, err.Error())
+	}
+
+	return nil
+}
+
+// writeICSLine writes a content line terminated by CRLF, folding it into
+// multiple lines if needed; continuation lines begin with a space. Lines are
+// only folded at character boundaries.
+func writeICSLine(w *bufio.Writer, line string) {
+	limit := icsLineLengthLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation line counts towards its length
+		limit = icsLineLengthLimit - 1
+	}
+
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteICS(t *testing.T) {
+	// GIVEN
+	buf := bytes.Buffer{}
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	// WHEN
+	err := WriteICS(&buf, getTestEntries(), now)
+
+	// THEN
+	require.NoError(t, err)
+	expected := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//dhth//hours//EN\r\n" +
+		"CALSCALE:GREGORIAN\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:hours-task-log-1\r\n" +
+		"DTSTAMP:20251024T120000Z\r\n" +
+		"DTSTART:20251024T090000Z\r\n" +
+		"DTEND:20251024T103000Z\r\n" +
+		"SUMMARY:write blog post\r\n" +
+		"DESCRIPTION:first line\\, with a comma\\n\"quoted\"\tand a tab\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:hours-task-log-3\r\n" +
+		"DTSTAMP:20251024T120000Z\r\n" +
+		"DTSTART:20251024T110000Z\r\n" +
+		"DTEND:20251024T110030Z\r\n" +
+		"SUMMARY:review PRs\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteICSConvertsTimestampsToUTC(t *testing.T) {
+	// GIVEN
+	buf := bytes.Buffer{}
+	zone := time.FixedZone("UTC+5:30", 5*60*60+30*60)
+	entries := []domain.TaskLogEntry{
+		{
+			ID:          1,
+			TaskSummary: "task",
+			BeginTS:     time.Date(2025, time.October, 24, 2, 0, 0, 0, zone),
+			EndTS:       time.Date(2025, time.October, 24, 3, 0, 0, 0, zone),
+		},
+	}
+
+	// WHEN
+	err := WriteICS(&buf, entries, time.Now())
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "DTSTART:20251023T203000Z\r\n")
+	assert.Contains(t, buf.String(), "DTEND:20251023T213000Z\r\n")
+}
+
+func TestWriteICSFoldsLongLines(t *testing.T) {
+	// GIVEN
+	buf := bytes.Buffer{}
+	comment := strings.Repeat("é", 100)
+	entries := []domain.TaskLogEntry{
+		{
+			ID:          1,
+			TaskSummary: "task",
+			BeginTS:     time.Now(),
+			EndTS:       time.Now(),
+			Comment:     &comment,
+		},
+	}
+
+	// WHEN
+	err := WriteICS(&buf, entries, time.Now())
+
+	// THEN
+	require.NoError(t, err)
+	var unfolded strings.Builder
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), icsLineLengthLimit, "line %d is too long", i)
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+		} else {
+			unfolded.WriteString("\n" + line)
+		}
+	}
+	assert.Contains(t, unfolded.String(), "\nDESCRIPTION:"+comment+"\n")
+}
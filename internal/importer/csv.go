@@ -0,0 +1,182 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// DefaultTimeFormat matches the timestamps written by "hours export csv".
+const DefaultTimeFormat = "2006-01-02 15:04:05"
+
+var (
+	errCouldntReadCSV      = errors.New("couldn't read CSV")
+	errCSVIsEmpty          = errors.New("CSV has no header row")
+	errColumnNotFound      = errors.New("column not found in header")
+	errTaskColumnEmpty     = errors.New("task column name cannot be empty")
+	errTimeColumnEmpty     = errors.New("begin and end column names cannot be empty")
+	errBeginTSUnparseable  = errors.New("couldn't parse begin timestamp")
+	errEndTSUnparseable    = errors.New("couldn't parse end timestamp")
+	errRowHasTooFewColumns = errors.New("row has too few columns")
+)
+
+// ColumnMapping tells which columns of a CSV file hold the fields of an entry;
+// columns are referred to by their names in the header row.
+type ColumnMapping struct {
+	Task    string
+	Begin   string
+	End     string
+	Comment string // optional
+	// TimeFormat is a Go time layout; timestamps are parsed in the local
+	// timezone unless the layout includes one
+	TimeFormat string
+}
+
+// DefaultColumnMapping matches the columns written by "hours export csv".
+func DefaultColumnMapping() ColumnMapping {
+	return ColumnMapping{
+		Task:       "task_summary",
+		Begin:      "begin",
+		End:        "end",
+		Comment:    "comment",
+		TimeFormat: DefaultTimeFormat,
+	}
+}
+
+type columnIndices struct {
+	task    int
+	begin   int
+	end     int
+	comment int
+}
+
+// ParseCSV reads entries from delimited data with a header row. Rows that
+// can't be parsed are returned as RowErrors; rows are numbered the way a
+// spreadsheet would, with the header being row 1.
+func ParseCSV(reader io.Reader, mapping ColumnMapping, delimiter rune) ([]Entry, []RowError, error) {
+	r := csv.NewReader(reader)
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errCSVIsEmpty
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errCouldntReadCSV, err.Error())
+	}
+
+	indices, err := getColumnIndices(header, mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []Entry
+	var rowErrors []RowError
+	row := 1
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row++
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", errCouldntReadCSV, err.Error())
+		}
+
+		entry, err := parseRecord(record, indices, mapping.TimeFormat)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{row, err})
+			continue
+		}
+		entry.Row = row
+		entries = append(entries, entry)
+	}
+
+	return entries, rowErrors, nil
+}
+
+func getColumnIndices(header []string, mapping ColumnMapping) (columnIndices, error) {
+	indices := columnIndices{-1, -1, -1, -1}
+
+	if strings.TrimSpace(mapping.Task) == "" {
+		return indices, errTaskColumnEmpty
+	}
+	if strings.TrimSpace(mapping.Begin) == "" || strings.TrimSpace(mapping.End) == "" {
+		return indices, errTimeColumnEmpty
+	}
+
+	for _, column := range []struct {
+		name  string
+		index *int
+	}{
+		{mapping.Task, &indices.task},
+		{mapping.Begin, &indices.begin},
+		{mapping.End, &indices.end},
+	} {
+		*column.index = findColumn(header, column.name)
+		if *column.index == -1 {
+			return indices, fmt.Errorf("%w: %q", errColumnNotFound, column.name)
+		}
+	}
+
+	if strings.TrimSpace(mapping.Comment) != "" {
+		indices.comment = findColumn(header, mapping.Comment)
+		if indices.comment == -1 {
+			return indices, fmt.Errorf("%w: %q", errColumnNotFound, mapping.Comment)
+		}
+	}
+
+	return indices, nil
+}
+
+// findColumn returns the index of a column in a header row, ignoring case and
+// surrounding whitespace, or -1 if it's not present.
+func findColumn(header []string, name string) int {
+	for i, column := range header {
+		// spreadsheet programs tend to prepend a byte order mark to the file
+		column = strings.TrimPrefix(column, "\ufeff")
+		if strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name)) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func parseRecord(record []string, indices columnIndices, timeFormat string) (Entry, error) {
+	var entry Entry
+
+	maxIndex := max(indices.task, indices.begin, indices.end, indices.comment)
+	if len(record) <= maxIndex {
+		return entry, fmt.Errorf("%w (expected at least %d, got %d)", errRowHasTooFewColumns, maxIndex+1, len(record))
+	}
+
+	beginStr := strings.TrimSpace(record[indices.begin])
+	beginTS, err := time.ParseInLocation(timeFormat, beginStr, time.Local)
+	if err != nil {
+		return entry, fmt.Errorf("%w: %q", errBeginTSUnparseable, beginStr)
+	}
+
+	endStr := strings.TrimSpace(record[indices.end])
+	endTS, err := time.ParseInLocation(timeFormat, endStr, time.Local)
+	if err != nil {
+		return entry, fmt.Errorf("%w: %q", errEndTSUnparseable, endStr)
+	}
+
+	entry.TaskSummary = record[indices.task]
+	entry.BeginTS = beginTS
+	entry.EndTS = endTS
+
+	if indices.comment != -1 {
+		comment := strings.TrimSpace(record[indices.comment])
+		if comment != "" {
+			entry.Comment = &comment
+		}
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	// GIVEN
+	input := `task_summary,begin,end,comment
+write blog post,2025-10-24 09:00:00,2025-10-24 10:30:00,"first line
+second line"
+review PRs,2025-10-24 11:00:00,2025-10-24 11:30:00,
+review PRs,2025/10/24 12:00,2025-10-24 12:30:00,
+review PRs,2025-10-24 13:00:00,13:30,
+too few columns
+`
+
+	// WHEN
+	entries, rowErrors, err := ParseCSV(strings.NewReader(input), DefaultColumnMapping(), ',')
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, 2, entries[0].Row)
+	assert.Equal(t, "write blog post", entries[0].TaskSummary)
+	assert.Equal(t, time.Date(2025, time.October, 24, 9, 0, 0, 0, time.Local), entries[0].BeginTS)
+	assert.Equal(t, time.Date(2025, time.October, 24, 10, 30, 0, 0, time.Local), entries[0].EndTS)
+	require.NotNil(t, entries[0].Comment)
+	assert.Equal(t, "first line\nsecond line", *entries[0].Comment)
+
+	assert.Equal(t, 3, entries[1].Row)
+	assert.Nil(t, entries[1].Comment)
+
+	require.Len(t, rowErrors, 3)
+	assert.Equal(t, 4, rowErrors[0].Row)
+	assert.ErrorIs(t, rowErrors[0].Err, errBeginTSUnparseable)
+	assert.Equal(t, 5, rowErrors[1].Row)
+	assert.ErrorIs(t, rowErrors[1].Err, errEndTSUnparseable)
+	assert.Equal(t, 6, rowErrors[2].Row)
+	assert.ErrorIs(t, rowErrors[2].Err, errRowHasTooFewColumns)
+}
+
+func TestParseCSVWithCustomMapping(t *testing.T) {
+	// GIVEN
+	input := "Start\tStop\tProject\n2025-10-24T09:00:00Z\t2025-10-24T10:00:00Z\thours\n"
+	mapping := ColumnMapping{
+		Task:       "project",
+		Begin:      "start",
+		End:        "stop",
+		TimeFormat: time.RFC3339,
+	}
+
+	// WHEN
+	entries, rowErrors, err := ParseCSV(strings.NewReader(input), mapping, '\t')
+
+	// THEN
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "hours", entries[0].TaskSummary)
+	assert.True(t, time.Date(2025, time.October, 24, 9, 0, 0, 0, time.UTC).Equal(entries[0].BeginTS))
+	assert.True(t, time.Date(2025, time.October, 24, 10, 0, 0, 0, time.UTC).Equal(entries[0].EndTS))
+	assert.Nil(t, entries[0].Comment)
+}
+
+func TestParseCSVFailsForMissingColumn(t *testing.T) {
+	// GIVEN
+	input := "task_summary,begin,finish\n"
+
+	// WHEN
+	_, _, err := ParseCSV(strings.NewReader(input), DefaultColumnMapping(), ',')
+
+	// THEN
+	assert.ErrorIs(t, err, errColumnNotFound)
+}
+
+func TestParseCSVFailsForEmptyInput(t *testing.T) {
+	// GIVEN
+	// WHEN
+	_, _, err := ParseCSV(strings.NewReader(""), DefaultColumnMapping(), ',')
+
+	// THEN
+	assert.ErrorIs(t, err, errCSVIsEmpty)
+}
@@ -0,0 +1,716 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dhth/hours/internal/types"
+)
+
+const (
+	icsDateFormat        = "20060102"
+	icsDateTimeFormat    = "20060102T150405"
+	icsUTCDateTimeFormat = "20060102T150405Z"
+)
+
+var (
+	errCouldntReadICS        = errors.New("couldn't read iCalendar file")
+	errICSHasNoCalendar      = errors.New("file doesn't contain an iCalendar calendar")
+	errICSRuleInvalid        = errors.New(`rule needs to be of the format "REGEX=TASK"`)
+	errICSRuleRegexInvalid   = errors.New("rule's regex is invalid")
+	errICSEventHasNoStart    = errors.New("event has no start")
+	errICSTimeUnparseable    = errors.New("couldn't parse timestamp")
+	errICSTimezoneUnknown    = errors.New("timezone is unknown")
+	errICSDurationInvalid    = errors.New("couldn't parse duration")
+	errICSEventIsAllDay      = errors.New("event lasts all day")
+	errICSEventIsCancelled   = errors.New("event is cancelled")
+	errICSEventHasNoSummary  = errors.New("event has no summary")
+	errICSEventIsTooShort    = errors.New("event is shorter than a minute")
+	errICSEventIsNotOver     = errors.New("event hasn't ended yet")
+	errICSEventMatchesNoRule = errors.New("event's summary matches no rule")
+)
+
+// icsUnescaper reverses the escaping of TEXT values (RFC 5545, 3.3.11).
+var icsUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+// ICSRule maps calendar events whose summaries match Pattern to a task.
+type ICSRule struct {
+	Pattern *regexp.Regexp
+	// Task can refer to the pattern's submatches, eg. "$1" or "${name}"
+	Task string
+}
+
+// ParseICSRule parses a rule of the format "REGEX=TASK". The text is split on
+// its last "=", so the regex may contain "=" but the task can't.
+func ParseICSRule(value string) (ICSRule, error) {
+	var rule ICSRule
+
+	index := strings.LastIndex(value, "=")
+	if index == -1 {
+		return rule, fmt.Errorf("%w: %q", errICSRuleInvalid, value)
+	}
+
+	pattern, task := value[:index], strings.TrimSpace(value[index+1:])
+	if pattern == "" || task == "" {
+		return rule, fmt.Errorf("%w: %q", errICSRuleInvalid, value)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return rule, fmt.Errorf("%w: %s", errICSRuleRegexInvalid, err.Error())
+	}
+
+	rule.Pattern = re
+	rule.Task = task
+
+	return rule, nil
+}
+
+// ICSOptions determines which calendar events are imported, and as what.
+type ICSOptions struct {
+	// Rules are tried in order; the first one to match an event's summary
+	// decides its task
+	Rules []ICSRule
+	// CreateTasks makes events that match no rule be imported under tasks
+	// named after their summaries
+	CreateTasks bool
+	// DateRange limits the import to events that end within it
+	DateRange types.DateRange
+	// Now is used to leave out events that haven't ended yet
+	Now time.Time
+}
+
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+	// loc is the timezone referred to by the TZID parameter, if it could be
+	// resolved
+	loc *time.Location
+}
+
+// icsTimezone holds what's needed from a VTIMEZONE component to make sense of
+// its TZID.
+type icsTimezone struct {
+	tzID string
+	// location is the IANA name some calendars put in X-LIC-LOCATION
+	location    string
+	offsets     []time.Duration
+	hasDaylight bool
+}
+
+// windowsTimezones maps the Windows timezone names used by Outlook and
+// Exchange to their IANA equivalents.
+var windowsTimezones = map[string]string{
+	"Dateline Standard Time":         "Etc/GMT+12",
+	"Hawaiian Standard Time":         "Pacific/Honolulu",
+	"Alaskan Standard Time":          "America/Anchorage",
+	"Pacific Standard Time":          "America/Los_Angeles",
+	"Mountain Standard Time":         "America/Denver",
+	"US Mountain Standard Time":      "America/Phoenix",
+	"Central Standard Time":          "America/Chicago",
+	"Central America Standard Time":  "America/Guatemala",
+	"Canada Central Standard Time":   "America/Regina",
+	"Eastern Standard Time":          "America/New_York",
+	"SA Pacific Standard Time":       "America/Bogota",
+	"Atlantic Standard Time":         "America/Halifax",
+	"Newfoundland Standard Time":     "America/St_Johns",
+	"E. South America Standard Time": "America/Sao_Paulo",
+	"Argentina Standard Time":        "America/Buenos_Aires",
+	"UTC":                            "Etc/UTC",
+	"GMT Standard Time":              "Europe/London",
+	"Greenwich Standard Time":        "Atlantic/Reykjavik",
+	"W. Europe Standard Time":        "Europe/Berlin",
+	"Central Europe Standard Time":   "Europe/Budapest",
+	"Central European Standard Time": "Europe/Warsaw",
+	"Romance Standard Time":          "Europe/Paris",
+	"E. Europe Standard Time":        "Europe/Chisinau",
+	"FLE Standard Time":              "Europe/Kiev",
+	"GTB Standard Time":              "Europe/Bucharest",
+	"Turkey Standard Time":           "Europe/Istanbul",
+	"Israel Standard Time":           "Asia/Jerusalem",
+	"South Africa Standard Time":     "Africa/Johannesburg",
+	"Russian Standard Time":          "Europe/Moscow",
+	"Arabian Standard Time":          "Asia/Dubai",
+	"Pakistan Standard Time":         "Asia/Karachi",
+	"India Standard Time":            "Asia/Calcutta",
+	"SE Asia Standard Time":          "Asia/Bangkok",
+	"China Standard Time":            "Asia/Shanghai",
+	"Singapore Standard Time":        "Asia/Singapore",
+	"Tokyo Standard Time":            "Asia/Tokyo",
+	"Korea Standard Time":            "Asia/Seoul",
+	"AUS Eastern Standard Time":      "Australia/Sydney",
+	"E. Australia Standard Time":     "Australia/Brisbane",
+	"Cen. Australia Standard Time":   "Australia/Adelaide",
+	"W. Australia Standard Time":     "Australia/Perth",
+	"New Zealand Standard Time":      "Pacific/Auckland",
+}
+
+type icsEvent struct {
+	// row is the position of the event in the file, starting at 1
+	row          int
+	uid          string
+	summary      string
+	status       string
+	dtStart      *icsProperty
+	dtEnd        *icsProperty
+	duration     *icsProperty
+	rrule        *icsProperty
+	exDates      []icsProperty
+	recurrenceID *icsProperty
+}
+
+// ParseICS reads events from an iCalendar (RFC 5545) file, and maps them to
+// entries as per opts. Recurring events are expanded within the date range.
+// Events are numbered by their position in the file, starting at 1; all
+// instances of a recurring event share its number.
+//
+// Along with entries, it returns events that couldn't be parsed, and events
+// that are deliberately left out (eg. all-day or cancelled events, ones in a
+// timezone that couldn't be resolved, or ones that match no rule), each with
+// the reason.
+func ParseICS(reader io.Reader, opts ICSOptions) ([]Entry, []RowError, []RowError, error) {
+	events, err := readICSEvents(reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// modified instances of recurring events are separate events with the same
+	// UID, and replace the instances they refer to
+	overridden := make(map[string]map[int64]bool)
+	for _, event := range events {
+		if event.recurrenceID == nil || event.uid == "" {
+			continue
+		}
+		recurrenceID, _, err := parseICSTime(*event.recurrenceID)
+		if err != nil {
+			continue
+		}
+		if overridden[event.uid] == nil {
+			overridden[event.uid] = make(map[int64]bool)
+		}
+		overridden[event.uid][recurrenceID.Unix()] = true
+	}
+
+	var entries []Entry
+	var invalid, skipped []RowError
+	for _, event := range events {
+		instances, err := event.instances(opts.DateRange, overridden[event.uid])
+		if errors.Is(err, errICSEventIsAllDay) || errors.Is(err, errRRuleUnsupported) || errors.Is(err, errICSTimezoneUnknown) {
+			if event.overlapsRange(opts.DateRange) {
+				skipped = append(skipped, RowError{event.row, err})
+			}
+			continue
+		}
+		if err != nil {
+			invalid = append(invalid, RowError{event.row, err})
+			continue
+		}
+
+		for _, entry := range instances {
+			err := event.mapTo(&entry, opts)
+			if err != nil {
+				skipped = append(skipped, RowError{event.row, fmt.Errorf("%w: %s", err, entry.describe())})
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b Entry) int { return a.BeginTS.Compare(b.BeginTS) })
+
+	return entries, invalid, skipped, nil
+}
+
+// mapTo fills in the task summary and comment of an instance of the event, or
+// returns why it's to be skipped.
+func (e icsEvent) mapTo(entry *Entry, opts ICSOptions) error {
+	entry.TaskSummary = e.summary
+
+	switch {
+	case strings.EqualFold(e.status, "CANCELLED"):
+		return errICSEventIsCancelled
+	case e.summary == "":
+		return errICSEventHasNoSummary
+	case entry.EndTS.Sub(entry.BeginTS) < time.Minute:
+		return errICSEventIsTooShort
+	case entry.EndTS.After(opts.Now):
+		return errICSEventIsNotOver
+	}
+
+	for _, rule := range opts.Rules {
+		match := rule.Pattern.FindStringSubmatchIndex(e.summary)
+		if match == nil {
+			continue
+		}
+		entry.TaskSummary = string(rule.Pattern.ExpandString(nil, rule.Task, e.summary, match))
+		summary := e.summary
+		entry.Comment = &summary
+		return nil
+	}
+
+	if !opts.CreateTasks {
+		return errICSEventMatchesNoRule
+	}
+
+	return nil
+}
+
+// overlapsRange is a rough check of whether an event that couldn't be
+// expanded is relevant to the date range; it's used to avoid reporting events
+// that are far outside of it.
+func (e icsEvent) overlapsRange(dateRange types.DateRange) bool {
+	if e.dtStart == nil {
+		return false
+	}
+
+	// the event's own timezone may be unknown, which is close enough for
+	// this check
+	dtStart := *e.dtStart
+	if dtStart.loc == nil {
+		dtStart.loc = time.UTC
+	}
+
+	start, _, err := parseICSTime(dtStart)
+	if err != nil {
+		return false
+	}
+
+	// recurring events may have instances in the range regardless of when they
+	// start
+	if e.rrule != nil {
+		return start.Before(dateRange.End)
+	}
+
+	return start.Before(dateRange.End) && !start.Before(dateRange.Start.AddDate(0, 0, -1))
+}
+
+// instances returns the occurrences of the event that end within the date
+// range; instances in overridden (keyed by their start's unix timestamp) are
+// left out.
+func (e icsEvent) instances(dateRange types.DateRange, overridden map[int64]bool) ([]Entry, error) {
+	if e.dtStart == nil {
+		return nil, errICSEventHasNoStart
+	}
+
+	start, allDay, err := parseICSTime(*e.dtStart)
+	if err != nil {
+		return nil, err
+	}
+	if allDay {
+		return nil, errICSEventIsAllDay
+	}
+
+	var length time.Duration
+	switch {
+	case e.dtEnd != nil:
+		end, _, err := parseICSTime(*e.dtEnd)
+		if err != nil {
+			return nil, err
+		}
+		length = end.Sub(start)
+	case e.duration != nil:
+		length, err = parseICSDuration(e.duration.value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inRange := func(end time.Time) bool {
+		return !end.Before(dateRange.Start) && end.Before(dateRange.End)
+	}
+
+	if e.rrule == nil || e.recurrenceID != nil {
+		end := start.Add(length)
+		if !inRange(end) {
+			return nil, nil
+		}
+
+		externalID := e.uid
+		if e.recurrenceID != nil {
+			recurrenceID, _, err := parseICSTime(*e.recurrenceID)
+			if err != nil {
+				return nil, err
+			}
+			externalID = getICSInstanceID(e.uid, recurrenceID)
+		}
+
+		return []Entry{e.newEntry(start, end, externalID)}, nil
+	}
+
+	rule, err := parseRRule(e.rrule.value, start.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	exDates := make(map[int64]bool)
+	for _, exDate := range e.exDates {
+		for value := range strings.SplitSeq(exDate.value, ",") {
+			exDate.value = value
+			t, _, err := parseICSTime(exDate)
+			if err != nil {
+				return nil, err
+			}
+			exDates[t.Unix()] = true
+		}
+	}
+
+	var entries []Entry
+	for _, occurrence := range rule.occurrences(start, dateRange.End) {
+		if exDates[occurrence.Unix()] || overridden[occurrence.Unix()] {
+			continue
+		}
+
+		end := occurrence.Add(length)
+		if !inRange(end) {
+			continue
+		}
+
+		entries = append(entries, e.newEntry(occurrence, end, getICSInstanceID(e.uid, occurrence)))
+	}
+
+	return entries, nil
+}
+
+func (e icsEvent) newEntry(begin, end time.Time, externalID string) Entry {
+	if e.uid == "" {
+		externalID = hashFields(e.summary, begin.UTC().Format(icsUTCDateTimeFormat))
+	}
+
+	return Entry{
+		Row:        e.row,
+		BeginTS:    begin.Local(),
+		EndTS:      end.Local(),
+		ExternalID: externalID,
+	}
+}
+
+// getICSInstanceID identifies an instance of a recurring event by the UID of
+// the event, and the instance's original start.
+func getICSInstanceID(uid string, start time.Time) string {
+	return fmt.Sprintf("%s/%s", uid, start.UTC().Format(icsUTCDateTimeFormat))
+}
+
+func readICSEvents(reader io.Reader) ([]icsEvent, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCouldntReadICS, err.Error())
+	}
+
+	// long lines are folded into multiple lines, with continuation lines
+	// beginning with whitespace (RFC 5545, 3.1)
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\n ", "")
+	content = strings.ReplaceAll(content, "\n\t", "")
+	content = strings.TrimPrefix(content, "\ufeff")
+
+	var events []icsEvent
+	var current *icsEvent
+	timezones := make(map[string]icsTimezone)
+	var timezone *icsTimezone
+	calendarFound := false
+	// nestedDepth tracks components within an event (eg. alarms), whose
+	// properties are ignored
+	nestedDepth := 0
+
+	for line := range strings.SplitSeq(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		prop, ok := parseICSProperty(line)
+		if !ok {
+			continue
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			switch {
+			case component == "VCALENDAR":
+				calendarFound = true
+			case current != nil:
+				nestedDepth++
+			case component == "VEVENT":
+				current = &icsEvent{row: len(events) + 1}
+			case component == "VTIMEZONE":
+				timezone = &icsTimezone{}
+			case timezone != nil && component == "DAYLIGHT":
+				timezone.hasDaylight = true
+			}
+			continue
+		case "END":
+			switch {
+			case current == nil && timezone != nil && strings.EqualFold(prop.value, "VTIMEZONE"):
+				timezones[timezone.tzID] = *timezone
+				timezone = nil
+			case current == nil:
+			case nestedDepth > 0:
+				nestedDepth--
+			case strings.EqualFold(prop.value, "VEVENT"):
+				events = append(events, *current)
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil && timezone != nil {
+			switch prop.name {
+			case "TZID":
+				timezone.tzID = prop.value
+			case "X-LIC-LOCATION":
+				timezone.location = prop.value
+			case "TZOFFSETTO":
+				if offset, ok := parseICSOffset(prop.value); ok {
+					timezone.offsets = append(timezone.offsets, offset)
+				}
+			}
+			continue
+		}
+
+		if current == nil || nestedDepth > 0 {
+			continue
+		}
+
+		switch prop.name {
+		case "UID":
+			current.uid = prop.value
+		case "SUMMARY":
+			current.summary = strings.TrimSpace(icsUnescaper.Replace(prop.value))
+		case "STATUS":
+			current.status = prop.value
+		case "DTSTART":
+			current.dtStart = &prop
+		case "DTEND":
+			current.dtEnd = &prop
+		case "DURATION":
+			current.duration = &prop
+		case "RRULE":
+			current.rrule = &prop
+		case "EXDATE":
+			current.exDates = append(current.exDates, prop)
+		case "RECURRENCE-ID":
+			current.recurrenceID = &prop
+		}
+	}
+
+	if !calendarFound {
+		return nil, errICSHasNoCalendar
+	}
+
+	resolved := make(map[string]*time.Location)
+	resolve := func(prop *icsProperty) {
+		tzID := prop.params["TZID"]
+		if tzID == "" {
+			return
+		}
+		loc, ok := resolved[tzID]
+		if !ok {
+			loc = resolveICSTimezone(tzID, timezones)
+			resolved[tzID] = loc
+		}
+		prop.loc = loc
+	}
+
+	for i := range events {
+		for _, prop := range []*icsProperty{events[i].dtStart, events[i].dtEnd, events[i].recurrenceID} {
+			if prop != nil {
+				resolve(prop)
+			}
+		}
+		for j := range events[i].exDates {
+			resolve(&events[i].exDates[j])
+		}
+	}
+
+	return events, nil
+}
+
+// resolveICSTimezone finds the timezone a TZID refers to. Besides IANA names,
+// it understands Windows timezone names, and timezones defined in the file
+// itself that either name an IANA timezone, or have a fixed offset. It returns
+// nil if the timezone can't be resolved.
+func resolveICSTimezone(tzID string, timezones map[string]icsTimezone) *time.Location {
+	if loc, err := time.LoadLocation(tzID); err == nil {
+		return loc
+	}
+
+	if name, ok := windowsTimezones[tzID]; ok {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+
+	timezone, ok := timezones[tzID]
+	if !ok {
+		return nil
+	}
+
+	if timezone.location != "" {
+		if loc, err := time.LoadLocation(timezone.location); err == nil {
+			return loc
+		}
+	}
+
+	// without the IANA name, the offsets can only be relied upon if they
+	// don't change over the year
+	if timezone.hasDaylight || len(timezone.offsets) == 0 {
+		return nil
+	}
+	for _, offset := range timezone.offsets[1:] {
+		if offset != timezone.offsets[0] {
+			return nil
+		}
+	}
+
+	return time.FixedZone(tzID, int(timezone.offsets[0].Seconds()))
+}
+
+var icsOffsetRegex = regexp.MustCompile(`^([+-])(\d{2})(\d{2})(\d{2})?$`)
+
+// parseICSOffset parses UTC-OFFSET values, eg. "+0530" or "-0800".
+func parseICSOffset(value string) (time.Duration, bool) {
+	match := icsOffsetRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, false
+	}
+
+	var offset time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, false
+		}
+		offset += time.Duration(n) * unit
+	}
+
+	if match[1] == "-" {
+		offset = -offset
+	}
+
+	return offset, true
+}
+
+// parseICSProperty parses a content line of the form
+// "NAME;PARAM=VALUE;...:VALUE". Parameter values may be quoted, and quoted
+// values may contain ";" and ":".
+func parseICSProperty(line string) (icsProperty, bool) {
+	prop := icsProperty{params: make(map[string]string)}
+
+	inQuotes := false
+	var parts []string
+	partStart := 0
+	valueStart := -1
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case r == ';':
+			parts = append(parts, line[partStart:i])
+			partStart = i + 1
+		case r == ':':
+			parts = append(parts, line[partStart:i])
+			valueStart = i + 1
+		}
+		if valueStart != -1 {
+			break
+		}
+	}
+
+	if valueStart == -1 || len(parts) == 0 {
+		return prop, false
+	}
+
+	prop.name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	prop.value = line[valueStart:]
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+
+	return prop, true
+}
+
+// parseICSTime parses DATE and DATE-TIME values. Times in UTC end with "Z";
+// others are in the timezone referred to by the TZID parameter (as resolved
+// when reading the file), or are "floating", in which case they're read in the
+// local timezone.
+func parseICSTime(prop icsProperty) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(icsDateFormat) {
+		t, err := time.ParseInLocation(icsDateFormat, value, time.Local)
+		if err != nil {
+			return t, true, fmt.Errorf("%w: %q", errICSTimeUnparseable, value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icsUTCDateTimeFormat, value)
+		if err != nil {
+			return t, false, fmt.Errorf("%w: %q", errICSTimeUnparseable, value)
+		}
+		return t, false, nil
+	}
+
+	loc := time.Local
+	if tzID := prop.params["TZID"]; tzID != "" {
+		if prop.loc == nil {
+			return time.Time{}, false, fmt.Errorf("%w: %q", errICSTimezoneUnknown, tzID)
+		}
+		loc = prop.loc
+	}
+
+	t, err := time.ParseInLocation(icsDateTimeFormat, value, loc)
+	if err != nil {
+		return t, false, fmt.Errorf("%w: %q", errICSTimeUnparseable, value)
+	}
+
+	return t, false, nil
+}
+
+var icsDurationRegex = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICSDuration parses DURATION values, eg. "PT1H30M" or "P1D".
+func parseICSDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	match := icsDurationRegex.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("%w: %q", errICSDurationInvalid, value)
+	}
+
+	var duration time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", errICSDurationInvalid, value)
+		}
+		duration += time.Duration(n) * unit
+	}
+
+	if match[1] == "-" {
+		duration = -duration
+	}
+
+	return duration, nil
+}
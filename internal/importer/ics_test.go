@@ -0,0 +1,371 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const icsCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//test//test//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup\r\n" +
+	"DTSTART:20251020T090000Z\r\n" +
+	"DTEND:20251020T091500Z\r\n" +
+	"RRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR\r\n" +
+	"EXDATE:20251022T090000Z\r\n" +
+	"SUMMARY:Daily standup\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"TRIGGER:-PT10M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup\r\n" +
+	"RECURRENCE-ID:20251023T090000Z\r\n" +
+	"DTSTART:20251023T100000Z\r\n" +
+	"DTEND:20251023T103000Z\r\n" +
+	"SUMMARY:Daily standup (moved)\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:one-on-one\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20251021T140000\r\n" +
+	"DURATION:PT45M\r\n" +
+	"SUMMARY:1:1 with Alex\\, weekly\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:offsite\r\n" +
+	"DTSTART;VALUE=DATE:20251021\r\n" +
+	"DTEND;VALUE=DATE:20251022\r\n" +
+	"SUMMARY:Offsite\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cancelled\r\n" +
+	"DTSTART:20251021T150000Z\r\n" +
+	"DTEND:20251021T160000Z\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"SUMMARY:Planning\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:long-\r\n" +
+	" summary\r\n" +
+	"DTSTART:20251022T120000Z\r\n" +
+	"DTEND:20251022T130000Z\r\n" +
+	"SUMMARY:Lunch\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// icsOutlookCalendar mimics calendars exported from Outlook, which refer to
+// timezones by their Windows names, or define their own.
+const icsOutlookCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Microsoft Corporation//Outlook 16.0 MIMEDIR//EN\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:W. Europe Standard Time\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:16011028T030000\r\n" +
+	"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10\r\n" +
+	"TZOFFSETFROM:+0200\r\n" +
+	"TZOFFSETTO:+0100\r\n" +
+	"END:STANDARD\r\n" +
+	"BEGIN:DAYLIGHT\r\n" +
+	"DTSTART:16010325T020000\r\n" +
+	"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3\r\n" +
+	"TZOFFSETFROM:+0100\r\n" +
+	"TZOFFSETTO:+0200\r\n" +
+	"END:DAYLIGHT\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Customized Time Zone\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:16010101T000000\r\n" +
+	"TZOFFSETFROM:+0530\r\n" +
+	"TZOFFSETTO:+0530\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Office Time\r\n" +
+	"X-LIC-LOCATION:America/New_York\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:16011104T020000\r\n" +
+	"TZOFFSETFROM:-0400\r\n" +
+	"TZOFFSETTO:-0500\r\n" +
+	"END:STANDARD\r\n" +
+	"BEGIN:DAYLIGHT\r\n" +
+	"DTSTART:16010311T020000\r\n" +
+	"TZOFFSETFROM:-0500\r\n" +
+	"TZOFFSETTO:-0400\r\n" +
+	"END:DAYLIGHT\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:review\r\n" +
+	"DTSTART;TZID=W. Europe Standard Time:20251021T140000\r\n" +
+	"DTEND;TZID=W. Europe Standard Time:20251021T150000\r\n" +
+	"SUMMARY:Design review\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:sync\r\n" +
+	"DTSTART;TZID=\"Customized Time Zone\":20251022T100000\r\n" +
+	"DTEND;TZID=\"Customized Time Zone\":20251022T103000\r\n" +
+	"SUMMARY:Sync with Bangalore\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:planning\r\n" +
+	"DTSTART;TZID=Office Time:20251023T090000\r\n" +
+	"DTEND;TZID=Office Time:20251023T100000\r\n" +
+	"SUMMARY:Planning\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:retro\r\n" +
+	"DTSTART;TZID=Nowhere Standard Time:20251024T090000\r\n" +
+	"DTEND;TZID=Nowhere Standard Time:20251024T100000\r\n" +
+	"SUMMARY:Retro\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:old-retro\r\n" +
+	"DTSTART;TZID=Nowhere Standard Time:20240124T090000\r\n" +
+	"DTEND;TZID=Nowhere Standard Time:20240124T100000\r\n" +
+	"SUMMARY:Retro\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func getICSTestDateRange() types.DateRange {
+	return types.DateRange{
+		Start:   time.Date(2025, time.October, 20, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2025, time.October, 25, 0, 0, 0, 0, time.UTC),
+		NumDays: 5,
+	}
+}
+
+func TestParseICSWithCreateTasks(t *testing.T) {
+	// GIVEN
+	opts := ICSOptions{
+		CreateTasks: true,
+		DateRange:   getICSTestDateRange(),
+		Now:         time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC),
+	}
+
+	// WHEN
+	entries, invalid, skipped, err := ParseICS(strings.NewReader(icsCalendar), opts)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.TaskSummary+" @ "+entry.BeginTS.UTC().Format(time.RFC3339)+" "+entry.ExternalID)
+	}
+	expected := []string{
+		"Daily standup @ 2025-10-20T09:00:00Z standup/20251020T090000Z",
+		"Daily standup @ 2025-10-21T09:00:00Z standup/20251021T090000Z",
+		"1:1 with Alex, weekly @ 2025-10-21T12:00:00Z one-on-one",
+		"Lunch @ 2025-10-22T12:00:00Z long-summary",
+		"Daily standup (moved) @ 2025-10-23T10:00:00Z standup/20251023T090000Z",
+		"Daily standup @ 2025-10-24T09:00:00Z standup/20251024T090000Z",
+	}
+	assert.Equal(t, expected, got)
+	assert.True(t, entries[2].EndTS.Sub(entries[2].BeginTS) == 45*time.Minute)
+	assert.Nil(t, entries[0].Comment)
+
+	require.Len(t, skipped, 2)
+	assert.Equal(t, 4, skipped[0].Row)
+	assert.ErrorIs(t, skipped[0].Err, errICSEventIsAllDay)
+	assert.Equal(t, 5, skipped[1].Row)
+	assert.ErrorIs(t, skipped[1].Err, errICSEventIsCancelled)
+}
+
+func TestParseICSWithRules(t *testing.T) {
+	// GIVEN
+	opts := ICSOptions{
+		Rules: []ICSRule{
+			{regexp.MustCompile(`(?i)standup`), "meetings"},
+			{regexp.MustCompile(`^1:1 with (\w+)`), "1:1s with $1"},
+		},
+		DateRange: getICSTestDateRange(),
+		Now:       time.Date(2025, time.October, 24, 9, 10, 0, 0, time.UTC),
+	}
+
+	// WHEN
+	entries, _, skipped, err := ParseICS(strings.NewReader(icsCalendar), opts)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, "meetings", entries[0].TaskSummary)
+	require.NotNil(t, entries[0].Comment)
+	assert.Equal(t, "Daily standup", *entries[0].Comment)
+	assert.Equal(t, "1:1s with Alex", entries[2].TaskSummary)
+	require.NotNil(t, entries[2].Comment)
+	assert.Equal(t, "1:1 with Alex, weekly", *entries[2].Comment)
+
+	require.Len(t, skipped, 4)
+	assert.ErrorIs(t, skipped[0].Err, errICSEventIsNotOver)
+	assert.ErrorIs(t, skipped[1].Err, errICSEventIsAllDay)
+	assert.ErrorIs(t, skipped[2].Err, errICSEventIsCancelled)
+	assert.Equal(t, 6, skipped[3].Row)
+	assert.ErrorIs(t, skipped[3].Err, errICSEventMatchesNoRule)
+}
+
+func TestParseICSResolvesTimezones(t *testing.T) {
+	// GIVEN
+	opts := ICSOptions{
+		CreateTasks: true,
+		DateRange:   getICSTestDateRange(),
+		Now:         time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC),
+	}
+
+	// WHEN
+	entries, invalid, skipped, err := ParseICS(strings.NewReader(icsOutlookCalendar), opts)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.TaskSummary+" @ "+entry.BeginTS.UTC().Format(time.RFC3339)+" - "+entry.EndTS.UTC().Format(time.RFC3339))
+	}
+	expected := []string{
+		"Design review @ 2025-10-21T12:00:00Z - 2025-10-21T13:00:00Z",
+		"Sync with Bangalore @ 2025-10-22T04:30:00Z - 2025-10-22T05:00:00Z",
+		"Planning @ 2025-10-23T13:00:00Z - 2025-10-23T14:00:00Z",
+	}
+	assert.Equal(t, expected, got)
+
+	// events in a timezone that can't be resolved are only reported if
+	// they're around the date range
+	require.Len(t, skipped, 1)
+	assert.Equal(t, 4, skipped[0].Row)
+	assert.ErrorIs(t, skipped[0].Err, errICSTimezoneUnknown)
+}
+
+func TestParseICSReportsInvalidEvents(t *testing.T) {
+	// GIVEN
+	input := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:b
+DTSTART:2025-10-21 14:00
+SUMMARY:Sync
+END:VEVENT
+BEGIN:VEVENT
+UID:c
+SUMMARY:Sync
+END:VEVENT
+END:VCALENDAR
+`
+	opts := ICSOptions{CreateTasks: true, DateRange: getICSTestDateRange(), Now: time.Now()}
+
+	// WHEN
+	entries, invalid, _, err := ParseICS(strings.NewReader(input), opts)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	require.Len(t, invalid, 2)
+	assert.ErrorIs(t, invalid[0].Err, errICSTimeUnparseable)
+	assert.ErrorIs(t, invalid[1].Err, errICSEventHasNoStart)
+}
+
+func TestParseICSFailsWithoutACalendar(t *testing.T) {
+	// GIVEN
+	// WHEN
+	_, _, _, err := ParseICS(strings.NewReader("task,begin,end\n"), ICSOptions{})
+
+	// THEN
+	assert.ErrorIs(t, err, errICSHasNoCalendar)
+}
+
+func TestParseICSRule(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		pattern     string
+		task        string
+		expectedErr error
+	}{
+		{name: "simple rule", input: "standup=meetings", pattern: "standup", task: "meetings"},
+		{name: "regex with =", input: "a=b=task", pattern: "a=b", task: "task"},
+		{name: "no separator", input: "standup", expectedErr: errICSRuleInvalid},
+		{name: "empty task", input: "standup= ", expectedErr: errICSRuleInvalid},
+		{name: "empty regex", input: "=meetings", expectedErr: errICSRuleInvalid},
+		{name: "invalid regex", input: "(standup=meetings", expectedErr: errICSRuleRegexInvalid},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseICSRule(tt.input)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.pattern, rule.Pattern.String())
+			assert.Equal(t, tt.task, rule.Task)
+		})
+	}
+}
+
+func TestParseICSOffset(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected time.Duration
+		valid    bool
+	}{
+		{"+0200", 2 * time.Hour, true},
+		{"+0530", 5*time.Hour + 30*time.Minute, true},
+		{"-0800", -8 * time.Hour, true},
+		{"+001730", 17*time.Minute + 30*time.Second, true},
+		{"0200", 0, false},
+		{"+02:00", 0, false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := parseICSOffset(tt.input)
+
+			assert.Equal(t, tt.valid, ok)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseICSDuration(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected time.Duration
+		valid    bool
+	}{
+		{"PT45M", 45 * time.Minute, true},
+		{"PT1H30M", 90 * time.Minute, true},
+		{"P1DT2H", 26 * time.Hour, true},
+		{"P1W", 7 * 24 * time.Hour, true},
+		{"-PT15M", -15 * time.Minute, true},
+		{"P", 0, false},
+		{"PT", 0, false},
+		{"1H", 0, false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseICSDuration(tt.input)
+
+			if !tt.valid {
+				assert.ErrorIs(t, err, errICSDurationInvalid)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
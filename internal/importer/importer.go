@@ -0,0 +1,420 @@
+package importer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+// Sources of imported entries.
+const (
+	SourceCSV   = "csv"
+	SourceTimew = "timew"
+	SourceToggl = "toggl"
+	SourceICS   = "ics"
+)
+
+const (
+	taskSummaryLengthLimit = 100
+	reportTimeFormat       = "2006/01/02 15:04"
+)
+
+var (
+	errTaskSummaryEmpty         = errors.New("task summary is empty")
+	errTaskSummaryTooLong       = errors.New("task summary is too long")
+	errTaskLogDurationIsInvalid = errors.New("task log duration is invalid")
+	errCouldntFetchTasks        = errors.New("couldn't fetch tasks")
+	errCouldntFetchTLs          = errors.New("couldn't fetch existing task logs")
+	errCouldntFetchActiveTask   = errors.New("couldn't fetch active task")
+	errCouldntFetchImportedIDs  = errors.New("couldn't fetch IDs of previously imported entries")
+	errTaskAlreadyBeingTracked  = errors.New("cannot start tracking as a task is already being tracked")
+	errOverlapsWithTL           = errors.New("overlaps with task log entry")
+	errOverlapsWithRow          = errors.New("overlaps with row")
+)
+
+// Entry is a finished time entry read from an external source.
+type Entry struct {
+	// Row is the position of the entry in the source, used for reporting
+	Row         int
+	TaskSummary string
+	BeginTS     time.Time
+	EndTS       time.Time
+	Comment     *string
+	// Active entries are still being tracked, and become the active task log;
+	// EndTS is ignored for them
+	Active bool
+	// ExternalID, if set, identifies the entry in its source; entries imported
+	// before are recognized by it, even if their task logs changed since
+	ExternalID string
+}
+
+func (e Entry) describe() string {
+	if e.Active {
+		return fmt.Sprintf("%s (%s ... now)", e.TaskSummary, e.BeginTS.Format(reportTimeFormat))
+	}
+
+	return fmt.Sprintf("%s (%s ... %s)",
+		e.TaskSummary,
+		e.BeginTS.Format(reportTimeFormat),
+		e.EndTS.Format(reportTimeFormat),
+	)
+}
+
+// RowError records why a row in the source couldn't be imported.
+type RowError struct {
+	Row int
+	Err error
+}
+
+// Plan is the outcome of checking entries against the database, before
+// anything is written to it.
+type Plan struct {
+	Source     string
+	NumRows    int
+	ToImport   []Entry
+	Duplicates []Entry
+	Invalid    []RowError
+	// Skipped holds rows that are deliberately left out; unlike invalid rows,
+	// they don't prevent the rest from being imported
+	Skipped  []RowError
+	NewTasks []string
+}
+
+func (p Plan) HasInvalidRows() bool {
+	return len(p.Invalid) > 0
+}
+
+// Skip records rows that the source deliberately leaves out.
+func (p *Plan) Skip(rowErrors []RowError) {
+	p.NumRows += len(rowErrors)
+	p.Skipped = append(p.Skipped, rowErrors...)
+	slices.SortStableFunc(p.Skipped, compareRowErrors)
+}
+
+// SkipOverlapping moves entries that overlap with existing task logs, or with
+// entries earlier in the plan, from the ones to be imported to the skipped
+// ones.
+func (p *Plan) SkipOverlapping(db *sql.DB) error {
+	var finished []Entry
+	for _, entry := range p.ToImport {
+		if !entry.Active {
+			finished = append(finished, entry)
+		}
+	}
+
+	if len(finished) == 0 {
+		return nil
+	}
+
+	minBegin, maxEnd := getTimeSpan(finished)
+	existingTLs, err := pers.FetchTLEntriesOverlapping(db, minBegin, maxEnd, pers.NoLimit)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchTLs, err.Error())
+	}
+
+	overlaps := func(a, b Entry) bool {
+		return a.BeginTS.Before(b.EndTS) && b.BeginTS.Before(a.EndTS)
+	}
+
+	var toImport, accepted []Entry
+	var skipped []RowError
+	for _, entry := range p.ToImport {
+		if entry.Active {
+			toImport = append(toImport, entry)
+			continue
+		}
+
+		tlIndex := slices.IndexFunc(existingTLs, func(tl domain.TaskLogEntry) bool {
+			return overlaps(entry, Entry{BeginTS: tl.BeginTS, EndTS: tl.EndTS})
+		})
+		if tlIndex != -1 {
+			tl := existingTLs[tlIndex]
+			skipped = append(skipped, RowError{entry.Row, fmt.Errorf("%w #%d (%s): %s", errOverlapsWithTL, tl.ID, tl.TaskSummary, entry.describe())})
+			continue
+		}
+
+		acceptedIndex := slices.IndexFunc(accepted, func(other Entry) bool { return overlaps(entry, other) })
+		if acceptedIndex != -1 {
+			skipped = append(skipped, RowError{entry.Row, fmt.Errorf("%w %d: %s", errOverlapsWithRow, accepted[acceptedIndex].Row, entry.describe())})
+			continue
+		}
+
+		accepted = append(accepted, entry)
+		toImport = append(toImport, entry)
+	}
+
+	p.ToImport = toImport
+	p.Skipped = append(p.Skipped, skipped...)
+	slices.SortStableFunc(p.Skipped, compareRowErrors)
+
+	p.NewTasks = slices.DeleteFunc(p.NewTasks, func(summary string) bool {
+		return !slices.ContainsFunc(p.ToImport, func(entry Entry) bool { return entry.TaskSummary == summary })
+	})
+
+	return nil
+}
+
+type entryKey struct {
+	taskSummary string
+	begin       int64
+	end         int64
+}
+
+func getEntryKey(taskSummary string, begin, end time.Time) entryKey {
+	return entryKey{taskSummary, begin.Unix(), end.Unix()}
+}
+
+// NewPlan validates entries, and sorts them into the ones to be imported, and
+// the ones that duplicate existing task logs (or earlier entries in the same
+// source). rowErrors are errors encountered while parsing the source, and are
+// reported alongside entries that fail validation.
+func NewPlan(db *sql.DB, source string, entries []Entry, rowErrors []RowError) (Plan, error) {
+	plan := Plan{
+		Source:  source,
+		NumRows: len(entries) + len(rowErrors),
+		Invalid: rowErrors,
+	}
+
+	var finished, active []Entry
+	for _, entry := range entries {
+		entry.TaskSummary = strings.TrimSpace(entry.TaskSummary)
+		err := validateEntry(entry)
+		if err != nil {
+			plan.Invalid = append(plan.Invalid, RowError{entry.Row, err})
+			continue
+		}
+
+		if entry.Active {
+			active = append(active, entry)
+		} else {
+			finished = append(finished, entry)
+		}
+	}
+
+	if len(finished) == 0 && len(active) == 0 {
+		slices.SortFunc(plan.Invalid, compareRowErrors)
+		return plan, nil
+	}
+
+	tasks, err := pers.FetchTasksWithStatus(db, types.TaskStatusAny, pers.NoLimit)
+	if err != nil {
+		return plan, fmt.Errorf("%w: %s", errCouldntFetchTasks, err.Error())
+	}
+
+	existingTasks := make(map[string]bool)
+	for _, task := range tasks {
+		existingTasks[task.Summary] = true
+	}
+
+	activeTask, err := pers.FetchActiveTaskDetails(db)
+	if err != nil {
+		return plan, fmt.Errorf("%w: %s", errCouldntFetchActiveTask, err.Error())
+	}
+	isTracking := activeTask.TaskID != -1
+
+	newTasks := make(map[string]bool)
+	toImport := func(entry Entry) {
+		if !existingTasks[entry.TaskSummary] && !newTasks[entry.TaskSummary] {
+			newTasks[entry.TaskSummary] = true
+			plan.NewTasks = append(plan.NewTasks, entry.TaskSummary)
+		}
+
+		plan.ToImport = append(plan.ToImport, entry)
+	}
+
+	if len(finished) > 0 {
+		existingTLs, err := fetchTLsInRange(db, finished)
+		if err != nil {
+			return plan, err
+		}
+
+		importedIDs, err := fetchImportedIDs(db, source)
+		if err != nil {
+			return plan, err
+		}
+
+		seen := make(map[entryKey]bool)
+		for _, tl := range existingTLs {
+			seen[getEntryKey(tl.TaskSummary, tl.BeginTS, tl.EndTS)] = true
+		}
+
+		for _, entry := range finished {
+			key := getEntryKey(entry.TaskSummary, entry.BeginTS, entry.EndTS)
+			if seen[key] || (entry.ExternalID != "" && importedIDs[entry.ExternalID]) {
+				plan.Duplicates = append(plan.Duplicates, entry)
+				continue
+			}
+			seen[key] = true
+			if entry.ExternalID != "" {
+				importedIDs[entry.ExternalID] = true
+			}
+			toImport(entry)
+		}
+	}
+
+	// finished entries can be imported while a task is being tracked, but an
+	// active one can't (this is enforced by the prevent_duplicate_active_insert
+	// trigger)
+	activeTaken := isTracking
+	for _, entry := range active {
+		switch {
+		case isTracking &&
+			activeTask.TaskSummary == entry.TaskSummary &&
+			activeTask.CurrentLogBeginTS.Unix() == entry.BeginTS.Unix():
+			plan.Duplicates = append(plan.Duplicates, entry)
+		case activeTaken:
+			plan.Invalid = append(plan.Invalid, RowError{entry.Row, errTaskAlreadyBeingTracked})
+		default:
+			activeTaken = true
+			toImport(entry)
+		}
+	}
+
+	slices.SortFunc(plan.Invalid, compareRowErrors)
+
+	return plan, nil
+}
+
+func fetchImportedIDs(db *sql.DB, source string) (map[string]bool, error) {
+	ids, err := pers.FetchImportedExternalIDs(db, source)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCouldntFetchImportedIDs, err.Error())
+	}
+
+	importedIDs := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		importedIDs[id] = true
+	}
+
+	return importedIDs, nil
+}
+
+func compareRowErrors(a, b RowError) int {
+	return a.Row - b.Row
+}
+
+func getTimeSpan(entries []Entry) (time.Time, time.Time) {
+	minBegin, maxEnd := entries[0].BeginTS, entries[0].EndTS
+	for _, entry := range entries[1:] {
+		if entry.BeginTS.Before(minBegin) {
+			minBegin = entry.BeginTS
+		}
+		if entry.EndTS.After(maxEnd) {
+			maxEnd = entry.EndTS
+		}
+	}
+
+	return minBegin, maxEnd
+}
+
+func fetchTLsInRange(db *sql.DB, entries []Entry) ([]domain.TaskLogEntry, error) {
+	minBegin, maxEnd := getTimeSpan(entries)
+
+	// existing task logs are looked up by end timestamp; an entry ending within
+	// [minBegin, maxEnd] is the only kind that can be duplicated
+	tls, err := pers.FetchTLEntriesBetweenTS(db, minBegin, maxEnd.Add(time.Second), types.TaskFilter{Status: types.TaskStatusAny}, pers.NoLimit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCouldntFetchTLs, err.Error())
+	}
+
+	return tls, nil
+}
+
+func validateEntry(entry Entry) error {
+	if entry.TaskSummary == "" {
+		return errTaskSummaryEmpty
+	}
+
+	if len(entry.TaskSummary) > taskSummaryLengthLimit {
+		return fmt.Errorf("%w (limit: %d characters)", errTaskSummaryTooLong, taskSummaryLengthLimit)
+	}
+
+	if entry.Active {
+		return nil
+	}
+
+	err := types.IsTaskLogDurationValid(entry.BeginTS, entry.EndTS)
+	if err != nil {
+		return fmt.Errorf("%w (%s ... %s): %w",
+			errTaskLogDurationIsInvalid,
+			entry.BeginTS.Format(reportTimeFormat),
+			entry.EndTS.Format(reportTimeFormat),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Apply imports the entries in the plan in a single transaction.
+func (p Plan) Apply(db *sql.DB) (pers.ImportResult, error) {
+	tls := make([]pers.ImportedTL, len(p.ToImport))
+	for i, entry := range p.ToImport {
+		tls[i] = pers.ImportedTL{
+			TaskSummary: entry.TaskSummary,
+			BeginTS:     entry.BeginTS,
+			EndTS:       entry.EndTS,
+			Comment:     entry.Comment,
+			Active:      entry.Active,
+			Source:      p.Source,
+			ExternalID:  entry.ExternalID,
+		}
+	}
+
+	return pers.ImportTLs(db, tls)
+}
+
+// WriteReport writes a human readable summary of the plan.
+func (p Plan) WriteReport(writer io.Writer) {
+	fmt.Fprintf(writer, `rows read:       %d
+to import:       %d
+new tasks:       %d
+duplicates:      %d
+invalid:         %d
+`, p.NumRows, len(p.ToImport), len(p.NewTasks), len(p.Duplicates), len(p.Invalid))
+	if len(p.Skipped) > 0 {
+		fmt.Fprintf(writer, "skipped:         %d\n", len(p.Skipped))
+	}
+
+	if len(p.NewTasks) > 0 {
+		fmt.Fprintln(writer, "\ntasks to be created:")
+		for _, summary := range p.NewTasks {
+			fmt.Fprintf(writer, "  %s\n", summary)
+		}
+	}
+
+	for _, entry := range p.ToImport {
+		if entry.Active {
+			fmt.Fprintf(writer, "\nwill start tracking:\n  row %d: %s\n", entry.Row, entry.describe())
+		}
+	}
+
+	if len(p.Duplicates) > 0 {
+		fmt.Fprintln(writer, "\nduplicate rows (will be skipped):")
+		for _, entry := range p.Duplicates {
+			fmt.Fprintf(writer, "  row %d: %s\n", entry.Row, entry.describe())
+		}
+	}
+
+	if len(p.Skipped) > 0 {
+		fmt.Fprintln(writer, "\nskipped rows:")
+		for _, rowErr := range p.Skipped {
+			fmt.Fprintf(writer, "  row %d: %s\n", rowErr.Row, rowErr.Err.Error())
+		}
+	}
+
+	if len(p.Invalid) > 0 {
+		fmt.Fprintln(writer, "\ninvalid rows:")
+		for _, rowErr := range p.Invalid {
+			fmt.Fprintf(writer, "  row %d: %s\n", rowErr.Row, rowErr.Err.Error())
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package importer
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite" // sqlite driver
+)
+
+func getTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	err = pers.InitDB(db)
+	require.NoError(t, err)
+
+	err = pers.UpgradeDB(db, 1)
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewPlan(t *testing.T) {
+	// GIVEN
+	db := getTestDB(t)
+	referenceTS := time.Date(2025, time.October, 24, 9, 0, 0, 0, time.Local)
+
+	taskID, err := pers.InsertTask(db, "existing task")
+	require.NoError(t, err)
+	_, err = pers.InsertManualTL(db, taskID, referenceTS, referenceTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+
+	entries := []Entry{
+		{Row: 2, TaskSummary: "existing task", BeginTS: referenceTS, EndTS: referenceTS.Add(time.Hour)},
+		{Row: 3, TaskSummary: " new task ", BeginTS: referenceTS, EndTS: referenceTS.Add(time.Hour)},
+		{Row: 4, TaskSummary: "new task", BeginTS: referenceTS, EndTS: referenceTS.Add(time.Hour)},
+		{Row: 5, TaskSummary: "existing task", BeginTS: referenceTS.Add(2 * time.Hour), EndTS: referenceTS.Add(3 * time.Hour)},
+		{Row: 7, TaskSummary: "new task", BeginTS: referenceTS, EndTS: referenceTS.Add(30 * time.Second)},
+		{Row: 8, TaskSummary: "", BeginTS: referenceTS, EndTS: referenceTS.Add(time.Hour)},
+	}
+	rowErrors := []RowError{{Row: 6, Err: errBeginTSUnparseable}}
+
+	// WHEN
+	plan, err := NewPlan(db, SourceCSV, entries, rowErrors)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, 7, plan.NumRows)
+
+	require.Len(t, plan.ToImport, 2)
+	assert.Equal(t, 3, plan.ToImport[0].Row)
+	assert.Equal(t, "new task", plan.ToImport[0].TaskSummary)
+	assert.Equal(t, 5, plan.ToImport[1].Row)
+
+	require.Len(t, plan.Duplicates, 2)
+	assert.Equal(t, 2, plan.Duplicates[0].Row)
+	assert.Equal(t, 4, plan.Duplicates[1].Row)
+
+	require.Len(t, plan.Invalid, 3)
+	assert.Equal(t, 6, plan.Invalid[0].Row)
+	assert.Equal(t, 7, plan.Invalid[1].Row)
+	assert.ErrorIs(t, plan.Invalid[1].Err, types.ErrDurationNotLongEnough)
+	assert.Equal(t, 8, plan.Invalid[2].Row)
+	assert.ErrorIs(t, plan.Invalid[2].Err, errTaskSummaryEmpty)
+
+	assert.Equal(t, []string{"new task"}, plan.NewTasks)
+}
+
+func TestPlanApply(t *testing.T) {
+	// GIVEN
+	db := getTestDB(t)
+	referenceTS := time.Date(2025, time.October, 24, 9, 0, 0, 0, time.Local)
+	comment := "a comment"
+	entries := []Entry{
+		{Row: 2, TaskSummary: "task 1", BeginTS: referenceTS, EndTS: referenceTS.Add(time.Hour), Comment: &comment},
+		{Row: 3, TaskSummary: "task 2", BeginTS: referenceTS, EndTS: referenceTS.Add(time.Hour)},
+		{Row: 4, TaskSummary: "task 1", BeginTS: referenceTS.Add(time.Hour), EndTS: referenceTS.Add(2 * time.Hour)},
+	}
+	plan, err := NewPlan(db, SourceCSV, entries, nil)
+	require.NoError(t, err)
+
+	// WHEN
+	result, err := plan.Apply(db)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NumTasksCreated)
+	assert.Equal(t, 3, result.NumTLsInserted)
+
+	planAgain, err := NewPlan(db, SourceCSV, entries, nil)
+	require.NoError(t, err)
+	assert.Empty(t, planAgain.ToImport)
+	assert.Len(t, planAgain.Duplicates, 3)
+}
+
+func TestNewPlanWithActiveEntries(t *testing.T) {
+	// GIVEN
+	db := getTestDB(t)
+	referenceTS := time.Date(2025, time.October, 24, 9, 0, 0, 0, time.Local)
+	entries := []Entry{
+		{Row: 1, TaskSummary: "task 1", BeginTS: referenceTS, EndTS: referenceTS.Add(time.Hour)},
+		{Row: 2, TaskSummary: "task 1", BeginTS: referenceTS.Add(time.Hour), Active: true},
+		{Row: 3, TaskSummary: "task 2", BeginTS: referenceTS.Add(2 * time.Hour), Active: true},
+	}
+
+	// WHEN
+	plan, err := NewPlan(db, SourceCSV, entries, nil)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, plan.ToImport, 2)
+	assert.True(t, plan.ToImport[1].Active)
+	require.Len(t, plan.Invalid, 1)
+	assert.Equal(t, 3, plan.Invalid[0].Row)
+	assert.ErrorIs(t, plan.Invalid[0].Err, errTaskAlreadyBeingTracked)
+}
+
+func TestNewPlanWhenATaskIsBeingTracked(t *testing.T) {
+	// GIVEN
+	db := getTestDB(t)
+	referenceTS := time.Date(2025, time.October, 24, 9, 0, 0, 0, time.Local)
+	entries := []Entry{
+		{Row: 1, TaskSummary: "task 1", BeginTS: referenceTS, EndTS: referenceTS.Add(time.Hour)},
+		{Row: 2, TaskSummary: "task 1", BeginTS: referenceTS.Add(time.Hour), Active: true},
+	}
+	plan, err := NewPlan(db, SourceTimew, entries, nil)
+	require.NoError(t, err)
+	result, err := plan.Apply(db)
+	require.NoError(t, err)
+	require.NotZero(t, result.ActiveTLID)
+
+	entriesTwo := append(entries,
+		Entry{
+			Row:         3,
+			TaskSummary: "task 2",
+			BeginTS:     referenceTS.Add(-2 * time.Hour),
+			EndTS:       referenceTS.Add(-time.Hour),
+		},
+		Entry{
+			Row:         4,
+			TaskSummary: "task 2",
+			BeginTS:     referenceTS.Add(3 * time.Hour),
+			Active:      true,
+		},
+	)
+
+	// WHEN
+	planTwo, err := NewPlan(db, SourceTimew, entriesTwo, nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Len(t, planTwo.Duplicates, 2)
+	require.Len(t, planTwo.ToImport, 1)
+	assert.Equal(t, 3, planTwo.ToImport[0].Row)
+	require.Len(t, planTwo.Invalid, 1)
+	assert.Equal(t, 4, planTwo.Invalid[0].Row)
+	assert.ErrorIs(t, planTwo.Invalid[0].Err, errTaskAlreadyBeingTracked)
+}
+
+func TestPlanApplyImportsFinishedEntriesWhileATaskIsBeingTracked(t *testing.T) {
+	// GIVEN
+	db := getTestDB(t)
+	referenceTS := time.Date(2025, time.October, 24, 9, 0, 0, 0, time.Local)
+	taskID, err := pers.InsertTask(db, "task 1")
+	require.NoError(t, err)
+	activeTLID, err := pers.InsertNewTL(db, taskID, referenceTS, nil)
+	require.NoError(t, err)
+
+	entries := []Entry{
+		{Row: 1, TaskSummary: "task 1", BeginTS: referenceTS.Add(-3 * time.Hour), EndTS: referenceTS.Add(-2 * time.Hour)},
+		{Row: 2, TaskSummary: "task 2", BeginTS: referenceTS.Add(-2 * time.Hour), EndTS: referenceTS.Add(-time.Hour)},
+	}
+	plan, err := NewPlan(db, SourceTimew, entries, nil)
+	require.NoError(t, err)
+	require.False(t, plan.HasInvalidRows())
+
+	// WHEN
+	result, err := plan.Apply(db)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NumTLsInserted)
+	assert.Equal(t, 1, result.NumTasksCreated)
+	assert.Zero(t, result.ActiveTLID)
+
+	activeTask, err := pers.FetchActiveTaskDetails(db)
+	require.NoError(t, err)
+	assert.Equal(t, taskID, activeTask.TaskID)
+	assert.Equal(t, activeTLID, activeTask.CurrentLogID)
+}
+
+func TestNewPlanRecognizesEntriesImportedBefore(t *testing.T) {
+	// GIVEN
+	db := getTestDB(t)
+	referenceTS := time.Date(2025, time.October, 24, 9, 0, 0, 0, time.Local)
+	entries := []Entry{
+		{Row: 1, TaskSummary: "task 1", BeginTS: referenceTS, EndTS: referenceTS.Add(time.Hour), ExternalID: "101"},
+		{Row: 2, TaskSummary: "task 1", BeginTS: referenceTS.Add(time.Hour), EndTS: referenceTS.Add(2 * time.Hour), ExternalID: "102"},
+	}
+	plan, err := NewPlan(db, SourceToggl, entries, nil)
+	require.NoError(t, err)
+	result, err := plan.Apply(db)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.NumTLsInserted)
+
+	// the task log is changed after being imported
+	tls, err := pers.FetchTLEntriesBetweenTS(db, referenceTS, referenceTS.Add(3*time.Hour), types.TaskFilter{Status: types.TaskStatusAny}, pers.NoLimit)
+	require.NoError(t, err)
+	require.Len(t, tls, 2)
+	_, err = pers.EditSavedTL(db, tls[0].ID, referenceTS.Add(-time.Hour), referenceTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+
+	entries = append(entries, Entry{
+		Row:         3,
+		TaskSummary: "task 1",
+		BeginTS:     referenceTS.Add(2 * time.Hour),
+		EndTS:       referenceTS.Add(3 * time.Hour),
+		ExternalID:  "103",
+	})
+
+	// WHEN
+	planAgain, err := NewPlan(db, SourceToggl, entries, nil)
+	planFromOtherSource, otherErr := NewPlan(db, SourceCSV, entries, nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Len(t, planAgain.Duplicates, 2)
+	require.Len(t, planAgain.ToImport, 1)
+	assert.Equal(t, "103", planAgain.ToImport[0].ExternalID)
+
+	require.NoError(t, otherErr)
+	assert.Len(t, planFromOtherSource.Duplicates, 1)
+	assert.Len(t, planFromOtherSource.ToImport, 2)
+}
+
+func TestPlanSkipOverlapping(t *testing.T) {
+	// GIVEN
+	db := getTestDB(t)
+	referenceTS := time.Date(2025, time.October, 24, 9, 0, 0, 0, time.Local)
+
+	taskID, err := pers.InsertTask(db, "existing task")
+	require.NoError(t, err)
+	_, err = pers.InsertManualTL(db, taskID, referenceTS, referenceTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+
+	entries := []Entry{
+		{Row: 1, TaskSummary: "meetings", BeginTS: referenceTS.Add(30 * time.Minute), EndTS: referenceTS.Add(90 * time.Minute)},
+		{Row: 2, TaskSummary: "meetings", BeginTS: referenceTS.Add(time.Hour), EndTS: referenceTS.Add(2 * time.Hour)},
+		{Row: 3, TaskSummary: "1:1s", BeginTS: referenceTS.Add(90 * time.Minute), EndTS: referenceTS.Add(150 * time.Minute)},
+		{Row: 4, TaskSummary: "reviews", BeginTS: referenceTS.Add(-time.Hour), EndTS: referenceTS.Add(10 * time.Minute)},
+	}
+	plan, err := NewPlan(db, SourceICS, entries, nil)
+	require.NoError(t, err)
+	require.Len(t, plan.NewTasks, 3)
+
+	// WHEN
+	err = plan.SkipOverlapping(db)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, plan.ToImport, 1)
+	assert.Equal(t, 2, plan.ToImport[0].Row)
+	assert.Equal(t, []string{"meetings"}, plan.NewTasks)
+
+	require.Len(t, plan.Skipped, 3)
+	assert.ErrorIs(t, plan.Skipped[0].Err, errOverlapsWithTL)
+	assert.ErrorIs(t, plan.Skipped[1].Err, errOverlapsWithRow)
+	assert.Equal(t, 4, plan.Skipped[2].Row)
+	assert.ErrorIs(t, plan.Skipped[2].Err, errOverlapsWithTL)
+}
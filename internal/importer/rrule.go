@@ -0,0 +1,354 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRecurrencePeriods bounds the expansion of recurrence rules without an end
+const maxRecurrencePeriods = 100000
+
+var (
+	errRRuleInvalid     = errors.New("recurrence rule is invalid")
+	errRRuleUnsupported = errors.New("recurrence rule is not supported")
+)
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+type rruleFreq uint8
+
+const (
+	freqDaily rruleFreq = iota
+	freqWeekly
+	freqMonthly
+	freqYearly
+)
+
+type rruleWeekday struct {
+	// ordinal is the occurrence of the weekday within the month (negative
+	// values count from the end); 0 means every occurrence
+	ordinal int
+	weekday time.Weekday
+}
+
+// rrule is the subset of RFC 5545 recurrence rules that's supported:
+// DAILY, WEEKLY, MONTHLY, and YEARLY frequencies, with INTERVAL, COUNT,
+// UNTIL, BYDAY, BYMONTHDAY, BYMONTH, and WKST.
+type rrule struct {
+	freq       rruleFreq
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []rruleWeekday
+	byMonthDay []int
+	byMonth    []time.Month
+	weekStart  time.Weekday
+}
+
+func parseRRule(value string, loc *time.Location) (rrule, error) {
+	rule := rrule{interval: 1, weekStart: time.Monday}
+	freqFound := false
+
+	for part := range strings.SplitSeq(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return rule, fmt.Errorf("%w: %q", errRRuleInvalid, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freqFound = true
+			switch strings.ToUpper(val) {
+			case "DAILY":
+				rule.freq = freqDaily
+			case "WEEKLY":
+				rule.freq = freqWeekly
+			case "MONTHLY":
+				rule.freq = freqMonthly
+			case "YEARLY":
+				rule.freq = freqYearly
+			default:
+				return rule, fmt.Errorf("%w: FREQ=%s", errRRuleUnsupported, val)
+			}
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(val)
+			if err != nil || rule.interval < 1 {
+				return rule, fmt.Errorf("%w: %q", errRRuleInvalid, part)
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(val)
+			if err != nil || rule.count < 1 {
+				return rule, fmt.Errorf("%w: %q", errRRuleInvalid, part)
+			}
+		case "UNTIL":
+			rule.until, err = parseRRuleUntil(val, loc)
+			if err != nil {
+				return rule, fmt.Errorf("%w: %q", errRRuleInvalid, part)
+			}
+		case "BYDAY":
+			for day := range strings.SplitSeq(val, ",") {
+				wd, err := parseRRuleWeekday(day)
+				if err != nil {
+					return rule, err
+				}
+				rule.byDay = append(rule.byDay, wd)
+			}
+		case "BYMONTHDAY":
+			for day := range strings.SplitSeq(val, ",") {
+				d, err := strconv.Atoi(day)
+				if err != nil || d == 0 || d < -31 || d > 31 {
+					return rule, fmt.Errorf("%w: %q", errRRuleInvalid, part)
+				}
+				rule.byMonthDay = append(rule.byMonthDay, d)
+			}
+		case "BYMONTH":
+			for month := range strings.SplitSeq(val, ",") {
+				m, err := strconv.Atoi(month)
+				if err != nil || m < 1 || m > 12 {
+					return rule, fmt.Errorf("%w: %q", errRRuleInvalid, part)
+				}
+				rule.byMonth = append(rule.byMonth, time.Month(m))
+			}
+		case "WKST":
+			wd, ok := icsWeekdays[strings.ToUpper(val)]
+			if !ok {
+				return rule, fmt.Errorf("%w: %q", errRRuleInvalid, part)
+			}
+			rule.weekStart = wd
+		default:
+			return rule, fmt.Errorf("%w: %s", errRRuleUnsupported, key)
+		}
+	}
+
+	if !freqFound {
+		return rule, fmt.Errorf("%w: FREQ is missing", errRRuleInvalid)
+	}
+
+	return rule, nil
+}
+
+func parseRRuleUntil(value string, loc *time.Location) (time.Time, error) {
+	switch {
+	case len(value) == len(icsDateFormat):
+		date, err := time.ParseInLocation(icsDateFormat, value, loc)
+		if err != nil {
+			return date, err
+		}
+		// the whole day is included
+		return date.AddDate(0, 0, 1).Add(-time.Second), nil
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse(icsUTCDateTimeFormat, value)
+	default:
+		return time.ParseInLocation(icsDateTimeFormat, value, loc)
+	}
+}
+
+func parseRRuleWeekday(value string) (rruleWeekday, error) {
+	var wd rruleWeekday
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return wd, fmt.Errorf("%w: BYDAY=%s", errRRuleInvalid, value)
+	}
+
+	weekday, ok := icsWeekdays[value[len(value)-2:]]
+	if !ok {
+		return wd, fmt.Errorf("%w: BYDAY=%s", errRRuleInvalid, value)
+	}
+	wd.weekday = weekday
+
+	if ordinal := value[:len(value)-2]; ordinal != "" {
+		n, err := strconv.Atoi(ordinal)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return wd, fmt.Errorf("%w: BYDAY=%s", errRRuleInvalid, value)
+		}
+		wd.ordinal = n
+	}
+
+	return wd, nil
+}
+
+// occurrences returns the starts of the instances of a recurring event that
+// begin before end. The first instance is always dtStart.
+func (r rrule) occurrences(dtStart, end time.Time) []time.Time {
+	var result []time.Time
+	numInstances := 0
+
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		candidates, periodStart := r.candidates(dtStart, period)
+		if !periodStart.Before(end) {
+			break
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Before(dtStart) {
+				continue
+			}
+			if !r.until.IsZero() && candidate.After(r.until) {
+				return result
+			}
+			if !candidate.Before(end) {
+				return result
+			}
+
+			numInstances++
+			if r.count > 0 && numInstances > r.count {
+				return result
+			}
+			result = append(result, candidate)
+		}
+	}
+
+	return result
+}
+
+// candidates returns the sorted instance starts within the nth period of the
+// rule, along with the start of the period.
+func (r rrule) candidates(dtStart time.Time, n int) ([]time.Time, time.Time) {
+	year, month, day := dtStart.Date()
+	hour, minute, sec := dtStart.Clock()
+	loc := dtStart.Location()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, sec, 0, loc)
+	}
+
+	var candidates []time.Time
+	var periodStart time.Time
+
+	switch r.freq {
+	case freqDaily:
+		candidate := at(year, month, day+n*r.interval)
+		periodStart = candidate
+		if r.matchesByMonth(candidate) && r.matchesByDay(candidate) && r.matchesByMonthDay(candidate) {
+			candidates = append(candidates, candidate)
+		}
+	case freqWeekly:
+		offset := (int(dtStart.Weekday()) - int(r.weekStart) + 7) % 7
+		weekStart := at(year, month, day-offset+7*n*r.interval)
+		periodStart = weekStart
+		weekdays := []time.Weekday{dtStart.Weekday()}
+		if len(r.byDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, wd := range r.byDay {
+				weekdays = append(weekdays, wd.weekday)
+			}
+		}
+		for _, weekday := range weekdays {
+			candidate := weekStart.AddDate(0, 0, (int(weekday)-int(r.weekStart)+7)%7)
+			if r.matchesByMonth(candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	case freqMonthly:
+		monthStart := at(year, month+time.Month(n*r.interval), 1)
+		periodStart = monthStart
+		if r.matchesByMonth(monthStart) {
+			candidates = r.monthCandidates(monthStart, day)
+		}
+	case freqYearly:
+		periodStart = at(year+n*r.interval, 1, 1)
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []time.Month{month}
+		}
+		for _, m := range months {
+			candidates = append(candidates, r.monthCandidates(at(year+n*r.interval, m, 1), day)...)
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b time.Time) int { return a.Compare(b) })
+	candidates = slices.CompactFunc(candidates, func(a, b time.Time) bool { return a.Equal(b) })
+
+	return candidates, periodStart
+}
+
+// monthCandidates returns the instance starts within the month beginning at
+// monthStart; defaultDay is used when the rule doesn't specify days.
+func (r rrule) monthCandidates(monthStart time.Time, defaultDay int) []time.Time {
+	var candidates []time.Time
+	daysInMonth := monthStart.AddDate(0, 1, -1).Day()
+
+	if len(r.byMonthDay) == 0 && len(r.byDay) == 0 {
+		// months without the day (eg. the 31st) are skipped, as per RFC 5545
+		if defaultDay <= daysInMonth {
+			candidates = append(candidates, monthStart.AddDate(0, 0, defaultDay-1))
+		}
+		return candidates
+	}
+
+	if len(r.byDay) == 0 {
+		for _, d := range r.byMonthDay {
+			if d < 0 {
+				d = daysInMonth + d + 1
+			}
+			if d >= 1 && d <= daysInMonth {
+				candidates = append(candidates, monthStart.AddDate(0, 0, d-1))
+			}
+		}
+		return candidates
+	}
+
+	for _, wd := range r.byDay {
+		var matches []time.Time
+		for d := 1; d <= daysInMonth; d++ {
+			candidate := monthStart.AddDate(0, 0, d-1)
+			if candidate.Weekday() == wd.weekday {
+				matches = append(matches, candidate)
+			}
+		}
+
+		switch {
+		case wd.ordinal == 0:
+			candidates = append(candidates, matches...)
+		case wd.ordinal > 0 && wd.ordinal <= len(matches):
+			candidates = append(candidates, matches[wd.ordinal-1])
+		case wd.ordinal < 0 && -wd.ordinal <= len(matches):
+			candidates = append(candidates, matches[len(matches)+wd.ordinal])
+		}
+	}
+
+	// when both are given, days need to satisfy both (eg. BYDAY=FR and
+	// BYMONTHDAY=13 make for Friday the 13th)
+	return slices.DeleteFunc(candidates, func(t time.Time) bool { return !r.matchesByMonthDay(t) })
+}
+
+func (r rrule) matchesByMonth(t time.Time) bool {
+	return len(r.byMonth) == 0 || slices.Contains(r.byMonth, t.Month())
+}
+
+func (r rrule) matchesByDay(t time.Time) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(r.byDay, func(wd rruleWeekday) bool { return wd.weekday == t.Weekday() })
+}
+
+func (r rrule) matchesByMonthDay(t time.Time) bool {
+	if len(r.byMonthDay) == 0 {
+		return true
+	}
+
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	return slices.ContainsFunc(r.byMonthDay, func(d int) bool {
+		if d < 0 {
+			d = daysInMonth + d + 1
+		}
+		return d == t.Day()
+	})
+}
@@ -0,0 +1,173 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRRuleOccurrences(t *testing.T) {
+	// Monday
+	dtStart := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		rule     string
+		expected []string
+	}{
+		{
+			name:     "daily with count",
+			rule:     "FREQ=DAILY;COUNT=3",
+			expected: []string{"2025-01-06", "2025-01-07", "2025-01-08"},
+		},
+		{
+			name:     "daily with until",
+			rule:     "FREQ=DAILY;INTERVAL=2;UNTIL=20250110T090000Z",
+			expected: []string{"2025-01-06", "2025-01-08", "2025-01-10"},
+		},
+		{
+			name:     "weekly on several days",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=4",
+			expected: []string{"2025-01-06", "2025-01-09", "2025-01-13", "2025-01-16"},
+		},
+		{
+			name:     "biweekly",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;COUNT=3",
+			expected: []string{"2025-01-06", "2025-01-20", "2025-02-03"},
+		},
+		{
+			name:     "monthly on the same day",
+			rule:     "FREQ=MONTHLY;COUNT=3",
+			expected: []string{"2025-01-06", "2025-02-06", "2025-03-06"},
+		},
+		{
+			name:     "monthly on the last friday",
+			rule:     "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			expected: []string{"2025-01-31", "2025-02-28", "2025-03-28"},
+		},
+		{
+			name:     "monthly on a day of the month",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=15,-1;COUNT=4",
+			expected: []string{"2025-01-15", "2025-01-31", "2025-02-15", "2025-02-28"},
+		},
+		{
+			name:     "yearly in several months",
+			rule:     "FREQ=YEARLY;BYMONTH=2,3",
+			expected: []string{"2025-02-06", "2025-03-06"},
+		},
+		{
+			name:     "bounded by end",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=1",
+			expected: []string{"2025-02-01", "2025-03-01"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseRRule(tt.rule, time.UTC)
+			require.NoError(t, err)
+
+			var got []string
+			for _, occurrence := range rule.occurrences(dtStart, end) {
+				assert.Equal(t, 9, occurrence.Hour())
+				got = append(got, occurrence.Format("2006-01-02"))
+			}
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestRRuleWithDaysOfTheWeekAndOfTheMonth(t *testing.T) {
+	// Monday
+	dtStart := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2032, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		rule     string
+		expected []string
+	}{
+		{
+			name:     "monthly on friday the 13th",
+			rule:     "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13;COUNT=3",
+			expected: []string{"2025-06-13", "2026-02-13", "2026-03-13"},
+		},
+		{
+			name:     "yearly on friday the 13th",
+			rule:     "FREQ=YEARLY;BYMONTH=6;BYDAY=FR;BYMONTHDAY=13",
+			expected: []string{"2025-06-13", "2031-06-13"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseRRule(tt.rule, time.UTC)
+			require.NoError(t, err)
+
+			var got []string
+			for _, occurrence := range rule.occurrences(dtStart, end) {
+				got = append(got, occurrence.Format("2006-01-02"))
+			}
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestRRuleSkipsMonthsWithoutTheDay(t *testing.T) {
+	// GIVEN
+	dtStart := time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC)
+	rule, err := parseRRule("FREQ=MONTHLY;COUNT=3", time.UTC)
+	require.NoError(t, err)
+
+	// WHEN
+	got := rule.occurrences(dtStart, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	// THEN
+	require.Len(t, got, 3)
+	assert.Equal(t, time.March, got[1].Month())
+	assert.Equal(t, time.May, got[2].Month())
+}
+
+func TestRRuleKeepsTheWallClockTimeAcrossDSTChanges(t *testing.T) {
+	// GIVEN
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	dtStart := time.Date(2025, time.March, 28, 9, 0, 0, 0, loc)
+	rule, err := parseRRule("FREQ=DAILY;COUNT=4", loc)
+	require.NoError(t, err)
+
+	// WHEN
+	got := rule.occurrences(dtStart, dtStart.AddDate(1, 0, 0))
+
+	// THEN
+	require.Len(t, got, 4)
+	for _, occurrence := range got {
+		assert.Equal(t, 9, occurrence.Hour())
+	}
+}
+
+func TestParseRRuleFails(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expectedErr error
+	}{
+		{name: "no frequency", input: "COUNT=3", expectedErr: errRRuleInvalid},
+		{name: "hourly", input: "FREQ=HOURLY", expectedErr: errRRuleUnsupported},
+		{name: "unsupported part", input: "FREQ=MONTHLY;BYSETPOS=-1", expectedErr: errRRuleUnsupported},
+		{name: "bad interval", input: "FREQ=DAILY;INTERVAL=0", expectedErr: errRRuleInvalid},
+		{name: "bad weekday", input: "FREQ=WEEKLY;BYDAY=XY", expectedErr: errRRuleInvalid},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseRRule(tt.input, time.UTC)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
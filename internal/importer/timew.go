@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const timewTimeFormat = "20060102T150405Z"
+
+var (
+	ErrIncorrectTimewSummarySourceProvided = errors.New("incorrect task summary source provided")
+
+	errCouldntDecodeTimewExport = errors.New("couldn't decode timewarrior export")
+	errIntervalHasNoTags        = errors.New("interval has no tags")
+	errIntervalHasNoAnnotation  = errors.New("interval has no annotation")
+)
+
+// TimewSummarySource determines what a Timewarrior interval's task summary is
+// derived from.
+type TimewSummarySource uint8
+
+const (
+	TSSValueFirstTag   = "first-tag"
+	TSSValueTags       = "tags"
+	TSSValueAnnotation = "annotation"
+)
+
+const (
+	TimewSummaryFromFirstTag TimewSummarySource = iota
+	TimewSummaryFromTags
+	TimewSummaryFromAnnotation
+)
+
+func ParseTimewSummarySource(value string) (TimewSummarySource, error) {
+	switch value {
+	case TSSValueFirstTag:
+		return TimewSummaryFromFirstTag, nil
+	case TSSValueTags:
+		return TimewSummaryFromTags, nil
+	case TSSValueAnnotation:
+		return TimewSummaryFromAnnotation, nil
+	default:
+		return TimewSummaryFromFirstTag, ErrIncorrectTimewSummarySourceProvided
+	}
+}
+
+var ValidTimewSummarySourceValues = []string{TSSValueFirstTag, TSSValueTags, TSSValueAnnotation}
+
+type timewInterval struct {
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Tags       []string `json:"tags"`
+	Annotation string   `json:"annotation"`
+}
+
+// ParseTimew reads entries from the output of "timew export". An interval
+// without an end becomes an active entry. The annotation of an interval
+// becomes the comment of its entry, unless it's used as the task summary.
+// Intervals are numbered by their position in the export, starting at 1.
+func ParseTimew(reader io.Reader, summarySource TimewSummarySource) ([]Entry, []RowError, error) {
+	var intervals []timewInterval
+	err := json.NewDecoder(reader).Decode(&intervals)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errCouldntDecodeTimewExport, err.Error())
+	}
+
+	var entries []Entry
+	var rowErrors []RowError
+	for i, interval := range intervals {
+		entry, err := parseTimewInterval(interval, summarySource)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{i + 1, err})
+			continue
+		}
+		entry.Row = i + 1
+		entries = append(entries, entry)
+	}
+
+	return entries, rowErrors, nil
+}
+
+func parseTimewInterval(interval timewInterval, summarySource TimewSummarySource) (Entry, error) {
+	var entry Entry
+
+	beginTS, err := time.Parse(timewTimeFormat, interval.Start)
+	if err != nil {
+		return entry, fmt.Errorf("%w: %q", errBeginTSUnparseable, interval.Start)
+	}
+	entry.BeginTS = beginTS.Local()
+
+	if interval.End == "" {
+		entry.Active = true
+	} else {
+		endTS, err := time.Parse(timewTimeFormat, interval.End)
+		if err != nil {
+			return entry, fmt.Errorf("%w: %q", errEndTSUnparseable, interval.End)
+		}
+		entry.EndTS = endTS.Local()
+	}
+
+	annotation := strings.TrimSpace(interval.Annotation)
+
+	switch summarySource {
+	case TimewSummaryFromTags:
+		if len(interval.Tags) == 0 {
+			return entry, errIntervalHasNoTags
+		}
+		entry.TaskSummary = strings.Join(interval.Tags, " ")
+	case TimewSummaryFromAnnotation:
+		if annotation == "" {
+			return entry, errIntervalHasNoAnnotation
+		}
+		entry.TaskSummary = annotation
+		return entry, nil
+	default:
+		if len(interval.Tags) == 0 {
+			return entry, errIntervalHasNoTags
+		}
+		entry.TaskSummary = interval.Tags[0]
+	}
+
+	if annotation != "" {
+		entry.Comment = &annotation
+	}
+
+	return entry, nil
+}
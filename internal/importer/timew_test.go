@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const timewExport = `[
+{"id":4,"start":"20251024T080000Z","end":"20251024T093000Z","tags":["hours","review"],"annotation":"look at PRs"},
+{"id":3,"start":"20251024T093000Z","end":"20251024T100000Z","tags":["blog"]},
+{"id":2,"start":"20251024T100000Z","end":"20251024T103000Z"},
+{"id":1,"start":"20251024T110000Z","tags":["hours"],"annotation":"fix bug"}
+]`
+
+func TestParseTimewWithFirstTag(t *testing.T) {
+	// GIVEN
+	// WHEN
+	entries, rowErrors, err := ParseTimew(strings.NewReader(timewExport), TimewSummaryFromFirstTag)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, 1, entries[0].Row)
+	assert.Equal(t, "hours", entries[0].TaskSummary)
+	assert.True(t, time.Date(2025, time.October, 24, 8, 0, 0, 0, time.UTC).Equal(entries[0].BeginTS))
+	assert.True(t, time.Date(2025, time.October, 24, 9, 30, 0, 0, time.UTC).Equal(entries[0].EndTS))
+	assert.False(t, entries[0].Active)
+	require.NotNil(t, entries[0].Comment)
+	assert.Equal(t, "look at PRs", *entries[0].Comment)
+
+	assert.Equal(t, "blog", entries[1].TaskSummary)
+	assert.Nil(t, entries[1].Comment)
+
+	assert.Equal(t, 4, entries[2].Row)
+	assert.True(t, entries[2].Active)
+	assert.True(t, entries[2].EndTS.IsZero())
+
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, 3, rowErrors[0].Row)
+	assert.ErrorIs(t, rowErrors[0].Err, errIntervalHasNoTags)
+}
+
+func TestParseTimewWithTags(t *testing.T) {
+	// GIVEN
+	// WHEN
+	entries, _, err := ParseTimew(strings.NewReader(timewExport), TimewSummaryFromTags)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "hours review", entries[0].TaskSummary)
+}
+
+func TestParseTimewWithAnnotation(t *testing.T) {
+	// GIVEN
+	// WHEN
+	entries, rowErrors, err := ParseTimew(strings.NewReader(timewExport), TimewSummaryFromAnnotation)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "look at PRs", entries[0].TaskSummary)
+	assert.Nil(t, entries[0].Comment)
+	assert.Equal(t, "fix bug", entries[1].TaskSummary)
+
+	require.Len(t, rowErrors, 2)
+	assert.ErrorIs(t, rowErrors[0].Err, errIntervalHasNoAnnotation)
+	assert.ErrorIs(t, rowErrors[1].Err, errIntervalHasNoAnnotation)
+}
+
+func TestParseTimewFailsForUnparseableTimestamps(t *testing.T) {
+	// GIVEN
+	input := `[{"start":"2025-10-24 08:00","end":"20251024T093000Z","tags":["hours"]}]`
+
+	// WHEN
+	entries, rowErrors, err := ParseTimew(strings.NewReader(input), TimewSummaryFromFirstTag)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	require.Len(t, rowErrors, 1)
+	assert.ErrorIs(t, rowErrors[0].Err, errBeginTSUnparseable)
+}
+
+func TestParseTimewFailsForInvalidJSON(t *testing.T) {
+	// GIVEN
+	// WHEN
+	_, _, err := ParseTimew(strings.NewReader(`{"start":`), TimewSummaryFromFirstTag)
+
+	// THEN
+	assert.ErrorIs(t, err, errCouldntDecodeTimewExport)
+}
+
+func TestParseTimewSummarySource(t *testing.T) {
+	for _, value := range ValidTimewSummarySourceValues {
+		_, err := ParseTimewSummarySource(value)
+		assert.NoError(t, err, value)
+	}
+
+	_, err := ParseTimewSummarySource("tag")
+	assert.ErrorIs(t, err, ErrIncorrectTimewSummarySourceProvided)
+}
@@ -0,0 +1,329 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	togglDateFormat = "2006-01-02"
+	togglTimeFormat = "15:04:05"
+)
+
+var (
+	errCouldntDecodeTogglJSON     = errors.New("couldn't decode Toggl JSON export")
+	errTogglJSONShapeUnknown      = errors.New("expected a list of time entries, or an object with a \"data\" list")
+	errTogglEntryHasNoSummary     = errors.New("entry has neither a project nor a description")
+	errTogglEntryIsRunning        = errors.New("entry is still running")
+	errTogglDurationUnparseable   = errors.New("couldn't parse duration")
+	errTogglEntryHasNoEndOrDur    = errors.New("entry has neither an end nor a duration")
+	errTogglCSVHasNoEndOrDuration = errors.New("CSV has neither end nor duration columns")
+)
+
+// togglSummary maps a Toggl entry's project and description to a task
+// summary.
+func togglSummary(project, description string) (string, error) {
+	project = strings.TrimSpace(project)
+	description = strings.TrimSpace(description)
+
+	switch {
+	case project != "" && description != "":
+		return fmt.Sprintf("%s: %s", project, description), nil
+	case project != "":
+		return project, nil
+	case description != "":
+		return description, nil
+	default:
+		return "", errTogglEntryHasNoSummary
+	}
+}
+
+func newTogglEntry(project, description string, beginTS, endTS time.Time, externalID string) (Entry, error) {
+	var entry Entry
+
+	summary, err := togglSummary(project, description)
+	if err != nil {
+		return entry, err
+	}
+
+	entry.TaskSummary = summary
+	entry.BeginTS = beginTS
+	entry.EndTS = endTS
+	entry.ExternalID = externalID
+
+	description = strings.TrimSpace(description)
+	if description != "" {
+		entry.Comment = &description
+	}
+
+	return entry, nil
+}
+
+type togglCSVColumns struct {
+	email       int
+	project     int
+	description int
+	startDate   int
+	startTime   int
+	endDate     int
+	endTime     int
+	duration    int
+}
+
+// ParseTogglCSV reads entries from a CSV export of Toggl Track's detailed
+// report. Timestamps are read in the local timezone. Toggl's CSV exports don't
+// include IDs, so entries are identified by their user, start, project, and
+// description.
+func ParseTogglCSV(reader io.Reader) ([]Entry, []RowError, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errCSVIsEmpty
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errCouldntReadCSV, err.Error())
+	}
+
+	columns := togglCSVColumns{
+		email:       findColumn(header, "Email"),
+		project:     findColumn(header, "Project"),
+		description: findColumn(header, "Description"),
+		startDate:   findColumn(header, "Start date"),
+		startTime:   findColumn(header, "Start time"),
+		endDate:     findFirstColumn(header, "End date", "Stop date"),
+		endTime:     findFirstColumn(header, "End time", "Stop time"),
+		duration:    findColumn(header, "Duration"),
+	}
+
+	for _, column := range []struct {
+		name  string
+		index int
+	}{
+		{"Project", columns.project},
+		{"Description", columns.description},
+		{"Start date", columns.startDate},
+		{"Start time", columns.startTime},
+	} {
+		if column.index == -1 {
+			return nil, nil, fmt.Errorf("%w: %q", errColumnNotFound, column.name)
+		}
+	}
+
+	hasEnd := columns.endDate != -1 && columns.endTime != -1
+	if !hasEnd && columns.duration == -1 {
+		return nil, nil, errTogglCSVHasNoEndOrDuration
+	}
+
+	var entries []Entry
+	var rowErrors []RowError
+	row := 1
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row++
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", errCouldntReadCSV, err.Error())
+		}
+
+		entry, err := parseTogglRecord(record, columns)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{row, err})
+			continue
+		}
+		entry.Row = row
+		entries = append(entries, entry)
+	}
+
+	return entries, rowErrors, nil
+}
+
+func findFirstColumn(header []string, names ...string) int {
+	for _, name := range names {
+		if index := findColumn(header, name); index != -1 {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func parseTogglRecord(record []string, columns togglCSVColumns) (Entry, error) {
+	field := func(index int) string {
+		if index == -1 || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	var zero Entry
+
+	beginStr := fmt.Sprintf("%s %s", field(columns.startDate), field(columns.startTime))
+	beginTS, err := time.ParseInLocation(togglDateFormat+" "+togglTimeFormat, beginStr, time.Local)
+	if err != nil {
+		return zero, fmt.Errorf("%w: %q", errBeginTSUnparseable, beginStr)
+	}
+
+	var endTS time.Time
+	endDate, endTime := field(columns.endDate), field(columns.endTime)
+	durationStr := field(columns.duration)
+	switch {
+	case endDate != "" && endTime != "":
+		endStr := fmt.Sprintf("%s %s", endDate, endTime)
+		endTS, err = time.ParseInLocation(togglDateFormat+" "+togglTimeFormat, endStr, time.Local)
+		if err != nil {
+			return zero, fmt.Errorf("%w: %q", errEndTSUnparseable, endStr)
+		}
+	case durationStr != "":
+		duration, err := parseTogglDuration(durationStr)
+		if err != nil {
+			return zero, err
+		}
+		endTS = beginTS.Add(duration)
+	default:
+		return zero, errTogglEntryHasNoEndOrDur
+	}
+
+	project, description := field(columns.project), field(columns.description)
+	externalID := hashFields(field(columns.email), beginStr, project, description)
+
+	return newTogglEntry(project, description, beginTS, endTS, externalID)
+}
+
+// parseTogglDuration parses durations in the "HH:MM:SS" format; the number of
+// hours can exceed 24.
+func parseTogglDuration(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("%w: %q", errTogglDurationUnparseable, value)
+	}
+
+	var values [3]int
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("%w: %q", errTogglDurationUnparseable, value)
+		}
+		values[i] = v
+	}
+
+	return time.Duration(values[0])*time.Hour +
+		time.Duration(values[1])*time.Minute +
+		time.Duration(values[2])*time.Second, nil
+}
+
+func hashFields(fields ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:16])
+}
+
+type togglJSONEntry struct {
+	ID          json.Number `json:"id"`
+	Project     string      `json:"project"`
+	ProjectName string      `json:"project_name"`
+	Description string      `json:"description"`
+	Start       string      `json:"start"`
+	End         string      `json:"end"`
+	Stop        string      `json:"stop"`
+	// Dur is in milliseconds, and is used by the detailed report
+	Dur *int64 `json:"dur"`
+	// Duration is in seconds, and is negative for running entries
+	Duration *int64 `json:"duration"`
+}
+
+// ParseTogglJSON reads entries from a JSON export of Toggl Track's detailed
+// report (an object with a "data" list of time entries), or a plain list of
+// time entries. Entries are identified by their Toggl IDs.
+func ParseTogglJSON(reader io.Reader) ([]Entry, []RowError, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errCouldntDecodeTogglJSON, err.Error())
+	}
+
+	var togglEntries []togglJSONEntry
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		err = json.Unmarshal(trimmed, &togglEntries)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var report struct {
+			Data []togglJSONEntry `json:"data"`
+		}
+		err = json.Unmarshal(trimmed, &report)
+		togglEntries = report.Data
+	default:
+		return nil, nil, fmt.Errorf("%w: %w", errCouldntDecodeTogglJSON, errTogglJSONShapeUnknown)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errCouldntDecodeTogglJSON, err.Error())
+	}
+
+	var entries []Entry
+	var rowErrors []RowError
+	for i, togglEntry := range togglEntries {
+		entry, err := parseTogglJSONEntry(togglEntry)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{i + 1, err})
+			continue
+		}
+		entry.Row = i + 1
+		entries = append(entries, entry)
+	}
+
+	return entries, rowErrors, nil
+}
+
+func parseTogglJSONEntry(togglEntry togglJSONEntry) (Entry, error) {
+	var zero Entry
+
+	beginTS, err := time.Parse(time.RFC3339, togglEntry.Start)
+	if err != nil {
+		return zero, fmt.Errorf("%w: %q", errBeginTSUnparseable, togglEntry.Start)
+	}
+
+	var endTS time.Time
+	endStr := togglEntry.End
+	if endStr == "" {
+		endStr = togglEntry.Stop
+	}
+
+	switch {
+	case endStr != "":
+		endTS, err = time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			return zero, fmt.Errorf("%w: %q", errEndTSUnparseable, endStr)
+		}
+	case togglEntry.Duration != nil && *togglEntry.Duration < 0:
+		return zero, errTogglEntryIsRunning
+	case togglEntry.Duration != nil:
+		endTS = beginTS.Add(time.Duration(*togglEntry.Duration) * time.Second)
+	case togglEntry.Dur != nil:
+		endTS = beginTS.Add(time.Duration(*togglEntry.Dur) * time.Millisecond)
+	default:
+		return zero, errTogglEntryHasNoEndOrDur
+	}
+
+	project := togglEntry.Project
+	if project == "" {
+		project = togglEntry.ProjectName
+	}
+
+	externalID := togglEntry.ID.String()
+	if externalID == "" {
+		externalID = hashFields(togglEntry.Start, project, togglEntry.Description)
+	}
+
+	return newTogglEntry(project, togglEntry.Description, beginTS.Local(), endTS.Local(), externalID)
+}
@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTogglCSV(t *testing.T) {
+	// GIVEN
+	input := "\ufeff" + `User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount (USD)
+jane,jane@example.com,,hours,,review PRs,No,2025-10-24,09:00:00,2025-10-24,10:30:00,01:30:00,,
+jane,jane@example.com,,hours,,,No,2025-10-24,11:00:00,,,00:45:00,,
+jane,jane@example.com,,,,,No,2025-10-24,12:00:00,2025-10-24,12:30:00,00:30:00,,
+jane,jane@example.com,,blog,,draft,No,2025-10-24,13:00:00,,,45m,,
+`
+
+	// WHEN
+	entries, rowErrors, err := ParseTogglCSV(strings.NewReader(input))
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, 2, entries[0].Row)
+	assert.Equal(t, "hours: review PRs", entries[0].TaskSummary)
+	assert.Equal(t, time.Date(2025, time.October, 24, 9, 0, 0, 0, time.Local), entries[0].BeginTS)
+	assert.Equal(t, time.Date(2025, time.October, 24, 10, 30, 0, 0, time.Local), entries[0].EndTS)
+	require.NotNil(t, entries[0].Comment)
+	assert.Equal(t, "review PRs", *entries[0].Comment)
+	assert.NotEmpty(t, entries[0].ExternalID)
+
+	assert.Equal(t, "hours", entries[1].TaskSummary)
+	assert.Equal(t, time.Date(2025, time.October, 24, 11, 45, 0, 0, time.Local), entries[1].EndTS)
+	assert.Nil(t, entries[1].Comment)
+	assert.NotEqual(t, entries[0].ExternalID, entries[1].ExternalID)
+
+	require.Len(t, rowErrors, 2)
+	assert.Equal(t, 4, rowErrors[0].Row)
+	assert.ErrorIs(t, rowErrors[0].Err, errTogglEntryHasNoSummary)
+	assert.Equal(t, 5, rowErrors[1].Row)
+	assert.ErrorIs(t, rowErrors[1].Err, errTogglDurationUnparseable)
+}
+
+func TestParseTogglCSVExternalIDsAreStable(t *testing.T) {
+	// GIVEN
+	input := `Email,Project,Description,Start date,Start time,End date,End time
+jane@example.com,hours,review PRs,2025-10-24,09:00:00,2025-10-24,10:30:00
+`
+
+	// WHEN
+	entries, _, err := ParseTogglCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	entriesAgain, _, errAgain := ParseTogglCSV(strings.NewReader(input))
+	require.NoError(t, errAgain)
+
+	// THEN
+	require.Len(t, entries, 1)
+	require.Len(t, entriesAgain, 1)
+	assert.Equal(t, entries[0].ExternalID, entriesAgain[0].ExternalID)
+}
+
+func TestParseTogglCSVFailsWithoutEndOrDuration(t *testing.T) {
+	// GIVEN
+	input := "Project,Description,Start date,Start time\n"
+
+	// WHEN
+	_, _, err := ParseTogglCSV(strings.NewReader(input))
+
+	// THEN
+	assert.ErrorIs(t, err, errTogglCSVHasNoEndOrDuration)
+}
+
+func TestParseTogglJSONDetailedReport(t *testing.T) {
+	// GIVEN
+	input := `{"total_count": 2, "data": [
+{"id": 101, "project": "hours", "description": "review PRs", "start": "2025-10-24T09:00:00+02:00", "end": "2025-10-24T10:30:00+02:00", "dur": 5400000},
+{"id": 102, "project": "blog", "description": "", "start": "2025-10-24T11:00:00+02:00", "dur": 1800000}
+]}`
+
+	// WHEN
+	entries, rowErrors, err := ParseTogglJSON(strings.NewReader(input))
+
+	// THEN
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "hours: review PRs", entries[0].TaskSummary)
+	assert.Equal(t, "101", entries[0].ExternalID)
+	assert.True(t, time.Date(2025, time.October, 24, 7, 0, 0, 0, time.UTC).Equal(entries[0].BeginTS))
+	assert.True(t, time.Date(2025, time.October, 24, 8, 30, 0, 0, time.UTC).Equal(entries[0].EndTS))
+
+	assert.Equal(t, "blog", entries[1].TaskSummary)
+	assert.Equal(t, "102", entries[1].ExternalID)
+	assert.True(t, time.Date(2025, time.October, 24, 9, 30, 0, 0, time.UTC).Equal(entries[1].EndTS))
+}
+
+func TestParseTogglJSONTimeEntries(t *testing.T) {
+	// GIVEN
+	input := `[
+{"id": 201, "project_name": "hours", "description": "fix bug", "start": "2025-10-24T09:00:00Z", "stop": null, "duration": 3600},
+{"id": 202, "project_name": "hours", "description": "ongoing", "start": "2025-10-24T11:00:00Z", "duration": -1761296400}
+]`
+
+	// WHEN
+	entries, rowErrors, err := ParseTogglJSON(strings.NewReader(input))
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "hours: fix bug", entries[0].TaskSummary)
+	assert.True(t, time.Date(2025, time.October, 24, 10, 0, 0, 0, time.UTC).Equal(entries[0].EndTS))
+
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, 2, rowErrors[0].Row)
+	assert.ErrorIs(t, rowErrors[0].Err, errTogglEntryIsRunning)
+}
+
+func TestParseTogglJSONFailsForUnknownShape(t *testing.T) {
+	// GIVEN
+	// WHEN
+	_, _, err := ParseTogglJSON(strings.NewReader(`"time entries"`))
+
+	// THEN
+	assert.ErrorIs(t, err, errTogglJSONShapeUnknown)
+}
+
+func TestParseTogglDuration(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"00:00:30", 30 * time.Second, true},
+		{"01:30:00", 90 * time.Minute, true},
+		{"26:00:00", 26 * time.Hour, true},
+		{"1:30", 0, false},
+		{"01:xx:00", 0, false},
+		{"-01:00:00", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseTogglDuration(tc.input)
+			if tc.ok {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, got)
+			} else {
+				assert.ErrorIs(t, err, errTogglDurationUnparseable)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrCouldntBackUpDB      = errors.New("couldn't back up database")
+	ErrNotAnHoursDB         = errors.New("file is not an hours database")
+	ErrDBIsFromNewerVersion = errors.New("database was created by a newer version of hours")
+	ErrDBIsCorrupted        = errors.New("database failed integrity check")
+)
+
+// BackupDB writes a consistent snapshot of the database to path. It's safe to
+// call while other connections are writing to the database. path must not
+// exist already.
+func BackupDB(db *sql.DB, path string) error {
+	_, err := db.Exec(`VACUUM INTO ?;`, path)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCouldntBackUpDB, err.Error())
+	}
+
+	return nil
+}
+
+// CheckRestorable verifies that the database at path was created by hours,
+// can be opened (and upgraded if needed) by this version of hours, and isn't
+// corrupted. It returns the database's version.
+func CheckRestorable(path string) (int, error) {
+	db, err := GetDB(path)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	versionInfo, err := fetchLatestDBVersion(db)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrNotAnHoursDB, err.Error())
+	}
+
+	if versionInfo.version > latestDBVersion {
+		return 0, fmt.Errorf(
+			"%w (version %d; this version of hours supports versions up to %d)",
+			ErrDBIsFromNewerVersion,
+			versionInfo.version,
+			latestDBVersion,
+		)
+	}
+
+	problems, err := checkIntegrity(db)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrDBIsCorrupted, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return 0, fmt.Errorf("%w: %s", ErrDBIsCorrupted, strings.Join(problems, "; "))
+	}
+
+	return versionInfo.version, nil
+}
@@ -0,0 +1,114 @@
+package persistence
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getFileDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+
+	db, err := GetDB(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	require.NoError(t, InitDB(db))
+	require.NoError(t, UpgradeNewDB(db))
+
+	return db
+}
+
+func TestBackupDB(t *testing.T) {
+	// GIVEN
+	dir := t.TempDir()
+	db := getFileDB(t, filepath.Join(dir, "hours.db"))
+	_, err := InsertTask(db, "backed up task")
+	require.NoError(t, err)
+	backupPath := filepath.Join(dir, "backup.db")
+
+	// WHEN
+	err = BackupDB(db, backupPath)
+
+	// THEN
+	require.NoError(t, err)
+
+	version, err := CheckRestorable(backupPath)
+	require.NoError(t, err)
+	assert.Equal(t, latestDBVersion, version)
+
+	backupDB, err := GetDB(backupPath)
+	require.NoError(t, err)
+	defer backupDB.Close()
+	tasks, err := FetchTasks(backupDB, true, 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "backed up task", tasks[0].Summary)
+}
+
+func TestBackupDBFailsIfTargetExists(t *testing.T) {
+	// GIVEN
+	dir := t.TempDir()
+	db := getFileDB(t, filepath.Join(dir, "hours.db"))
+	backupPath := filepath.Join(dir, "backup.db")
+	require.NoError(t, BackupDB(db, backupPath))
+
+	// WHEN
+	err := BackupDB(db, backupPath)
+
+	// THEN
+	assert.ErrorIs(t, err, ErrCouldntBackUpDB)
+}
+
+func TestCheckRestorable(t *testing.T) {
+	t.Run("older versions are restorable", func(t *testing.T) {
+		// GIVEN
+		path := filepath.Join(t.TempDir(), "old.db")
+		db, err := GetDB(path)
+		require.NoError(t, err)
+		require.NoError(t, InitDB(db))
+		require.NoError(t, db.Close())
+
+		// WHEN
+		version, err := CheckRestorable(path)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 1, version)
+	})
+
+	t.Run("newer versions are rejected", func(t *testing.T) {
+		// GIVEN
+		path := filepath.Join(t.TempDir(), "newer.db")
+		db := getFileDB(t, path)
+		_, err := db.Exec(`INSERT INTO db_versions (version, created_at) VALUES (?, ?);`,
+			latestDBVersion+1, time.Now().UTC().Add(time.Minute))
+		require.NoError(t, err)
+
+		// WHEN
+		_, err = CheckRestorable(path)
+
+		// THEN
+		assert.ErrorIs(t, err, ErrDBIsFromNewerVersion)
+	})
+
+	t.Run("other sqlite databases are rejected", func(t *testing.T) {
+		// GIVEN
+		path := filepath.Join(t.TempDir(), "other.db")
+		db, err := GetDB(path)
+		require.NoError(t, err)
+		_, err = db.Exec(`CREATE TABLE notes (id INTEGER PRIMARY KEY);`)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		// WHEN
+		_, err = CheckRestorable(path)
+
+		// THEN
+		assert.ErrorIs(t, err, ErrNotAnHoursDB)
+	})
+}
@@ -0,0 +1,53 @@
+package persistence
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/types"
+)
+
+// SetTaskBudget sets the time budget of a task; a nil budget unsets it.
+func SetTaskBudget(db *sql.DB, taskID int, budgetSecs *int) error {
+	_, err := db.Exec(`
+UPDATE task
+SET budget_secs = ?,
+    updated_at = ?
+WHERE id = ?;
+`, budgetSecs, time.Now().UTC(), taskID)
+
+	return err
+}
+
+// FetchTasksWithBudget returns the tasks that have a budget, and that a filter
+// lets in.
+func FetchTasksWithBudget(db *sql.DB, filter types.TaskFilter, limit int) ([]domain.Task, error) {
+	filterSQL, filterArgs := taskFilterSQL(filter, "AND")
+
+	args := append(filterArgs, limit)
+	rows, err := db.Query(`
+SELECT `+taskColumns+`
+FROM task t
+WHERE t.budget_secs IS NOT NULL
+AND t.deleted_at IS NULL`+filterSQL+`
+ORDER BY t.updated_at DESC
+LIMIT ?;
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addTaskDetails(db, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
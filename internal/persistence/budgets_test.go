@@ -0,0 +1,45 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgets(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+
+	taskID, err := InsertTaskWithDetails(db, "fix login bug", UnassignedProjectID, []string{"work"})
+	require.NoError(t, err)
+	otherTaskID, err := InsertTaskWithDetails(db, "write docs", UnassignedProjectID, nil)
+	require.NoError(t, err)
+	_, err = InsertTaskWithDetails(db, "team lunch", UnassignedProjectID, nil)
+	require.NoError(t, err)
+
+	// WHEN
+	budget := 36000
+	err = SetTaskBudget(db, taskID, &budget)
+	require.NoError(t, err)
+	otherBudget := 3600
+	err = SetTaskBudget(db, otherTaskID, &otherBudget)
+	require.NoError(t, err)
+	err = SetTaskBudget(db, otherTaskID, nil)
+	require.NoError(t, err)
+
+	// THEN
+	tasks, err := FetchTasksWithBudget(db, types.TaskFilter{Status: types.TaskStatusAny}, 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, taskID, tasks[0].ID)
+	require.NotNil(t, tasks[0].BudgetSecs)
+	assert.Equal(t, budget, *tasks[0].BudgetSecs)
+	assert.Equal(t, []string{"work"}, tasks[0].Tags)
+
+	tasks, err = FetchTasksWithBudget(db, types.TaskFilter{Status: types.TaskStatusAny, ExcludeTags: []string{"work"}}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
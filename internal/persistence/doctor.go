@@ -0,0 +1,248 @@
+package persistence
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OrphanedTLsTaskSummary is the summary of the task orphaned task logs are
+// moved to when repairing the database.
+const OrphanedTLsTaskSummary = "orphaned task log entries"
+
+var (
+	ErrCouldntDiagnoseDB = errors.New("couldn't check database")
+	ErrCouldntRepairDB   = errors.New("couldn't repair database")
+)
+
+// TaskRollupDrift is a task whose total time spent (which is maintained
+// separately) doesn't match the sum of its task logs.
+type TaskRollupDrift struct {
+	TaskID       int
+	TaskSummary  string
+	RecordedSecs int
+	ActualSecs   int
+}
+
+type DiagnosedTL struct {
+	ID      int
+	TaskID  *int
+	BeginTS time.Time
+	EndTS   *time.Time
+	Active  bool
+}
+
+type DBDiagnosis struct {
+	// IntegrityErrors holds the messages reported by SQLite's integrity check
+	IntegrityErrors []string
+	RollupDrifts    []TaskRollupDrift
+	// OrphanedTLs are task logs without a task, or with one that doesn't exist
+	OrphanedTLs []DiagnosedTL
+	// InvalidTLs are saved task logs that don't end after they begin
+	InvalidTLs []DiagnosedTL
+	// ActiveTLs is only a problem if there's more than one of them
+	ActiveTLs []DiagnosedTL
+}
+
+type RepairResult struct {
+	NumTasksRecomputed int
+	NumTLsReassigned   int
+	// OrphanedTLsTaskID is the ID of the task orphaned task logs were moved to
+	OrphanedTLsTaskID int
+}
+
+// DiagnoseDB checks the database for problems; it doesn't change anything.
+func DiagnoseDB(db *sql.DB) (DBDiagnosis, error) {
+	var diagnosis DBDiagnosis
+	var err error
+
+	diagnosis.IntegrityErrors, err = checkIntegrity(db)
+	if err != nil {
+		return diagnosis, fmt.Errorf("%w: %s", ErrCouldntDiagnoseDB, err.Error())
+	}
+
+	diagnosis.RollupDrifts, err = fetchTaskRollupDrifts(db)
+	if err != nil {
+		return diagnosis, fmt.Errorf("%w: %s", ErrCouldntDiagnoseDB, err.Error())
+	}
+
+	diagnosis.OrphanedTLs, err = fetchDiagnosedTLs(db, `
+SELECT tl.id, tl.task_id, tl.begin_ts, tl.end_ts, tl.active
+FROM task_log tl
+LEFT JOIN task t ON tl.task_id = t.id
+WHERE t.id IS NULL
+ORDER BY tl.id;
+`)
+	if err != nil {
+		return diagnosis, fmt.Errorf("%w: %s", ErrCouldntDiagnoseDB, err.Error())
+	}
+
+	diagnosis.InvalidTLs, err = fetchDiagnosedTLs(db, `
+SELECT id, task_id, begin_ts, end_ts, active
+FROM task_log
+WHERE active = 0
+AND (end_ts IS NULL OR end_ts <= begin_ts)
+ORDER BY id;
+`)
+	if err != nil {
+		return diagnosis, fmt.Errorf("%w: %s", ErrCouldntDiagnoseDB, err.Error())
+	}
+
+	diagnosis.ActiveTLs, err = fetchDiagnosedTLs(db, `
+SELECT id, task_id, begin_ts, end_ts, active
+FROM task_log
+WHERE active = 1
+ORDER BY begin_ts;
+`)
+	if err != nil {
+		return diagnosis, fmt.Errorf("%w: %s", ErrCouldntDiagnoseDB, err.Error())
+	}
+
+	return diagnosis, nil
+}
+
+// RepairDB fixes the problems in a diagnosis that can be fixed without losing
+// data, in a single transaction: orphaned task logs are moved to a new task,
+// and the time spent on every task is recomputed from its task logs.
+func RepairDB(db *sql.DB, diagnosis DBDiagnosis) (RepairResult, error) {
+	result, err := runInTxAndReturnA(db, func(tx *sql.Tx) (RepairResult, error) {
+		var result RepairResult
+
+		if len(diagnosis.OrphanedTLs) > 0 {
+			taskID, err := insertTask(tx, OrphanedTLsTaskSummary)
+			if err != nil {
+				return result, err
+			}
+			result.OrphanedTLsTaskID = taskID
+
+			for _, tl := range diagnosis.OrphanedTLs {
+				_, err = tx.Exec(`
+UPDATE task_log
+SET task_id = ?
+WHERE id = ?;
+`, taskID, tl.ID)
+				if err != nil {
+					return result, err
+				}
+				result.NumTLsReassigned++
+			}
+		}
+
+		res, err := tx.Exec(`
+UPDATE task
+SET secs_spent = (
+    SELECT COALESCE(SUM(tl.secs_spent), 0)
+    FROM task_log tl
+    WHERE tl.task_id = task.id
+    AND tl.active = 0
+    AND tl.deleted_at IS NULL
+)
+WHERE secs_spent != (
+    SELECT COALESCE(SUM(tl.secs_spent), 0)
+    FROM task_log tl
+    WHERE tl.task_id = task.id
+    AND tl.active = 0
+    AND tl.deleted_at IS NULL
+);
+`)
+		if err != nil {
+			return result, err
+		}
+
+		numRecomputed, err := res.RowsAffected()
+		if err != nil {
+			return result, err
+		}
+		result.NumTasksRecomputed = int(numRecomputed)
+
+		return result, nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("%w: %s", ErrCouldntRepairDB, err.Error())
+	}
+
+	return result, nil
+}
+
+func checkIntegrity(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`PRAGMA integrity_check;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var result string
+		err = rows.Scan(&result)
+		if err != nil {
+			return nil, err
+		}
+
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+
+	return problems, rows.Err()
+}
+
+func fetchTaskRollupDrifts(db *sql.DB) ([]TaskRollupDrift, error) {
+	rows, err := db.Query(`
+SELECT t.id, t.summary, t.secs_spent, COALESCE(SUM(tl.secs_spent), 0) AS actual_secs
+FROM task t
+LEFT JOIN task_log tl ON tl.task_id = t.id AND tl.active = 0 AND tl.deleted_at IS NULL
+GROUP BY t.id
+HAVING t.secs_spent != actual_secs
+ORDER BY t.id;
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drifts []TaskRollupDrift
+	for rows.Next() {
+		var drift TaskRollupDrift
+		err = rows.Scan(&drift.TaskID, &drift.TaskSummary, &drift.RecordedSecs, &drift.ActualSecs)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, drift)
+	}
+
+	return drifts, rows.Err()
+}
+
+func fetchDiagnosedTLs(db *sql.DB, query string) ([]DiagnosedTL, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tls []DiagnosedTL
+	for rows.Next() {
+		var tl DiagnosedTL
+		var taskID sql.NullInt64
+		var endTS sql.NullTime
+		err = rows.Scan(&tl.ID, &taskID, &tl.BeginTS, &endTS, &tl.Active)
+		if err != nil {
+			return nil, err
+		}
+
+		if taskID.Valid {
+			id := int(taskID.Int64)
+			tl.TaskID = &id
+		}
+		tl.BeginTS = tl.BeginTS.Local()
+		if endTS.Valid {
+			end := endTS.Time.Local()
+			tl.EndTS = &end
+		}
+		tls = append(tls, tl)
+	}
+
+	return tls, rows.Err()
+}
@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseAndRepairDB(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	referenceTS := time.Date(2025, time.October, 20, 9, 0, 0, 0, time.UTC)
+
+	taskID, err := InsertTask(db, "task with drift")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "task without problems")
+	require.NoError(t, err)
+	_, err = InsertManualTL(db, taskID, referenceTS, referenceTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+	_, err = InsertManualTL(db, otherTaskID, referenceTS.Add(time.Hour), referenceTS.Add(2*time.Hour), nil, false)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`UPDATE task SET secs_spent = 10 WHERE id = ?;`, taskID)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+INSERT INTO task_log (task_id, begin_ts, end_ts, secs_spent, active)
+VALUES (999, ?, ?, 1800, false), (NULL, ?, ?, 600, false), (?, ?, ?, 0, false);
+`,
+		referenceTS.Add(3*time.Hour), referenceTS.Add(3*time.Hour+30*time.Minute),
+		referenceTS.Add(4*time.Hour), referenceTS.Add(4*time.Hour+10*time.Minute),
+		otherTaskID, referenceTS.Add(5*time.Hour), referenceTS.Add(5*time.Hour),
+	)
+	require.NoError(t, err)
+	_, err = InsertNewTL(db, taskID, referenceTS.Add(6*time.Hour), nil)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE task_log SET active = 1, end_ts = NULL WHERE id = 1;`)
+	require.NoError(t, err)
+
+	// WHEN
+	diagnosis, err := DiagnoseDB(db)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Empty(t, diagnosis.IntegrityErrors)
+
+	require.Len(t, diagnosis.RollupDrifts, 1)
+	assert.Equal(t, TaskRollupDrift{TaskID: taskID, TaskSummary: "task with drift", RecordedSecs: 10, ActualSecs: 0}, diagnosis.RollupDrifts[0])
+
+	require.Len(t, diagnosis.OrphanedTLs, 2)
+	require.NotNil(t, diagnosis.OrphanedTLs[0].TaskID)
+	assert.Equal(t, 999, *diagnosis.OrphanedTLs[0].TaskID)
+	assert.Nil(t, diagnosis.OrphanedTLs[1].TaskID)
+
+	require.Len(t, diagnosis.InvalidTLs, 1)
+	assert.Equal(t, 5, diagnosis.InvalidTLs[0].ID)
+
+	assert.Len(t, diagnosis.ActiveTLs, 2)
+
+	// WHEN
+	result, err := RepairDB(db, diagnosis)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NumTLsReassigned)
+	assert.Equal(t, 2, result.NumTasksRecomputed)
+
+	diagnosis, err = DiagnoseDB(db)
+	require.NoError(t, err)
+	assert.Empty(t, diagnosis.RollupDrifts)
+	assert.Empty(t, diagnosis.OrphanedTLs)
+
+	orphanedTask, err := fetchTaskByID(db, result.OrphanedTLsTaskID)
+	require.NoError(t, err)
+	assert.Equal(t, OrphanedTLsTaskSummary, orphanedTask.Summary)
+	assert.Equal(t, 2400, orphanedTask.SecsSpent)
+}
@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"database/sql"
+
+	"github.com/dhth/hours/internal/domain"
+)
+
+func FetchGoals(db *sql.DB) (domain.Goals, error) {
+	var goals domain.Goals
+
+	rows, err := db.Query(`
+SELECT kind, secs
+FROM goal;
+`)
+	if err != nil {
+		return goals, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind string
+		var secs int
+		err = rows.Scan(&kind, &secs)
+		if err != nil {
+			return goals, err
+		}
+
+		switch kind {
+		case domain.GoalKindDaily:
+			goals.DailySecs = &secs
+		case domain.GoalKindWeekly:
+			goals.WeeklySecs = &secs
+		}
+	}
+
+	return goals, rows.Err()
+}
+
+// SetGoal sets the daily or weekly goal; a nil goal unsets it.
+func SetGoal(db *sql.DB, kind string, secs *int) error {
+	if secs == nil {
+		_, err := db.Exec(`
+DELETE FROM goal
+WHERE kind = ?;
+`, kind)
+		return err
+	}
+
+	_, err := db.Exec(`
+INSERT INTO goal (kind, secs)
+VALUES (?, ?)
+ON CONFLICT (kind) DO UPDATE SET secs = excluded.secs;
+`, kind, *secs)
+
+	return err
+}
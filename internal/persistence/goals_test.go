@@ -0,0 +1,35 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoals(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+
+	goals, err := FetchGoals(db)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Goals{}, goals)
+
+	// WHEN
+	daily := 21600
+	weekly := 108000
+	require.NoError(t, SetGoal(db, domain.GoalKindDaily, &daily))
+	require.NoError(t, SetGoal(db, domain.GoalKindWeekly, &weekly))
+	updatedDaily := 25200
+	require.NoError(t, SetGoal(db, domain.GoalKindDaily, &updatedDaily))
+	require.NoError(t, SetGoal(db, domain.GoalKindWeekly, nil))
+
+	// THEN
+	goals, err = FetchGoals(db)
+	require.NoError(t, err)
+	require.NotNil(t, goals.DailySecs)
+	assert.Equal(t, updatedDaily, *goals.DailySecs)
+	assert.Nil(t, goals.WeeklySecs)
+}
@@ -4,17 +4,34 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
-const latestDBVersion = 1 // only upgrade this after adding a migration in getMigrations
+const latestDBVersion = 10 // only upgrade this after adding a migration in getMigrations
+
+const (
+	// BackupDirName is the directory, next to the database file, that backups
+	// are written to by default.
+	BackupDirName         = "hours-backups"
+	BackupTimestampFormat = "20060102T150405"
+)
 
 var (
 	ErrDBDowngraded          = errors.New("database downgraded")
 	ErrDBMigrationFailed     = errors.New("database migration failed")
 	ErrCouldntFetchDBVersion = errors.New("couldn't fetch version")
+
+	ErrCouldntBackUpBeforeMigration = errors.New("couldn't back up database before migrating it")
 )
 
+type Migration struct {
+	Version int
+	Query   string
+}
+
 type dbVersionInfo struct {
 	id        int
 	version   int
@@ -26,10 +43,120 @@ func getMigrations() map[int]string {
 	// these migrations should not be modified once released.
 	// that is, migrations is an append-only map.
 
-	// migrations[2] = `
-	// ALTER TABLE task
-	// ADD COLUMN new_col TEXT;
-	// `
+	// records entries imported from other sources, so that re-running an
+	// import can skip them
+	migrations[2] = `
+CREATE TABLE IF NOT EXISTS imported_entry (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    task_log_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE(source, external_id)
+);
+`
+
+	// tags let tasks be grouped (eg. by kind of work)
+	migrations[3] = `
+CREATE TABLE IF NOT EXISTS tag (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS task_tag (
+    task_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (task_id, tag_id),
+    FOREIGN KEY(task_id) REFERENCES task(id),
+    FOREIGN KEY(tag_id) REFERENCES tag(id)
+);
+`
+
+	// every task belongs to a project, and every project to a client; existing
+	// tasks are moved to an "unassigned" project (of an "unassigned" client)
+	migrations[4] = `
+CREATE TABLE IF NOT EXISTS client (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS project (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    client_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(client_id) REFERENCES client(id)
+);
+
+INSERT INTO client (id, name, created_at)
+VALUES (1, 'unassigned', CURRENT_TIMESTAMP);
+
+INSERT INTO project (id, name, client_id, created_at)
+VALUES (1, 'unassigned', 1, CURRENT_TIMESTAMP);
+
+ALTER TABLE task ADD COLUMN project_id INTEGER NOT NULL DEFAULT 1 REFERENCES project(id);
+`
+
+	// hourly rates (in hundredths of the currency's unit) are set on projects,
+	// and can be overridden for individual tasks; tasks are billable by default
+	migrations[5] = `
+ALTER TABLE project ADD COLUMN hourly_rate INTEGER;
+ALTER TABLE project ADD COLUMN currency TEXT;
+ALTER TABLE task ADD COLUMN hourly_rate INTEGER;
+ALTER TABLE task ADD COLUMN currency TEXT;
+ALTER TABLE task ADD COLUMN billable BOOLEAN NOT NULL DEFAULT true;
+`
+
+	// tasks can have a budget for the time to be spent on them
+	migrations[6] = `
+ALTER TABLE task ADD COLUMN budget_secs INTEGER;
+`
+
+	// daily and weekly goals for the time to be tracked
+	migrations[7] = `
+CREATE TABLE IF NOT EXISTS goal (
+    kind TEXT PRIMARY KEY,
+    secs INTEGER NOT NULL
+);
+`
+
+	// the periods during which task logs were paused; an active task log's
+	// current pause has no end timestamp yet
+	migrations[8] = `
+CREATE TABLE IF NOT EXISTS task_log_pause (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_log_id INTEGER NOT NULL,
+    begin_ts TIMESTAMP NOT NULL,
+    end_ts TIMESTAMP,
+    FOREIGN KEY(task_log_id) REFERENCES task_log(id)
+);
+`
+
+	// deleting tasks and task logs moves them to the trash, from where they can
+	// be restored or purged
+	migrations[9] = `
+ALTER TABLE task ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE task_log ADD COLUMN deleted_at TIMESTAMP;
+`
+
+	// the trigger added by InitDB rejected every insert into task_log while a
+	// task log was active; only inserting a second active task log needs to be
+	// rejected
+	migrations[10] = `
+DROP TRIGGER IF EXISTS prevent_duplicate_active_insert;
+
+CREATE TRIGGER prevent_duplicate_active_insert
+BEFORE INSERT ON task_log
+WHEN NEW.active = 1
+BEGIN
+    SELECT CASE
+        WHEN EXISTS (SELECT 1 FROM task_log WHERE active = 1)
+        THEN RAISE(ABORT, 'Only one row with active=1 is allowed')
+    END;
+END;
+`
 
 	return migrations
 }
@@ -77,8 +204,80 @@ func UpgradeDBIfNeeded(db *sql.DB) error {
 	return nil
 }
 
+// UpgradeDB runs all migrations after currentVersion. The database file is
+// backed up before it's changed.
 func UpgradeDB(db *sql.DB, currentVersion int) error {
+	if currentVersion >= latestDBVersion {
+		return nil
+	}
+
+	backupPath, err := backUpBeforeMigration(db, currentVersion)
+	if err != nil {
+		return err
+	}
+
+	err = runMigrations(db, currentVersion, getMigrations())
+	if err != nil && backupPath != "" {
+		return fmt.Errorf("%w; the database from before the migration was backed up to %s", err, backupPath)
+	}
+
+	return err
+}
+
+// UpgradeNewDB runs all migrations on a database that was just initialized;
+// there's nothing worth backing up in that case.
+func UpgradeNewDB(db *sql.DB) error {
+	return runMigrations(db, 1, getMigrations())
+}
+
+// PendingMigrations returns the database's current version, and the
+// migrations that need to be run to bring it to the latest version.
+func PendingMigrations(db *sql.DB) (int, []Migration, error) {
+	latestVersionInDB, err := fetchLatestDBVersion(db)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %s", ErrCouldntFetchDBVersion, err.Error())
+	}
+
+	if latestVersionInDB.version > latestDBVersion {
+		return 0, nil, fmt.Errorf("%w; debug info: version=%d", ErrDBDowngraded, latestVersionInDB.version)
+	}
+
 	migrations := getMigrations()
+	var pending []Migration
+	for i := latestVersionInDB.version + 1; i <= latestDBVersion; i++ {
+		pending = append(pending, Migration{Version: i, Query: migrations[i]})
+	}
+
+	return latestVersionInDB.version, pending, nil
+}
+
+// DryRunMigrations runs pending migrations against a copy of the database,
+// written to scratchPath. The database itself isn't changed.
+func DryRunMigrations(db *sql.DB, scratchPath string) error {
+	return dryRunMigrations(db, scratchPath, getMigrations())
+}
+
+func dryRunMigrations(db *sql.DB, scratchPath string, migrations map[int]string) error {
+	latestVersionInDB, err := fetchLatestDBVersion(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCouldntFetchDBVersion, err.Error())
+	}
+
+	err = BackupDB(db, scratchPath)
+	if err != nil {
+		return err
+	}
+
+	scratchDB, err := GetDB(scratchPath)
+	if err != nil {
+		return err
+	}
+	defer scratchDB.Close()
+
+	return runMigrations(scratchDB, latestVersionInDB.version, migrations)
+}
+
+func runMigrations(db *sql.DB, currentVersion int, migrations map[int]string) error {
 	for i := currentVersion + 1; i <= latestDBVersion; i++ {
 		migrateQuery := migrations[i]
 		migrateErr := runMigration(db, migrateQuery, i)
@@ -89,6 +288,37 @@ func UpgradeDB(db *sql.DB, currentVersion int) error {
 	return nil
 }
 
+// backUpBeforeMigration writes a snapshot of the database to BackupDirName,
+// next to the database file. In-memory databases aren't backed up.
+func backUpBeforeMigration(db *sql.DB, currentVersion int) (string, error) {
+	var seq int
+	var name, dbPath string
+	err := db.QueryRow(`PRAGMA database_list;`).Scan(&seq, &name, &dbPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrCouldntBackUpBeforeMigration, err.Error())
+	}
+
+	if dbPath == "" {
+		return "", nil
+	}
+
+	stem := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath))
+	backupPath := filepath.Join(filepath.Dir(dbPath), BackupDirName,
+		fmt.Sprintf("%s-%s-pre-migration-v%d.db", stem, time.Now().Format(BackupTimestampFormat), currentVersion))
+
+	err = os.MkdirAll(filepath.Dir(backupPath), 0o755)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrCouldntBackUpBeforeMigration, err.Error())
+	}
+
+	err = BackupDB(db, backupPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrCouldntBackUpBeforeMigration, err.Error())
+	}
+
+	return backupPath, nil
+}
+
 func runMigration(db *sql.DB, migrateQuery string, version int) error {
 	tx, err := db.Begin()
 	if err != nil {
@@ -130,3 +360,8 @@ VALUES (?, ?);
 
 	return nil
 }
+
+// LatestDBVersion returns the database version this version of hours works with.
+func LatestDBVersion() int {
+	return latestDBVersion
+}
@@ -2,9 +2,11 @@ package persistence
 
 import (
 	"database/sql"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	_ "modernc.org/sqlite" // sqlite driver
 )
 
@@ -67,3 +69,83 @@ func TestRunMigrationFailsWhenGivenBadMigration(t *testing.T) {
 	// THEN
 	assert.Error(t, migrateErr)
 }
+
+func getV1FileDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+
+	db, err := GetDB(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, InitDB(db))
+
+	return db
+}
+
+func TestUpgradeDBBacksUpTheDatabaseFirst(t *testing.T) {
+	// GIVEN
+	dir := t.TempDir()
+	db := getV1FileDB(t, filepath.Join(dir, "hours.db"))
+
+	// WHEN
+	err := UpgradeDB(db, 1)
+
+	// THEN
+	require.NoError(t, err)
+
+	backups, err := filepath.Glob(filepath.Join(dir, BackupDirName, "hours-*-pre-migration-v1.db"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	version, err := CheckRestorable(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+}
+
+func TestPendingMigrations(t *testing.T) {
+	// GIVEN
+	db := getV1FileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+
+	// WHEN
+	version, pending, err := PendingMigrations(db)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+	require.Len(t, pending, latestDBVersion-1)
+	assert.Equal(t, 2, pending[0].Version)
+	assert.NotEmpty(t, pending[0].Query)
+}
+
+func TestDryRunMigrationsDoesntChangeTheDatabase(t *testing.T) {
+	// GIVEN
+	dir := t.TempDir()
+	db := getV1FileDB(t, filepath.Join(dir, "hours.db"))
+	scratchPath := filepath.Join(dir, "scratch.db")
+
+	// WHEN
+	err := DryRunMigrations(db, scratchPath)
+
+	// THEN
+	require.NoError(t, err)
+
+	version, _, err := PendingMigrations(db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	scratchVersion, err := CheckRestorable(scratchPath)
+	require.NoError(t, err)
+	assert.Equal(t, latestDBVersion, scratchVersion)
+}
+
+func TestDryRunMigrationsReportsFailures(t *testing.T) {
+	// GIVEN
+	dir := t.TempDir()
+	db := getV1FileDB(t, filepath.Join(dir, "hours.db"))
+	migrations := map[int]string{2: "BAD SQL CODE;"}
+
+	// WHEN
+	err := dryRunMigrations(db, filepath.Join(dir, "scratch.db"), migrations)
+
+	// THEN
+	assert.ErrorIs(t, err, ErrDBMigrationFailed)
+}
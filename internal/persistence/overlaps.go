@@ -0,0 +1,127 @@
+package persistence
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+)
+
+const overlapTimeFormat = "2006/01/02 15:04"
+
+var ErrTLOverlaps = errors.New("db: task log overlaps other task logs")
+
+// TLOverlapError lists the task logs (saved ones, and the active one) that a
+// task log overlaps.
+type TLOverlapError struct {
+	Entries []domain.TaskLogEntry
+}
+
+func (e *TLOverlapError) Error() string {
+	entries := make([]string, len(e.Entries))
+	for i, entry := range e.Entries {
+		if entry.EndTS.IsZero() {
+			entries[i] = fmt.Sprintf("#%d for %q (being tracked since %s)",
+				entry.ID,
+				entry.TaskSummary,
+				entry.BeginTS.Format(overlapTimeFormat),
+			)
+			continue
+		}
+
+		entries[i] = fmt.Sprintf("#%d for %q (%s ... %s)",
+			entry.ID,
+			entry.TaskSummary,
+			entry.BeginTS.Format(overlapTimeFormat),
+			entry.EndTS.Format(overlapTimeFormat),
+		)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrTLOverlaps.Error(), strings.Join(entries, ", "))
+}
+
+func (e *TLOverlapError) Unwrap() error {
+	return ErrTLOverlaps
+}
+
+// FetchOverlappingTLs returns the saved task logs that overlap the time range
+// [beginTs, endTs), leaving out the task log with the ID excludeTLID (if
+// any). If includeActive is set, the active task log is returned as well if it
+// overlaps the range; since it's still being tracked, its EndTS is zero.
+func FetchOverlappingTLs(db *sql.DB, beginTs, endTs time.Time, excludeTLID int, includeActive bool) ([]domain.TaskLogEntry, error) {
+	return fetchOverlappingTLs(db, beginTs, endTs, excludeTLID, includeActive)
+}
+
+func fetchOverlappingTLs(q querier, beginTs, endTs time.Time, excludeTLID int, includeActive bool) ([]domain.TaskLogEntry, error) {
+	entries, err := fetchTLEntriesOverlapping(q, beginTs, endTs, NoLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlapping []domain.TaskLogEntry
+	for _, entry := range entries {
+		if entry.ID != excludeTLID {
+			overlapping = append(overlapping, entry)
+		}
+	}
+
+	if !includeActive {
+		return overlapping, nil
+	}
+
+	active, err := fetchActiveTLOverlapping(q, endTs)
+	if err != nil {
+		return nil, err
+	}
+
+	if active != nil && active.ID != excludeTLID {
+		overlapping = append(overlapping, *active)
+	}
+
+	return overlapping, nil
+}
+
+// fetchActiveTLOverlapping returns the active task log if it began before
+// endTs. It runs till now, and task logs can't end in the future, so that's
+// enough for it to overlap a task log ending at endTs.
+func fetchActiveTLOverlapping(q querier, endTs time.Time) (*domain.TaskLogEntry, error) {
+	var entry domain.TaskLogEntry
+	row := q.QueryRow(`
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.comment
+FROM task_log tl JOIN task t ON tl.task_id = t.id
+WHERE tl.active = true
+AND tl.begin_ts < ?;
+`, endTs.UTC())
+	err := row.Scan(
+		&entry.ID,
+		&entry.TaskID,
+		&entry.TaskSummary,
+		&entry.BeginTS,
+		&entry.Comment,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry.BeginTS = entry.BeginTS.Local()
+
+	return &entry, nil
+}
+
+func checkTLOverlaps(q querier, beginTs, endTs time.Time, excludeTLID int) error {
+	overlapping, err := fetchOverlappingTLs(q, beginTs, endTs, excludeTLID, true)
+	if err != nil {
+		return err
+	}
+
+	if len(overlapping) > 0 {
+		return &TLOverlapError{overlapping}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectingOverlappingTLs(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(time.Hour), nil, true)
+	require.NoError(t, err)
+	otherTLID, err := InsertManualTL(db, taskID, beginTS.Add(2*time.Hour), beginTS.Add(3*time.Hour), nil, true)
+	require.NoError(t, err)
+
+	// WHEN
+	_, insertErr := InsertManualTL(db, taskID, beginTS.Add(30*time.Minute), beginTS.Add(150*time.Minute), nil, true)
+	_, editErr := EditSavedTL(db, tlID, beginTS, beginTS.Add(150*time.Minute), nil, true)
+	_, selfEditErr := EditSavedTL(db, tlID, beginTS.Add(-30*time.Minute), beginTS.Add(2*time.Hour), nil, true)
+	lenientTLID, lenientErr := InsertManualTL(db, taskID, beginTS.Add(30*time.Minute), beginTS.Add(90*time.Minute), nil, false)
+
+	// THEN
+	var overlapErr *TLOverlapError
+	require.True(t, errors.As(insertErr, &overlapErr))
+	require.Len(t, overlapErr.Entries, 2)
+	assert.Equal(t, tlID, overlapErr.Entries[0].ID)
+	assert.Equal(t, otherTLID, overlapErr.Entries[1].ID)
+	assert.ErrorIs(t, insertErr, ErrTLOverlaps)
+
+	require.True(t, errors.As(editErr, &overlapErr))
+	require.Len(t, overlapErr.Entries, 1)
+	assert.Equal(t, otherTLID, overlapErr.Entries[0].ID)
+
+	assert.NoError(t, selfEditErr)
+	require.NoError(t, lenientErr)
+
+	overlapping, err := FetchOverlappingTLs(db, beginTS, beginTS.Add(time.Hour), lenientTLID, true)
+	require.NoError(t, err)
+	require.Len(t, overlapping, 1)
+	assert.Equal(t, tlID, overlapping[0].ID)
+}
+
+func TestRejectingTLsOverlappingTheActiveTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	activeTLID, err := InsertNewTL(db, otherTaskID, beginTS.Add(time.Hour), nil)
+	require.NoError(t, err)
+
+	// WHEN
+	_, overlapErr := InsertManualTL(db, taskID, beginTS.Add(30*time.Minute), beginTS.Add(90*time.Minute), nil, true)
+	_, beforeErr := InsertManualTL(db, taskID, beginTS, beginTS.Add(time.Hour), nil, true)
+	withoutActive, withoutActiveErr := FetchOverlappingTLs(db, beginTS.Add(90*time.Minute), beginTS.Add(2*time.Hour), -1, false)
+
+	// THEN
+	var tlOverlapErr *TLOverlapError
+	require.True(t, errors.As(overlapErr, &tlOverlapErr))
+	require.Len(t, tlOverlapErr.Entries, 1)
+	assert.Equal(t, activeTLID, tlOverlapErr.Entries[0].ID)
+	assert.True(t, tlOverlapErr.Entries[0].EndTS.IsZero())
+	assert.Contains(t, overlapErr.Error(), `#1 for "review pr" (being tracked since`)
+
+	require.NoError(t, beforeErr)
+
+	require.NoError(t, withoutActiveErr)
+	assert.Empty(t, withoutActive)
+}
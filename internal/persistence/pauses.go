@@ -0,0 +1,157 @@
+package persistence
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+)
+
+var (
+	ErrTLAlreadyPaused = errors.New("db: active task log is already paused")
+	ErrTLNotPaused     = errors.New("db: active task log is not paused")
+)
+
+// PauseActiveTL pauses the active task log at ts; the time it stays paused for
+// doesn't count towards the time spent on it.
+func PauseActiveTL(db *sql.DB, ts time.Time) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		tlID, err := fetchActiveTLID(tx)
+		if err != nil {
+			return err
+		}
+
+		pauses, err := fetchTLPauses(tx, tlID)
+		if err != nil {
+			return err
+		}
+
+		if domain.PausedAt(pauses) != nil {
+			return ErrTLAlreadyPaused
+		}
+
+		_, err = tx.Exec(`
+INSERT INTO task_log_pause (task_log_id, begin_ts)
+VALUES (?, ?);
+`, tlID, ts.UTC())
+
+		return err
+	})
+}
+
+func ResumeActiveTL(db *sql.DB, ts time.Time) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		tlID, err := fetchActiveTLID(tx)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.Exec(`
+UPDATE task_log_pause
+SET end_ts = ?
+WHERE task_log_id = ?
+AND end_ts IS NULL;
+`, ts.UTC(), tlID)
+		if err != nil {
+			return err
+		}
+
+		numRows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if numRows == 0 {
+			return ErrTLNotPaused
+		}
+
+		return nil
+	})
+}
+
+func fetchActiveTLID(tx *sql.Tx) (int, error) {
+	var tlID int
+	err := tx.QueryRow(`
+SELECT id
+FROM task_log
+WHERE active = true;
+`).Scan(&tlID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, ErrNoTaskActive
+	}
+
+	return tlID, err
+}
+
+func fetchTLPauses(q querier, tlID int) ([]domain.TaskLogPause, error) {
+	rows, err := q.Query(`
+SELECT id, begin_ts, end_ts
+FROM task_log_pause
+WHERE task_log_id = ?
+ORDER BY begin_ts;
+`, tlID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pauses []domain.TaskLogPause
+	for rows.Next() {
+		var pause domain.TaskLogPause
+		var endTS sql.NullTime
+		err = rows.Scan(&pause.ID, &pause.BeginTS, &endTS)
+		if err != nil {
+			return nil, err
+		}
+
+		pause.BeginTS = pause.BeginTS.Local()
+		if endTS.Valid {
+			end := endTS.Time.Local()
+			pause.EndTS = &end
+		}
+		pauses = append(pauses, pause)
+	}
+
+	return pauses, rows.Err()
+}
+
+// endTLPauses ends the current pause of a task log that's being finished at
+// endTS (dropping the pauses that begin after it), and returns how long the
+// task log was paused for.
+func endTLPauses(tx *sql.Tx, tlID int, beginTS, endTS time.Time) (int, error) {
+	pauses, err := fetchTLPauses(tx, tlID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pause := range pauses {
+		switch {
+		case !pause.BeginTS.Before(endTS):
+			_, err = tx.Exec(`
+DELETE FROM task_log_pause
+WHERE id = ?;
+`, pause.ID)
+		case pause.EndTS == nil:
+			_, err = tx.Exec(`
+UPDATE task_log_pause
+SET end_ts = ?
+WHERE id = ?;
+`, endTS.UTC(), pause.ID)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return domain.PausedSeconds(pauses, beginTS, endTS), nil
+}
+
+func deleteTLPauses(tx *sql.Tx, tlID int) error {
+	_, err := tx.Exec(`
+DELETE FROM task_log_pause
+WHERE task_log_id = ?;
+`, tlID)
+
+	return err
+}
@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPausingActiveTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	tlID, err := InsertNewTL(db, taskID, beginTS, nil)
+	require.NoError(t, err)
+
+	// WHEN
+	require.NoError(t, PauseActiveTL(db, beginTS.Add(time.Hour)))
+	assert.ErrorIs(t, PauseActiveTL(db, beginTS.Add(time.Hour)), ErrTLAlreadyPaused)
+	require.NoError(t, ResumeActiveTL(db, beginTS.Add(90*time.Minute)))
+	assert.ErrorIs(t, ResumeActiveTL(db, beginTS.Add(90*time.Minute)), ErrTLNotPaused)
+	require.NoError(t, PauseActiveTL(db, beginTS.Add(2*time.Hour)))
+
+	details, err := FetchActiveTaskDetails(db)
+	require.NoError(t, err)
+
+	secsSpent, err := FinishActiveTL(db, tlID, taskID, beginTS, beginTS.Add(3*time.Hour), nil)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, details.CurrentLogPauses, 2)
+	require.NotNil(t, details.PausedAt())
+	assert.True(t, beginTS.Add(2*time.Hour).Equal(*details.PausedAt()))
+
+	assert.Equal(t, (60+30)*60, secsSpent)
+
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, secsSpent, task.SecsSpent)
+
+	pauses, err := fetchTLPauses(db, tlID)
+	require.NoError(t, err)
+	require.Len(t, pauses, 2)
+	require.NotNil(t, pauses[1].EndTS)
+	assert.True(t, beginTS.Add(3*time.Hour).Equal(*pauses[1].EndTS))
+
+	assert.ErrorIs(t, PauseActiveTL(db, beginTS.Add(4*time.Hour)), ErrNoTaskActive)
+}
+
+func TestQuickSwitchingPausedTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	tlID, err := InsertNewTL(db, taskID, beginTS, nil)
+	require.NoError(t, err)
+	require.NoError(t, PauseActiveTL(db, beginTS.Add(time.Hour)))
+
+	// WHEN
+	_, err = QuickSwitchActiveTL(db, otherTaskID, beginTS.Add(2*time.Hour), nil)
+
+	// THEN
+	require.NoError(t, err)
+
+	taskLog, err := fetchTLByID(db, tlID)
+	require.NoError(t, err)
+	assert.Equal(t, 60*60, taskLog.SecsSpent)
+
+	details, err := FetchActiveTaskDetails(db)
+	require.NoError(t, err)
+	assert.Equal(t, otherTaskID, details.TaskID)
+	assert.Empty(t, details.CurrentLogPauses)
+}
+
+func TestDeletingPausedActiveTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	tlID, err := InsertNewTL(db, taskID, beginTS, nil)
+	require.NoError(t, err)
+	require.NoError(t, PauseActiveTL(db, beginTS.Add(time.Hour)))
+
+	// WHEN
+	err = DeleteActiveTL(db)
+
+	// THEN
+	require.NoError(t, err)
+
+	pauses, err := fetchTLPauses(db, tlID)
+	require.NoError(t, err)
+	assert.Empty(t, pauses)
+}
@@ -0,0 +1,183 @@
+package persistence
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/types"
+)
+
+const (
+	// UnassignedProjectID is the project tasks belong to unless they're moved
+	// to another one; it's created by the migration that introduced projects.
+	UnassignedProjectID = 1
+	// UnassignedName is the name of both the unassigned project, and its client.
+	UnassignedName = "unassigned"
+)
+
+var ErrProjectAlreadyExists = errors.New("project already exists")
+
+// InsertProject adds a project for a client, creating the client if it doesn't
+// exist yet.
+func InsertProject(db *sql.DB, name, client string) (int, error) {
+	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
+		var exists bool
+		err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM project WHERE name = ?);`, name).Scan(&exists)
+		if err != nil {
+			return -1, err
+		}
+		if exists {
+			return -1, ErrProjectAlreadyExists
+		}
+
+		now := time.Now().UTC()
+		_, err = tx.Exec(`
+INSERT INTO client (name, created_at)
+VALUES (?, ?)
+ON CONFLICT(name) DO NOTHING;
+`, client, now)
+		if err != nil {
+			return -1, err
+		}
+
+		res, err := tx.Exec(`
+INSERT INTO project (name, client_id, created_at)
+SELECT ?, id, ?
+FROM client
+WHERE name = ?;
+`, name, now, client)
+		if err != nil {
+			return -1, err
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return -1, err
+		}
+
+		return int(lastID), nil
+	})
+}
+
+func FetchProjects(db *sql.DB) ([]domain.Project, error) {
+	rows, err := db.Query(`
+SELECT p.id, p.name, c.id, c.name, p.hourly_rate, p.currency
+FROM project p
+JOIN client c ON p.client_id = c.id
+ORDER BY c.name, p.name;
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []domain.Project
+	for rows.Next() {
+		var project domain.Project
+		var rate sql.NullInt64
+		var currency sql.NullString
+		err = rows.Scan(&project.ID, &project.Name, &project.ClientID, &project.Client, &rate, &currency)
+		if err != nil {
+			return nil, err
+		}
+		project.Rate = getRate(rate, currency)
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+func SetTaskProject(db *sql.DB, taskID, projectID int) error {
+	_, err := db.Exec(`
+UPDATE task
+SET project_id = ?,
+    updated_at = ?
+WHERE id = ?;
+`, projectID, time.Now().UTC(), taskID)
+
+	return err
+}
+
+// addTaskDetails adds the tags, project, and client of tasks, which are stored
+// separately.
+func addTaskDetails(db *sql.DB, tasks []domain.Task) error {
+	err := addTagsToTasks(db, tasks)
+	if err != nil {
+		return err
+	}
+
+	return addProjectsToTasks(db, tasks)
+}
+
+func addProjectsToTasks(db *sql.DB, tasks []domain.Task) error {
+	projects, err := FetchProjects(db)
+	if err != nil {
+		return err
+	}
+
+	projectsByID := make(map[int]domain.Project, len(projects))
+	for _, project := range projects {
+		projectsByID[project.ID] = project
+	}
+
+	for i := range tasks {
+		project := projectsByID[tasks[i].ProjectID]
+		tasks[i].Project = project.Name
+		tasks[i].Client = project.Client
+	}
+
+	return nil
+}
+
+// FetchTaskGroups returns the groups (tags, a project, or a client) each task
+// belongs to; tasks that don't belong to any group (which is only possible
+// when grouping by tag), and tasks in the trash are left out.
+func FetchTaskGroups(db *sql.DB, groupBy types.GroupBy) (map[int][]string, error) {
+	var query string
+	switch groupBy {
+	case types.GroupByTag:
+		return fetchTagsByTask(db)
+	case types.GroupByProject:
+		query = `
+SELECT t.id, p.name
+FROM task t
+JOIN project p ON t.project_id = p.id
+WHERE t.deleted_at IS NULL;
+`
+	case types.GroupByClient:
+		query = `
+SELECT t.id, c.name
+FROM task t
+JOIN project p ON t.project_id = p.id
+JOIN client c ON p.client_id = c.id
+WHERE t.deleted_at IS NULL;
+`
+	default:
+		query = `
+SELECT id, summary
+FROM task
+WHERE deleted_at IS NULL;
+`
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := make(map[int][]string)
+	for rows.Next() {
+		var taskID int
+		var group string
+		err = rows.Scan(&taskID, &group)
+		if err != nil {
+			return nil, err
+		}
+		groups[taskID] = append(groups[taskID], group)
+	}
+
+	return groups, rows.Err()
+}
@@ -0,0 +1,126 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationAssignsExistingTasksToUnassignedProject(t *testing.T) {
+	// GIVEN
+	db := getV1FileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	_, err := db.Exec(`INSERT INTO task (summary, active, created_at, updated_at) VALUES ('write blog post', true, ?, ?);`,
+		time.Now().UTC(), time.Now().UTC())
+	require.NoError(t, err)
+
+	// WHEN
+	err = UpgradeDB(db, 1)
+	require.NoError(t, err)
+
+	// THEN
+	tasks, err := FetchTasksWithStatus(db, types.TaskStatusAny, 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, UnassignedProjectID, tasks[0].ProjectID)
+	assert.Equal(t, UnassignedName, tasks[0].Project)
+	assert.Equal(t, UnassignedName, tasks[0].Client)
+}
+
+func TestProjects(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+
+	// WHEN
+	websiteID, err := InsertProject(db, "website", "acme")
+	require.NoError(t, err)
+	_, err = InsertProject(db, "app", "acme")
+	require.NoError(t, err)
+	_, err = InsertProject(db, "website", "globex")
+	errDuplicate := err
+
+	taskID, err := InsertTaskWithDetails(db, "fix login bug", websiteID, nil)
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "write blog post")
+	require.NoError(t, err)
+	err = SetTaskProject(db, otherTaskID, websiteID)
+	require.NoError(t, err)
+	err = SetTaskProject(db, otherTaskID, UnassignedProjectID)
+	require.NoError(t, err)
+
+	// THEN
+	assert.ErrorIs(t, errDuplicate, ErrProjectAlreadyExists)
+
+	projects, err := FetchProjects(db)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Project{
+		{ID: 3, Name: "app", ClientID: 2, Client: "acme"},
+		{ID: 2, Name: "website", ClientID: 2, Client: "acme"},
+		{ID: UnassignedProjectID, Name: UnassignedName, ClientID: 1, Client: UnassignedName},
+	}, projects)
+
+	tasks, err := FetchTasksWithStatus(db, types.TaskStatusAny, 10)
+	require.NoError(t, err)
+	projectsByTask := make(map[int]string)
+	clientsByTask := make(map[int]string)
+	for _, task := range tasks {
+		projectsByTask[task.ID] = task.Project
+		clientsByTask[task.ID] = task.Client
+	}
+	assert.Equal(t, map[int]string{taskID: "website", otherTaskID: UnassignedName}, projectsByTask)
+	assert.Equal(t, map[int]string{taskID: "acme", otherTaskID: UnassignedName}, clientsByTask)
+}
+
+func TestFetchTaskGroups(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+
+	websiteID, err := InsertProject(db, "website", "acme")
+	require.NoError(t, err)
+	taskID, err := InsertTaskWithDetails(db, "fix login bug", websiteID, []string{"bugs", "work"})
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "write blog post")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		groupBy  types.GroupBy
+		expected map[int][]string
+	}{
+		{
+			name:     "task",
+			groupBy:  types.GroupByTask,
+			expected: map[int][]string{taskID: {"fix login bug"}, otherTaskID: {"write blog post"}},
+		},
+		{
+			name:     "tag",
+			groupBy:  types.GroupByTag,
+			expected: map[int][]string{taskID: {"bugs", "work"}},
+		},
+		{
+			name:     "project",
+			groupBy:  types.GroupByProject,
+			expected: map[int][]string{taskID: {"website"}, otherTaskID: {UnassignedName}},
+		},
+		{
+			name:     "client",
+			groupBy:  types.GroupByClient,
+			expected: map[int][]string{taskID: {"acme"}, otherTaskID: {UnassignedName}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			got, err := FetchTaskGroups(db, tt.groupBy)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -10,6 +10,10 @@ import (
 	"github.com/dhth/hours/internal/types"
 )
 
+// NoLimit can be passed as the limit to queries that shouldn't limit the
+// number of rows returned (SQLite treats a negative LIMIT as no limit).
+const NoLimit = -1
+
 var (
 	ErrCouldntRollBackTx          = errors.New("db: couldn't roll back transaction")
 	ErrCouldntGetTaskLogDetails   = errors.New("db: couldn't get task log details")
@@ -20,13 +24,41 @@ var (
 	ErrNoTaskActive               = errors.New("db: no task is being actively tracked right now")
 	ErrCouldntGetActiveTask       = errors.New("db: couldn't get active task details")
 	ErrCouldntLastInsertID        = errors.New("db: couldn't get ID of the row last inserted")
+	ErrTLNotFound                 = errors.New("db: task log not found")
 )
 
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
 type QuickSwitchResult struct {
 	LastActiveTaskID    int
 	CurrentlyActiveTLID int
 }
 
+// ImportedTL is a task log to be imported; its task is referred to by
+// summary, and is created if it doesn't exist yet.
+type ImportedTL struct {
+	TaskSummary string
+	BeginTS     time.Time
+	EndTS       time.Time
+	Comment     *string
+	// Active task logs are still being tracked; EndTS is ignored for them
+	Active bool
+	// ExternalID, if set, identifies the entry in its source; it's recorded so
+	// that the entry can be recognized when imported again
+	Source     string
+	ExternalID string
+}
+
+type ImportResult struct {
+	NumTasksCreated int
+	NumTLsInserted  int
+	ActiveTLID      int
+}
+
 type TaskTrackingData struct {
 	SecsSpent int
 	UpdatedAt time.Time
@@ -39,28 +71,9 @@ type activeTaskLogEntry struct {
 	Comment *string
 }
 
-func InsertNewTL(db *sql.DB, taskID int, beginTs time.Time) (int, error) {
+func InsertNewTL(db *sql.DB, taskID int, beginTs time.Time, comment *string) (int, error) {
 	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
-		stmt, err := tx.Prepare(`
-INSERT INTO task_log (task_id, begin_ts, active)
-VALUES (?, ?, ?);
-`)
-		if err != nil {
-			return -1, err
-		}
-		defer stmt.Close()
-
-		res, err := stmt.Exec(taskID, beginTs.UTC(), true)
-		if err != nil {
-			return -1, err
-		}
-
-		lastID, err := res.LastInsertId()
-		if err != nil {
-			return -1, err
-		}
-
-		return int(lastID), nil
+		return insertNewTL(tx, taskID, beginTs, comment)
 	})
 }
 
@@ -69,34 +82,71 @@ func EditActiveTL(db *sql.DB, beginTs time.Time, comment *string) error {
 UPDATE task_log
     SET begin_ts=?,
     comment = ?
-WHERE active is true;
+WHERE active is true
+AND task_id IN (SELECT id FROM task WHERE deleted_at IS NULL);
 `)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(beginTs.UTC(), comment)
-	return err
-}
+	res, err := stmt.Exec(beginTs.UTC(), comment)
+	if err != nil {
+		return err
+	}
 
-func DeleteActiveTL(db *sql.DB) error {
-	stmt, err := db.Prepare(`
-DELETE FROM task_log
-WHERE active=true;
-`)
+	numRows, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec()
+	if numRows == 0 {
+		return ErrNoTaskActive
+	}
 
-	return err
+	return nil
 }
 
-func FinishActiveTL(db *sql.DB, taskLogID int, taskID int, beginTs, endTs time.Time, secsSpent int, comment *string) error {
+func DeleteActiveTL(db *sql.DB) error {
 	return runInTx(db, func(tx *sql.Tx) error {
+		tlID, err := fetchActiveTLID(tx)
+		if errors.Is(err, ErrNoTaskActive) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		err = deleteTLPauses(tx, tlID)
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.Prepare(`
+DELETE FROM task_log
+WHERE id=?;
+`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		_, err = stmt.Exec(tlID)
+
+		return err
+	})
+}
+
+// FinishActiveTL saves the active task log, and returns the time spent on it
+// (which excludes the time it was paused for).
+func FinishActiveTL(db *sql.DB, taskLogID int, taskID int, beginTs, endTs time.Time, comment *string) (int, error) {
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (int, error) {
+		pausedSecs, err := endTLPauses(tx, taskLogID, beginTs, endTs)
+		if err != nil {
+			return 0, err
+		}
+
+		secsSpent := int(endTs.Sub(beginTs).Seconds()) - pausedSecs
+
 		stmt, err := tx.Prepare(`
 UPDATE task_log
 SET active = 0,
@@ -108,13 +158,13 @@ WHERE id = ?
 AND active = 1;
 `)
 		if err != nil {
-			return err
+			return 0, err
 		}
 		defer stmt.Close()
 
 		_, err = stmt.Exec(beginTs.UTC(), endTs.UTC(), secsSpent, comment, taskLogID)
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		tStmt, err := tx.Prepare(`
@@ -124,29 +174,31 @@ SET secs_spent = secs_spent+?,
 WHERE id = ?;
     `)
 		if err != nil {
-			return err
+			return 0, err
 		}
 		defer tStmt.Close()
 
 		_, err = tStmt.Exec(secsSpent, time.Now().UTC(), taskID)
 
-		return err
+		return secsSpent, err
 	})
 }
 
-func QuickSwitchActiveTL(db *sql.DB, newActiveTaskID int, ts time.Time) (QuickSwitchResult, error) {
+func QuickSwitchActiveTL(db *sql.DB, newActiveTaskID int, ts time.Time, comment *string) (QuickSwitchResult, error) {
 	return runInTxAndReturnA(db, func(tx *sql.Tx) (QuickSwitchResult, error) {
 		// fetch currently active task
 		currentlyActiveTaskRow := tx.QueryRow(`
-SELECT t.id, tl.begin_ts
+SELECT tl.id, t.id, tl.begin_ts
 FROM task_log tl left join task t on tl.task_id = t.id
 WHERE tl.active=true;
 `)
 
 		var zero QuickSwitchResult
+		var currentlyActiveTLID int
 		var currentlyActiveTaskID int
 		var currentlyActiveTaskBeginTS time.Time
 		err := currentlyActiveTaskRow.Scan(
+			&currentlyActiveTLID,
 			&currentlyActiveTaskID,
 			&currentlyActiveTaskBeginTS,
 		)
@@ -158,7 +210,12 @@ WHERE tl.active=true;
 
 		tsUTC := ts.UTC()
 
-		secsSpent := int(tsUTC.Sub(currentlyActiveTaskBeginTS).Seconds())
+		pausedSecs, err := endTLPauses(tx, currentlyActiveTLID, currentlyActiveTaskBeginTS, tsUTC)
+		if err != nil {
+			return zero, fmt.Errorf("%w: %s", ErrCouldntFinishActiveTL, err.Error())
+		}
+
+		secsSpent := int(tsUTC.Sub(currentlyActiveTaskBeginTS).Seconds()) - pausedSecs
 
 		// finish currently active task log
 		tlUpdateStmt, err := tx.Prepare(`
@@ -198,78 +255,61 @@ WHERE id = ?;
 
 		// insert new task log
 		tlInsertStmt, err := tx.Prepare(`
-INSERT INTO task_log (task_id, begin_ts, active)
-VALUES (?, ?, ?);
+INSERT INTO task_log (task_id, begin_ts, comment, active)
+VALUES (?, ?, ?, ?);
 `)
 		if err != nil {
 			return zero, fmt.Errorf("%w: %s", ErrCouldntPrepareStatement, err.Error())
 		}
 		defer tlInsertStmt.Close()
 
-		insertRes, err := tlInsertStmt.Exec(newActiveTaskID, tsUTC, true)
+		insertRes, err := tlInsertStmt.Exec(newActiveTaskID, tsUTC, comment, true)
 		if err != nil {
 			return zero, fmt.Errorf("%w: %s", ErrCouldntCreateTL, err.Error())
 		}
 
-		currentlyActiveTLID, err := insertRes.LastInsertId()
+		newActiveTLID, err := insertRes.LastInsertId()
 		if err != nil {
 			return zero, fmt.Errorf("%w: %s", ErrCouldntLastInsertID, err.Error())
 		}
 
-		return QuickSwitchResult{currentlyActiveTaskID, int(currentlyActiveTLID)}, nil
+		return QuickSwitchResult{currentlyActiveTaskID, int(newActiveTLID)}, nil
 	})
 }
 
-func InsertManualTL(db *sql.DB, taskID int, beginTs time.Time, endTs time.Time, comment *string) (int, error) {
+// InsertManualTL saves a task log; if rejectOverlaps is set, it fails with a
+// *TLOverlapError if the task log overlaps saved ones, or the active one.
+func InsertManualTL(db *sql.DB, taskID int, beginTs time.Time, endTs time.Time, comment *string, rejectOverlaps bool) (int, error) {
 	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
-		stmt, err := tx.Prepare(`
-INSERT INTO task_log (task_id, begin_ts, end_ts, secs_spent, comment, active)
-VALUES (?, ?, ?, ?, ?, ?);
-`)
-		if err != nil {
-			return -1, err
-		}
-		defer stmt.Close()
-
-		secsSpent := int(endTs.Sub(beginTs).Seconds())
-
-		res, err := stmt.Exec(taskID, beginTs.UTC(), endTs.UTC(), secsSpent, comment, false)
-		if err != nil {
-			return -1, err
-		}
-
-		lastID, err := res.LastInsertId()
-		if err != nil {
-			return -1, err
-		}
-
-		tStmt, err := tx.Prepare(`
-UPDATE task
-SET secs_spent = secs_spent+?,
-    updated_at = ?
-WHERE id = ?;
-    `)
-		if err != nil {
-			return -1, err
+		if rejectOverlaps {
+			err := checkTLOverlaps(tx, beginTs, endTs, -1)
+			if err != nil {
+				return -1, err
+			}
 		}
-		defer tStmt.Close()
 
-		_, err = tStmt.Exec(secsSpent, time.Now().UTC(), taskID)
-		if err != nil {
-			return -1, err
-		}
-
-		return int(lastID), nil
+		return insertManualTL(tx, taskID, beginTs, endTs, comment)
 	})
 }
 
-func EditSavedTL(db *sql.DB, tlID int, beginTs time.Time, endTs time.Time, comment *string) (int, error) {
+// EditSavedTL updates a saved task log; if rejectOverlaps is set, it fails
+// with a *TLOverlapError if the updated task log overlaps other saved ones, or
+// the active one.
+func EditSavedTL(db *sql.DB, tlID int, beginTs time.Time, endTs time.Time, comment *string, rejectOverlaps bool) (int, error) {
 	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
+		if rejectOverlaps {
+			err := checkTLOverlaps(tx, beginTs, endTs, tlID)
+			if err != nil {
+				return -1, err
+			}
+		}
+
 		var tl domain.TaskLogEntry
 		row := tx.QueryRow(`
 SELECT id, task_id, begin_ts, end_ts, secs_spent, comment
 FROM task_log
-WHERE id=?;
+WHERE id=?
+AND deleted_at IS NULL;
     `, tlID)
 
 		if row.Err() != nil {
@@ -303,7 +343,12 @@ WHERE id=?;
 		}
 		defer stmt.Close()
 
-		secsSpent := int(endTs.Sub(beginTs).Seconds())
+		pauses, err := fetchTLPauses(tx, tlID)
+		if err != nil {
+			return -1, err
+		}
+
+		secsSpent := int(endTs.Sub(beginTs).Seconds()) - domain.PausedSeconds(pauses, beginTs, endTs)
 
 		res, err := stmt.Exec(beginTs.UTC(), endTs.UTC(), secsSpent, comment, tlID)
 		if err != nil {
@@ -341,13 +386,14 @@ WHERE id = ?;
 
 func FetchActiveTaskDetails(db *sql.DB) (domain.ActiveTaskDetails, error) {
 	row := db.QueryRow(`
-SELECT t.id, t.summary, tl.begin_ts, tl.comment
+SELECT tl.id, t.id, t.summary, tl.begin_ts, tl.comment
 FROM task_log tl left join task t on tl.task_id = t.id
 WHERE tl.active=true;
 `)
 
 	var activeTaskDetails domain.ActiveTaskDetails
 	err := row.Scan(
+		&activeTaskDetails.CurrentLogID,
 		&activeTaskDetails.TaskID,
 		&activeTaskDetails.TaskSummary,
 		&activeTaskDetails.CurrentLogBeginTS,
@@ -360,32 +406,14 @@ WHERE tl.active=true;
 		return activeTaskDetails, err
 	}
 	activeTaskDetails.CurrentLogBeginTS = activeTaskDetails.CurrentLogBeginTS.Local()
-	return activeTaskDetails, nil
+
+	activeTaskDetails.CurrentLogPauses, err = fetchTLPauses(db, activeTaskDetails.CurrentLogID)
+	return activeTaskDetails, err
 }
 
 func InsertTask(db *sql.DB, summary string) (int, error) {
 	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
-		stmt, err := tx.Prepare(`
-INSERT into task (summary, active, created_at, updated_at)
-VALUES (?, true, ?, ?);
-`)
-		if err != nil {
-			return -1, err
-		}
-		defer stmt.Close()
-
-		now := time.Now().UTC()
-		res, err := stmt.Exec(summary, now, now)
-		if err != nil {
-			return -1, err
-		}
-
-		lastID, err := res.LastInsertId()
-		if err != nil {
-			return -1, err
-		}
-
-		return int(lastID), nil
+		return insertTask(tx, summary)
 	})
 }
 
@@ -395,17 +423,19 @@ UPDATE task
 SET summary = ?,
     updated_at = ?
 WHERE id = ?
+AND deleted_at IS NULL
 `)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(summary, time.Now().UTC(), id)
+	res, err := stmt.Exec(summary, time.Now().UTC(), id)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	return checkTaskUpdated(res, id)
 }
 
 func UpdateTaskActiveStatus(db *sql.DB, id int, active bool) error {
@@ -414,16 +444,33 @@ UPDATE task
 SET active = ?,
     updated_at = ?
 WHERE id = ?
+AND deleted_at IS NULL
 `)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(active, time.Now().UTC(), id)
+	res, err := stmt.Exec(active, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+
+	return checkTaskUpdated(res, id)
+}
+
+// checkTaskUpdated returns ErrTaskNotFound if an update of the task with the
+// ID id didn't change any rows, ie, the task doesn't exist or is in the trash.
+func checkTaskUpdated(res sql.Result, id int) error {
+	numRows, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
+
+	if numRows == 0 {
+		return fmt.Errorf("%w (ID: %d)", ErrTaskNotFound, id)
+	}
+
 	return nil
 }
 
@@ -431,11 +478,15 @@ func FetchTaskTrackingData(db *sql.DB, taskID int) (TaskTrackingData, error) {
 	row := db.QueryRow(`
 SELECT secs_spent, updated_at
 FROM task
-WHERE id=?;
+WHERE id=?
+AND deleted_at IS NULL;
     `, taskID)
 
 	var data TaskTrackingData
 	err := row.Scan(&data.SecsSpent, &data.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TaskTrackingData{}, fmt.Errorf("%w (ID: %d)", ErrTaskNotFound, taskID)
+	}
 	if err != nil {
 		return TaskTrackingData{}, err
 	}
@@ -443,42 +494,103 @@ WHERE id=?;
 	return data, nil
 }
 
-func FetchTasks(db *sql.DB, active bool, limit int) ([]domain.Task, error) {
-	var tasks []domain.Task
-
-	rows, err := db.Query(`
-SELECT id, summary, secs_spent, created_at, updated_at, active
-FROM task
-WHERE active=?
-ORDER by updated_at DESC
-LIMIT ?;
-    `, active, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// taskColumns are the columns scanTasks expects.
+const taskColumns = "id, summary, secs_spent, created_at, updated_at, active, project_id, hourly_rate, currency, billable, budget_secs"
 
+func scanTasks(rows *sql.Rows) ([]domain.Task, error) {
+	var tasks []domain.Task
 	for rows.Next() {
 		var entry domain.Task
-		err = rows.Scan(
+		var rate sql.NullInt64
+		var currency sql.NullString
+		var budgetSecs sql.NullInt64
+		err := rows.Scan(
 			&entry.ID,
 			&entry.Summary,
 			&entry.SecsSpent,
 			&entry.CreatedAt,
 			&entry.UpdatedAt,
 			&entry.Active,
+			&entry.ProjectID,
+			&rate,
+			&currency,
+			&entry.Billable,
+			&budgetSecs,
 		)
 		if err != nil {
 			return nil, err
 		}
+		entry.Rate = getRate(rate, currency)
+		if budgetSecs.Valid {
+			secs := int(budgetSecs.Int64)
+			entry.BudgetSecs = &secs
+		}
 		entry.CreatedAt = entry.CreatedAt.Local()
 		entry.UpdatedAt = entry.UpdatedAt.Local()
 		tasks = append(tasks, entry)
+	}
+
+	return tasks, rows.Err()
+}
+
+func FetchTasks(db *sql.DB, active bool, limit int) ([]domain.Task, error) {
+	rows, err := db.Query(`
+SELECT `+taskColumns+`
+FROM task
+WHERE active=?
+AND deleted_at IS NULL
+ORDER by updated_at DESC
+LIMIT ?;
+    `, active, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
 	}
-	if rows.Err() != nil {
+
+	err = addTaskDetails(db, tasks)
+	if err != nil {
 		return nil, err
 	}
+
+	return tasks, nil
+}
+
+func FetchTasksWithStatus(db *sql.DB, taskStatus types.TaskStatus, limit int) ([]domain.Task, error) {
+	tsFilter := "WHERE deleted_at IS NULL"
+	switch taskStatus {
+	case types.TaskStatusActive:
+		tsFilter += "\nAND active is true"
+	case types.TaskStatusInactive:
+		tsFilter += "\nAND active is false"
+	}
+
+	rows, err := db.Query(`
+SELECT `+taskColumns+`
+FROM task
+`+tsFilter+`
+ORDER by updated_at DESC
+LIMIT ?;
+    `, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addTaskDetails(db, tasks)
+	if err != nil {
+		return nil, err
+	}
+
 	return tasks, nil
 }
 
@@ -495,6 +607,8 @@ func FetchTLEntries(db *sql.DB, desc bool, limit int) ([]domain.TaskLogEntry, er
 SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
 FROM task_log tl left join task t on tl.task_id=t.id
 WHERE tl.active=false
+AND tl.deleted_at IS NULL
+AND t.deleted_at IS NULL
 ORDER by tl.end_ts %s
 LIMIT ?;
 `, order)
@@ -530,14 +644,12 @@ LIMIT ?;
 	return logEntries, nil
 }
 
-func FetchTLEntriesBetweenTS(db *sql.DB, beginTs, endTs time.Time, taskStatus types.TaskStatus, limit int) ([]domain.TaskLogEntry, error) {
-	var tsFilter string
-	switch taskStatus {
-	case types.TaskStatusActive:
-		tsFilter = "AND t.active is true"
-	case types.TaskStatusInactive:
-		tsFilter = "AND t.active is false"
-	}
+func FetchTLEntriesBetweenTS(db *sql.DB, beginTs, endTs time.Time, filter types.TaskFilter, limit int) ([]domain.TaskLogEntry, error) {
+	filterSQL, filterArgs := taskFilterSQL(filter, "AND")
+
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	args = append(args, filterArgs...)
+	args = append(args, limit)
 
 	var logEntries []domain.TaskLogEntry
 
@@ -545,11 +657,13 @@ func FetchTLEntriesBetweenTS(db *sql.DB, beginTs, endTs time.Time, taskStatus ty
 SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
 FROM task_log tl left join task t on tl.task_id=t.id
 WHERE tl.active=false
+AND tl.deleted_at IS NULL
+AND t.deleted_at IS NULL
 AND tl.end_ts >= ?
 AND tl.end_ts < ?
-`+tsFilter+`
+`+filterSQL+`
 ORDER by tl.begin_ts ASC LIMIT ?;
-    `, beginTs.UTC(), endTs.UTC(), limit)
+    `, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -580,24 +694,68 @@ ORDER by tl.begin_ts ASC LIMIT ?;
 	return logEntries, nil
 }
 
-func FetchStats(db *sql.DB, taskStatus types.TaskStatus, limit int) ([]domain.TaskReportEntry, error) {
-	var tsFilter string
-	switch taskStatus {
-	case types.TaskStatusActive:
-		tsFilter = "WHERE t.active is true"
-	case types.TaskStatusInactive:
-		tsFilter = "WHERE t.active is false"
+// FetchTLEntriesOverlapping returns saved task log entries that overlap with
+// the time range [beginTs, endTs).
+func FetchTLEntriesOverlapping(db *sql.DB, beginTs, endTs time.Time, limit int) ([]domain.TaskLogEntry, error) {
+	return fetchTLEntriesOverlapping(db, beginTs, endTs, limit)
+}
+
+func fetchTLEntriesOverlapping(q querier, beginTs, endTs time.Time, limit int) ([]domain.TaskLogEntry, error) {
+	var logEntries []domain.TaskLogEntry
+
+	rows, err := q.Query(`
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
+FROM task_log tl left join task t on tl.task_id=t.id
+WHERE tl.active=false
+AND tl.deleted_at IS NULL
+AND t.deleted_at IS NULL
+AND tl.end_ts > ?
+AND tl.begin_ts < ?
+ORDER by tl.begin_ts ASC LIMIT ?;
+    `, beginTs.UTC(), endTs.UTC(), limit)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.TaskLogEntry
+		err = rows.Scan(
+			&entry.ID,
+			&entry.TaskID,
+			&entry.TaskSummary,
+			&entry.BeginTS,
+			&entry.EndTS,
+			&entry.SecsSpent,
+			&entry.Comment,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.BeginTS = entry.BeginTS.Local()
+		entry.EndTS = entry.EndTS.Local()
+		logEntries = append(logEntries, entry)
+	}
+
+	return logEntries, rows.Err()
+}
+
+func FetchStats(db *sql.DB, filter types.TaskFilter, limit int) ([]domain.TaskReportEntry, error) {
+	filterSQL, filterArgs := taskFilterSQL(filter, "AND")
+
+	args := append([]any{}, filterArgs...)
+	args = append(args, limit)
 
 	rows, err := db.Query(`
 SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries, t.secs_spent
 from task_log tl
 LEFT JOIN task t on tl.task_id = t.id
-`+tsFilter+`
+WHERE tl.deleted_at IS NULL
+AND t.deleted_at IS NULL`+filterSQL+`
 GROUP BY tl.task_id
 ORDER BY t.secs_spent DESC
 limit ?;
-`, limit)
+`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -625,24 +783,24 @@ limit ?;
 	return tLE, nil
 }
 
-func FetchStatsBetweenTS(db *sql.DB, beginTs, endTs time.Time, taskStatus types.TaskStatus, limit int) ([]domain.TaskReportEntry, error) {
-	var activeFilter string
-	switch taskStatus {
-	case types.TaskStatusActive:
-		activeFilter = " AND t.active is true"
-	case types.TaskStatusInactive:
-		activeFilter = " AND t.active is false"
-	}
+func FetchStatsBetweenTS(db *sql.DB, beginTs, endTs time.Time, filter types.TaskFilter, limit int) ([]domain.TaskReportEntry, error) {
+	filterSQL, filterArgs := taskFilterSQL(filter, "AND")
+
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	args = append(args, filterArgs...)
+	args = append(args, limit)
 
 	rows, err := db.Query(`
 SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries,  SUM(tl.secs_spent) AS secs_spent
 FROM task_log tl 
 LEFT JOIN task t ON tl.task_id = t.id
-WHERE tl.end_ts >= ? AND tl.end_ts < ?`+activeFilter+`
+WHERE tl.end_ts >= ? AND tl.end_ts < ?
+AND tl.deleted_at IS NULL
+AND t.deleted_at IS NULL`+filterSQL+`
 GROUP BY tl.task_id
 ORDER BY secs_spent DESC
 LIMIT ?;
-`, beginTs.UTC(), endTs.UTC(), limit)
+`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -670,25 +828,25 @@ LIMIT ?;
 	return tLE, nil
 }
 
-func FetchReportBetweenTS(db *sql.DB, beginTs, endTs time.Time, taskStatus types.TaskStatus, limit int) ([]domain.TaskReportEntry, error) {
-	var tsFilter string
-	switch taskStatus {
-	case types.TaskStatusActive:
-		tsFilter = "AND t.active is true"
-	case types.TaskStatusInactive:
-		tsFilter = "AND t.active is false"
-	}
+func FetchReportBetweenTS(db *sql.DB, beginTs, endTs time.Time, filter types.TaskFilter, limit int) ([]domain.TaskReportEntry, error) {
+	filterSQL, filterArgs := taskFilterSQL(filter, "AND")
+
+	args := []any{beginTs.UTC(), endTs.UTC()}
+	args = append(args, filterArgs...)
+	args = append(args, limit)
 
 	rows, err := db.Query(`
 SELECT tl.task_id, t.summary, COUNT(tl.id) as num_entries,  SUM(tl.secs_spent) AS secs_spent
 FROM task_log tl 
 LEFT JOIN task t ON tl.task_id = t.id
 WHERE tl.end_ts >= ? AND tl.end_ts < ?
-`+tsFilter+`
+AND tl.deleted_at IS NULL
+AND t.deleted_at IS NULL
+`+filterSQL+`
 GROUP BY tl.task_id
 ORDER BY t.updated_at ASC
 LIMIT ?;
-`, beginTs.UTC(), endTs.UTC(), limit)
+`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -716,36 +874,241 @@ LIMIT ?;
 	return tLE, nil
 }
 
-func DeleteTL(db *sql.DB, entry *domain.TaskLogEntry) error {
+func FetchSavedTLByID(db *sql.DB, id int) (domain.TaskLogEntry, error) {
+	return fetchSavedTLByID(db, id)
+}
+
+func fetchSavedTLByID(q querier, id int) (domain.TaskLogEntry, error) {
+	var tl domain.TaskLogEntry
+	row := q.QueryRow(`
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.comment
+FROM task_log tl left join task t on tl.task_id=t.id
+WHERE tl.id=?
+AND tl.active=false
+AND tl.deleted_at IS NULL
+AND t.deleted_at IS NULL;
+    `, id)
+
+	err := row.Scan(
+		&tl.ID,
+		&tl.TaskID,
+		&tl.TaskSummary,
+		&tl.BeginTS,
+		&tl.EndTS,
+		&tl.SecsSpent,
+		&tl.Comment,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tl, fmt.Errorf("%w (ID: %d)", ErrTLNotFound, id)
+	} else if err != nil {
+		return tl, err
+	}
+	tl.BeginTS = tl.BeginTS.Local()
+	tl.EndTS = tl.EndTS.Local()
+
+	return tl, nil
+}
+
+// DeleteTL moves a saved task log to the trash; the time spent on it no
+// longer counts towards its task.
+func DeleteTL(db *sql.DB, entry *domain.TaskLogEntry, deletedAt time.Time) error {
 	return runInTx(db, func(tx *sql.Tx) error {
-		stmt, err := tx.Prepare(`
-DELETE from task_log
-WHERE ID=?;
-`)
+		tl, err := fetchSavedTLByID(tx, entry.ID)
 		if err != nil {
 			return err
 		}
-		defer stmt.Close()
 
-		_, err = stmt.Exec(entry.ID)
+		_, err = tx.Exec(`
+UPDATE task_log
+SET deleted_at = ?
+WHERE id = ?;
+`, deletedAt.UTC(), tl.ID)
 		if err != nil {
 			return err
 		}
 
-		tStmt, err := tx.Prepare(`
+		return addToTaskSecsSpent(tx, tl.TaskID, -tl.SecsSpent)
+	})
+}
+
+// ImportTLs inserts task logs, creating tasks as needed, in a single
+// transaction; either all entries are imported, or none are. Finished entries
+// can be imported while a task is being tracked, but at most one entry can be
+// active, and only if no task is being tracked already.
+func ImportTLs(db *sql.DB, entries []ImportedTL) (ImportResult, error) {
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (ImportResult, error) {
+		var result ImportResult
+		taskIDs := make(map[string]int)
+
+		for _, entry := range entries {
+			taskID, ok := taskIDs[entry.TaskSummary]
+			if !ok {
+				row := tx.QueryRow(`
+SELECT id
+FROM task
+WHERE summary = ?
+AND deleted_at IS NULL
+ORDER BY id
+LIMIT 1;
+`, entry.TaskSummary)
+				err := row.Scan(&taskID)
+				if errors.Is(err, sql.ErrNoRows) {
+					taskID, err = insertTask(tx, entry.TaskSummary)
+					if err != nil {
+						return result, err
+					}
+					result.NumTasksCreated++
+				} else if err != nil {
+					return result, err
+				}
+				taskIDs[entry.TaskSummary] = taskID
+			}
+
+			var tlID int
+			var err error
+			if entry.Active {
+				tlID, err = insertNewTL(tx, taskID, entry.BeginTS, entry.Comment)
+				if err != nil {
+					return result, err
+				}
+				result.ActiveTLID = tlID
+			} else {
+				tlID, err = insertManualTL(tx, taskID, entry.BeginTS, entry.EndTS, entry.Comment)
+				if err != nil {
+					return result, err
+				}
+				result.NumTLsInserted++
+			}
+
+			if entry.ExternalID == "" {
+				continue
+			}
+
+			_, err = tx.Exec(`
+INSERT INTO imported_entry (source, external_id, task_log_id, created_at)
+VALUES (?, ?, ?, ?);
+`, entry.Source, entry.ExternalID, tlID, time.Now().UTC())
+			if err != nil {
+				return result, err
+			}
+		}
+
+		return result, nil
+	})
+}
+
+// FetchImportedExternalIDs returns the IDs of the entries imported from a
+// source so far.
+func FetchImportedExternalIDs(db *sql.DB, source string) ([]string, error) {
+	rows, err := db.Query(`
+SELECT external_id
+FROM imported_entry
+WHERE source = ?;
+`, source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func insertTask(tx *sql.Tx, summary string) (int, error) {
+	stmt, err := tx.Prepare(`
+INSERT into task (summary, active, created_at, updated_at)
+VALUES (?, true, ?, ?);
+`)
+	if err != nil {
+		return -1, err
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	res, err := stmt.Exec(summary, now, now)
+	if err != nil {
+		return -1, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(lastID), nil
+}
+
+func insertNewTL(tx *sql.Tx, taskID int, beginTs time.Time, comment *string) (int, error) {
+	stmt, err := tx.Prepare(`
+INSERT INTO task_log (task_id, begin_ts, comment, active)
+VALUES (?, ?, ?, ?);
+`)
+	if err != nil {
+		return -1, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(taskID, beginTs.UTC(), comment, true)
+	if err != nil {
+		return -1, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(lastID), nil
+}
+
+func insertManualTL(tx *sql.Tx, taskID int, beginTs time.Time, endTs time.Time, comment *string) (int, error) {
+	stmt, err := tx.Prepare(`
+INSERT INTO task_log (task_id, begin_ts, end_ts, secs_spent, comment, active)
+VALUES (?, ?, ?, ?, ?, ?);
+`)
+	if err != nil {
+		return -1, err
+	}
+	defer stmt.Close()
+
+	secsSpent := int(endTs.Sub(beginTs).Seconds())
+
+	res, err := stmt.Exec(taskID, beginTs.UTC(), endTs.UTC(), secsSpent, comment, false)
+	if err != nil {
+		return -1, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	tStmt, err := tx.Prepare(`
 UPDATE task
-SET secs_spent = secs_spent-?,
+SET secs_spent = secs_spent+?,
     updated_at = ?
 WHERE id = ?;
     `)
-		if err != nil {
-			return err
-		}
-		defer tStmt.Close()
+	if err != nil {
+		return -1, err
+	}
+	defer tStmt.Close()
 
-		_, err = tStmt.Exec(entry.SecsSpent, time.Now().UTC(), entry.TaskID)
-		return err
-	})
+	_, err = tStmt.Exec(secsSpent, time.Now().UTC(), taskID)
+	if err != nil {
+		return -1, err
+	}
+
+	return int(lastID), nil
 }
 
 func runInTxAndReturnID(db *sql.DB, fn func(tx *sql.Tx) (int, error)) (int, error) {
@@ -54,6 +54,93 @@ func TestRepository(t *testing.T) {
 		assert.Zero(t, task.SecsSpent)
 	})
 
+	t.Run("TestImportTLs", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now().Truncate(time.Second)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		comment := testComment
+		entries := []ImportedTL{
+			{
+				TaskSummary: "seeded task 1",
+				BeginTS:     referenceTS.Add(time.Hour * -2),
+				EndTS:       referenceTS.Add(time.Hour * -1),
+				Comment:     &comment,
+			},
+			{
+				TaskSummary: "imported task",
+				BeginTS:     referenceTS.Add(time.Hour * -3),
+				EndTS:       referenceTS.Add(time.Hour * -2),
+			},
+			{
+				TaskSummary: "imported task",
+				BeginTS:     referenceTS.Add(time.Hour * -1),
+				EndTS:       referenceTS,
+			},
+		}
+
+		// WHEN
+		result, err := ImportTLs(testDB, entries)
+
+		// THEN
+		require.NoError(t, err, "failed to import task logs")
+		assert.Equal(t, 1, result.NumTasksCreated)
+		assert.Equal(t, 3, result.NumTLsInserted)
+
+		seededTask, err := fetchTaskByID(testDB, 1)
+		require.NoError(t, err, "failed to fetch task")
+		assert.Equal(t, 6*secsInOneHour, seededTask.SecsSpent)
+
+		importedTask, err := fetchTaskByID(testDB, 3)
+		require.NoError(t, err, "failed to fetch task")
+		assert.Equal(t, "imported task", importedTask.Summary)
+		assert.Equal(t, 2*secsInOneHour, importedTask.SecsSpent)
+
+		taskLog, err := fetchTLByID(testDB, 4)
+		require.NoError(t, err, "failed to fetch task log")
+		assert.Equal(t, 1, taskLog.TaskID)
+		require.NotNil(t, taskLog.Comment)
+		assert.Equal(t, comment, *taskLog.Comment)
+	})
+
+	t.Run("TestImportTLs with an active entry", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now().Truncate(time.Second)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		entries := []ImportedTL{
+			{
+				TaskSummary: "seeded task 2",
+				BeginTS:     referenceTS.Add(time.Hour * -2),
+				EndTS:       referenceTS.Add(time.Hour * -1),
+			},
+			{
+				TaskSummary: "seeded task 2",
+				BeginTS:     referenceTS.Add(time.Hour * -1),
+				Active:      true,
+			},
+		}
+
+		// WHEN
+		result, err := ImportTLs(testDB, entries)
+
+		// THEN
+		require.NoError(t, err, "failed to import task logs")
+		assert.Equal(t, 1, result.NumTLsInserted)
+
+		activeTaskDetails, err := FetchActiveTaskDetails(testDB)
+		require.NoError(t, err, "failed to fetch active task details")
+		assert.Equal(t, result.ActiveTLID, activeTaskDetails.CurrentLogID)
+		assert.Equal(t, 2, activeTaskDetails.TaskID)
+
+		_, err = ImportTLs(testDB, entries[1:])
+		assert.Error(t, err, "importing an active entry while a task is active should fail")
+	})
+
 	t.Run("EditActiveTL", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
@@ -65,7 +152,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now()
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		_, insertErr := InsertNewTL(testDB, taskID, beginTS)
+		_, insertErr := InsertNewTL(testDB, taskID, beginTS, nil)
 		require.NoError(t, insertErr, "failed to insert task log")
 
 		// WHEN
@@ -102,7 +189,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now()
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS)
+		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS, nil)
 		require.NoError(t, insertErr, "failed to insert task log")
 
 		taskBefore, err := fetchTaskByID(testDB, taskID)
@@ -111,10 +198,11 @@ func TestRepository(t *testing.T) {
 
 		// WHEN
 		comment := testComment
-		err = FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, numSeconds, &comment)
+		secsSpent, err := FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, &comment)
 
 		// THEN
 		require.NoError(t, err, "failed to update task log")
+		assert.Equal(t, numSeconds, secsSpent)
 
 		taskLog, err := fetchTLByID(testDB, tlID)
 		require.NoError(t, err, "failed to fetch task log")
@@ -139,11 +227,11 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now()
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS)
+		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS, nil)
 		require.NoError(t, insertErr, "failed to insert task log")
 
 		// WHEN
-		err = FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, numSeconds, nil)
+		_, err = FinishActiveTL(testDB, tlID, taskID, beginTS, endTS, nil)
 
 		// THEN
 		require.NoError(t, err, "failed to update task log")
@@ -167,14 +255,14 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		now := time.Now().Truncate(time.Second)
 		beginTS := now.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS)
+		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS, nil)
 		require.NoError(t, insertErr, "failed to insert task log")
 
 		taskBefore, err := fetchTaskByID(testDB, taskID)
 		require.NoError(t, err, "failed to fetch task")
 
 		// WHEN
-		result, err := QuickSwitchActiveTL(testDB, secondTaskID, now)
+		result, err := QuickSwitchActiveTL(testDB, secondTaskID, now, nil)
 
 		// THEN
 		require.NoError(t, err, "failed to quick switch active task")
@@ -198,6 +286,78 @@ func TestRepository(t *testing.T) {
 		require.Nil(t, activeTL.Comment)
 	})
 
+	t.Run("TestFetchSavedTLByID", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now().Truncate(time.Second)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		taskID := 1
+		endTS := referenceTS.Add(time.Hour * -1)
+		beginTS := endTS.Add(time.Minute * -45)
+		comment := testComment
+		savedTLID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment, false)
+		require.NoError(t, err, "failed to insert task log")
+		activeTLID, err := InsertNewTL(testDB, taskID, referenceTS, nil)
+		require.NoError(t, err, "failed to insert active task log")
+
+		// WHEN
+		savedTL, savedErr := FetchSavedTLByID(testDB, savedTLID)
+		_, activeErr := FetchSavedTLByID(testDB, activeTLID)
+		_, missingErr := FetchSavedTLByID(testDB, 1000)
+
+		// THEN
+		require.NoError(t, savedErr)
+		assert.Equal(t, savedTLID, savedTL.ID)
+		assert.Equal(t, taskID, savedTL.TaskID)
+		assert.Equal(t, seedData.tasks[0].Summary, savedTL.TaskSummary)
+		assert.True(t, beginTS.Equal(savedTL.BeginTS))
+		assert.True(t, endTS.Equal(savedTL.EndTS))
+		assert.Equal(t, 45*60, savedTL.SecsSpent)
+		require.NotNil(t, savedTL.Comment)
+		assert.Equal(t, comment, *savedTL.Comment)
+
+		assert.ErrorIs(t, activeErr, ErrTLNotFound)
+		assert.ErrorIs(t, missingErr, ErrTLNotFound)
+	})
+
+	t.Run("TestQuickSwitchActiveTL sets comment on the new active task log", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Now().Truncate(time.Second)
+		seedData := getTestData(referenceTS)
+		seedDB(t, testDB, seedData)
+		taskID := 1
+		secondTaskID := 2
+		now := time.Now().Truncate(time.Second)
+		beginTS := now.Add(time.Minute * -30)
+		firstComment := "first comment"
+		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS, &firstComment)
+		require.NoError(t, insertErr, "failed to insert task log")
+
+		// WHEN
+		comment := testComment
+		result, err := QuickSwitchActiveTL(testDB, secondTaskID, now, &comment)
+
+		// THEN
+		require.NoError(t, err, "failed to quick switch active task")
+
+		finishedTL, err := fetchTLByID(testDB, tlID)
+		require.NoError(t, err, "failed to fetch last active task log")
+
+		activeTaskDetails, err := FetchActiveTaskDetails(testDB)
+		require.NoError(t, err, "failed to fetch active task details")
+
+		require.NotNil(t, finishedTL.Comment)
+		assert.Equal(t, firstComment, *finishedTL.Comment)
+		assert.Equal(t, result.CurrentlyActiveTLID, activeTaskDetails.CurrentLogID)
+		assert.Equal(t, secondTaskID, activeTaskDetails.TaskID)
+		require.NotNil(t, activeTaskDetails.CurrentLogComment)
+		assert.Equal(t, comment, *activeTaskDetails.CurrentLogComment)
+	})
+
 	t.Run("TestQuickSwitchActiveTL works correctly with edited active task log", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
@@ -210,7 +370,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		now := time.Now().Truncate(time.Second)
 		beginTS := now.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS)
+		tlID, insertErr := InsertNewTL(testDB, taskID, beginTS, nil)
 		require.NoError(t, insertErr, "failed to insert task log")
 
 		taskBefore, err := fetchTaskByID(testDB, taskID)
@@ -222,7 +382,7 @@ func TestRepository(t *testing.T) {
 		require.NoError(t, err, "failed to update active task log")
 
 		// WHEN
-		result, err := QuickSwitchActiveTL(testDB, secondTaskID, now)
+		result, err := QuickSwitchActiveTL(testDB, secondTaskID, now, nil)
 
 		// THEN
 		require.NoError(t, err, "failed to quick switch active task")
@@ -254,7 +414,7 @@ func TestRepository(t *testing.T) {
 		now := time.Now().Truncate(time.Second)
 
 		// WHEN
-		_, err := QuickSwitchActiveTL(testDB, 1, now)
+		_, err := QuickSwitchActiveTL(testDB, 1, now, nil)
 
 		// THEN
 		require.ErrorIs(t, ErrNoTaskActive, err)
@@ -278,7 +438,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now()
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment)
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment, false)
 
 		// THEN
 		require.NoError(t, err, "failed to insert task log")
@@ -308,7 +468,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now()
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, nil)
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, nil, false)
 
 		// THEN
 		require.NoError(t, err, "failed to insert task log")
@@ -333,7 +493,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now().Truncate(time.Second)
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment)
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 		taskBefore, err := fetchTaskByID(testDB, taskID)
 		require.NoError(t, err, "failed to fetch task after tl insert")
@@ -343,7 +503,7 @@ func TestRepository(t *testing.T) {
 		updatedComment := testCommentUpdated
 		newBeginTS := beginTS.Add(time.Second * -1 * time.Duration(numSecondsDelta*2))
 		newEndTS := endTS.Add(time.Second * -1 * time.Duration(numSecondsDelta))
-		_, err = EditSavedTL(testDB, tlID, newBeginTS, newEndTS, &updatedComment)
+		_, err = EditSavedTL(testDB, tlID, newBeginTS, newEndTS, &updatedComment, false)
 
 		// THEN
 		require.NoError(t, err, "failed to edit saved task log")
@@ -375,7 +535,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now().Truncate(time.Second)
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment)
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 		taskBefore, err := fetchTaskByID(testDB, taskID)
 		require.NoError(t, err, "failed to fetch task after tl insert")
@@ -384,7 +544,7 @@ func TestRepository(t *testing.T) {
 		numSecondsDelta := 60
 		updatedComment := testCommentUpdated
 		newBeginTS := beginTS.Add(time.Second * time.Duration(numSecondsDelta))
-		_, err = EditSavedTL(testDB, tlID, newBeginTS, endTS, &updatedComment)
+		_, err = EditSavedTL(testDB, tlID, newBeginTS, endTS, &updatedComment, false)
 
 		// THEN
 		require.NoError(t, err, "failed to edit saved task log")
@@ -416,7 +576,7 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		endTS := time.Now().Truncate(time.Second)
 		beginTS := endTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment)
+		tlID, err := InsertManualTL(testDB, taskID, beginTS, endTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 		taskBefore, err := fetchTaskByID(testDB, taskID)
 		require.NoError(t, err, "failed to fetch task after tl insert")
@@ -426,7 +586,7 @@ func TestRepository(t *testing.T) {
 		updatedComment := testCommentUpdated
 		newBeginTS := beginTS.Add(time.Second * -1 * time.Duration(numSecondsDelta))
 		newEndTS := endTS.Add(time.Second * -1 * time.Duration(numSecondsDelta))
-		_, err = EditSavedTL(testDB, tlID, newBeginTS, newEndTS, &updatedComment)
+		_, err = EditSavedTL(testDB, tlID, newBeginTS, newEndTS, &updatedComment, false)
 
 		// THEN
 		require.NoError(t, err, "failed to edit saved task log")
@@ -461,7 +621,7 @@ func TestRepository(t *testing.T) {
 		require.NoError(t, err, "failed to fetch task log")
 
 		// WHEN
-		err = DeleteTL(testDB, &taskLog)
+		err = DeleteTL(testDB, &taskLog, referenceTS)
 
 		// THEN
 		require.NoError(t, err, "failed to insert task log")
@@ -470,6 +630,9 @@ func TestRepository(t *testing.T) {
 		require.NoError(t, err, "failed to fetch task")
 
 		assert.Equal(t, numSecondsBefore-taskLog.SecsSpent, taskAfter.SecsSpent)
+
+		_, err = FetchSavedTLByID(testDB, tlID)
+		assert.ErrorIs(t, err, ErrTLNotFound)
 	})
 
 	t.Run("TestFetchTLEntriesBetweenTS for all tasks", func(t *testing.T) {
@@ -485,12 +648,12 @@ func TestRepository(t *testing.T) {
 		tlEndTS := referenceTS.Add(time.Hour * 2)
 		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
 		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 
 		// WHEN
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusAny, 100)
+		entries, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskFilter{Status: types.TaskStatusAny}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -510,7 +673,7 @@ func TestRepository(t *testing.T) {
 
 		// WHEN
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 10 * -1)
-		entries, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusActive, 100)
+		entries, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskFilter{Status: types.TaskStatusActive}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -530,13 +693,36 @@ func TestRepository(t *testing.T) {
 
 		// WHEN
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 10 * -1)
-		entries, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusInactive, 100)
+		entries, err := FetchTLEntriesBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskFilter{Status: types.TaskStatusInactive}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
 		require.Len(t, entries, 1)
 	})
 
+	t.Run("TestFetchTLEntriesOverlapping", func(t *testing.T) {
+		t.Cleanup(func() { cleanupDB(t, testDB) })
+
+		// GIVEN
+		referenceTS := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.Local)
+		taskID, err := InsertTask(testDB, "task")
+		require.NoError(t, err, "failed to insert task")
+		for _, offset := range []int{0, 2, 4} {
+			beginTS := referenceTS.Add(time.Hour * time.Duration(offset))
+			_, err = InsertManualTL(testDB, int(taskID), beginTS, beginTS.Add(time.Hour), nil, false)
+			require.NoError(t, err, "failed to insert task log")
+		}
+
+		// WHEN
+		// ends when the first one does, and begins when the third one ends
+		entries, err := FetchTLEntriesOverlapping(testDB, referenceTS.Add(time.Hour), referenceTS.Add(time.Hour*4), 100)
+
+		// THEN
+		require.NoError(t, err, "failed to fetch overlapping entries")
+		require.Len(t, entries, 1)
+		assert.True(t, referenceTS.Add(time.Hour*2).Equal(entries[0].BeginTS))
+	})
+
 	t.Run("TestFetchStats for all tasks", func(t *testing.T) {
 		t.Cleanup(func() { cleanupDB(t, testDB) })
 
@@ -550,11 +736,11 @@ func TestRepository(t *testing.T) {
 		numSeconds := 60 * 90
 		tlEndTS := referenceTS.Add(time.Hour * 2)
 		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 
 		// WHEN
-		entries, err := FetchStats(testDB, types.TaskStatusAny, 100)
+		entries, err := FetchStats(testDB, types.TaskFilter{Status: types.TaskStatusAny}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -581,7 +767,7 @@ func TestRepository(t *testing.T) {
 		require.NoError(t, err, "failed to make task inactive")
 
 		// WHEN
-		entries, err := FetchStats(testDB, types.TaskStatusActive, 100)
+		entries, err := FetchStats(testDB, types.TaskFilter{Status: types.TaskStatusActive}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -604,7 +790,7 @@ func TestRepository(t *testing.T) {
 		require.NoError(t, err, "failed to make task inactive")
 
 		// WHEN
-		entries, err := FetchStats(testDB, types.TaskStatusInactive, 100)
+		entries, err := FetchStats(testDB, types.TaskFilter{Status: types.TaskStatusInactive}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -628,12 +814,12 @@ func TestRepository(t *testing.T) {
 		tlEndTS := referenceTS.Add(time.Hour * 2)
 		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
 		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 
 		// WHEN
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusAny, 100)
+		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskFilter{Status: types.TaskStatusAny}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -661,14 +847,14 @@ func TestRepository(t *testing.T) {
 		tlEndTS := referenceTS.Add(time.Hour * 2)
 		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
 		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 		err = UpdateTaskActiveStatus(testDB, 2, false)
 		require.NoError(t, err, "failed to make task inactive")
 
 		// WHEN
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusActive, 100)
+		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskFilter{Status: types.TaskStatusActive}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -692,14 +878,14 @@ func TestRepository(t *testing.T) {
 		tlEndTS := referenceTS.Add(time.Hour * 2)
 		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
 		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 		err = UpdateTaskActiveStatus(testDB, 1, false)
 		require.NoError(t, err, "failed to make task inactive")
 
 		// WHEN
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusInactive, 100)
+		entries, err := FetchStatsBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskFilter{Status: types.TaskStatusInactive}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -723,12 +909,12 @@ func TestRepository(t *testing.T) {
 		tlEndTS := referenceTS.Add(time.Hour * 2)
 		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
 		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 
 		// WHEN
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusAny, 100)
+		entries, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskFilter{Status: types.TaskStatusAny}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -756,7 +942,7 @@ func TestRepository(t *testing.T) {
 		tlEndTS := referenceTS.Add(time.Hour * 2)
 		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
 		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 
 		err = UpdateTaskActiveStatus(testDB, 2, false)
@@ -764,7 +950,7 @@ func TestRepository(t *testing.T) {
 
 		// WHEN
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusActive, 100)
+		entries, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskFilter{Status: types.TaskStatusActive}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -788,7 +974,7 @@ func TestRepository(t *testing.T) {
 		tlEndTS := referenceTS.Add(time.Hour * 2)
 		tlBeginTS := tlEndTS.Add(time.Second * -1 * time.Duration(numSeconds))
 		comment := taskLogComment
-		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment)
+		_, err = InsertManualTL(testDB, taskID, tlBeginTS, tlEndTS, &comment, false)
 		require.NoError(t, err, "failed to insert task log")
 
 		err = UpdateTaskActiveStatus(testDB, 1, false)
@@ -796,7 +982,7 @@ func TestRepository(t *testing.T) {
 
 		// WHEN
 		reportBeginTS := referenceTS.Add(time.Hour * 24 * 7 * -2)
-		entries, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskStatusInactive, 100)
+		entries, err := FetchReportBetweenTS(testDB, reportBeginTS, referenceTS, types.TaskFilter{Status: types.TaskStatusInactive}, 100)
 
 		// THEN
 		require.NoError(t, err, "failed to fetch report entries")
@@ -815,13 +1001,34 @@ func cleanupDB(t *testing.T, testDB *sql.DB) {
 	t.Helper()
 
 	var err error
-	for _, tbl := range []string{"task_log", "task"} {
+	for _, tbl := range []string{
+		"imported_entry",
+		"task_log_pause",
+		"task_log",
+		"task_tag",
+		"tag",
+		"goal",
+		"task",
+		"project",
+		"client",
+	} {
 		_, err = testDB.Exec(fmt.Sprintf("DELETE FROM %s", tbl))
 		require.NoErrorf(t, err, "failed to clean up table %q: %v", tbl, err)
 
 		_, err := testDB.Exec("DELETE FROM sqlite_sequence WHERE name=?;", tbl)
 		require.NoErrorf(t, err, "failed to reset auto increment for table %q: %v", tbl, err)
 	}
+
+	// tasks belong to the "unassigned" project unless they're moved to another
+	// one, so it needs to be seeded again, the same way migrations do
+	_, err = testDB.Exec(`
+INSERT INTO client (id, name, created_at)
+VALUES (1, 'unassigned', CURRENT_TIMESTAMP);
+
+INSERT INTO project (id, name, client_id, created_at)
+VALUES (1, 'unassigned', 1, CURRENT_TIMESTAMP);
+`)
+	require.NoErrorf(t, err, "failed to seed the unassigned project: %v", err)
 }
 
 type testData struct {
@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+)
+
+func getRate(hundredths sql.NullInt64, currency sql.NullString) *domain.Rate {
+	if !hundredths.Valid || !currency.Valid {
+		return nil
+	}
+
+	return &domain.Rate{Hundredths: int(hundredths.Int64), Currency: currency.String}
+}
+
+func rateValues(rate *domain.Rate) (any, any) {
+	if rate == nil {
+		return nil, nil
+	}
+
+	return rate.Hundredths, rate.Currency
+}
+
+// SetProjectRate sets the hourly rate of a project; a nil rate unsets it.
+func SetProjectRate(db *sql.DB, projectID int, rate *domain.Rate) error {
+	hundredths, currency := rateValues(rate)
+	_, err := db.Exec(`
+UPDATE project
+SET hourly_rate = ?,
+    currency = ?
+WHERE id = ?;
+`, hundredths, currency, projectID)
+
+	return err
+}
+
+// SetTaskRate sets the hourly rate of a task, which takes precedence over its
+// project's; a nil rate unsets it.
+func SetTaskRate(db *sql.DB, taskID int, rate *domain.Rate) error {
+	hundredths, currency := rateValues(rate)
+	_, err := db.Exec(`
+UPDATE task
+SET hourly_rate = ?,
+    currency = ?,
+    updated_at = ?
+WHERE id = ?;
+`, hundredths, currency, time.Now().UTC(), taskID)
+
+	return err
+}
+
+func SetTaskBillable(db *sql.DB, taskID int, billable bool) error {
+	_, err := db.Exec(`
+UPDATE task
+SET billable = ?,
+    updated_at = ?
+WHERE id = ?;
+`, billable, time.Now().UTC(), taskID)
+
+	return err
+}
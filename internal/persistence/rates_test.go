@@ -0,0 +1,53 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRates(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+
+	projectID, err := InsertProject(db, "website", "acme")
+	require.NoError(t, err)
+	taskID, err := InsertTaskWithDetails(db, "fix login bug", projectID, nil)
+	require.NoError(t, err)
+	otherTaskID, err := InsertTaskWithDetails(db, "team lunch", projectID, nil)
+	require.NoError(t, err)
+
+	// WHEN
+	err = SetProjectRate(db, projectID, &domain.Rate{Hundredths: 12050, Currency: "EUR"})
+	require.NoError(t, err)
+	err = SetTaskRate(db, taskID, &domain.Rate{Hundredths: 15000, Currency: "USD"})
+	require.NoError(t, err)
+	err = SetTaskRate(db, otherTaskID, &domain.Rate{Hundredths: 1000, Currency: "USD"})
+	require.NoError(t, err)
+	err = SetTaskRate(db, otherTaskID, nil)
+	require.NoError(t, err)
+	err = SetTaskBillable(db, otherTaskID, false)
+	require.NoError(t, err)
+
+	// THEN
+	projects, err := FetchProjects(db)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	assert.Equal(t, &domain.Rate{Hundredths: 12050, Currency: "EUR"}, projects[0].Rate)
+	assert.Nil(t, projects[1].Rate)
+
+	tasks, err := FetchTasksWithStatus(db, types.TaskStatusAny, 10)
+	require.NoError(t, err)
+	tasksByID := make(map[int]domain.Task)
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+	}
+	assert.Equal(t, &domain.Rate{Hundredths: 15000, Currency: "USD"}, tasksByID[taskID].Rate)
+	assert.True(t, tasksByID[taskID].Billable)
+	assert.Nil(t, tasksByID[otherTaskID].Rate)
+	assert.False(t, tasksByID[otherTaskID].Billable)
+}
@@ -0,0 +1,427 @@
+package persistence
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+)
+
+var (
+	ErrSnapshotOutdated = errors.New("db: task logs or tasks have changed since")
+	ErrAnotherTLActive  = errors.New("db: another task is being tracked right now")
+)
+
+// Snapshot holds the state of some task logs (along with their pauses, and
+// the entries they were imported from), and the active status of some tasks,
+// so that it can be restored later on. A task log that doesn't exist is part
+// of a snapshot as well; restoring the snapshot deletes it.
+type Snapshot struct {
+	tlIDs      []int
+	taskIDs    []int
+	tls        map[int]snapshotTL
+	pauses     map[int][]domain.TaskLogPause
+	imported   map[int][]importedEntry
+	taskActive map[int]bool
+}
+
+type snapshotTL struct {
+	taskID    int
+	beginTS   time.Time
+	endTS     sql.NullTime
+	secsSpent int
+	comment   sql.NullString
+	active    bool
+	deletedAt sql.NullTime
+}
+
+// countsTowardsTask returns whether the time spent on a task log is part of
+// its task's total.
+func (tl snapshotTL) countsTowardsTask() bool {
+	return !tl.active && !tl.deletedAt.Valid
+}
+
+type importedEntry struct {
+	id         int
+	source     string
+	externalID string
+	createdAt  time.Time
+}
+
+// HasActiveTL returns whether the snapshot includes the active task log.
+func (s Snapshot) HasActiveTL() bool {
+	for _, tl := range s.tls {
+		if tl.active {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TakeSnapshot captures the state of the task logs with the IDs tlIDs, and
+// of the tasks with the IDs taskIDs.
+func TakeSnapshot(db *sql.DB, tlIDs, taskIDs []int) (Snapshot, error) {
+	return takeSnapshot(db, tlIDs, taskIDs)
+}
+
+// RestoreSnapshot brings the task logs and tasks covered by two snapshots
+// back to the state in target, as long as they're still in the state captured
+// in current. The time spent on the tasks of the affected task logs is
+// adjusted accordingly.
+func RestoreSnapshot(db *sql.DB, current, target Snapshot) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		tlIDs := unionOfIDs(current.tlIDs, target.tlIDs)
+		taskIDs := unionOfIDs(current.taskIDs, target.taskIDs)
+
+		actual, err := takeSnapshot(tx, tlIDs, taskIDs)
+		if err != nil {
+			return err
+		}
+
+		if !actual.matches(current) {
+			return ErrSnapshotOutdated
+		}
+
+		if target.HasActiveTL() {
+			activeTLID, err := fetchActiveTLID(tx)
+			if err != nil && !errors.Is(err, ErrNoTaskActive) {
+				return err
+			}
+
+			if err == nil && !slices.Contains(tlIDs, activeTLID) {
+				return ErrAnotherTLActive
+			}
+		}
+
+		secsDelta := make(map[int]int)
+		for _, tl := range actual.tls {
+			if tl.countsTowardsTask() {
+				secsDelta[tl.taskID] -= tl.secsSpent
+			}
+		}
+
+		for _, tl := range target.tls {
+			if tl.countsTowardsTask() {
+				secsDelta[tl.taskID] += tl.secsSpent
+			}
+		}
+
+		// task logs that exist on both sides are restored in place; the ones
+		// missing from target are deleted before the ones missing from actual
+		// are inserted, since no task log can be inserted as active while
+		// another one is
+		for _, id := range tlIDs {
+			_, inActual := actual.tls[id]
+			_, inTarget := target.tls[id]
+			if inActual && !inTarget {
+				err = hardDeleteTL(tx, id)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, id := range tlIDs {
+			_, inActual := actual.tls[id]
+			tl, inTarget := target.tls[id]
+			if inActual && inTarget {
+				err = updateTLFromSnapshot(tx, id, tl)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, id := range tlIDs {
+			_, inActual := actual.tls[id]
+			tl, inTarget := target.tls[id]
+			if !inActual && inTarget {
+				err = insertTLFromSnapshot(tx, id, tl)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, id := range tlIDs {
+			err = restoreTLRelations(tx, target, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, id := range taskIDs {
+			active, ok := target.taskActive[id]
+			if !ok {
+				continue
+			}
+
+			_, err = tx.Exec(`
+UPDATE task
+SET active = ?,
+    updated_at = ?
+WHERE id = ?;
+`, active, time.Now().UTC(), id)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, taskID := range slices.Sorted(maps.Keys(secsDelta)) {
+			err = addToTaskSecsSpent(tx, taskID, secsDelta[taskID])
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func takeSnapshot(q querier, tlIDs, taskIDs []int) (Snapshot, error) {
+	snapshot := Snapshot{
+		tlIDs:      slices.Clone(tlIDs),
+		taskIDs:    slices.Clone(taskIDs),
+		tls:        make(map[int]snapshotTL),
+		pauses:     make(map[int][]domain.TaskLogPause),
+		imported:   make(map[int][]importedEntry),
+		taskActive: make(map[int]bool),
+	}
+
+	for _, id := range tlIDs {
+		var tl snapshotTL
+		err := q.QueryRow(`
+SELECT task_id, begin_ts, end_ts, secs_spent, comment, active, deleted_at
+FROM task_log
+WHERE id = ?;
+`, id).Scan(&tl.taskID, &tl.beginTS, &tl.endTS, &tl.secsSpent, &tl.comment, &tl.active, &tl.deletedAt)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return snapshot, err
+		default:
+			snapshot.tls[id] = tl
+		}
+
+		pauses, err := fetchTLPauses(q, id)
+		if err != nil {
+			return snapshot, err
+		}
+		if len(pauses) > 0 {
+			snapshot.pauses[id] = pauses
+		}
+
+		imported, err := fetchImportedEntries(q, id)
+		if err != nil {
+			return snapshot, err
+		}
+		if len(imported) > 0 {
+			snapshot.imported[id] = imported
+		}
+	}
+
+	for _, id := range taskIDs {
+		var active bool
+		err := q.QueryRow(`
+SELECT active
+FROM task
+WHERE id = ?;
+`, id).Scan(&active)
+		if errors.Is(err, sql.ErrNoRows) {
+			return snapshot, fmt.Errorf("%w (ID: %d)", ErrTaskNotFound, id)
+		} else if err != nil {
+			return snapshot, err
+		}
+		snapshot.taskActive[id] = active
+	}
+
+	return snapshot, nil
+}
+
+func fetchImportedEntries(q querier, tlID int) ([]importedEntry, error) {
+	rows, err := q.Query(`
+SELECT id, source, external_id, created_at
+FROM imported_entry
+WHERE task_log_id = ?
+ORDER BY id;
+`, tlID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []importedEntry
+	for rows.Next() {
+		var entry importedEntry
+		err = rows.Scan(&entry.id, &entry.source, &entry.externalID, &entry.createdAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// matches compares the task logs (and their pauses) and the active status of
+// tasks in two snapshots; the task logs covered by only one of them are
+// expected to not exist in the other.
+func (s Snapshot) matches(other Snapshot) bool {
+	for _, id := range unionOfIDs(s.tlIDs, other.tlIDs) {
+		tl, ok := s.tls[id]
+		otherTL, otherOk := other.tls[id]
+		if ok != otherOk {
+			return false
+		}
+
+		if ok && !tl.equals(otherTL) {
+			return false
+		}
+
+		if !pausesEqual(s.pauses[id], other.pauses[id]) {
+			return false
+		}
+	}
+
+	for id, active := range s.taskActive {
+		otherActive, ok := other.taskActive[id]
+		if ok && active != otherActive {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (tl snapshotTL) equals(other snapshotTL) bool {
+	return tl.taskID == other.taskID &&
+		tl.beginTS.Equal(other.beginTS) &&
+		tl.endTS.Valid == other.endTS.Valid &&
+		tl.endTS.Time.Equal(other.endTS.Time) &&
+		tl.secsSpent == other.secsSpent &&
+		tl.comment == other.comment &&
+		tl.active == other.active &&
+		tl.deletedAt.Valid == other.deletedAt.Valid &&
+		tl.deletedAt.Time.Equal(other.deletedAt.Time)
+}
+
+func pausesEqual(pauses, otherPauses []domain.TaskLogPause) bool {
+	return slices.EqualFunc(pauses, otherPauses, func(a, b domain.TaskLogPause) bool {
+		if a.ID != b.ID || !a.BeginTS.Equal(b.BeginTS) || (a.EndTS == nil) != (b.EndTS == nil) {
+			return false
+		}
+
+		return a.EndTS == nil || a.EndTS.Equal(*b.EndTS)
+	})
+}
+
+func hardDeleteTL(tx *sql.Tx, tlID int) error {
+	err := deleteTLPauses(tx, tlID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+DELETE FROM imported_entry
+WHERE task_log_id = ?;
+`, tlID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+DELETE FROM task_log
+WHERE id = ?;
+`, tlID)
+
+	return err
+}
+
+func updateTLFromSnapshot(tx *sql.Tx, tlID int, tl snapshotTL) error {
+	_, err := tx.Exec(`
+UPDATE task_log
+SET task_id = ?,
+    begin_ts = ?,
+    end_ts = ?,
+    secs_spent = ?,
+    comment = ?,
+    active = ?,
+    deleted_at = ?
+WHERE id = ?;
+`, tl.taskID, tl.beginTS.UTC(), nullTimeArg(tl.endTS), tl.secsSpent, tl.comment, tl.active, nullTimeArg(tl.deletedAt), tlID)
+
+	return err
+}
+
+func insertTLFromSnapshot(tx *sql.Tx, tlID int, tl snapshotTL) error {
+	_, err := tx.Exec(`
+INSERT INTO task_log (id, task_id, begin_ts, end_ts, secs_spent, comment, active, deleted_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+`, tlID, tl.taskID, tl.beginTS.UTC(), nullTimeArg(tl.endTS), tl.secsSpent, tl.comment, tl.active, nullTimeArg(tl.deletedAt))
+
+	return err
+}
+
+// restoreTLRelations replaces the pauses of a task log, and the entries it was
+// imported from, with the ones in snapshot.
+func restoreTLRelations(tx *sql.Tx, snapshot Snapshot, tlID int) error {
+	err := deleteTLPauses(tx, tlID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+DELETE FROM imported_entry
+WHERE task_log_id = ?;
+`, tlID)
+	if err != nil {
+		return err
+	}
+
+	for _, pause := range snapshot.pauses[tlID] {
+		var endTS any
+		if pause.EndTS != nil {
+			endTS = pause.EndTS.UTC()
+		}
+
+		_, err := tx.Exec(`
+INSERT INTO task_log_pause (id, task_log_id, begin_ts, end_ts)
+VALUES (?, ?, ?, ?);
+`, pause.ID, tlID, pause.BeginTS.UTC(), endTS)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range snapshot.imported[tlID] {
+		_, err := tx.Exec(`
+INSERT INTO imported_entry (id, source, external_id, task_log_id, created_at)
+VALUES (?, ?, ?, ?, ?);
+`, entry.id, entry.source, entry.externalID, tlID, entry.createdAt.UTC())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func nullTimeArg(t sql.NullTime) any {
+	if !t.Valid {
+		return nil
+	}
+
+	return t.Time.UTC()
+}
+
+func unionOfIDs(ids, otherIDs []int) []int {
+	union := slices.Concat(ids, otherIDs)
+	slices.Sort(union)
+
+	return slices.Compact(union)
+}
@@ -0,0 +1,224 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoringSnapshotOfDeletedTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	comment := "outline"
+	tlID, err := InsertNewTL(db, taskID, beginTS, &comment)
+	require.NoError(t, err)
+	require.NoError(t, PauseActiveTL(db, beginTS.Add(time.Hour)))
+	require.NoError(t, ResumeActiveTL(db, beginTS.Add(2*time.Hour)))
+	_, err = FinishActiveTL(db, tlID, taskID, beginTS, beginTS.Add(4*time.Hour), &comment)
+	require.NoError(t, err)
+	tl, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+
+	before, err := TakeSnapshot(db, []int{tlID}, []int{taskID})
+	require.NoError(t, err)
+	require.NoError(t, DeleteTL(db, &tl, beginTS.Add(5*time.Hour)))
+	after, err := TakeSnapshot(db, []int{tlID}, []int{taskID})
+	require.NoError(t, err)
+
+	// WHEN
+	outdatedErr := RestoreSnapshot(db, before, after)
+	err = RestoreSnapshot(db, after, before)
+
+	// THEN
+	assert.ErrorIs(t, outdatedErr, ErrSnapshotOutdated)
+	require.NoError(t, err)
+
+	restored, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+	assert.Equal(t, tl, restored)
+
+	pauses, err := fetchTLPauses(db, tlID)
+	require.NoError(t, err)
+	require.Len(t, pauses, 1)
+	assert.True(t, beginTS.Add(time.Hour).Equal(pauses[0].BeginTS))
+
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 3*60*60, task.SecsSpent)
+
+	// WHEN
+	err = RestoreSnapshot(db, before, after)
+
+	// THEN
+	require.NoError(t, err)
+	_, err = FetchSavedTLByID(db, tlID)
+	assert.ErrorIs(t, err, ErrTLNotFound)
+
+	task, err = fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, task.SecsSpent)
+}
+
+func TestRestoringSnapshotOfSplitTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(2*time.Hour), nil, false)
+	require.NoError(t, err)
+
+	before, err := TakeSnapshot(db, []int{tlID}, []int{taskID, otherTaskID})
+	require.NoError(t, err)
+	newTLID, err := SplitTL(db, tlID, beginTS.Add(time.Hour), otherTaskID)
+	require.NoError(t, err)
+	after, err := TakeSnapshot(db, []int{tlID, newTLID}, []int{taskID, otherTaskID})
+	require.NoError(t, err)
+
+	// WHEN
+	err = RestoreSnapshot(db, after, before)
+
+	// THEN
+	require.NoError(t, err)
+
+	tl, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+	assert.True(t, beginTS.Add(2*time.Hour).Equal(tl.EndTS))
+	assert.Equal(t, 2*60*60, tl.SecsSpent)
+
+	_, err = FetchSavedTLByID(db, newTLID)
+	assert.ErrorIs(t, err, ErrTLNotFound)
+
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 2*60*60, task.SecsSpent)
+
+	otherTask, err := fetchTaskByID(db, otherTaskID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, otherTask.SecsSpent)
+}
+
+func TestRestoringSnapshotOfDiscardedTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	tlID, err := InsertNewTL(db, taskID, beginTS, nil)
+	require.NoError(t, err)
+
+	before, err := TakeSnapshot(db, []int{tlID}, []int{taskID})
+	require.NoError(t, err)
+	require.NoError(t, DeleteActiveTL(db))
+	after, err := TakeSnapshot(db, []int{tlID}, []int{taskID})
+	require.NoError(t, err)
+	otherTLID, err := InsertNewTL(db, otherTaskID, beginTS.Add(time.Hour), nil)
+	require.NoError(t, err)
+
+	// WHEN
+	anotherActiveErr := RestoreSnapshot(db, after, before)
+	require.NoError(t, DeleteActiveTL(db))
+	err = RestoreSnapshot(db, after, before)
+
+	// THEN
+	assert.ErrorIs(t, anotherActiveErr, ErrAnotherTLActive)
+	require.NoError(t, err)
+	assert.True(t, before.HasActiveTL())
+	assert.False(t, after.HasActiveTL())
+
+	details, err := FetchActiveTaskDetails(db)
+	require.NoError(t, err)
+	assert.Equal(t, tlID, details.CurrentLogID)
+	assert.NotEqual(t, otherTLID, details.CurrentLogID)
+	assert.Equal(t, taskID, details.TaskID)
+	assert.True(t, beginTS.Equal(details.CurrentLogBeginTS))
+}
+
+func TestRestoringSnapshotOfTaskStatus(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+
+	before, err := TakeSnapshot(db, nil, []int{taskID})
+	require.NoError(t, err)
+	require.NoError(t, UpdateTaskActiveStatus(db, taskID, false))
+	after, err := TakeSnapshot(db, nil, []int{taskID})
+	require.NoError(t, err)
+
+	// WHEN
+	err = RestoreSnapshot(db, after, before)
+
+	// THEN
+	require.NoError(t, err)
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.True(t, task.Active)
+}
+
+func TestRestoringSnapshotsWhileAnotherTaskIsBeingTracked(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(2*time.Hour), nil, false)
+	require.NoError(t, err)
+	otherTLID, err := InsertManualTL(db, taskID, beginTS.Add(2*time.Hour), beginTS.Add(4*time.Hour), nil, false)
+	require.NoError(t, err)
+	tl, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+	activeTLID, err := InsertNewTL(db, otherTaskID, beginTS.Add(5*time.Hour), nil)
+	require.NoError(t, err)
+
+	beforeDelete, err := TakeSnapshot(db, []int{tlID}, []int{taskID})
+	require.NoError(t, err)
+	require.NoError(t, DeleteTL(db, &tl, beginTS.Add(5*time.Hour)))
+	afterDelete, err := TakeSnapshot(db, []int{tlID}, []int{taskID})
+	require.NoError(t, err)
+
+	beforeSplit, err := TakeSnapshot(db, []int{otherTLID}, []int{taskID})
+	require.NoError(t, err)
+	newTLID, err := SplitTL(db, otherTLID, beginTS.Add(3*time.Hour), taskID)
+	require.NoError(t, err)
+	afterSplit, err := TakeSnapshot(db, []int{otherTLID, newTLID}, []int{taskID})
+	require.NoError(t, err)
+
+	// WHEN
+	undoSplitErr := RestoreSnapshot(db, afterSplit, beforeSplit)
+	redoSplitErr := RestoreSnapshot(db, beforeSplit, afterSplit)
+	undoDeleteErr := RestoreSnapshot(db, afterDelete, beforeDelete)
+
+	// THEN
+	require.NoError(t, undoSplitErr)
+	require.NoError(t, redoSplitErr)
+	require.NoError(t, undoDeleteErr)
+
+	restored, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+	assert.Equal(t, tl, restored)
+
+	newTL, err := FetchSavedTLByID(db, newTLID)
+	require.NoError(t, err)
+	assert.True(t, beginTS.Add(3*time.Hour).Equal(newTL.BeginTS))
+
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 4*60*60, task.SecsSpent)
+
+	details, err := FetchActiveTaskDetails(db)
+	require.NoError(t, err)
+	assert.Equal(t, activeTLID, details.CurrentLogID)
+}
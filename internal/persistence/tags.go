@@ -0,0 +1,171 @@
+package persistence
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/types"
+)
+
+// taskFilterSQL returns the conditions (joined by AND, and prefixed with
+// keyword) that restrict a query to the tasks a filter lets in, along with
+// their arguments. The task table needs to be aliased as "t" in the query.
+func taskFilterSQL(filter types.TaskFilter, keyword string) (string, []any) {
+	var conditions []string
+	var args []any
+
+	switch filter.Status {
+	case types.TaskStatusActive:
+		conditions = append(conditions, "t.active is true")
+	case types.TaskStatusInactive:
+		conditions = append(conditions, "t.active is false")
+	}
+
+	tagCondition := func(operator string, tags []string) {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(tags)), ", ")
+		conditions = append(conditions, `t.id `+operator+` (
+    SELECT tt.task_id
+    FROM task_tag tt
+    JOIN tag g ON tt.tag_id = g.id
+    WHERE g.name IN (`+placeholders+`)
+)`)
+		for _, tag := range tags {
+			args = append(args, tag)
+		}
+	}
+
+	if len(filter.Tags) > 0 {
+		tagCondition("IN", filter.Tags)
+	}
+
+	if len(filter.ExcludeTags) > 0 {
+		tagCondition("NOT IN", filter.ExcludeTags)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return "\n" + keyword + " " + strings.Join(conditions, "\nAND ") + "\n", args
+}
+
+func InsertTaskWithDetails(db *sql.DB, summary string, projectID int, tags []string) (int, error) {
+	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
+		id, err := insertTask(tx, summary)
+		if err != nil {
+			return -1, err
+		}
+
+		_, err = tx.Exec(`
+UPDATE task
+SET project_id = ?
+WHERE id = ?;
+`, projectID, id)
+		if err != nil {
+			return -1, err
+		}
+
+		return id, setTaskTags(tx, id, tags)
+	})
+}
+
+func UpdateTaskWithTags(db *sql.DB, id int, summary string, tags []string) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+UPDATE task
+SET summary = ?,
+    updated_at = ?
+WHERE id = ?;
+`, summary, time.Now().UTC(), id)
+		if err != nil {
+			return err
+		}
+
+		return setTaskTags(tx, id, tags)
+	})
+}
+
+// SetTaskTags replaces the tags of a task.
+func SetTaskTags(db *sql.DB, taskID int, tags []string) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		return setTaskTags(tx, taskID, tags)
+	})
+}
+
+func setTaskTags(tx *sql.Tx, taskID int, tags []string) error {
+	_, err := tx.Exec(`
+DELETE FROM task_tag
+WHERE task_id = ?;
+`, taskID)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		_, err = tx.Exec(`
+INSERT INTO tag (name, created_at)
+VALUES (?, ?)
+ON CONFLICT(name) DO NOTHING;
+`, tag, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+INSERT INTO task_tag (task_id, tag_id)
+SELECT ?, id
+FROM tag
+WHERE name = ?;
+`, taskID, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchTagsByTask returns the tags of all tasks (not in the trash) that have
+// any, sorted by name.
+func fetchTagsByTask(db *sql.DB) (map[int][]string, error) {
+	rows, err := db.Query(`
+SELECT tt.task_id, g.name
+FROM task_tag tt
+JOIN tag g ON tt.tag_id = g.id
+JOIN task t ON tt.task_id = t.id
+WHERE t.deleted_at IS NULL
+ORDER BY g.name;
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[int][]string)
+	for rows.Next() {
+		var taskID int
+		var tag string
+		err = rows.Scan(&taskID, &tag)
+		if err != nil {
+			return nil, err
+		}
+		tags[taskID] = append(tags[taskID], tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func addTagsToTasks(db *sql.DB, tasks []domain.Task) error {
+	tagsByTask, err := fetchTagsByTask(db)
+	if err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		tasks[i].Tags = tagsByTask[tasks[i].ID]
+	}
+
+	return nil
+}
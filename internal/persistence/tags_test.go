@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskTags(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+
+	// WHEN
+	taskID, err := InsertTaskWithDetails(db, "write blog post", UnassignedProjectID, []string{"personal", "writing"})
+	require.NoError(t, err)
+	otherTaskID, err := InsertTaskWithDetails(db, "review PR", UnassignedProjectID, []string{"work"})
+	require.NoError(t, err)
+	err = UpdateTaskWithTags(db, otherTaskID, "review PRs", []string{"review", "work"})
+	require.NoError(t, err)
+	err = SetTaskTags(db, taskID, []string{"writing"})
+	require.NoError(t, err)
+
+	// THEN
+	tasks, err := FetchTasksWithStatus(db, types.TaskStatusAny, 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	tagsByTask := make(map[int][]string)
+	for _, task := range tasks {
+		tagsByTask[task.ID] = task.Tags
+	}
+	assert.Equal(t, []string{"writing"}, tagsByTask[taskID])
+	assert.Equal(t, []string{"review", "work"}, tagsByTask[otherTaskID])
+}
+
+func TestTagFilters(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	referenceTS := time.Date(2025, time.October, 20, 9, 0, 0, 0, time.UTC)
+
+	blogTaskID, err := InsertTaskWithDetails(db, "write blog post", UnassignedProjectID, []string{"personal", "writing"})
+	require.NoError(t, err)
+	docsTaskID, err := InsertTaskWithDetails(db, "write docs", UnassignedProjectID, []string{"work", "writing"})
+	require.NoError(t, err)
+	untaggedTaskID, err := InsertTask(db, "untagged task")
+	require.NoError(t, err)
+
+	_, err = InsertManualTL(db, blogTaskID, referenceTS, referenceTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+	_, err = InsertManualTL(db, docsTaskID, referenceTS.Add(time.Hour), referenceTS.Add(3*time.Hour), nil, false)
+	require.NoError(t, err)
+	_, err = InsertManualTL(db, untaggedTaskID, referenceTS.Add(3*time.Hour), referenceTS.Add(3*time.Hour+30*time.Minute), nil, false)
+	require.NoError(t, err)
+
+	start := referenceTS.Add(-time.Hour)
+	end := referenceTS.Add(24 * time.Hour)
+
+	testCases := []struct {
+		name     string
+		filter   types.TaskFilter
+		expected []string
+	}{
+		{
+			name:     "no tags",
+			filter:   types.TaskFilter{Status: types.TaskStatusAny},
+			expected: []string{"write docs", "write blog post", "untagged task"},
+		},
+		{
+			name:     "a tag",
+			filter:   types.TaskFilter{Status: types.TaskStatusAny, Tags: []string{"writing"}},
+			expected: []string{"write docs", "write blog post"},
+		},
+		{
+			name:     "tags match any of them",
+			filter:   types.TaskFilter{Status: types.TaskStatusAny, Tags: []string{"personal", "work"}},
+			expected: []string{"write docs", "write blog post"},
+		},
+		{
+			name:     "excluded tag",
+			filter:   types.TaskFilter{Status: types.TaskStatusAny, ExcludeTags: []string{"work"}},
+			expected: []string{"write blog post", "untagged task"},
+		},
+		{
+			name:     "tag and excluded tag",
+			filter:   types.TaskFilter{Status: types.TaskStatusAny, Tags: []string{"writing"}, ExcludeTags: []string{"personal"}},
+			expected: []string{"write docs"},
+		},
+		{
+			name:     "tag and status",
+			filter:   types.TaskFilter{Status: types.TaskStatusInactive, Tags: []string{"writing"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			entries, err := FetchStatsBetweenTS(db, start, end, tt.filter, 100)
+			require.NoError(t, err)
+			tlEntries, err := FetchTLEntriesBetweenTS(db, start, end, tt.filter, 100)
+			require.NoError(t, err)
+
+			// THEN
+			var summaries []string
+			for _, entry := range entries {
+				summaries = append(summaries, entry.TaskSummary)
+			}
+			assert.Equal(t, tt.expected, summaries)
+			assert.Len(t, tlEntries, len(tt.expected))
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package persistence
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/types"
+)
+
+var (
+	ErrSplitTSOutOfRange = errors.New("db: task log entry can only be split between its begin and end timestamps")
+	ErrTLsForSameEntry   = errors.New("db: a task log entry cannot be merged with itself")
+	ErrTLsForDiffTasks   = errors.New("db: task log entries are for different tasks")
+	ErrTLsNotAdjacent    = errors.New("db: task log entries are not adjacent")
+	ErrTLAlreadyForTask  = errors.New("db: task log entry is already for this task")
+	ErrTaskNotFound      = errors.New("db: task not found")
+)
+
+const mergedCommentsSep = "\n\n"
+
+// MaxMergeGap is the longest gap allowed between two task logs being merged;
+// entries further apart are unlikely to be parts of the same stretch of work.
+const MaxMergeGap = 30 * time.Minute
+
+// SplitTL splits a saved task log at splitTS into two; the part after splitTS
+// becomes a new task log for the task with the ID taskID (which can be the
+// task of the original task log). The ID of the new task log is returned.
+func SplitTL(db *sql.DB, tlID int, splitTS time.Time, taskID int) (int, error) {
+	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
+		tl, err := fetchSavedTLByID(tx, tlID)
+		if err != nil {
+			return -1, err
+		}
+
+		if !splitTS.After(tl.BeginTS) || !splitTS.Before(tl.EndTS) {
+			return -1, ErrSplitTSOutOfRange
+		}
+
+		err = checkTaskExists(tx, taskID)
+		if err != nil {
+			return -1, err
+		}
+
+		pauses, err := fetchTLPauses(tx, tl.ID)
+		if err != nil {
+			return -1, err
+		}
+
+		secsBefore := int(splitTS.Sub(tl.BeginTS).Seconds()) - domain.PausedSeconds(pauses, tl.BeginTS, splitTS)
+		secsAfter := int(tl.EndTS.Sub(splitTS).Seconds()) - domain.PausedSeconds(pauses, splitTS, tl.EndTS)
+
+		_, err = tx.Exec(`
+UPDATE task_log
+SET end_ts = ?,
+    secs_spent = ?
+WHERE id = ?;
+`, splitTS.UTC(), secsBefore, tl.ID)
+		if err != nil {
+			return -1, err
+		}
+
+		res, err := tx.Exec(`
+INSERT INTO task_log (task_id, begin_ts, end_ts, secs_spent, comment, active)
+VALUES (?, ?, ?, ?, ?, ?);
+`, taskID, splitTS.UTC(), tl.EndTS.UTC(), secsAfter, tl.Comment, false)
+		if err != nil {
+			return -1, err
+		}
+
+		newTLID, err := res.LastInsertId()
+		if err != nil {
+			return -1, fmt.Errorf("%w: %s", ErrCouldntLastInsertID, err.Error())
+		}
+
+		err = splitTLPauses(tx, pauses, splitTS, int(newTLID))
+		if err != nil {
+			return -1, err
+		}
+
+		err = addToTaskSecsSpent(tx, tl.TaskID, secsBefore-tl.SecsSpent)
+		if err != nil {
+			return -1, err
+		}
+
+		err = addToTaskSecsSpent(tx, taskID, secsAfter)
+		if err != nil {
+			return -1, err
+		}
+
+		return int(newTLID), nil
+	})
+}
+
+// MergeTLs merges two adjacent saved task logs of the same task into the
+// earlier one. The gap between them (if any, and no longer than MaxMergeGap)
+// is recorded as a pause, so the time spent on the task stays the same. The
+// ID of the merged task log is returned.
+func MergeTLs(db *sql.DB, tlID, otherTLID int) (int, error) {
+	return runInTxAndReturnID(db, func(tx *sql.Tx) (int, error) {
+		if tlID == otherTLID {
+			return -1, ErrTLsForSameEntry
+		}
+
+		earlier, err := fetchSavedTLByID(tx, tlID)
+		if err != nil {
+			return -1, err
+		}
+
+		later, err := fetchSavedTLByID(tx, otherTLID)
+		if err != nil {
+			return -1, err
+		}
+
+		if later.BeginTS.Before(earlier.BeginTS) {
+			earlier, later = later, earlier
+		}
+
+		if earlier.TaskID != later.TaskID {
+			return -1, ErrTLsForDiffTasks
+		}
+
+		if later.BeginTS.Before(earlier.EndTS) {
+			return -1, fmt.Errorf("%w: they overlap", ErrTLsNotAdjacent)
+		}
+
+		if later.BeginTS.After(earlier.EndTS) {
+			inBetween, err := fetchTLEntriesOverlapping(tx, earlier.EndTS, later.BeginTS, 1)
+			if err != nil {
+				return -1, err
+			}
+
+			if len(inBetween) > 0 {
+				return -1, fmt.Errorf("%w: entry #%d lies between them", ErrTLsNotAdjacent, inBetween[0].ID)
+			}
+
+			if gap := later.BeginTS.Sub(earlier.EndTS); gap > MaxMergeGap {
+				return -1, fmt.Errorf("%w: the gap of %s between them is longer than %s",
+					ErrTLsNotAdjacent,
+					types.HumanizeDuration(int(gap.Seconds())),
+					types.HumanizeDuration(int(MaxMergeGap.Seconds())),
+				)
+			}
+
+			_, err = tx.Exec(`
+INSERT INTO task_log_pause (task_log_id, begin_ts, end_ts)
+VALUES (?, ?, ?);
+`, earlier.ID, earlier.EndTS.UTC(), later.BeginTS.UTC())
+			if err != nil {
+				return -1, err
+			}
+		}
+
+		_, err = tx.Exec(`
+UPDATE task_log_pause
+SET task_log_id = ?
+WHERE task_log_id = ?;
+`, earlier.ID, later.ID)
+		if err != nil {
+			return -1, err
+		}
+
+		pauses, err := fetchTLPauses(tx, earlier.ID)
+		if err != nil {
+			return -1, err
+		}
+
+		secsSpent := int(later.EndTS.Sub(earlier.BeginTS).Seconds()) - domain.PausedSeconds(pauses, earlier.BeginTS, later.EndTS)
+
+		_, err = tx.Exec(`
+UPDATE task_log
+SET end_ts = ?,
+    secs_spent = ?,
+    comment = ?
+WHERE id = ?;
+`, later.EndTS.UTC(), secsSpent, mergeComments(earlier.Comment, later.Comment), earlier.ID)
+		if err != nil {
+			return -1, err
+		}
+
+		_, err = tx.Exec(`
+DELETE FROM task_log
+WHERE id = ?;
+`, later.ID)
+		if err != nil {
+			return -1, err
+		}
+
+		// entries imported from other tools are looked up by the task logs
+		// they were imported as
+		_, err = tx.Exec(`
+UPDATE imported_entry
+SET task_log_id = ?
+WHERE task_log_id = ?;
+`, earlier.ID, later.ID)
+		if err != nil {
+			return -1, err
+		}
+
+		err = addToTaskSecsSpent(tx, earlier.TaskID, secsSpent-earlier.SecsSpent-later.SecsSpent)
+		if err != nil {
+			return -1, err
+		}
+
+		return earlier.ID, nil
+	})
+}
+
+// MoveTL reassigns a saved task log to the task with the ID taskID.
+func MoveTL(db *sql.DB, tlID, taskID int) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		tl, err := fetchSavedTLByID(tx, tlID)
+		if err != nil {
+			return err
+		}
+
+		if tl.TaskID == taskID {
+			return ErrTLAlreadyForTask
+		}
+
+		err = checkTaskExists(tx, taskID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+UPDATE task_log
+SET task_id = ?
+WHERE id = ?;
+`, taskID, tl.ID)
+		if err != nil {
+			return err
+		}
+
+		err = addToTaskSecsSpent(tx, tl.TaskID, -tl.SecsSpent)
+		if err != nil {
+			return err
+		}
+
+		return addToTaskSecsSpent(tx, taskID, tl.SecsSpent)
+	})
+}
+
+func checkTaskExists(tx *sql.Tx, taskID int) error {
+	var id int
+	err := tx.QueryRow(`
+SELECT id
+FROM task
+WHERE id = ?
+AND deleted_at IS NULL;
+`, taskID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w (ID: %d)", ErrTaskNotFound, taskID)
+	}
+
+	return err
+}
+
+// splitTLPauses moves the pauses (or parts of them) after splitTS to the task
+// log with the ID newTLID.
+func splitTLPauses(tx *sql.Tx, pauses []domain.TaskLogPause, splitTS time.Time, newTLID int) error {
+	for _, pause := range pauses {
+		var err error
+		switch {
+		case !pause.BeginTS.Before(splitTS):
+			_, err = tx.Exec(`
+UPDATE task_log_pause
+SET task_log_id = ?
+WHERE id = ?;
+`, newTLID, pause.ID)
+		case pause.EndTS != nil && pause.EndTS.After(splitTS):
+			_, err = tx.Exec(`
+UPDATE task_log_pause
+SET end_ts = ?
+WHERE id = ?;
+`, splitTS.UTC(), pause.ID)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(`
+INSERT INTO task_log_pause (task_log_id, begin_ts, end_ts)
+VALUES (?, ?, ?);
+`, newTLID, splitTS.UTC(), pause.EndTS.UTC())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addToTaskSecsSpent(tx *sql.Tx, taskID, secs int) error {
+	if secs == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(`
+UPDATE task
+SET secs_spent = secs_spent+?,
+    updated_at = ?
+WHERE id = ?;
+`, secs, time.Now().UTC(), taskID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCouldntUpdateTaskTimeSpent, err.Error())
+	}
+
+	return nil
+}
+
+func mergeComments(comment, otherComment *string) *string {
+	switch {
+	case comment == nil:
+		return otherComment
+	case otherComment == nil, *comment == *otherComment:
+		return comment
+	}
+
+	merged := *comment + mergedCommentsSep + *otherComment
+	return &merged
+}
@@ -0,0 +1,199 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplittingTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	comment := "outline"
+	tlID, err := InsertNewTL(db, taskID, beginTS, &comment)
+	require.NoError(t, err)
+	require.NoError(t, PauseActiveTL(db, beginTS.Add(time.Hour)))
+	require.NoError(t, ResumeActiveTL(db, beginTS.Add(2*time.Hour)))
+	_, err = FinishActiveTL(db, tlID, taskID, beginTS, beginTS.Add(4*time.Hour), &comment)
+	require.NoError(t, err)
+
+	// WHEN
+	_, outOfRangeErr := SplitTL(db, tlID, beginTS.Add(4*time.Hour), otherTaskID)
+	newTLID, err := SplitTL(db, tlID, beginTS.Add(90*time.Minute), otherTaskID)
+
+	// THEN
+	assert.ErrorIs(t, outOfRangeErr, ErrSplitTSOutOfRange)
+	require.NoError(t, err)
+
+	tl, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+	assert.True(t, beginTS.Add(90*time.Minute).Equal(tl.EndTS))
+	assert.Equal(t, 60*60, tl.SecsSpent)
+
+	newTL, err := FetchSavedTLByID(db, newTLID)
+	require.NoError(t, err)
+	assert.Equal(t, otherTaskID, newTL.TaskID)
+	assert.True(t, beginTS.Add(90*time.Minute).Equal(newTL.BeginTS))
+	assert.True(t, beginTS.Add(4*time.Hour).Equal(newTL.EndTS))
+	assert.Equal(t, 2*60*60, newTL.SecsSpent)
+	require.NotNil(t, newTL.Comment)
+	assert.Equal(t, comment, *newTL.Comment)
+
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 60*60, task.SecsSpent)
+
+	otherTask, err := fetchTaskByID(db, otherTaskID)
+	require.NoError(t, err)
+	assert.Equal(t, 2*60*60, otherTask.SecsSpent)
+
+	pauses, err := fetchTLPauses(db, newTLID)
+	require.NoError(t, err)
+	require.Len(t, pauses, 1)
+	assert.True(t, beginTS.Add(90*time.Minute).Equal(pauses[0].BeginTS))
+}
+
+func TestMergingTLs(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	comment := "outline"
+	otherComment := "first draft"
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(time.Hour), &comment, false)
+	require.NoError(t, err)
+	laterTLID, err := InsertManualTL(db, taskID, beginTS.Add(90*time.Minute), beginTS.Add(2*time.Hour), &otherComment, false)
+	require.NoError(t, err)
+	otherTaskTLID, err := InsertManualTL(db, otherTaskID, beginTS.Add(2*time.Hour), beginTS.Add(3*time.Hour), nil, false)
+	require.NoError(t, err)
+	laterStillTLID, err := InsertManualTL(db, taskID, beginTS.Add(3*time.Hour), beginTS.Add(4*time.Hour), nil, false)
+	require.NoError(t, err)
+
+	// WHEN
+	_, diffTasksErr := MergeTLs(db, laterTLID, otherTaskTLID)
+	_, notAdjacentErr := MergeTLs(db, laterTLID, laterStillTLID)
+	mergedTLID, err := MergeTLs(db, laterTLID, tlID)
+
+	// THEN
+	assert.ErrorIs(t, diffTasksErr, ErrTLsForDiffTasks)
+	assert.ErrorIs(t, notAdjacentErr, ErrTLsNotAdjacent)
+	require.NoError(t, err)
+	assert.Equal(t, tlID, mergedTLID)
+
+	merged, err := FetchSavedTLByID(db, mergedTLID)
+	require.NoError(t, err)
+	assert.True(t, beginTS.Equal(merged.BeginTS))
+	assert.True(t, beginTS.Add(2*time.Hour).Equal(merged.EndTS))
+	assert.Equal(t, 90*60, merged.SecsSpent)
+	require.NotNil(t, merged.Comment)
+	assert.Equal(t, "outline\n\nfirst draft", *merged.Comment)
+
+	_, err = FetchSavedTLByID(db, laterTLID)
+	assert.ErrorIs(t, err, ErrTLNotFound)
+
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, (90+60)*60, task.SecsSpent)
+
+	pauses, err := fetchTLPauses(db, mergedTLID)
+	require.NoError(t, err)
+	require.Len(t, pauses, 1)
+	assert.True(t, beginTS.Add(time.Hour).Equal(pauses[0].BeginTS))
+}
+
+func TestMergingTLsFailsForGapsLongerThanTheMax(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+	laterTLID, err := InsertManualTL(db, taskID, beginTS.Add(time.Hour+MaxMergeGap+time.Minute), beginTS.Add(3*time.Hour), nil, false)
+	require.NoError(t, err)
+
+	// WHEN
+	_, err = MergeTLs(db, tlID, laterTLID)
+
+	// THEN
+	assert.ErrorIs(t, err, ErrTLsNotAdjacent)
+
+	_, err = FetchSavedTLByID(db, laterTLID)
+	require.NoError(t, err)
+
+	pauses, err := fetchTLPauses(db, tlID)
+	require.NoError(t, err)
+	assert.Empty(t, pauses)
+}
+
+func TestMovingTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+
+	// WHEN
+	sameTaskErr := MoveTL(db, tlID, taskID)
+	unknownTaskErr := MoveTL(db, tlID, 100)
+	err = MoveTL(db, tlID, otherTaskID)
+
+	// THEN
+	assert.ErrorIs(t, sameTaskErr, ErrTLAlreadyForTask)
+	assert.ErrorIs(t, unknownTaskErr, ErrTaskNotFound)
+	require.NoError(t, err)
+
+	tl, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+	assert.Equal(t, otherTaskID, tl.TaskID)
+
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, task.SecsSpent)
+
+	otherTask, err := fetchTaskByID(db, otherTaskID)
+	require.NoError(t, err)
+	assert.Equal(t, 60*60, otherTask.SecsSpent)
+}
+
+func TestSplittingTLWhileATaskIsBeingTracked(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(2*time.Hour), nil, false)
+	require.NoError(t, err)
+	_, err = InsertNewTL(db, otherTaskID, beginTS.Add(3*time.Hour), nil)
+	require.NoError(t, err)
+
+	// WHEN
+	newTLID, err := SplitTL(db, tlID, beginTS.Add(time.Hour), taskID)
+
+	// THEN
+	require.NoError(t, err)
+
+	newTL, err := FetchSavedTLByID(db, newTLID)
+	require.NoError(t, err)
+	assert.True(t, beginTS.Add(time.Hour).Equal(newTL.BeginTS))
+
+	_, err = InsertNewTL(db, taskID, beginTS.Add(4*time.Hour), nil)
+	assert.Error(t, err, "only one task log can be active")
+}
@@ -0,0 +1,329 @@
+package persistence
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrNotInTrash              = errors.New("db: not in the trash")
+	ErrTaskInTrash             = errors.New("db: task is in the trash; restore it first")
+	ErrCannotTrashTrackedTask  = errors.New("db: a task being tracked cannot be deleted")
+	ErrCouldntFetchTrashedItem = errors.New("db: couldn't fetch item in the trash")
+)
+
+type TrashedItemKind string
+
+const (
+	TrashedTask TrashedItemKind = "task"
+	TrashedTL   TrashedItemKind = "log"
+)
+
+// TrashedItem is a task, or a saved task log, that's in the trash. The task
+// logs of a trashed task are left out everywhere as well, but they aren't
+// trashed themselves.
+type TrashedItem struct {
+	Kind        TrashedItemKind `json:"kind"`
+	ID          int             `json:"id"`
+	TaskID      int             `json:"task_id"`
+	TaskSummary string          `json:"task_summary"`
+	// BeginTS and EndTS are only set for task logs
+	BeginTS   *time.Time `json:"begin_ts,omitempty"`
+	EndTS     *time.Time `json:"end_ts,omitempty"`
+	SecsSpent int        `json:"secs_spent"`
+	DeletedAt time.Time  `json:"deleted_at"`
+}
+
+type PurgeResult struct {
+	NumTasks int
+	// NumTLs includes the task logs of the tasks purged
+	NumTLs int
+}
+
+// TrashTask moves a task (along with its task logs) to the trash.
+func TrashTask(db *sql.DB, taskID int, deletedAt time.Time) error {
+	return runInTx(db, func(tx *sql.Tx) error {
+		err := checkTaskExists(tx, taskID)
+		if err != nil {
+			return err
+		}
+
+		activeTLID, err := fetchActiveTLID(tx)
+		if err != nil && !errors.Is(err, ErrNoTaskActive) {
+			return err
+		}
+
+		if err == nil {
+			var activeTaskID int
+			err = tx.QueryRow(`
+SELECT task_id
+FROM task_log
+WHERE id = ?;
+`, activeTLID).Scan(&activeTaskID)
+			if err != nil {
+				return err
+			}
+
+			if activeTaskID == taskID {
+				return ErrCannotTrashTrackedTask
+			}
+		}
+
+		_, err = tx.Exec(`
+UPDATE task
+SET deleted_at = ?
+WHERE id = ?;
+`, deletedAt.UTC(), taskID)
+
+		return err
+	})
+}
+
+// FetchTrash returns the items in the trash, most recently deleted first.
+func FetchTrash(db *sql.DB) ([]TrashedItem, error) {
+	tasks, err := fetchTrashedTasks(db, "")
+	if err != nil {
+		return nil, err
+	}
+
+	tls, err := fetchTrashedTLs(db, "")
+	if err != nil {
+		return nil, err
+	}
+
+	items := slices.Concat(tasks, tls)
+	slices.SortStableFunc(items, func(a, b TrashedItem) int {
+		return cmp.Or(
+			b.DeletedAt.Compare(a.DeletedAt),
+			cmp.Compare(b.ID, a.ID),
+		)
+	})
+
+	return items, nil
+}
+
+// RestoreFromTrash takes an item out of the trash, and returns it. The time
+// spent on a restored task log counts towards its task again.
+func RestoreFromTrash(db *sql.DB, kind TrashedItemKind, id int) (TrashedItem, error) {
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (TrashedItem, error) {
+		item, err := fetchTrashedItem(tx, kind, id)
+		if err != nil {
+			return item, err
+		}
+
+		switch kind {
+		case TrashedTask:
+			_, err = tx.Exec(`
+UPDATE task
+SET deleted_at = NULL
+WHERE id = ?;
+`, id)
+			return item, err
+		default:
+			var taskTrashed bool
+			err = tx.QueryRow(`
+SELECT deleted_at IS NOT NULL
+FROM task
+WHERE id = ?;
+`, item.TaskID).Scan(&taskTrashed)
+			if err != nil {
+				return item, err
+			}
+
+			if taskTrashed {
+				return item, fmt.Errorf("%w (ID: %d)", ErrTaskInTrash, item.TaskID)
+			}
+
+			_, err = tx.Exec(`
+UPDATE task_log
+SET deleted_at = NULL
+WHERE id = ?;
+`, id)
+			if err != nil {
+				return item, err
+			}
+
+			return item, addToTaskSecsSpent(tx, item.TaskID, item.SecsSpent)
+		}
+	})
+}
+
+// PurgeTrash permanently deletes the items that were moved to the trash
+// before deletedBefore (or all of them, if it's nil).
+func PurgeTrash(db *sql.DB, deletedBefore *time.Time) (PurgeResult, error) {
+	return runInTxAndReturnA(db, func(tx *sql.Tx) (PurgeResult, error) {
+		var result PurgeResult
+
+		condition := "deleted_at IS NOT NULL"
+		var args []any
+		if deletedBefore != nil {
+			condition += " AND deleted_at < ?"
+			args = append(args, deletedBefore.UTC())
+		}
+
+		taskIDs, err := fetchIDs(tx, `
+SELECT id
+FROM task
+WHERE `+condition+`;
+`, args...)
+		if err != nil {
+			return result, err
+		}
+
+		tlIDs, err := fetchIDs(tx, `
+SELECT id
+FROM task_log
+WHERE `+condition+`;
+`, args...)
+		if err != nil {
+			return result, err
+		}
+
+		// a purged task's task logs go with it, even the ones trashed later on
+		for _, taskID := range taskIDs {
+			taskTLIDs, err := fetchIDs(tx, `
+SELECT id
+FROM task_log
+WHERE task_id = ?;
+`, taskID)
+			if err != nil {
+				return result, err
+			}
+			tlIDs = unionOfIDs(tlIDs, taskTLIDs)
+		}
+
+		for _, tlID := range tlIDs {
+			err = hardDeleteTL(tx, tlID)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		for _, taskID := range taskIDs {
+			_, err = tx.Exec(`
+DELETE FROM task_tag
+WHERE task_id = ?;
+`, taskID)
+			if err != nil {
+				return result, err
+			}
+
+			_, err = tx.Exec(`
+DELETE FROM task
+WHERE id = ?;
+`, taskID)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		result.NumTasks = len(taskIDs)
+		result.NumTLs = len(tlIDs)
+
+		return result, nil
+	})
+}
+
+func fetchTrashedItem(q querier, kind TrashedItemKind, id int) (TrashedItem, error) {
+	var items []TrashedItem
+	var err error
+	switch kind {
+	case TrashedTask:
+		items, err = fetchTrashedTasks(q, "AND id = ?", id)
+	default:
+		items, err = fetchTrashedTLs(q, "AND tl.id = ?", id)
+	}
+	if err != nil {
+		return TrashedItem{}, fmt.Errorf("%w: %s", ErrCouldntFetchTrashedItem, err.Error())
+	}
+
+	if len(items) == 0 {
+		return TrashedItem{}, fmt.Errorf("%w (%s ID: %d)", ErrNotInTrash, kind, id)
+	}
+
+	return items[0], nil
+}
+
+func fetchTrashedTasks(q querier, condition string, args ...any) ([]TrashedItem, error) {
+	rows, err := q.Query(`
+SELECT id, summary, secs_spent, deleted_at
+FROM task
+WHERE deleted_at IS NOT NULL
+`+condition+`
+ORDER BY deleted_at DESC;
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []TrashedItem
+	for rows.Next() {
+		item := TrashedItem{Kind: TrashedTask}
+		err = rows.Scan(&item.ID, &item.TaskSummary, &item.SecsSpent, &item.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		item.TaskID = item.ID
+		item.DeletedAt = item.DeletedAt.Local()
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func fetchTrashedTLs(q querier, condition string, args ...any) ([]TrashedItem, error) {
+	rows, err := q.Query(`
+SELECT tl.id, tl.task_id, t.summary, tl.begin_ts, tl.end_ts, tl.secs_spent, tl.deleted_at
+FROM task_log tl left join task t on tl.task_id=t.id
+WHERE tl.deleted_at IS NOT NULL
+`+condition+`
+ORDER BY tl.deleted_at DESC;
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []TrashedItem
+	for rows.Next() {
+		item := TrashedItem{Kind: TrashedTL}
+		var beginTS, endTS time.Time
+		err = rows.Scan(&item.ID, &item.TaskID, &item.TaskSummary, &beginTS, &endTS, &item.SecsSpent, &item.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		beginTS = beginTS.Local()
+		endTS = endTS.Local()
+		item.BeginTS = &beginTS
+		item.EndTS = &endTS
+		item.DeletedAt = item.DeletedAt.Local()
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func fetchIDs(tx *sql.Tx, query string, args ...any) ([]int, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
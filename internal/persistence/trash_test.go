@@ -0,0 +1,210 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashingAndRestoringTL(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+	tl, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+
+	// WHEN
+	err = DeleteTL(db, &tl, beginTS.Add(2*time.Hour))
+
+	// THEN
+	require.NoError(t, err)
+
+	entries, err := FetchTLEntries(db, true, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, task.SecsSpent)
+
+	trash, err := FetchTrash(db)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, TrashedTL, trash[0].Kind)
+	assert.Equal(t, tlID, trash[0].ID)
+	assert.True(t, beginTS.Add(2*time.Hour).Equal(trash[0].DeletedAt))
+
+	// WHEN
+	_, notInTrashErr := RestoreFromTrash(db, TrashedTask, taskID)
+	item, err := RestoreFromTrash(db, TrashedTL, tlID)
+
+	// THEN
+	assert.ErrorIs(t, notInTrashErr, ErrNotInTrash)
+	require.NoError(t, err)
+	assert.Equal(t, 60*60, item.SecsSpent)
+
+	restored, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+	assert.Equal(t, tl, restored)
+
+	task, err = fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 60*60, task.SecsSpent)
+}
+
+func TestTrashingTask(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	trackedTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	tlID, err := InsertManualTL(db, taskID, beginTS, beginTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+	otherTLID, err := InsertManualTL(db, taskID, beginTS.Add(time.Hour), beginTS.Add(2*time.Hour), nil, false)
+	require.NoError(t, err)
+	otherTL, err := FetchSavedTLByID(db, otherTLID)
+	require.NoError(t, err)
+	require.NoError(t, DeleteTL(db, &otherTL, beginTS.Add(3*time.Hour)))
+	_, err = InsertNewTL(db, trackedTaskID, beginTS.Add(3*time.Hour), nil)
+	require.NoError(t, err)
+
+	// WHEN
+	trackedErr := TrashTask(db, trackedTaskID, beginTS.Add(4*time.Hour))
+	err = TrashTask(db, taskID, beginTS.Add(4*time.Hour))
+
+	// THEN
+	assert.ErrorIs(t, trackedErr, ErrCannotTrashTrackedTask)
+	require.NoError(t, err)
+
+	tasks, err := FetchTasksWithStatus(db, types.TaskStatusAny, 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, trackedTaskID, tasks[0].ID)
+
+	entries, err := FetchTLEntriesBetweenTS(db, beginTS, beginTS.Add(24*time.Hour), types.TaskFilter{Status: types.TaskStatusAny}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = FetchSavedTLByID(db, tlID)
+	assert.ErrorIs(t, err, ErrTLNotFound)
+
+	// WHEN
+	_, taskInTrashErr := RestoreFromTrash(db, TrashedTL, otherTLID)
+	_, err = RestoreFromTrash(db, TrashedTask, taskID)
+
+	// THEN
+	assert.ErrorIs(t, taskInTrashErr, ErrTaskInTrash)
+	require.NoError(t, err)
+
+	entries, err = FetchTLEntriesBetweenTS(db, beginTS, beginTS.Add(24*time.Hour), types.TaskFilter{Status: types.TaskStatusAny}, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, tlID, entries[0].ID)
+
+	_, err = FetchSavedTLByID(db, tlID)
+	require.NoError(t, err, "task logs of a trashed task aren't trashed themselves")
+}
+
+func TestTrashedTasksCantBeChanged(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	now := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	require.NoError(t, SetTaskTags(db, taskID, []string{"writing"}))
+	tlID, err := InsertManualTL(db, taskID, now.Add(-time.Hour), now, nil, false)
+	require.NoError(t, err)
+	require.NoError(t, TrashTask(db, taskID, now))
+
+	// WHEN
+	updateErr := UpdateTask(db, taskID, "write post")
+	statusErr := UpdateTaskActiveStatus(db, taskID, false)
+	_, trackingDataErr := FetchTaskTrackingData(db, taskID)
+	projectGroups, projectGroupsErr := FetchTaskGroups(db, types.GroupByProject)
+	tagGroups, tagGroupsErr := FetchTaskGroups(db, types.GroupByTag)
+	deleteTLErr := DeleteTL(db, &domain.TaskLogEntry{ID: tlID}, now)
+
+	// THEN
+	assert.ErrorIs(t, updateErr, ErrTaskNotFound)
+	assert.ErrorIs(t, statusErr, ErrTaskNotFound)
+	assert.ErrorIs(t, trackingDataErr, ErrTaskNotFound)
+	assert.ErrorIs(t, deleteTLErr, ErrTLNotFound)
+	require.NoError(t, projectGroupsErr)
+	assert.Empty(t, projectGroups)
+	require.NoError(t, tagGroupsErr)
+	assert.Empty(t, tagGroups)
+
+	task, err := fetchTaskByID(db, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, "write blog", task.Summary)
+	assert.True(t, task.Active)
+}
+
+func TestPurgingTrash(t *testing.T) {
+	// GIVEN
+	db := getFileDB(t, filepath.Join(t.TempDir(), "hours.db"))
+	beginTS := time.Date(2025, 10, 24, 9, 0, 0, 0, time.UTC)
+	taskID, err := InsertTask(db, "write blog")
+	require.NoError(t, err)
+	otherTaskID, err := InsertTask(db, "review pr")
+	require.NoError(t, err)
+	tlID, err := InsertNewTL(db, taskID, beginTS, nil)
+	require.NoError(t, err)
+	require.NoError(t, PauseActiveTL(db, beginTS.Add(30*time.Minute)))
+	require.NoError(t, ResumeActiveTL(db, beginTS.Add(40*time.Minute)))
+	_, err = FinishActiveTL(db, tlID, taskID, beginTS, beginTS.Add(time.Hour), nil)
+	require.NoError(t, err)
+	tl, err := FetchSavedTLByID(db, tlID)
+	require.NoError(t, err)
+	require.NoError(t, DeleteTL(db, &tl, beginTS.Add(24*time.Hour)))
+	otherTLID, err := InsertManualTL(db, otherTaskID, beginTS, beginTS.Add(time.Hour), nil, false)
+	require.NoError(t, err)
+	require.NoError(t, TrashTask(db, otherTaskID, beginTS.Add(48*time.Hour)))
+
+	// WHEN
+	cutoff := beginTS.Add(36 * time.Hour)
+	result, err := PurgeTrash(db, &cutoff)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, PurgeResult{NumTasks: 0, NumTLs: 1}, result)
+
+	pauses, err := fetchTLPauses(db, tlID)
+	require.NoError(t, err)
+	assert.Empty(t, pauses)
+
+	trash, err := FetchTrash(db)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, TrashedTask, trash[0].Kind)
+
+	// WHEN
+	result, err = PurgeTrash(db, nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, PurgeResult{NumTasks: 1, NumTLs: 1}, result)
+
+	_, err = fetchTLByID(db, otherTLID)
+	require.Error(t, err)
+
+	trash, err = FetchTrash(db)
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+
+	diagnosis, err := DiagnoseDB(db)
+	require.NoError(t, err)
+	assert.Empty(t, diagnosis.RollupDrifts)
+	assert.Empty(t, diagnosis.OrphanedTLs)
+}
@@ -11,6 +11,7 @@ const (
 	TimePeriodToday = "today"
 	TimePeriodWeek  = "week"
 	timeFormat      = "2006/01/02 15:04"
+	timeOnlyFormat  = "15:04"
 	dateFormat      = "2006/01/02"
 )
 
@@ -21,6 +22,7 @@ var (
 	errEndDateIsNotAfterStartDate = errors.New("end date is not after start date")
 	errTimePeriodNotValid         = errors.New("time period is not valid")
 	errTimePeriodTooLarge         = errors.New("time period is too large")
+	ErrTimestampInvalid           = fmt.Errorf("timestamp is invalid; expected format: %q or %q", timeFormat, timeOnlyFormat)
 )
 
 func parseDateRange(rangeStr string, now time.Time) (DateRange, error) {
@@ -126,3 +128,22 @@ func GetDateRangeFromPeriod(period string, now time.Time, fullWeek bool, maxDays
 		NumDays: numDays,
 	}, nil
 }
+
+// ParseTimestamp parses a timestamp provided by the user, either as a full
+// timestamp (eg. "2024/06/08 09:30"), or as a time of day (eg. "09:30"), in
+// which case the date of "now" is used.
+func ParseTimestamp(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	ts, err := time.ParseInLocation(timeFormat, value, time.Local)
+	if err == nil {
+		return ts, nil
+	}
+
+	tod, err := time.ParseInLocation(timeOnlyFormat, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: got %q", ErrTimestampInvalid, value)
+	}
+
+	return time.Date(now.Year(), now.Month(), now.Day(), tod.Hour(), tod.Minute(), 0, 0, now.Location()), nil
+}
@@ -243,3 +243,64 @@ func TestGetDateRangeFromPeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTimestamp(t *testing.T) {
+	now := time.Date(2024, 6, 20, 20, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name        string
+		input       string
+		expectedStr string
+		expectedErr error
+	}{
+		// success
+		{
+			name:        "full timestamp",
+			input:       "2024/06/18 09:30",
+			expectedStr: "2024/06/18 09:30",
+		},
+		{
+			name:        "time of day",
+			input:       "09:30",
+			expectedStr: "2024/06/20 09:30",
+		},
+		{
+			name:        "surrounding whitespace",
+			input:       " 09:30 ",
+			expectedStr: "2024/06/20 09:30",
+		},
+		// failures
+		{
+			name:        "empty string",
+			input:       "",
+			expectedErr: ErrTimestampInvalid,
+		},
+		{
+			name:        "only a date",
+			input:       "2024/06/18",
+			expectedErr: ErrTimestampInvalid,
+		},
+		{
+			name:        "invalid time of day",
+			input:       "25:30",
+			expectedErr: ErrTimestampInvalid,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			// WHEN
+			got, err := ParseTimestamp(tt.input, now)
+
+			// THEN
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStr, got.Format(timeFormat))
+		})
+	}
+}
@@ -4,10 +4,25 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
-var ErrIncorrectTaskStatusProvided = errors.New("incorrect task status provided")
+var (
+	ErrIncorrectTaskStatusProvided   = errors.New("incorrect task status provided")
+	ErrIncorrectOutputFormatProvided = errors.New("incorrect output format provided")
+	ErrIncorrectTagProvided          = errors.New("incorrect tag provided")
+	ErrIncorrectGroupByProvided      = errors.New("incorrect group by value provided")
+	ErrIncorrectRateProvided         = errors.New("incorrect rate provided")
+	ErrIncorrectCurrencyProvided     = errors.New("incorrect currency provided")
+	ErrIncorrectRoundingProvided     = errors.New("incorrect rounding mode provided")
+	ErrIncorrectBudgetProvided       = errors.New("incorrect budget provided")
+	ErrIncorrectGoalProvided         = errors.New("incorrect goal provided")
+	ErrIncorrectTimeboxAlertProvided = errors.New("incorrect timebox alert provided")
+)
 
 type TimeProvider interface {
 	Now() time.Time
@@ -76,6 +91,306 @@ func ParseTaskStatus(value string) (TaskStatus, error) {
 
 var ValidTaskStatusValues = []string{TSValueActive, TSValueInactive, TSValueAny}
 
+const tagLengthLimit = 30
+
+var tagRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_./:-]*$`)
+
+// ParseTag validates a tag; tags are case-insensitive, and are stored in lower
+// case.
+func ParseTag(value string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(value))
+	if tag == "" {
+		return "", fmt.Errorf("%w: tag cannot be empty", ErrIncorrectTagProvided)
+	}
+
+	if len(tag) > tagLengthLimit {
+		return "", fmt.Errorf("%w: %q is longer than %d characters", ErrIncorrectTagProvided, tag, tagLengthLimit)
+	}
+
+	if !tagRegex.MatchString(tag) {
+		return "", fmt.Errorf("%w: %q (tags can only contain letters, digits, and the characters _./:-)", ErrIncorrectTagProvided, tag)
+	}
+
+	return tag, nil
+}
+
+// ParseTags validates tags, and returns them sorted, without duplicates.
+// Each value can hold several comma separated tags.
+func ParseTags(values []string) ([]string, error) {
+	var tags []string
+	for _, value := range values {
+		for part := range strings.SplitSeq(value, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+
+			tag, err := ParseTag(part)
+			if err != nil {
+				return nil, err
+			}
+			tags = append(tags, tag)
+		}
+	}
+
+	slices.Sort(tags)
+	return slices.Compact(tags), nil
+}
+
+// TaskFilter determines the tasks whose records are shown.
+type TaskFilter struct {
+	Status TaskStatus
+	// Tags, if set, only lets in tasks that have at least one of these tags
+	Tags []string
+	// ExcludeTags keeps out tasks that have any of these tags
+	ExcludeTags []string
+}
+
+func NewTaskFilter(status TaskStatus, tags, excludeTags []string) (TaskFilter, error) {
+	var err error
+	filter := TaskFilter{Status: status}
+
+	filter.Tags, err = ParseTags(tags)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.ExcludeTags, err = ParseTags(excludeTags)
+	if err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// GroupBy is what time spent is totalled by in reports and stats.
+type GroupBy uint8
+
+const (
+	GBValueTask    = "task"
+	GBValueTag     = "tag"
+	GBValueProject = "project"
+	GBValueClient  = "client"
+)
+
+const (
+	GroupByTask GroupBy = iota
+	GroupByTag
+	GroupByProject
+	GroupByClient
+)
+
+func (g GroupBy) String() string {
+	switch g {
+	case GroupByTag:
+		return GBValueTag
+	case GroupByProject:
+		return GBValueProject
+	case GroupByClient:
+		return GBValueClient
+	default:
+		return GBValueTask
+	}
+}
+
+func ParseGroupBy(value string) (GroupBy, error) {
+	switch value {
+	case GBValueTask:
+		return GroupByTask, nil
+	case GBValueTag:
+		return GroupByTag, nil
+	case GBValueProject:
+		return GroupByProject, nil
+	case GBValueClient:
+		return GroupByClient, nil
+	default:
+		return GroupByTask, ErrIncorrectGroupByProvided
+	}
+}
+
+var ValidGroupByValues = []string{GBValueTask, GBValueTag, GBValueProject, GBValueClient}
+
+var (
+	rateRegex     = regexp.MustCompile(`^([0-9]+)(\.([0-9]{1,2}))?$`)
+	currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// ParseRate parses an hourly rate (eg. "120", or "120.50") into hundredths of
+// a currency's unit.
+func ParseRate(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	matches := rateRegex.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, fmt.Errorf("%w: %q (rates need to be positive numbers with at most 2 decimal places)", ErrIncorrectRateProvided, value)
+	}
+
+	units, err := strconv.Atoi(matches[1])
+	if err != nil || units > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: %q is too large", ErrIncorrectRateProvided, value)
+	}
+
+	var hundredths int
+	if matches[3] != "" {
+		// "120.5" is 120.50
+		hundredths, _ = strconv.Atoi((matches[3] + "0")[:2])
+	}
+
+	amount := units*100 + hundredths
+	if amount == 0 {
+		return 0, fmt.Errorf("%w: rate cannot be zero", ErrIncorrectRateProvided)
+	}
+
+	return amount, nil
+}
+
+// ParseCurrency validates an ISO 4217 currency code (eg. "EUR"); codes are
+// case-insensitive, and are stored in upper case.
+func ParseCurrency(value string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(value))
+	if !currencyRegex.MatchString(currency) {
+		return "", fmt.Errorf("%w: %q (currencies need to be 3 letter codes, eg. \"EUR\")", ErrIncorrectCurrencyProvided, value)
+	}
+
+	return currency, nil
+}
+
+// ParseBudget parses a time budget (eg. "10h", or "1h30m") into seconds;
+// budgets can't be shorter than a minute.
+func ParseBudget(value string) (int, error) {
+	return parseTimeAmount(value, ErrIncorrectBudgetProvided, "budgets")
+}
+
+// ParseGoal parses a goal for the time to be tracked (eg. "6h", or "7h30m")
+// into seconds; goals can't be shorter than a minute.
+func ParseGoal(value string) (int, error) {
+	return parseTimeAmount(value, ErrIncorrectGoalProvided, "goals")
+}
+
+func parseTimeAmount(value string, errIncorrect error, kind string) (int, error) {
+	amount, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q (%s need to be durations, eg. \"10h\", or \"1h30m\")", errIncorrect, value, kind)
+	}
+
+	if amount < time.Minute {
+		return 0, fmt.Errorf("%w: %q (%s need to be at least a minute long)", errIncorrect, value, kind)
+	}
+
+	return int(amount / time.Second), nil
+}
+
+// RoundingMode is how tracked time is rounded when billed.
+type RoundingMode uint8
+
+const (
+	RMValueUp      = "up"
+	RMValueDown    = "down"
+	RMValueNearest = "nearest"
+)
+
+const (
+	RoundingNearest RoundingMode = iota
+	RoundingUp
+	RoundingDown
+)
+
+func ParseRoundingMode(value string) (RoundingMode, error) {
+	switch value {
+	case RMValueUp:
+		return RoundingUp, nil
+	case RMValueDown:
+		return RoundingDown, nil
+	case RMValueNearest:
+		return RoundingNearest, nil
+	default:
+		return RoundingNearest, ErrIncorrectRoundingProvided
+	}
+}
+
+var ValidRoundingModeValues = []string{RMValueNearest, RMValueUp, RMValueDown}
+
+// TimeboxAlert is how the TUI signals that a timebox (or a break) is over.
+type TimeboxAlert uint8
+
+const (
+	TAValueBell = "bell"
+	TAValueOSC9 = "osc9"
+)
+
+const (
+	TimeboxAlertBell TimeboxAlert = iota
+	TimeboxAlertOSC9
+)
+
+func ParseTimeboxAlert(value string) (TimeboxAlert, error) {
+	switch value {
+	case TAValueBell:
+		return TimeboxAlertBell, nil
+	case TAValueOSC9:
+		return TimeboxAlertOSC9, nil
+	default:
+		return TimeboxAlertBell, ErrIncorrectTimeboxAlertProvided
+	}
+}
+
+var ValidTimeboxAlertValues = []string{TAValueBell, TAValueOSC9}
+
+type OutputFormat uint8
+
+const (
+	OFValueTable    = "table"
+	OFValueJSON     = "json"
+	OFValueMarkdown = "markdown"
+	OFValueHTML     = "html"
+)
+
+const (
+	OutputFormatTable OutputFormat = iota
+	OutputFormatJSON
+	OutputFormatMarkdown
+	OutputFormatHTML
+)
+
+func (f OutputFormat) String() string {
+	switch f {
+	case OutputFormatJSON:
+		return "JSON"
+	case OutputFormatMarkdown:
+		return "Markdown"
+	case OutputFormatHTML:
+		return "HTML"
+	default:
+		return "table"
+	}
+}
+
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch value {
+	case OFValueTable:
+		return OutputFormatTable, nil
+	case OFValueJSON:
+		return OutputFormatJSON, nil
+	default:
+		return OutputFormatTable, ErrIncorrectOutputFormatProvided
+	}
+}
+
+var ValidOutputFormatValues = []string{OFValueTable, OFValueJSON}
+
+// ParseRecordsOutputFormat parses the output format of records (ie, reports,
+// logs, and stats), which can also be rendered as Markdown or HTML.
+func ParseRecordsOutputFormat(value string) (OutputFormat, error) {
+	switch value {
+	case OFValueMarkdown:
+		return OutputFormatMarkdown, nil
+	case OFValueHTML:
+		return OutputFormatHTML, nil
+	default:
+		return ParseOutputFormat(value)
+	}
+}
+
+var ValidRecordsOutputFormatValues = []string{OFValueTable, OFValueJSON, OFValueMarkdown, OFValueHTML}
+
 type DateRange struct {
 	Start   time.Time
 	End     time.Time
@@ -56,3 +56,171 @@ func TestHumanizeDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTags(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []string
+		expected []string
+		err      error
+	}{
+		{
+			name:     "no tags",
+			input:    nil,
+			expected: nil,
+		},
+		{
+			name:     "tags are lowercased, sorted and deduplicated",
+			input:    []string{"Writing", "personal", "writing"},
+			expected: []string{"personal", "writing"},
+		},
+		{
+			name:     "comma separated tags",
+			input:    []string{" work , client:acme,", "v1.2"},
+			expected: []string{"client:acme", "v1.2", "work"},
+		},
+		{
+			name:  "tag with a space",
+			input: []string{"deep work"},
+			err:   ErrIncorrectTagProvided,
+		},
+		{
+			name:  "tag starting with a symbol",
+			input: []string{"-work"},
+			err:   ErrIncorrectTagProvided,
+		},
+		{
+			name:  "tag that's too long",
+			input: []string{"a-tag-that-is-way-too-long-to-be-useful"},
+			err:   ErrIncorrectTagProvided,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTags(tt.input)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected int
+		err      error
+	}{
+		{name: "whole number", input: "120", expected: 12000},
+		{name: "one decimal place", input: "120.5", expected: 12050},
+		{name: "two decimal places", input: " 0.05 ", expected: 5},
+		{name: "zero", input: "0.00", err: ErrIncorrectRateProvided},
+		{name: "negative", input: "-10", err: ErrIncorrectRateProvided},
+		{name: "too many decimal places", input: "10.505", err: ErrIncorrectRateProvided},
+		{name: "not a number", input: "ten", err: ErrIncorrectRateProvided},
+		{name: "too large", input: "99999999999", err: ErrIncorrectRateProvided},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRate(tt.input)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{name: "upper case", input: "EUR", expected: "EUR"},
+		{name: "lower case", input: " usd ", expected: "USD"},
+		{name: "too short", input: "EU", err: ErrIncorrectCurrencyProvided},
+		{name: "symbol", input: "€", err: ErrIncorrectCurrencyProvided},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCurrency(tt.input)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseBudget(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected int
+		err      error
+	}{
+		{name: "hours", input: "10h", expected: 36000},
+		{name: "hours and minutes", input: " 1h30m ", expected: 5400},
+		{name: "minutes", input: "45m", expected: 2700},
+		{name: "shorter than a minute", input: "30s", err: ErrIncorrectBudgetProvided},
+		{name: "negative", input: "-2h", err: ErrIncorrectBudgetProvided},
+		{name: "number without unit", input: "10", err: ErrIncorrectBudgetProvided},
+		{name: "empty", input: "", err: ErrIncorrectBudgetProvided},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBudget(tt.input)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseGoal(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected int
+		err      error
+	}{
+		{name: "hours", input: "6h", expected: 21600},
+		{name: "hours and minutes", input: "7h30m", expected: 27000},
+		{name: "shorter than a minute", input: "10s", err: ErrIncorrectGoalProvided},
+		{name: "number without unit", input: "6", err: ErrIncorrectGoalProvided},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGoal(tt.input)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dhth/hours/internal/domain"
 	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/types"
 )
@@ -16,24 +17,50 @@ const (
 	ActiveTaskTimePlaceholder = "{{time}}"
 	activeSecsThreshold       = 60
 	activeSecsThresholdStr    = "<1m"
+	activePausedSuffix        = ", paused"
 )
 
-func ShowActiveTask(db *sql.DB, writer io.Writer, template string) error {
+type activeTaskJSON struct {
+	TaskLogID   int        `json:"task_log_id"`
+	TaskID      int        `json:"task_id"`
+	TaskSummary string     `json:"task_summary"`
+	BeginTS     time.Time  `json:"begin_ts"`
+	SecsSpent   int        `json:"secs_spent"`
+	Comment     *string    `json:"comment"`
+	Paused      bool       `json:"paused"`
+	PausedAt    *time.Time `json:"paused_at"`
+}
+
+func ShowActiveTask(db *sql.DB, writer io.Writer, template string, outputFormat types.OutputFormat, now time.Time) error {
 	activeTaskDetails, err := pers.FetchActiveTaskDetails(db)
 	if err != nil {
 		return err
 	}
 
+	if outputFormat == types.OutputFormatJSON {
+		output, err := getActiveTaskJSON(activeTaskDetails, now)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(writer, output)
+		return nil
+	}
+
 	if activeTaskDetails.TaskID == -1 {
 		return nil
 	}
 
-	timeSpent := time.Since(activeTaskDetails.CurrentLogBeginTS).Seconds()
+	timeSpent := activeTaskDetails.SecsSpent(now)
 	var timeSpentStr string
 	if timeSpent <= activeSecsThreshold {
 		timeSpentStr = activeSecsThresholdStr
 	} else {
-		timeSpentStr = types.HumanizeDuration(int(timeSpent))
+		timeSpentStr = types.HumanizeDuration(timeSpent)
+	}
+
+	if activeTaskDetails.PausedAt() != nil {
+		timeSpentStr += activePausedSuffix
 	}
 
 	activeStr := strings.Replace(template, ActiveTaskPlaceholder, activeTaskDetails.TaskSummary, 1)
@@ -41,3 +68,21 @@ func ShowActiveTask(db *sql.DB, writer io.Writer, template string) error {
 	fmt.Fprint(writer, activeStr)
 	return nil
 }
+
+func getActiveTaskJSON(details domain.ActiveTaskDetails, now time.Time) (string, error) {
+	// null signifies that no task is being tracked
+	if details.TaskID == -1 {
+		return marshalJSON(nil)
+	}
+
+	return marshalJSON(activeTaskJSON{
+		TaskLogID:   details.CurrentLogID,
+		TaskID:      details.TaskID,
+		TaskSummary: details.TaskSummary,
+		BeginTS:     details.CurrentLogBeginTS,
+		SecsSpent:   details.SecsSpent(now),
+		Comment:     details.CurrentLogComment,
+		Paused:      details.PausedAt() != nil,
+		PausedAt:    details.PausedAt(),
+	})
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+var errCouldntGenerateBudgetStats = errors.New("couldn't generate budget stats")
+
+const budgetTimeCharsBudget = 16
+
+var budgetStatsHeaders = []string{"Task", "Budget", "TimeSpent", "SpentInPeriod", "Remaining", "Used"}
+
+// RenderBudgetStats shows how much of their budgets tasks have used up, and
+// how much time was spent on them in a period.
+func RenderBudgetStats(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	outputFormat types.OutputFormat,
+	dateRange *types.DateRange,
+	filter types.TaskFilter,
+) error {
+	entries, err := getBudgetReport(db, dateRange, filter)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateBudgetStats, err.Error())
+	}
+
+	var output string
+	switch outputFormat {
+	case types.OutputFormatJSON:
+		output, err = marshalJSON(entries)
+	case types.OutputFormatMarkdown, types.OutputFormatHTML:
+		output, err = getBudgetStatsDoc(entries, dateRange).render(style, outputFormat)
+	default:
+		output, err = getBudgetStatsTable(entries, style, plain)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateBudgetStats, err.Error())
+	}
+
+	fmt.Fprint(writer, output)
+	return nil
+}
+
+func getBudgetReport(db *sql.DB, dateRange *types.DateRange, filter types.TaskFilter) ([]domain.BudgetReportEntry, error) {
+	tasks, err := pers.FetchTasksWithBudget(db, filter, tasksLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := fetchStats(db, dateRange, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	secsSpentInPeriod := make(map[int]int, len(stats))
+	for _, entry := range stats {
+		secsSpentInPeriod[entry.TaskID] = entry.SecsSpent
+	}
+
+	return domain.GetBudgetReport(tasks, secsSpentInPeriod), nil
+}
+
+// formatBudgetRemaining shows the time left in a budget, or by how much it has
+// been overrun.
+func formatBudgetRemaining(entry domain.BudgetReportEntry) string {
+	if entry.OverBudget() {
+		return "over by " + types.HumanizeDuration(-entry.SecsRemaining)
+	}
+
+	return types.HumanizeDuration(entry.SecsRemaining)
+}
+
+func getBudgetStatsValues(entry domain.BudgetReportEntry) []string {
+	return []string{
+		entry.TaskSummary,
+		types.HumanizeDuration(entry.BudgetSecs),
+		types.HumanizeDuration(entry.SecsSpent),
+		types.HumanizeDuration(entry.SecsSpentInPeriod),
+		formatBudgetRemaining(entry),
+		fmt.Sprintf("%d%%", entry.PercentUsed),
+	}
+}
+
+func getBudgetStatsDoc(entries []domain.BudgetReportEntry, dateRange *types.DateRange) recordsDoc {
+	doc := recordsDoc{
+		title:   getRecordsTitle("Budgets", dateRange),
+		headers: budgetStatsHeaders,
+	}
+
+	for _, entry := range entries {
+		values := getBudgetStatsValues(entry)
+		row := make([]recordsCell, len(values))
+		for i, value := range values {
+			row[i] = recordsCell{text: value, task: entry.TaskSummary}
+		}
+		doc.rows = append(doc.rows, row)
+	}
+
+	return doc
+}
+
+func getBudgetStatsTable(entries []domain.BudgetReportEntry, style Style, plain bool) (string, error) {
+	data := make([][]string, 0, max(len(entries), 1))
+	if len(entries) == 0 {
+		data = append(data, []string{
+			utils.RightPadTrim("", 20, false),
+			"",
+			"",
+			"",
+			utils.RightPadTrim("", budgetTimeCharsBudget, false),
+			"",
+		})
+	}
+
+	rs := style.getReportStyles(plain)
+
+	for _, entry := range entries {
+		row := getBudgetStatsValues(entry)
+		row[0] = utils.RightPadTrim(row[0], 20, false)
+		row[4] = utils.RightPadTrim(row[4], budgetTimeCharsBudget, false)
+
+		if !plain {
+			rowStyle := style.getDynamicStyle(entry.TaskSummary)
+			for j, value := range row {
+				row[j] = rowStyle.Render(value)
+			}
+			if entry.OverBudget() {
+				row[4] = style.tlFormErrStyle.Render(utils.RightPadTrim(formatBudgetRemaining(entry), budgetTimeCharsBudget, false))
+				row[5] = style.tlFormErrStyle.Render(fmt.Sprintf("%d%%", entry.PercentUsed))
+			}
+		}
+
+		data = append(data, row)
+	}
+
+	headers := make([]string, len(budgetStatsHeaders))
+	for i, h := range budgetStatsHeaders {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+	b := bytes.Buffer{}
+	table := tablewriter.NewTable(
+		&b,
+		tablewriter.WithConfig(tablewriter.Config{
+			Header: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment:  tw.AlignCenter,
+					AutoWrap:   tw.WrapNone,
+					AutoFormat: tw.Off,
+				},
+			},
+			Row: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment: tw.AlignLeft,
+					AutoWrap:  tw.WrapNone,
+				},
+			},
+		}),
+		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{Symbols: rs.symbols(tw.StyleASCII)})),
+		tablewriter.WithHeader(headers),
+	)
+
+	if err := table.Bulk(data); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntAddDataToTable, err.Error())
+	}
+
+	if err := table.Render(); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntRenderTable, err.Error())
+	}
+
+	return b.String(), nil
+}
@@ -3,6 +3,8 @@ package ui
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -41,15 +43,14 @@ LIMIT 1
 
 		switch isTrackingActive {
 		case false:
-			_, err = pers.InsertNewTL(db, taskID, beginTs)
+			_, err = pers.InsertNewTL(db, taskID, beginTs, nil)
 			if err != nil {
 				return trackingToggledMsg{err: err}
 			}
 			return trackingToggledMsg{taskID: taskID}
 
 		default:
-			secsSpent := int(endTs.Sub(beginTs).Seconds())
-			err := pers.FinishActiveTL(db, activeTaskLogID, activeTaskID, beginTs, endTs, secsSpent, comment)
+			secsSpent, err := pers.FinishActiveTL(db, activeTaskLogID, activeTaskID, beginTs, endTs, comment)
 			if err != nil {
 				return trackingToggledMsg{err: err}
 			}
@@ -58,9 +59,22 @@ LIMIT 1
 	}
 }
 
+func toggleActiveTLPause(db *sql.DB, ts time.Time, pause bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if pause {
+			err = pers.PauseActiveTL(db, ts)
+		} else {
+			err = pers.ResumeActiveTL(db, ts)
+		}
+
+		return activeTLPauseToggledMsg{ts, pause, err}
+	}
+}
+
 func quickSwitchActiveIssue(db *sql.DB, taskID int, ts time.Time) tea.Cmd {
 	return func() tea.Msg {
-		result, err := pers.QuickSwitchActiveTL(db, taskID, ts)
+		result, err := pers.QuickSwitchActiveTL(db, taskID, ts, nil)
 		return activeTLSwitchedMsg{
 			lastActiveTaskID:      result.LastActiveTaskID,
 			currentlyActiveTaskID: taskID,
@@ -73,22 +87,95 @@ func quickSwitchActiveIssue(db *sql.DB, taskID int, ts time.Time) tea.Cmd {
 
 func updateActiveTL(db *sql.DB, beginTS time.Time, comment *string) tea.Cmd {
 	return func() tea.Msg {
-		err := pers.EditActiveTL(db, beginTS, comment)
-		return activeTLUpdatedMsg{beginTS, comment, err}
+		activeTask, err := pers.FetchActiveTaskDetails(db)
+		if err != nil {
+			return activeTLUpdatedMsg{err: err}
+		}
+		if activeTask.TaskID == -1 {
+			return activeTLUpdatedMsg{err: pers.ErrNoTaskActive}
+		}
+
+		change, err := recordChange(db, "editing the active recording",
+			[]int{activeTask.CurrentLogID}, []int{activeTask.TaskID},
+			func() ([]int, error) {
+				return nil, pers.EditActiveTL(db, beginTS, comment)
+			},
+		)
+		return activeTLUpdatedMsg{beginTS, comment, change, err}
 	}
 }
 
-func insertManualTL(db *sql.DB, taskID int, beginTS time.Time, endTS time.Time, comment *string) tea.Cmd {
+func insertManualTL(db *sql.DB, taskID int, beginTS time.Time, endTS time.Time, comment *string, rejectOverlaps bool) tea.Cmd {
 	return func() tea.Msg {
-		_, err := pers.InsertManualTL(db, taskID, beginTS, endTS, comment)
+		_, err := pers.InsertManualTL(db, taskID, beginTS, endTS, comment, rejectOverlaps)
 		return manualTLInsertedMsg{taskID, err}
 	}
 }
 
-func editSavedTL(db *sql.DB, tlID, taskID int, beginTS time.Time, endTS time.Time, comment *string) tea.Cmd {
+func editSavedTL(db *sql.DB, tlID, taskID int, beginTS time.Time, endTS time.Time, comment *string, rejectOverlaps bool) tea.Cmd {
+	return func() tea.Msg {
+		change, err := recordChange(db, fmt.Sprintf("editing task log entry #%d", tlID),
+			[]int{tlID}, []int{taskID},
+			func() ([]int, error) {
+				_, err := pers.EditSavedTL(db, tlID, beginTS, endTS, comment, rejectOverlaps)
+				return nil, err
+			},
+		)
+		return savedTLEditedMsg{tlID, taskID, change, err}
+	}
+}
+
+func fetchTLOverlaps(db *sql.DB, check tlOverlapCheck) tea.Cmd {
 	return func() tea.Msg {
-		_, err := pers.EditSavedTL(db, tlID, beginTS, endTS, comment)
-		return savedTLEditedMsg{tlID, taskID, err}
+		entries, err := pers.FetchOverlappingTLs(db, check.beginTS, check.endTS, check.excludeTLID, check.includeActive)
+		return tLOverlapsFetchedMsg{check, entries, err}
+	}
+}
+
+func splitTL(db *sql.DB, entry domain.TaskLogEntry, splitTS time.Time, taskID int) tea.Cmd {
+	return func() tea.Msg {
+		taskIDs := []int{entry.TaskID}
+		if taskID != entry.TaskID {
+			taskIDs = append(taskIDs, taskID)
+		}
+
+		var newTLID int
+		change, err := recordChange(db, fmt.Sprintf("splitting task log entry #%d", entry.ID),
+			[]int{entry.ID}, taskIDs,
+			func() ([]int, error) {
+				var err error
+				newTLID, err = pers.SplitTL(db, entry.ID, splitTS, taskID)
+				return []int{newTLID}, err
+			},
+		)
+		return tLSplitMsg{entry, taskID, newTLID, change, err}
+	}
+}
+
+func mergeTLs(db *sql.DB, taskID, tlID, otherTLID int, gap time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		var mergedID int
+		change, err := recordChange(db, fmt.Sprintf("merging task log entries #%d and #%d", tlID, otherTLID),
+			[]int{tlID, otherTLID}, []int{taskID},
+			func() ([]int, error) {
+				var err error
+				mergedID, err = pers.MergeTLs(db, tlID, otherTLID)
+				return nil, err
+			},
+		)
+		return tLsMergedMsg{taskID, mergedID, gap, change, err}
+	}
+}
+
+func moveTL(db *sql.DB, entry domain.TaskLogEntry, taskID int) tea.Cmd {
+	return func() tea.Msg {
+		change, err := recordChange(db, fmt.Sprintf("moving task log entry #%d", entry.ID),
+			[]int{entry.ID}, []int{entry.TaskID, taskID},
+			func() ([]int, error) {
+				return nil, pers.MoveTL(db, entry.ID, taskID)
+			},
+		)
+		return tLMovedMsg{entry, taskID, change, err}
 	}
 }
 
@@ -133,41 +220,125 @@ func fetchTLS(db *sql.DB, tlIDToFocusOn *int) tea.Cmd {
 	}
 }
 
-func deleteTL(db *sql.DB, entry *domain.TaskLogEntry) tea.Cmd {
+// fetchGoalProgress fetches the goals, and the task log entries from the
+// current week, which the progress shown in the footer is based on.
+func fetchGoalProgress(db *sql.DB, now time.Time) tea.Cmd {
 	return func() tea.Msg {
-		err := pers.DeleteTL(db, entry)
+		goals, err := pers.FetchGoals(db)
+		if err != nil {
+			return goalProgressFetchedMsg{err: err}
+		}
+
+		if goals.WeeklySecs == nil {
+			return goalProgressFetchedMsg{goals: goals}
+		}
+
+		week, err := types.GetDateRangeFromPeriod(types.TimePeriodWeek, now, false, nil)
+		if err != nil {
+			return goalProgressFetchedMsg{err: err}
+		}
+
+		entries, err := pers.FetchTLEntriesOverlapping(db, week.Start, week.End, statsLogEntriesLimit)
+		return goalProgressFetchedMsg{
+			goals:       goals,
+			weekEntries: entries,
+			err:         err,
+		}
+	}
+}
+
+// startBreak starts tracking time on the break task, which is created if it
+// doesn't exist yet.
+func startBreak(db *sql.DB, beginTS time.Time) tea.Cmd {
+	return func() tea.Msg {
+		tasks, err := pers.FetchTasks(db, true, 1000)
+		if err != nil {
+			return breakStartedMsg{err: err}
+		}
+
+		breakTaskID := -1
+		for _, task := range tasks {
+			if strings.EqualFold(task.Summary, breakTaskSummary) {
+				breakTaskID = task.ID
+				break
+			}
+		}
+
+		if breakTaskID == -1 {
+			breakTaskID, err = pers.InsertTask(db, breakTaskSummary)
+			if err != nil {
+				return breakStartedMsg{err: err}
+			}
+		}
+
+		_, err = pers.InsertNewTL(db, breakTaskID, beginTS, nil)
+		return breakStartedMsg{breakTaskID, err}
+	}
+}
+
+func deleteTL(db *sql.DB, entry *domain.TaskLogEntry, deletedAt time.Time) tea.Cmd {
+	return func() tea.Msg {
+		change, err := recordChange(db, fmt.Sprintf("deleting task log entry #%d", entry.ID),
+			[]int{entry.ID}, []int{entry.TaskID},
+			func() ([]int, error) {
+				return nil, pers.DeleteTL(db, entry, deletedAt)
+			},
+		)
 		return tLDeletedMsg{
-			entry: entry,
-			err:   err,
+			entry:  entry,
+			change: change,
+			err:    err,
 		}
 	}
 }
 
 func deleteActiveTL(db *sql.DB) tea.Cmd {
 	return func() tea.Msg {
-		err := pers.DeleteActiveTL(db)
-		return activeTaskLogDeletedMsg{err}
+		activeTask, err := pers.FetchActiveTaskDetails(db)
+		if err != nil {
+			return activeTaskLogDeletedMsg{err: err}
+		}
+		if activeTask.TaskID == -1 {
+			return activeTaskLogDeletedMsg{err: pers.ErrNoTaskActive}
+		}
+
+		change, err := recordChange(db, "discarding the active recording",
+			[]int{activeTask.CurrentLogID}, []int{activeTask.TaskID},
+			func() ([]int, error) {
+				return nil, pers.DeleteActiveTL(db)
+			},
+		)
+		return activeTaskLogDeletedMsg{change, err}
 	}
 }
 
-func createTask(db *sql.DB, summary string) tea.Cmd {
+func createTask(db *sql.DB, summary string, projectID int, tags []string) tea.Cmd {
 	return func() tea.Msg {
-		_, err := pers.InsertTask(db, summary)
+		_, err := pers.InsertTaskWithDetails(db, summary, projectID, tags)
 		return taskCreatedMsg{err}
 	}
 }
 
-func updateTask(db *sql.DB, task *taskListItem, summary string) tea.Cmd {
+func updateTask(db *sql.DB, task *taskListItem, summary string, tags []string) tea.Cmd {
 	return func() tea.Msg {
-		err := pers.UpdateTask(db, task.ID, summary)
-		return taskUpdatedMsg{task, summary, err}
+		err := pers.UpdateTaskWithTags(db, task.ID, summary, tags)
+		return taskUpdatedMsg{task, summary, tags, err}
 	}
 }
 
 func updateTaskActiveStatus(db *sql.DB, task *taskListItem, active bool) tea.Cmd {
 	return func() tea.Msg {
-		err := pers.UpdateTaskActiveStatus(db, task.ID, active)
-		return taskActiveStatusUpdatedMsg{task, active, err}
+		desc := fmt.Sprintf("deactivating task %q", task.Summary)
+		if active {
+			desc = fmt.Sprintf("activating task %q", task.Summary)
+		}
+
+		change, err := recordChange(db, desc, nil, []int{task.ID},
+			func() ([]int, error) {
+				return nil, pers.UpdateTaskActiveStatus(db, task.ID, active)
+			},
+		)
+		return taskActiveStatusUpdatedMsg{task, active, change, err}
 	}
 }
 
@@ -195,7 +366,8 @@ func getRecordsData(
 	db *sql.DB,
 	style Style,
 	dateRange types.DateRange,
-	taskStatus types.TaskStatus,
+	filter types.TaskFilter,
+	groupBy types.GroupBy,
 	plain bool,
 ) tea.Cmd {
 	return func() tea.Msg {
@@ -204,13 +376,15 @@ func getRecordsData(
 
 		switch analyticsType {
 		case reportRecords:
-			data, err = getReport(db, style, dateRange.Start, dateRange.NumDays, taskStatus, plain)
+			data, err = getReport(db, style, dateRange.Start, dateRange.NumDays, filter, plain)
 		case reportAggRecords:
-			data, err = getReportAgg(db, style, dateRange.Start, dateRange.NumDays, taskStatus, plain)
+			data, err = getReportAgg(db, style, dateRange.Start, dateRange.NumDays, filter, plain)
+		case reportGroupedRecords:
+			data, err = getReportGrouped(db, style, dateRange.Start, dateRange.NumDays, filter, groupBy, plain)
 		case reportLogs:
-			data, err = getTaskLog(db, style, dateRange.Start, dateRange.End, taskStatus, 20, plain)
+			data, err = getTaskLog(db, style, dateRange.Start, dateRange.End, filter, 20, plain)
 		case reportStats:
-			data, err = getStats(db, style, &dateRange, taskStatus, plain)
+			data, err = getStats(db, style, &dateRange, filter, groupBy, plain)
 		}
 
 		return recordsDataFetchedMsg{
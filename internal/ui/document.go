@@ -0,0 +1,289 @@
+package ui
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"image/color"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+var (
+	errCommentsNotApplicable  = errors.New("comments can only be included in HTML output")
+	errCommentsNeedAPeriod    = errors.New("comments can't be included when period=all")
+	errCouldntRenderHTML      = errors.New("couldn't render HTML")
+	errCouldntFetchTLComments = errors.New("couldn't fetch comments of task log entries")
+)
+
+const (
+	htmlBackgroundColor = "#1e1e2e"
+	htmlTextColor       = "#cdd6f4"
+)
+
+// recordsDoc is a format agnostic version of a report, log, or stats table; it's
+// used to render records as Markdown or HTML.
+type recordsDoc struct {
+	title   string
+	headers []string
+	rows    [][]recordsCell
+	footer  []string
+	// comments is only rendered (in HTML output) if withComments is set
+	comments     []domain.TaskLogEntry
+	withComments bool
+}
+
+type recordsCell struct {
+	text   string
+	detail string
+	// task determines the color of the cell in HTML output; cells without a
+	// task aren't colored
+	task string
+}
+
+func getRecordsTitle(kind string, dateRange *types.DateRange) string {
+	if dateRange == nil {
+		return fmt.Sprintf("%s (all time)", kind)
+	}
+
+	if dateRange.NumDays <= 1 {
+		return fmt.Sprintf("%s: %s", kind, dateRange.Start.Format(dateFormat))
+	}
+
+	return fmt.Sprintf("%s: %s ... %s",
+		kind,
+		dateRange.Start.Format(dateFormat),
+		dateRange.End.AddDate(0, 0, -1).Format(dateFormat),
+	)
+}
+
+func fetchTLsWithComments(db *sql.DB, start, end time.Time, filter types.TaskFilter) ([]domain.TaskLogEntry, error) {
+	entries, err := pers.FetchTLEntriesBetweenTS(db, start, end, filter, logLimit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errCouldntFetchTLComments, err.Error())
+	}
+
+	var withComments []domain.TaskLogEntry
+	for _, entry := range entries {
+		if entry.Comment != nil && strings.TrimSpace(*entry.Comment) != "" {
+			withComments = append(withComments, entry)
+		}
+	}
+
+	return withComments, nil
+}
+
+func (d recordsDoc) render(style Style, outputFormat types.OutputFormat) (string, error) {
+	if outputFormat == types.OutputFormatHTML {
+		return d.html(style)
+	}
+
+	return d.markdown(), nil
+}
+
+var markdownCellEscaper = strings.NewReplacer(
+	"|", `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+// markdown renders the document as a GitHub flavoured Markdown table.
+func (d recordsDoc) markdown() string {
+	var b strings.Builder
+
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			fmt.Fprintf(&b, " %s |", markdownCellEscaper.Replace(strings.TrimSpace(cell)))
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(d.headers)
+
+	separators := make([]string, len(d.headers))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	writeRow(separators)
+
+	for _, row := range d.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell.text
+			if cell.detail != "" {
+				cells[i] = fmt.Sprintf("%s (%s)", cell.text, cell.detail)
+			}
+		}
+		writeRow(cells)
+	}
+
+	if len(d.footer) > 0 {
+		cells := make([]string, len(d.footer))
+		for i, cell := range d.footer {
+			if strings.TrimSpace(cell) != "" {
+				cells[i] = fmt.Sprintf("**%s**", cell)
+			}
+		}
+		writeRow(cells)
+	}
+
+	return b.String()
+}
+
+// cssColor converts a theme color (either a hex code, or an ANSI 256 color
+// code) to a hex code.
+func cssColor(value string) template.CSS {
+	var c color.Color = lipgloss.Color(value)
+	if c == nil {
+		c = lipgloss.Color(fallbackTaskColor)
+	}
+
+	r, g, b, _ := c.RGBA()
+	return template.CSS(fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8))
+}
+
+type htmlCell struct {
+	Text   string
+	Detail string
+	Color  template.CSS
+}
+
+type htmlComment struct {
+	Time    string
+	Task    string
+	Comment string
+	Color   template.CSS
+}
+
+type htmlDoc struct {
+	Title         string
+	Headers       []string
+	Rows          [][]htmlCell
+	Footer        []string
+	Comments      []htmlComment
+	WithComments  bool
+	Background    template.CSS
+	Text          template.CSS
+	TitleColor    template.CSS
+	HeaderColor   template.CSS
+	FooterColor   template.CSS
+	BorderColor   template.CSS
+	CommentsColor template.CSS
+}
+
+var recordsHTMLTemplate = template.Must(template.New("records").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { background-color: {{.Background}}; color: {{.Text}}; font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 14px; margin: 2em; }
+h1 { color: {{.TitleColor}}; font-size: 1.2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid {{.BorderColor}}; padding: 0.4em 0.8em; text-align: left; vertical-align: top; }
+th { color: {{.HeaderColor}}; }
+tfoot td { color: {{.FooterColor}}; font-weight: bold; }
+.detail { opacity: 0.75; padding-left: 0.5em; }
+.comment { white-space: pre-wrap; }
+details { margin-top: 2em; }
+summary { color: {{.CommentsColor}}; cursor: pointer; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td{{if .Color}} style="color: {{.Color}}"{{end}}>{{.Text}}{{if .Detail}}<span class="detail">{{.Detail}}</span>{{end}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+{{- if .Footer}}
+<tfoot>
+<tr>{{range .Footer}}<td>{{.}}</td>{{end}}</tr>
+</tfoot>
+{{- end}}
+</table>
+{{- if .WithComments}}
+<details>
+<summary>Comments ({{len .Comments}})</summary>
+<table>
+<thead>
+<tr><th>Time</th><th>Task</th><th>Comment</th></tr>
+</thead>
+<tbody>
+{{- range .Comments}}
+<tr style="color: {{.Color}}"><td>{{.Time}}</td><td>{{.Task}}</td><td class="comment">{{.Comment}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</details>
+{{- end}}
+</body>
+</html>
+`))
+
+// html renders the document as a self-contained HTML page, with tasks shown in
+// the theme's colors.
+func (d recordsDoc) html(style Style) (string, error) {
+	colorCache := make(map[string]template.CSS)
+	taskColor := func(task string) template.CSS {
+		c, ok := colorCache[task]
+		if !ok {
+			c = cssColor(style.getDynamicColor(task))
+			colorCache[task] = c
+		}
+		return c
+	}
+
+	doc := htmlDoc{
+		Title:         d.title,
+		Headers:       d.headers,
+		Footer:        d.footer,
+		WithComments:  d.withComments,
+		Background:    template.CSS(htmlBackgroundColor),
+		Text:          template.CSS(htmlTextColor),
+		TitleColor:    cssColor(style.theme.RecordsDateRange),
+		HeaderColor:   cssColor(style.theme.RecordsHeader),
+		FooterColor:   cssColor(style.theme.RecordsFooter),
+		BorderColor:   cssColor(style.theme.RecordsBorder),
+		CommentsColor: cssColor(style.theme.RecordsHelp),
+	}
+
+	for _, row := range d.rows {
+		cells := make([]htmlCell, len(row))
+		for i, cell := range row {
+			cells[i] = htmlCell{Text: cell.text, Detail: cell.detail}
+			if cell.task != "" {
+				cells[i].Color = taskColor(cell.task)
+			}
+		}
+		doc.Rows = append(doc.Rows, cells)
+	}
+
+	for _, entry := range d.comments {
+		doc.Comments = append(doc.Comments, htmlComment{
+			Time:    fmt.Sprintf("%s ... %s", entry.BeginTS.Format(timeFormat), entry.EndTS.Format(timeFormat)),
+			Task:    entry.TaskSummary,
+			Comment: taskLogComment(entry.Comment),
+			Color:   taskColor(entry.TaskSummary),
+		})
+	}
+
+	var b bytes.Buffer
+	if err := recordsHTMLTemplate.Execute(&b, doc); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntRenderHTML, err.Error())
+	}
+
+	return b.String(), nil
+}
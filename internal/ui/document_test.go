@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/ui/theme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordsDocMarkdown(t *testing.T) {
+	// GIVEN
+	doc := recordsDoc{
+		headers: []string{"Task", "Comment"},
+		rows: [][]recordsCell{
+			{{text: "a|b", task: "a|b"}, {text: "line 1\nline 2"}},
+			{{text: "c", detail: "1h"}, {}},
+		},
+		footer: []string{"2h", ""},
+	}
+
+	// WHEN
+	got := doc.markdown()
+
+	// THEN
+	expected := `| Task | Comment |
+| --- | --- |
+| a\|b | line 1<br>line 2 |
+| c (1h) |  |
+| **2h** |  |
+`
+	assert.Equal(t, expected, got)
+}
+
+func TestRecordsDocHTML(t *testing.T) {
+	// GIVEN
+	style := NewStyle(theme.Default())
+	comment := "<b>not bold</b>"
+	doc := recordsDoc{
+		title:        "Log: 2025/10/24",
+		headers:      []string{"Task"},
+		rows:         [][]recordsCell{{{text: "<script>", task: "<script>"}}},
+		withComments: true,
+	}
+	doc.comments = append(doc.comments, domain.TaskLogEntry{
+		TaskSummary: "task",
+		BeginTS:     time.Date(2025, time.October, 24, 9, 0, 0, 0, time.UTC),
+		EndTS:       time.Date(2025, time.October, 24, 10, 0, 0, 0, time.UTC),
+		Comment:     &comment,
+	})
+
+	// WHEN
+	got, err := doc.html(style)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, got, "<title>Log: 2025/10/24</title>")
+	assert.Contains(t, got, "&lt;script&gt;")
+	assert.NotContains(t, got, "<script>")
+	assert.Contains(t, got, "&lt;b&gt;not bold&lt;/b&gt;")
+	assert.Contains(t, got, "<summary>Comments (1)</summary>")
+	assert.Contains(t, got, string(cssColor(style.getDynamicColor("<script>"))))
+}
+
+func TestCSSColor(t *testing.T) {
+	assert.Equal(t, "#ada7ff", string(cssColor("#ada7ff")))
+	assert.Equal(t, "#ff0000", string(cssColor("9")))
+	assert.Equal(t, "#000000", string(cssColor("16")))
+}
@@ -135,7 +135,7 @@ func GenerateData(db *sql.DB, numDays, numTasks uint8, rng *rand.Rand, now time.
 				comment = &commentStr
 			}
 
-			_, err = pers.InsertManualTL(db, int(i+1), beginTs, endTs, comment)
+			_, err = pers.InsertManualTL(db, int(i+1), beginTs, endTs, comment, false)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+var (
+	errCouldntGenerateGoalReport = errors.New("couldn't generate goal report")
+	errNoDailyGoal               = errors.New(`no daily goal is set; set one via "hours goals set --daily <DURATION>"`)
+)
+
+const (
+	// the current streak is looked up this many days at a time
+	goalStreakWindowDays = 28
+	goalStreakMaxWindows = 100
+)
+
+// goalReportJSON adds the period a goal report is for to its JSON
+// representation.
+type goalReportJSON struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	domain.GoalReport
+	WeeklyGoalSecs      *int `json:"weekly_goal_secs,omitempty"`
+	SecsTrackedThisWeek *int `json:"secs_tracked_this_week,omitempty"`
+}
+
+// RenderGoals shows which days in a period met the daily goal, along with the
+// current streak of such days.
+func RenderGoals(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	outputFormat types.OutputFormat,
+	dateRange types.DateRange,
+	now time.Time,
+) error {
+	goals, err := pers.FetchGoals(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateGoalReport, err.Error())
+	}
+
+	if goals.DailySecs == nil {
+		return errNoDailyGoal
+	}
+
+	report, err := getGoalReport(db, *goals.DailySecs, dateRange, now)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateGoalReport, err.Error())
+	}
+
+	var secsThisWeek *int
+	if goals.WeeklySecs != nil {
+		secs, err := fetchSecsTrackedThisWeek(db, now)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntGenerateGoalReport, err.Error())
+		}
+		secsThisWeek = &secs
+	}
+
+	var output string
+	switch outputFormat {
+	case types.OutputFormatJSON:
+		output, err = marshalJSON(goalReportJSON{
+			From:                dateRange.Start.Format(time.DateOnly),
+			To:                  dateRange.End.AddDate(0, 0, -1).Format(time.DateOnly),
+			GoalReport:          report,
+			WeeklyGoalSecs:      goals.WeeklySecs,
+			SecsTrackedThisWeek: secsThisWeek,
+		})
+	default:
+		output, err = getGoalReportTable(report, style, plain)
+		if err == nil {
+			output += getGoalSummary(report, goals.WeeklySecs, secsThisWeek)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntGenerateGoalReport, err.Error())
+	}
+
+	fmt.Fprint(writer, output)
+	return nil
+}
+
+func fetchSecsTrackedPerDay(db *sql.DB, start time.Time, numDays int) ([]int, error) {
+	end := start.AddDate(0, 0, numDays)
+	entries, err := pers.FetchTLEntriesOverlapping(db, start, end, statsLogEntriesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.SecondsTrackedPerDay(entries, start, numDays), nil
+}
+
+func getGoalReport(db *sql.DB, dailyGoalSecs int, dateRange types.DateRange, now time.Time) (domain.GoalReport, error) {
+	secsPerDay, err := fetchSecsTrackedPerDay(db, dateRange.Start, dateRange.NumDays)
+	if err != nil {
+		return domain.GoalReport{}, err
+	}
+
+	report := domain.GetGoalReport(dailyGoalSecs, dateRange.Start, secsPerDay)
+
+	report.CurrentStreak, err = getCurrentStreak(db, dailyGoalSecs, now)
+	if err != nil {
+		return domain.GoalReport{}, err
+	}
+
+	return report, nil
+}
+
+// getCurrentStreak goes back a few weeks at a time, till it finds a day that
+// didn't meet the goal.
+func getCurrentStreak(db *sql.DB, dailyGoalSecs int, now time.Time) (int, error) {
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	var streak int
+	for window := range goalStreakMaxWindows {
+		start := end.AddDate(0, 0, -goalStreakWindowDays)
+		secsPerDay, err := fetchSecsTrackedPerDay(db, start, goalStreakWindowDays)
+		if err != nil {
+			return 0, err
+		}
+
+		// today isn't over yet, so it doesn't break the streak
+		if window == 0 && secsPerDay[len(secsPerDay)-1] < dailyGoalSecs {
+			secsPerDay = secsPerDay[:len(secsPerDay)-1]
+		}
+
+		windowStreak, unbroken := domain.CountStreak(secsPerDay, dailyGoalSecs)
+		streak += windowStreak
+		if !unbroken {
+			break
+		}
+
+		end = start
+	}
+
+	return streak, nil
+}
+
+func pluralizeDays(num int) string {
+	if num == 1 {
+		return "1 day"
+	}
+
+	return fmt.Sprintf("%d days", num)
+}
+
+func fetchSecsTrackedThisWeek(db *sql.DB, now time.Time) (int, error) {
+	week, err := types.GetDateRangeFromPeriod(types.TimePeriodWeek, now, false, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	secsPerDay, err := fetchSecsTrackedPerDay(db, week.Start, week.NumDays)
+	if err != nil {
+		return 0, err
+	}
+
+	var secs int
+	for _, secsOnDay := range secsPerDay {
+		secs += secsOnDay
+	}
+
+	return secs, nil
+}
+
+func getGoalSummary(report domain.GoalReport, weeklyGoalSecs, secsThisWeek *int) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\nDaily goal: %s; met on %d of %s\n",
+		types.HumanizeDuration(report.DailyGoalSecs),
+		report.DaysMet,
+		pluralizeDays(len(report.Days)),
+	)
+	fmt.Fprintf(&b, "Current streak: %s; longest streak in period: %s\n",
+		pluralizeDays(report.CurrentStreak),
+		pluralizeDays(report.LongestStreak),
+	)
+
+	if weeklyGoalSecs != nil && secsThisWeek != nil {
+		fmt.Fprintf(&b, "Tracked this week: %s\n", describeGoalProgress(*secsThisWeek, *weeklyGoalSecs))
+	}
+
+	return b.String()
+}
+
+// describeGoalProgress shows the time tracked towards a goal, and the time
+// left to meet it.
+func describeGoalProgress(secsTracked, goalSecs int) string {
+	progress := fmt.Sprintf("%s of %s", types.HumanizeDuration(secsTracked), types.HumanizeDuration(goalSecs))
+	if secsTracked >= goalSecs {
+		return progress + " (met)"
+	}
+
+	return fmt.Sprintf("%s (%s left)", progress, types.HumanizeDuration(goalSecs-secsTracked))
+}
+
+func getGoalReportTable(report domain.GoalReport, style Style, plain bool) (string, error) {
+	rs := style.getReportStyles(plain)
+
+	data := make([][]string, len(report.Days))
+	for i, day := range report.Days {
+		met := "no"
+		if day.GoalMet {
+			met = "yes"
+		}
+
+		row := []string{
+			day.Date,
+			types.HumanizeDuration(day.SecsTracked),
+			met,
+		}
+
+		if !plain {
+			rowStyle := style.tlFormWarnStyle
+			if day.GoalMet {
+				rowStyle = style.tlFormOkStyle
+			}
+			for j, value := range row {
+				row[j] = rowStyle.Render(value)
+			}
+		}
+
+		data[i] = row
+	}
+
+	headerValues := []string{"Date", "Tracked", "GoalMet"}
+	headers := make([]string, len(headerValues))
+	for i, h := range headerValues {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+	b := bytes.Buffer{}
+	table := tablewriter.NewTable(
+		&b,
+		tablewriter.WithConfig(tablewriter.Config{
+			Header: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment:  tw.AlignCenter,
+					AutoWrap:   tw.WrapNone,
+					AutoFormat: tw.Off,
+				},
+			},
+			Row: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment: tw.AlignLeft,
+					AutoWrap:  tw.WrapNone,
+				},
+			},
+		}),
+		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{Symbols: rs.symbols(tw.StyleASCII)})),
+		tablewriter.WithHeader(headers),
+	)
+
+	if err := table.Bulk(data); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntAddDataToTable, err.Error())
+	}
+
+	if err := table.Render(); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntRenderTable, err.Error())
+	}
+
+	return b.String(), nil
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -10,6 +11,8 @@ import (
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 	c "github.com/dhth/hours/internal/common"
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/types"
 )
 
@@ -27,19 +30,32 @@ func (m *Model) getCmdToCreateOrUpdateTask() tea.Cmd {
 		return nil
 	}
 
+	tags, err := types.ParseTags([]string{m.taskInputs[tagsField].Value()})
+	if err != nil {
+		m.message = errMsg(err.Error())
+		return nil
+	}
+
 	var cmd tea.Cmd
 	switch m.taskMgmtContext {
 	case taskCreateCxt:
-		cmd = createTask(m.db, m.taskInputs[summaryField].Value())
-		m.taskInputs[summaryField].SetValue("")
+		// tasks added while filtering by a project are added to it
+		projectID := m.projectFilterID
+		if projectID == 0 {
+			projectID = pers.UnassignedProjectID
+		}
+		cmd = createTask(m.db, m.taskInputs[summaryField].Value(), projectID, tags)
 	case taskUpdateCxt:
 		selectedTask, ok := m.activeTasksList.SelectedItem().(*taskListItem)
 		if !ok {
 			m.message = errMsg("Something went wrong")
 			return nil
 		}
-		cmd = updateTask(m.db, selectedTask, m.taskInputs[summaryField].Value())
-		m.taskInputs[summaryField].SetValue("")
+		cmd = updateTask(m.db, selectedTask, m.taskInputs[summaryField].Value(), tags)
+	}
+
+	for i := range m.taskInputs {
+		m.taskInputs[i].SetValue("")
 	}
 
 	m.activeView = taskListView
@@ -132,7 +148,7 @@ func (m *Model) getCmdToCreateOrEditTL() tea.Cmd {
 			m.message = errMsg(genericErrorMsg)
 			return nil
 		}
-		cmd = insertManualTL(m.db, task.ID, beginTS, endTS, comment)
+		cmd = insertManualTL(m.db, task.ID, beginTS, endTS, comment, m.rejectOverlaps)
 	case tasklogUpdate:
 		m.activeView = taskLogView
 		tl, ok := m.taskLogList.SelectedItem().(taskLogListItem)
@@ -140,7 +156,7 @@ func (m *Model) getCmdToCreateOrEditTL() tea.Cmd {
 			m.message = errMsg(genericErrorMsg)
 			return nil
 		}
-		cmd = editSavedTL(m.db, tl.ID, tl.TaskID, beginTS, endTS, comment)
+		cmd = editSavedTL(m.db, tl.ID, tl.TaskID, beginTS, endTS, comment, m.rejectOverlaps)
 	}
 
 	return cmd
@@ -163,7 +179,7 @@ func (m *Model) handleEscapeInForms() {
 		if m.tasklogSaveType == tasklogInsert {
 			m.activeView = taskListView
 		}
-	case editSavedTLView:
+	case editSavedTLView, splitSavedTLView:
 		m.activeView = taskLogView
 	}
 }
@@ -187,6 +203,8 @@ func (m *Model) goForwardInView() {
 			m.tLInputs[entryBeginTS].Focus()
 			m.tLCommentInput.Blur()
 		}
+	case taskInputView:
+		m.switchTaskInputFocus()
 	case finishActiveTLView, manualTasklogEntryView, editSavedTLView:
 		switch m.trackingFocussedField {
 		case entryBeginTS:
@@ -205,8 +223,22 @@ func (m *Model) goForwardInView() {
 	}
 }
 
+// switchTaskInputFocus moves focus to the other field of the task form; since
+// it only has two fields, going forward and backward are the same.
+func (m *Model) switchTaskInputFocus() {
+	m.taskInputs[m.taskInputFocussedField].Blur()
+	if m.taskInputFocussedField == summaryField {
+		m.taskInputFocussedField = tagsField
+	} else {
+		m.taskInputFocussedField = summaryField
+	}
+	m.taskInputs[m.taskInputFocussedField].Focus()
+}
+
 func (m *Model) goBackwardInView() {
 	switch m.activeView {
+	case taskInputView:
+		m.switchTaskInputFocus()
 	case taskLogView:
 		m.activeView = taskListView
 	case taskListView:
@@ -265,6 +297,10 @@ func (m *Model) handleRequestToGoBackOrQuit() bool {
 		fs := m.activeTasksList.FilterState()
 		if fs == list.Filtering || fs == list.FilterApplied {
 			m.activeTasksList.ResetFilter()
+		} else if m.tLToMove != nil {
+			m.cancelMovingTL()
+		} else if m.tLToSplit != nil {
+			m.cancelSplittingTL()
 		} else {
 			shouldQuit = true
 		}
@@ -320,6 +356,10 @@ func (m *Model) goToActiveTask() {
 	if m.activeTasksList.IsFiltered() {
 		m.activeTasksList.ResetFilter()
 	}
+	if _, ok := m.taskIndexMap[m.activeTaskID]; !ok && m.projectFilterID != 0 {
+		m.projectFilterID = 0
+		m.setActiveTaskListItems()
+	}
 	activeIndex, ok := m.taskIndexMap[m.activeTaskID]
 	if !ok {
 		m.message = errMsg(genericErrorMsg)
@@ -416,7 +456,7 @@ func (m *Model) getCmdToDeleteTL() tea.Cmd {
 		m.message = errMsg("Couldn't delete task log entry")
 		return nil
 	}
-	return deleteTL(m.db, &entry.TaskLogEntry)
+	return deleteTL(m.db, &entry.TaskLogEntry, m.timeProvider.Now())
 }
 
 func (m *Model) getCmdToActivateDeactivatedTask() tea.Cmd {
@@ -500,6 +540,7 @@ func (m *Model) handleRequestToCreateTask() {
 	m.activeView = taskInputView
 	m.taskInputFocussedField = summaryField
 	m.taskInputs[summaryField].Focus()
+	m.taskInputs[tagsField].Blur()
 	m.taskMgmtContext = taskCreateCxt
 }
 
@@ -519,6 +560,8 @@ func (m *Model) handleRequestToUpdateTask() {
 	m.taskInputFocussedField = summaryField
 	m.taskInputs[summaryField].Focus()
 	m.taskInputs[summaryField].SetValue(task.Summary)
+	m.taskInputs[tagsField].Blur()
+	m.taskInputs[tagsField].SetValue(strings.Join(task.Tags, ", "))
 	m.taskMgmtContext = taskUpdateCxt
 }
 
@@ -657,18 +700,15 @@ func (m *Model) handleTasksFetchedMsg(msg tasksFetchedMsg) tea.Cmd {
 	switch msg.active {
 	case true:
 		m.taskMap = make(map[int]*taskListItem)
-		m.taskIndexMap = make(map[int]int)
-		tasks := make([]list.Item, len(msg.tasks))
+		m.activeTasks = make([]*taskListItem, len(msg.tasks))
 		for i, task := range msg.tasks {
 			item := &taskListItem{Task: task}
 			item.updateListTitle()
 			item.updateListDesc(m.timeProvider)
-			tasks[i] = item
+			m.activeTasks[i] = item
 			m.taskMap[item.ID] = item
-			m.taskIndexMap[item.ID] = i
 		}
-		m.activeTasksList.SetItems(tasks)
-		m.activeTasksList.Title = "Tasks"
+		m.setActiveTaskListItems()
 		m.tasksFetched = true
 		cmd = fetchActiveTask(m.db)
 
@@ -686,6 +726,67 @@ func (m *Model) handleTasksFetchedMsg(msg tasksFetchedMsg) tea.Cmd {
 	return cmd
 }
 
+// setActiveTaskListItems shows the active tasks that belong to the project
+// being filtered by (or all of them, if there's no filter).
+func (m *Model) setActiveTaskListItems() {
+	m.taskIndexMap = make(map[int]int)
+	var items []list.Item
+	for _, task := range m.activeTasks {
+		if m.projectFilterID != 0 && task.ProjectID != m.projectFilterID {
+			continue
+		}
+		m.taskIndexMap[task.ID] = len(items)
+		items = append(items, task)
+	}
+	m.activeTasksList.SetItems(items)
+
+	m.activeTasksList.Title = "Tasks"
+	if m.projectFilterID != 0 {
+		for _, task := range m.activeTasks {
+			if task.ProjectID == m.projectFilterID {
+				m.activeTasksList.Title = fmt.Sprintf("Tasks (project: %s)", task.Project)
+				break
+			}
+		}
+	}
+}
+
+// handleRequestToFilterByProject cycles through the projects of active tasks,
+// and then back to showing all of them.
+func (m *Model) handleRequestToFilterByProject() {
+	if m.activeTasksList.IsFiltered() {
+		m.message = errMsg(removeFilterMsg)
+		return
+	}
+
+	var projects []taskListItem
+	seen := make(map[int]bool)
+	for _, task := range m.activeTasks {
+		if !seen[task.ProjectID] {
+			seen[task.ProjectID] = true
+			projects = append(projects, *task)
+		}
+	}
+	slices.SortFunc(projects, func(a, b taskListItem) int {
+		return strings.Compare(a.Project, b.Project)
+	})
+
+	nextProjectID := 0
+	if m.projectFilterID == 0 && len(projects) > 0 {
+		nextProjectID = projects[0].ProjectID
+	}
+	for i, project := range projects {
+		if project.ProjectID == m.projectFilterID && i+1 < len(projects) {
+			nextProjectID = projects[i+1].ProjectID
+			break
+		}
+	}
+
+	m.projectFilterID = nextProjectID
+	m.setActiveTaskListItems()
+	m.activeTasksList.Select(0)
+}
+
 func (m *Model) handleManualTLInsertedMsg(msg manualTLInsertedMsg) []tea.Cmd {
 	if msg.err != nil {
 		m.message = errMsg(msg.err.Error())
@@ -702,11 +803,13 @@ func (m *Model) handleManualTLInsertedMsg(msg manualTLInsertedMsg) []tea.Cmd {
 }
 
 func (m *Model) handleSavedTLEditedMsg(msg savedTLEditedMsg) []tea.Cmd {
-	if msg.err != nil {
+	if m.changeFailed(msg.err) {
 		m.message = errMsg(msg.err.Error())
 		return nil
 	}
 
+	m.pushUndoableChange(msg.change)
+
 	var cmds []tea.Cmd
 	if _, ok := m.taskMap[msg.taskID]; ok {
 		cmds = append(cmds, fetchTaskTrackingData(m.db, msg.taskID))
@@ -759,6 +862,7 @@ func (m *Model) handleActiveTaskFetchedMsg(msg activeTaskFetchedMsg) {
 	m.activeTaskID = msg.activeTask.TaskID
 	m.activeTLBeginTS = msg.activeTask.CurrentLogBeginTS
 	m.activeTLComment = msg.activeTask.CurrentLogComment
+	m.activeTLPauses = msg.activeTask.CurrentLogPauses
 
 	activeTask, ok := m.taskMap[m.activeTaskID]
 	if ok {
@@ -778,6 +882,7 @@ func (m *Model) handleTrackingToggledMsg(msg trackingToggledMsg) []tea.Cmd {
 	if msg.err != nil {
 		m.message = errMsg(msg.err.Error())
 		m.trackingActive = false
+		m.pomodoro = pomodoroState{tickID: m.pomodoro.tickID}
 		return nil
 	}
 
@@ -796,15 +901,22 @@ func (m *Model) handleTrackingToggledMsg(msg trackingToggledMsg) []tea.Cmd {
 		m.lastTrackingChange = trackingFinished
 		task.trackingActive = false
 		m.activeTLComment = nil
+		m.activeTLPauses = nil
 		m.trackingActive = false
 		m.activeTaskID = -1
 		cmds = append(cmds, fetchTaskTrackingData(m.db, msg.taskID))
 		cmds = append(cmds, fetchTLS(m.db, nil))
+		if pomodoroCmd := m.handlePomodoroTrackingFinished(m.activeTLEndTS); pomodoroCmd != nil {
+			cmds = append(cmds, pomodoroCmd)
+		}
 	case false:
 		m.lastTrackingChange = trackingStarted
 		task.trackingActive = true
 		m.trackingActive = true
 		m.activeTaskID = msg.taskID
+		if m.pomodoro.phase == pomodoroBreakOver {
+			m.pomodoro.phase = pomodoroOff
+		}
 	}
 
 	task.updateListTitle()
@@ -838,9 +950,16 @@ func (m *Model) handleActiveTLSwitchedMsg(msg activeTLSwitchedMsg) tea.Cmd {
 	currentlyActiveTask.updateListTitle()
 
 	m.activeTLComment = nil
+	m.activeTLPauses = nil
 	m.activeTaskID = msg.currentlyActiveTaskID
 	m.activeTLBeginTS = msg.ts
 
+	// switching away from a recorded break ends it early, the same way
+	// stopping tracking does
+	if m.pomodoro.breakRecorded {
+		m.pomodoro = pomodoroState{tickID: m.pomodoro.tickID}
+	}
+
 	return tea.Batch(
 		fetchTaskTrackingData(m.db, msg.lastActiveTaskID),
 		fetchTLS(m.db, nil),
@@ -848,11 +967,13 @@ func (m *Model) handleActiveTLSwitchedMsg(msg activeTLSwitchedMsg) tea.Cmd {
 }
 
 func (m *Model) handleTLDeleted(msg tLDeletedMsg) []tea.Cmd {
-	if msg.err != nil {
+	if m.changeFailed(msg.err) {
 		m.message = errMsg("Error deleting entry: " + msg.err.Error())
 		return nil
 	}
 
+	m.pushUndoableChange(msg.change)
+
 	var cmds []tea.Cmd
 	if _, ok := m.taskMap[msg.entry.TaskID]; ok {
 		cmds = append(cmds, fetchTaskTrackingData(m.db, msg.entry.TaskID))
@@ -863,11 +984,13 @@ func (m *Model) handleTLDeleted(msg tLDeletedMsg) []tea.Cmd {
 }
 
 func (m *Model) handleActiveTLDeletedMsg(msg activeTaskLogDeletedMsg) {
-	if msg.err != nil {
+	if m.changeFailed(msg.err) {
 		m.message = errMsg(fmt.Sprintf("Error deleting active log entry: %s", msg.err))
 		return
 	}
 
+	m.pushUndoableChange(msg.change)
+
 	activeTask, ok := m.taskMap[m.activeTaskID]
 	if !ok {
 		m.message = errMsg(genericErrorMsg)
@@ -879,7 +1002,39 @@ func (m *Model) handleActiveTLDeletedMsg(msg activeTaskLogDeletedMsg) {
 	m.lastTrackingChange = trackingFinished
 	m.trackingActive = false
 	m.activeTLComment = nil
+	m.activeTLPauses = nil
 	m.activeTaskID = -1
+	m.pomodoro = pomodoroState{tickID: m.pomodoro.tickID}
+}
+
+func (m *Model) getCmdToToggleActiveTLPause() tea.Cmd {
+	if !m.trackingActive {
+		m.message = errMsg("Nothing is being tracked right now")
+		return nil
+	}
+
+	now := m.timeProvider.Now().Truncate(time.Second)
+	paused := domain.PausedAt(m.activeTLPauses) != nil
+
+	return toggleActiveTLPause(m.db, now, !paused)
+}
+
+func (m *Model) handleActiveTLPauseToggledMsg(msg activeTLPauseToggledMsg) {
+	if msg.err != nil {
+		m.message = errMsg(msg.err.Error())
+		return
+	}
+
+	if msg.paused {
+		m.activeTLPauses = append(m.activeTLPauses, domain.TaskLogPause{BeginTS: msg.ts})
+		return
+	}
+
+	for i := range m.activeTLPauses {
+		if m.activeTLPauses[i].EndTS == nil {
+			m.activeTLPauses[i].EndTS = &msg.ts
+		}
+	}
 }
 
 func (m *Model) clearAllTaskLogInputs() {
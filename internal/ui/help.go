@@ -52,6 +52,9 @@ func getHelpText(style Style) string {
   h<Left>                                 Go to previous page
   l<Right>                                Go to next page
   <ctrl+r>                                Refresh list
+  <ctrl+z>                                Undo the last deletion/edit/discard/status
+                                              change
+  <ctrl+y>                                Redo the last undone change
 `),
 		style.helpPrimary.Render("Task List View"),
 		style.helpSecondary.Render(`
@@ -69,6 +72,14 @@ func getHelpText(style Style) string {
   <ctrl+x>                                Discard currently active recording
   <ctrl+t>                                Go to currently tracked item
   <ctrl+d>                                Deactivate task
+  <ctrl+p>                                Pause/resume the currently active task log
+  p                                       Filter tasks by project; cycles through
+                                              projects, and then back to all tasks
+  P                                       Start a timebox for the currently active task
+                                              (or start tracking the selected task in one);
+                                              cancel the timebox if one is running
+  c                                       Continue with another timebox once the
+                                              current one is over
 `),
 		style.helpPrimary.Render("Task Logs List View"),
 		style.helpSecondary.Render(`
@@ -77,7 +88,12 @@ func getHelpText(style Style) string {
 
   d                                       Show task log details
   <ctrl+s>/u                              Update task log entry
-  <ctrl+d>                                Delete task log entry
+  <ctrl+d>                                Move task log entry to the trash
+  s                                       Split task log entry in two (the second part can be for another task)
+  M                                       Merge task log entry with the one before it
+  m                                       Move task log entry to another task; pick
+                                              the task in the Tasks List View, and
+                                              press enter
 `),
 		style.helpPrimary.Render("Task Log Details View"),
 		style.helpSecondary.Render(`
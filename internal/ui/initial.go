@@ -19,6 +19,8 @@ const (
 func InitialModel(db *sql.DB,
 	style Style,
 	timeProvider types.TimeProvider,
+	pomodoroCfg PomodoroConfig,
+	rejectOverlaps bool,
 	debug bool,
 	logFramesCfg logFramesConfig,
 ) Model {
@@ -47,22 +49,28 @@ This can be used to record details about your work on this task.`
 	tLCommentInput.ShowLineNumbers = false
 	tLCommentInput.Prompt = "  ┃ "
 
-	taskInputs := make([]textinput.Model, 1)
+	taskInputs := make([]textinput.Model, 2)
 	taskInputs[summaryField] = textinput.New()
 	taskInputs[summaryField].Placeholder = "task summary goes here"
 	taskInputs[summaryField].Focus()
 	taskInputs[summaryField].CharLimit = 100
 	taskInputs[summaryField].SetWidth(textInputWidth)
 
+	taskInputs[tagsField] = textinput.New()
+	taskInputs[tagsField].Placeholder = "tags (comma separated, optional)"
+	taskInputs[tagsField].CharLimit = 200
+	taskInputs[tagsField].SetWidth(textInputWidth)
+
 	m := Model{
 		db:           db,
 		style:        style,
 		timeProvider: timeProvider,
 		activeTasksList: list.New(activeTaskItems,
-			newItemDelegate(
+			newBudgetDelegate(
 				style.listItemTitleColor,
 				style.listItemDescColor,
 				lipgloss.Color(style.theme.ActiveTasks),
+				lipgloss.Color(style.theme.TaskLogFormError),
 			), listWidth, 0),
 		inactiveTasksList: list.New(inactiveTaskItems,
 			newItemDelegate(
@@ -82,6 +90,8 @@ This can be used to record details about your work on this task.`
 		tLInputs:          tLInputs,
 		tLCommentInput:    tLCommentInput,
 		taskInputs:        taskInputs,
+		pomodoroCfg:       pomodoroCfg,
+		rejectOverlaps:    rejectOverlaps,
 		debug:             debug,
 		logFramesCfg:      logFramesCfg,
 	}
@@ -129,7 +139,8 @@ func initialRecordsModel(
 	timeProvider types.TimeProvider,
 	dateRange types.DateRange,
 	period string,
-	taskStatus types.TaskStatus,
+	filter types.TaskFilter,
+	groupBy types.GroupBy,
 	plain bool,
 	initialData string,
 ) recordsModel {
@@ -140,7 +151,8 @@ func initialRecordsModel(
 		timeProvider: timeProvider,
 		dateRange:    dateRange,
 		period:       period,
-		taskStatus:   taskStatus,
+		filter:       filter,
+		groupBy:      groupBy,
 		plain:        plain,
 		report:       initialData,
 	}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+var (
+	errInvoiceOutputFormatNotApplicable = errors.New("invoices can only be output as markdown, HTML, or JSON")
+	errCouldntBuildInvoice              = errors.New("couldn't build invoice")
+)
+
+// invoiceJSON adds the period an invoice is for to its JSON representation.
+type invoiceJSON struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	domain.Invoice
+}
+
+func RenderInvoice(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	outputFormat types.OutputFormat,
+	dateRange types.DateRange,
+	client string,
+	roundTo time.Duration,
+	roundingMode types.RoundingMode,
+) error {
+	if outputFormat == types.OutputFormatTable {
+		return errInvoiceOutputFormatNotApplicable
+	}
+
+	invoice, err := getInvoice(db, dateRange, client, roundTo, roundingMode)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCouldntBuildInvoice, err)
+	}
+
+	var output string
+	switch outputFormat {
+	case types.OutputFormatJSON:
+		output, err = marshalJSON(invoiceJSON{
+			From:    dateRange.Start.Format(time.DateOnly),
+			To:      dateRange.End.AddDate(0, 0, -1).Format(time.DateOnly),
+			Invoice: invoice,
+		})
+	case types.OutputFormatHTML:
+		output, err = getInvoiceDoc(invoice, dateRange).html(style)
+	default:
+		doc := getInvoiceDoc(invoice, dateRange)
+		output = fmt.Sprintf("## %s\n\n%s", doc.title, doc.markdown())
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(writer, output)
+	return nil
+}
+
+func getInvoice(db *sql.DB,
+	dateRange types.DateRange,
+	client string,
+	roundTo time.Duration,
+	roundingMode types.RoundingMode,
+) (domain.Invoice, error) {
+	var zero domain.Invoice
+
+	entries, err := pers.FetchStatsBetweenTS(db, dateRange.Start, dateRange.End, types.TaskFilter{Status: types.TaskStatusAny}, statsLogEntriesLimit)
+	if err != nil {
+		return zero, err
+	}
+
+	tasks, err := pers.FetchTasksWithStatus(db, types.TaskStatusAny, tasksLimit)
+	if err != nil {
+		return zero, err
+	}
+
+	projects, err := pers.FetchProjects(db)
+	if err != nil {
+		return zero, err
+	}
+
+	tasksByID := make(map[int]domain.Task, len(tasks))
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+	}
+
+	projectsByID := make(map[int]domain.Project, len(projects))
+	for _, project := range projects {
+		projectsByID[project.ID] = project
+	}
+
+	return domain.BuildInvoice(client, entries, tasksByID, projectsByID, roundTo, roundingMode)
+}
+
+// formatHours formats seconds as decimal hours (eg. 5400 as "1.50"), which is
+// how time is usually shown on invoices.
+func formatHours(secs int) string {
+	return domain.FormatHundredths((secs*100 + 1800) / 3600)
+}
+
+func formatAmount(hundredths int, currency string) string {
+	return fmt.Sprintf("%s %s", domain.FormatHundredths(hundredths), currency)
+}
+
+func getInvoiceDoc(invoice domain.Invoice, dateRange types.DateRange) recordsDoc {
+	doc := recordsDoc{
+		title:   getRecordsTitle(fmt.Sprintf("Invoice for %s", invoice.Client), &dateRange),
+		headers: []string{"Project", "Task", "Hours", "Rate", "Amount"},
+	}
+
+	for _, item := range invoice.LineItems {
+		doc.rows = append(doc.rows, []recordsCell{
+			{text: item.Project},
+			{text: item.TaskSummary, task: item.TaskSummary},
+			{text: formatHours(item.SecsBilled)},
+			{text: item.Rate.String()},
+			{text: formatAmount(item.Amount, item.Rate.Currency)},
+		})
+	}
+
+	var secsBilled int
+	amounts := make([]string, len(invoice.Totals))
+	for i, total := range invoice.Totals {
+		secsBilled += total.SecsBilled
+		amounts[i] = formatAmount(total.Amount, total.Currency)
+	}
+
+	doc.footer = []string{"Total", "", formatHours(secsBilled), "", strings.Join(amounts, "; ")}
+
+	return doc
+}
@@ -0,0 +1,18 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var errCouldntMarshalJSON = errors.New("couldn't marshal JSON")
+
+func marshalJSON(value any) (string, error) {
+	result, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntMarshalJSON, err.Error())
+	}
+
+	return string(result) + "\n", nil
+}
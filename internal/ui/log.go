@@ -10,6 +10,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/dhth/hours/internal/domain"
 	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/types"
 	"github.com/dhth/hours/internal/utils"
@@ -30,16 +31,39 @@ func RenderTaskLog(db *sql.DB,
 	style Style,
 	writer io.Writer,
 	plain bool,
+	outputFormat types.OutputFormat,
 	dateRange types.DateRange,
 	period string,
-	taskStatus types.TaskStatus,
+	filter types.TaskFilter,
 	interactive bool,
+	withComments bool,
 ) error {
+	if interactive && outputFormat != types.OutputFormatTable {
+		return fmt.Errorf("%w with %s output", errInteractiveModeNotApplicable, outputFormat)
+	}
+
+	if withComments && outputFormat != types.OutputFormatHTML {
+		return errCommentsNotApplicable
+	}
+
 	if interactive && dateRange.NumDays > interactiveLogDayLimit {
 		return fmt.Errorf("%w (limited to %d day); use non-interactive mode to see logs for a larger time period", errInteractiveModeNotApplicable, interactiveLogDayLimit)
 	}
 
-	log, err := getTaskLog(db, style, dateRange.Start, dateRange.End, taskStatus, logLimit, plain)
+	var log string
+	var err error
+	switch outputFormat {
+	case types.OutputFormatJSON:
+		log, err = getTaskLogJSON(db, dateRange.Start, dateRange.End, filter, logLimit)
+	case types.OutputFormatMarkdown, types.OutputFormatHTML:
+		var doc recordsDoc
+		doc, err = getTaskLogDoc(db, dateRange, filter, logLimit, withComments)
+		if err == nil {
+			log, err = doc.render(style, outputFormat)
+		}
+	default:
+		log, err = getTaskLog(db, style, dateRange.Start, dateRange.End, filter, logLimit, plain)
+	}
 	if err != nil {
 		return fmt.Errorf("%w: %s", errCouldntGenerateLogs, err.Error())
 	}
@@ -52,7 +76,8 @@ func RenderTaskLog(db *sql.DB,
 			types.RealTimeProvider{},
 			dateRange,
 			period,
-			taskStatus,
+			filter,
+			types.GroupByTask,
 			plain,
 			log,
 		))
@@ -66,16 +91,68 @@ func RenderTaskLog(db *sql.DB,
 	return nil
 }
 
+func getTaskLogJSON(db *sql.DB, start, end time.Time, filter types.TaskFilter, limit int) (string, error) {
+	entries, err := pers.FetchTLEntriesBetweenTS(db, start, end, filter, limit)
+	if err != nil {
+		return "", err
+	}
+
+	if entries == nil {
+		entries = []domain.TaskLogEntry{}
+	}
+
+	return marshalJSON(entries)
+}
+
+func getTaskLogDoc(db *sql.DB,
+	dateRange types.DateRange,
+	filter types.TaskFilter,
+	limit int,
+	withComments bool,
+) (recordsDoc, error) {
+	doc := recordsDoc{
+		title:   getRecordsTitle("Log", &dateRange),
+		headers: []string{"ID", "Task", "Comment", "Duration", "TimeSpent"},
+	}
+
+	entries, err := pers.FetchTLEntriesBetweenTS(db, dateRange.Start, dateRange.End, filter, limit)
+	if err != nil {
+		return doc, err
+	}
+
+	for _, entry := range entries {
+		var comment string
+		if entry.Comment != nil {
+			comment = *entry.Comment
+		}
+
+		doc.rows = append(doc.rows, []recordsCell{
+			{text: fmt.Sprintf("%d", entry.ID), task: entry.TaskSummary},
+			{text: entry.TaskSummary, task: entry.TaskSummary},
+			{text: comment, task: entry.TaskSummary},
+			{text: fmt.Sprintf("%s ... %s", entry.BeginTS.Format(timeFormat), entry.EndTS.Format(timeFormat)), task: entry.TaskSummary},
+			{text: types.HumanizeDuration(entry.SecsSpent), task: entry.TaskSummary},
+		})
+
+		if withComments && comment != "" {
+			doc.comments = append(doc.comments, entry)
+		}
+	}
+	doc.withComments = withComments
+
+	return doc, nil
+}
+
 func getTaskLog(db *sql.DB,
 	style Style,
 	start,
 	end time.Time,
-	taskStatus types.TaskStatus,
+	filter types.TaskFilter,
 	limit int,
 	plain bool) (string,
 	error,
 ) {
-	entries, err := pers.FetchTLEntriesBetweenTS(db, start, end, taskStatus, limit)
+	entries, err := pers.FetchTLEntriesBetweenTS(db, start, end, filter, limit)
 	if err != nil {
 		return "", err
 	}
@@ -92,6 +169,7 @@ func getTaskLog(db *sql.DB,
 
 	if len(entries) == 0 {
 		data[0] = []string{
+			"",
 			utils.RightPadTrim("", 20, false),
 			utils.RightPadTrim("", 40, false),
 			utils.RightPadTrim("", 39, false),
@@ -109,6 +187,7 @@ func getTaskLog(db *sql.DB,
 
 		if plain {
 			data[i] = []string{
+				fmt.Sprintf("%d", entry.ID),
 				utils.RightPadTrim(entry.TaskSummary, 20, false),
 				utils.RightPadTrimWithMoreLinesIndicator(taskLogComment(entry.Comment), 40),
 				fmt.Sprintf("%s  ...  %s", entry.BeginTS.Format(timeFormat), entry.EndTS.Format(timeFormat)),
@@ -121,6 +200,7 @@ func getTaskLog(db *sql.DB,
 				styleCache[entry.TaskSummary] = rowStyle
 			}
 			data[i] = []string{
+				rowStyle.Render(fmt.Sprintf("%d", entry.ID)),
 				rowStyle.Render(utils.RightPadTrim(entry.TaskSummary, 20, false)),
 				rowStyle.Render(utils.RightPadTrimWithMoreLinesIndicator(taskLogComment(entry.Comment), 40)),
 				rowStyle.Render(fmt.Sprintf("%s  ...  %s", entry.BeginTS.Format(timeFormat), entry.EndTS.Format(timeFormat))),
@@ -129,7 +209,7 @@ func getTaskLog(db *sql.DB,
 		}
 	}
 
-	headerValues := []string{"Task", "Comment", "Duration", "TimeSpent"}
+	headerValues := []string{"ID", "Task", "Comment", "Duration", "TimeSpent"}
 	headers := make([]string, len(headerValues))
 	for i, h := range headerValues {
 		headers[i] = rs.headerStyle.Render(h)
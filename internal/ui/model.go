@@ -31,6 +31,7 @@ const (
 	finishActiveTLView                          // Form to finish active task log
 	manualTasklogEntryView                      // Form to manually create a new task log entry
 	editSavedTLView                             // Form to edit an existing task log
+	splitSavedTLView                            // Form to split an existing task log in two
 	taskInputView                               // Form to create or edit task details
 	helpView                                    // Help documentation view
 	insufficientDimensionsView                  // Error view when terminal is too small
@@ -47,6 +48,7 @@ type taskInputField uint
 
 const (
 	summaryField taskInputField = iota
+	tagsField
 )
 
 type tLTrackingFormField uint
@@ -64,6 +66,7 @@ type recordsKind uint
 const (
 	reportRecords recordsKind = iota
 	reportAggRecords
+	reportGroupedRecords
 	reportLogs
 	reportStats
 )
@@ -110,34 +113,50 @@ type Model struct {
 	inactiveTasksList              list.Model
 	taskMap                        map[int]*taskListItem
 	taskIndexMap                   map[int]int
-	activeTLBeginTS                time.Time
-	activeTLEndTS                  time.Time
-	activeTLComment                *string
-	tasksFetched                   bool
-	taskLogList                    list.Model
-	tLInputs                       []textinput.Model
-	trackingFocussedField          tLTrackingFormField
-	tLCommentInput                 textarea.Model
-	taskInputs                     []textinput.Model
-	taskMgmtContext                taskMgmtContext
-	taskInputFocussedField         taskInputField
-	helpVP                         viewport.Model
-	helpVPReady                    bool
-	tLDetailsVP                    viewport.Model
-	tLDetailsVPReady               bool
-	lastTrackingChange             trackingChange
-	changesLocked                  bool
-	activeTaskID                   int
-	tasklogSaveType                tasklogSaveType
-	message                        userMsg
-	showHelpIndicator              bool
-	terminalWidth                  int
-	terminalHeight                 int
-	secsTrackedToday               int
-	trackingActive                 bool
-	debug                          bool
-	frameCounter                   uint
-	logFramesCfg                   logFramesConfig
+	// activeTasks holds all active tasks, including the ones hidden by the
+	// project filter
+	activeTasks            []*taskListItem
+	projectFilterID        int
+	activeTLBeginTS        time.Time
+	activeTLEndTS          time.Time
+	activeTLComment        *string
+	activeTLPauses         []domain.TaskLogPause
+	tasksFetched           bool
+	taskLogList            list.Model
+	tLInputs               []textinput.Model
+	trackingFocussedField  tLTrackingFormField
+	tLCommentInput         textarea.Model
+	taskInputs             []textinput.Model
+	taskMgmtContext        taskMgmtContext
+	taskInputFocussedField taskInputField
+	helpVP                 viewport.Model
+	helpVPReady            bool
+	tLDetailsVP            viewport.Model
+	tLDetailsVPReady       bool
+	lastTrackingChange     trackingChange
+	changesLocked          bool
+	activeTaskID           int
+	tasklogSaveType        tasklogSaveType
+	message                userMsg
+	showHelpIndicator      bool
+	terminalWidth          int
+	terminalHeight         int
+	secsTrackedToday       int
+	goals                  domain.Goals
+	weekTaskLogs           []domain.TaskLogEntry
+	secsTrackedThisWeek    int
+	pomodoroCfg            PomodoroConfig
+	rejectOverlaps         bool
+	tLOverlaps             tlOverlapCheck
+	tLToMove               *domain.TaskLogEntry
+	tLToSplit              *tlSplit
+	undoStack              []undoableChange
+	redoStack              []undoableChange
+	pomodoro               pomodoroState
+	trackingActive         bool
+	debug                  bool
+	frameCounter           uint
+	logFramesCfg           logFramesConfig
 }
 
 func (m *Model) blurTLTrackingInputs() {
@@ -154,6 +173,7 @@ func (m Model) Init() tea.Cmd {
 		fetchTasks(m.db, true),
 		fetchTLS(m.db, nil),
 		fetchTasks(m.db, false),
+		fetchGoalProgress(m.db, m.timeProvider.Now()),
 	)
 }
 
@@ -172,10 +192,29 @@ func (m *Model) recalculateSecondsTrackedToday() {
 		activeTaskLogBeginTS = &m.activeTLBeginTS
 	}
 
+	now := m.timeProvider.Now()
 	m.secsTrackedToday = domain.SecondsTrackedToday(
 		finishedTaskLogs,
 		activeTaskLogBeginTS,
-		m.timeProvider.Now(),
+		m.activeTLPauses,
+		now,
+	)
+
+	if m.goals.WeeklySecs == nil {
+		return
+	}
+
+	week, err := types.GetDateRangeFromPeriod(types.TimePeriodWeek, now, false, nil)
+	if err != nil {
+		return
+	}
+
+	m.secsTrackedThisWeek = domain.SecondsTrackedSince(
+		m.weekTaskLogs,
+		activeTaskLogBeginTS,
+		m.activeTLPauses,
+		week.Start,
+		now,
 	)
 }
 
@@ -187,7 +226,8 @@ type recordsModel struct {
 	dateRange    types.DateRange
 	period       string
 	plain        bool
-	taskStatus   types.TaskStatus
+	filter       types.TaskFilter
+	groupBy      types.GroupBy
 	report       string
 	quitting     bool
 	busy         bool
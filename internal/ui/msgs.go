@@ -41,17 +41,55 @@ type manualTLInsertedMsg struct {
 type savedTLEditedMsg struct {
 	tlID   int
 	taskID int
+	change *undoableChange
 	err    error
 }
 
 type activeTLUpdatedMsg struct {
 	beginTS time.Time
 	comment *string
+	change  *undoableChange
 	err     error
 }
 
+type tLOverlapsFetchedMsg struct {
+	check   tlOverlapCheck
+	entries []domain.TaskLogEntry
+	err     error
+}
+
+type tLSplitMsg struct {
+	entry   domain.TaskLogEntry
+	taskID  int
+	newTLID int
+	change  *undoableChange
+	err     error
+}
+
+type tLsMergedMsg struct {
+	taskID   int
+	mergedID int
+	gap      time.Duration
+	change   *undoableChange
+	err      error
+}
+
+type tLMovedMsg struct {
+	entry  domain.TaskLogEntry
+	taskID int
+	change *undoableChange
+	err    error
+}
+
+type activeTLPauseToggledMsg struct {
+	ts     time.Time
+	paused bool
+	err    error
+}
+
 type activeTaskLogDeletedMsg struct {
-	err error
+	change *undoableChange
+	err    error
 }
 
 type activeTaskFetchedMsg struct {
@@ -66,6 +104,12 @@ type tLsFetchedMsg struct {
 	err           error
 }
 
+type goalProgressFetchedMsg struct {
+	goals       domain.Goals
+	weekEntries []domain.TaskLogEntry
+	err         error
+}
+
 type taskCreatedMsg struct {
 	err error
 }
@@ -73,18 +117,21 @@ type taskCreatedMsg struct {
 type taskUpdatedMsg struct {
 	tsk     *taskListItem
 	summary string
+	tags    []string
 	err     error
 }
 
 type taskActiveStatusUpdatedMsg struct {
 	tsk    *taskListItem
 	active bool
+	change *undoableChange
 	err    error
 }
 
 type tLDeletedMsg struct {
-	entry *domain.TaskLogEntry
-	err   error
+	entry  *domain.TaskLogEntry
+	change *undoableChange
+	err    error
 }
 
 type tasksFetchedMsg struct {
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+var errCouldntFindOverlaps = errors.New("couldn't find overlapping task log entries")
+
+const (
+	noOverlapsMsg = "No overlapping task log entries found\n"
+	// the number of overlapping entries listed in the task log entry form
+	overlapsShownInForm = 2
+)
+
+// RenderOverlaps lists the pairs of task log entries in a period that
+// overlap.
+func RenderOverlaps(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	outputFormat types.OutputFormat,
+	dateRange types.DateRange,
+) error {
+	entries, err := pers.FetchTLEntriesOverlapping(db, dateRange.Start, dateRange.End, pers.NoLimit)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFindOverlaps, err.Error())
+	}
+
+	overlaps := domain.FindOverlaps(entries)
+
+	var output string
+	switch {
+	case outputFormat == types.OutputFormatJSON:
+		if overlaps == nil {
+			overlaps = []domain.TaskLogOverlap{}
+		}
+		output, err = marshalJSON(overlaps)
+	case len(overlaps) == 0:
+		output = noOverlapsMsg
+	default:
+		output, err = getOverlapsTable(overlaps, style, plain)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFindOverlaps, err.Error())
+	}
+
+	fmt.Fprint(writer, output)
+	return nil
+}
+
+func getOverlapsTable(overlaps []domain.TaskLogOverlap, style Style, plain bool) (string, error) {
+	rs := style.getReportStyles(plain)
+
+	data := make([][]string, len(overlaps))
+	for i, overlap := range overlaps {
+		row := []string{
+			fmt.Sprintf("%d, %d", overlap.Entry.ID, overlap.OtherEntry.ID),
+			utils.RightPadTrim(overlap.Entry.TaskSummary, 20, false),
+			utils.RightPadTrim(overlap.OtherEntry.TaskSummary, 20, false),
+			fmt.Sprintf("%s  ...  %s", overlap.BeginTS.Format(timeFormat), overlap.EndTS.Format(timeFormat)),
+			types.HumanizeDuration(overlap.Secs),
+		}
+
+		if !plain {
+			for j, value := range row {
+				row[j] = style.tlFormWarnStyle.Render(value)
+			}
+		}
+
+		data[i] = row
+	}
+
+	headerValues := []string{"IDs", "Task", "OtherTask", "OverlapPeriod", "Overlap"}
+	headers := make([]string, len(headerValues))
+	for i, h := range headerValues {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+	b := bytes.Buffer{}
+	table := tablewriter.NewTable(
+		&b,
+		tablewriter.WithConfig(tablewriter.Config{
+			Header: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment:  tw.AlignCenter,
+					AutoWrap:   tw.WrapNone,
+					AutoFormat: tw.Off,
+				},
+			},
+			Row: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment: tw.AlignLeft,
+					AutoWrap:  tw.WrapNone,
+				},
+			},
+		}),
+		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{Symbols: rs.symbols(tw.StyleASCII)})),
+		tablewriter.WithHeader(headers),
+	)
+
+	if err := table.Bulk(data); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntAddDataToTable, err.Error())
+	}
+
+	if err := table.Render(); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntRenderTable, err.Error())
+	}
+
+	return b.String(), nil
+}
+
+// tlOverlapCheck holds the task logs (saved ones, and the active one, unless
+// it's the one being entered) that the task log being entered in the TUI
+// overlaps.
+type tlOverlapCheck struct {
+	beginTS       time.Time
+	endTS         time.Time
+	excludeTLID   int
+	includeActive bool
+	entries       []domain.TaskLogEntry
+}
+
+func (c tlOverlapCheck) isFor(other tlOverlapCheck) bool {
+	return c.beginTS.Equal(other.beginTS) &&
+		c.endTS.Equal(other.endTS) &&
+		c.excludeTLID == other.excludeTLID &&
+		c.includeActive == other.includeActive
+}
+
+// getCmdToCheckTLOverlaps looks up the saved task logs that the task log being
+// entered overlaps, whenever its begin or end timestamp changes.
+func (m *Model) getCmdToCheckTLOverlaps() tea.Cmd {
+	if m.db == nil {
+		return nil
+	}
+
+	check := tlOverlapCheck{excludeTLID: -1, includeActive: true}
+	switch m.activeView {
+	case finishActiveTLView:
+		check.includeActive = false
+	case manualTasklogEntryView:
+	case editSavedTLView:
+		tl, ok := m.taskLogList.SelectedItem().(taskLogListItem)
+		if ok {
+			check.excludeTLID = tl.ID
+		}
+	default:
+		m.tLOverlaps = tlOverlapCheck{}
+		return nil
+	}
+
+	var err error
+	check.beginTS, check.endTS, err = types.ParseTaskLogTimes(m.tLInputs[entryBeginTS].Value(), m.tLInputs[entryEndTS].Value())
+	if err != nil {
+		m.tLOverlaps = tlOverlapCheck{}
+		return nil
+	}
+
+	if m.tLOverlaps.isFor(check) {
+		return nil
+	}
+
+	m.tLOverlaps = check
+	return fetchTLOverlaps(m.db, check)
+}
+
+func (m *Model) handleTLOverlapsFetchedMsg(msg tLOverlapsFetchedMsg) {
+	if msg.err != nil {
+		m.message = errMsg("Error checking for overlapping task logs: " + msg.err.Error())
+		return
+	}
+
+	if m.tLOverlaps.isFor(msg.check) {
+		m.tLOverlaps.entries = msg.entries
+	}
+}
+
+// getOverlapsContext describes the saved task logs that the task log being
+// entered overlaps.
+func getOverlapsContext(entries []domain.TaskLogEntry, rejected bool) string {
+	descs := make([]string, 0, overlapsShownInForm+1)
+	for i, entry := range entries {
+		if i == overlapsShownInForm {
+			descs = append(descs, fmt.Sprintf("%d more", len(entries)-overlapsShownInForm))
+			break
+		}
+
+		if entry.EndTS.IsZero() {
+			descs = append(descs, fmt.Sprintf("%s (being tracked since %s)",
+				utils.Trim(entry.TaskSummary, 20),
+				entry.BeginTS.Format(timeOnlyFormat),
+			))
+			continue
+		}
+
+		descs = append(descs, fmt.Sprintf("%s (%s ... %s)",
+			utils.Trim(entry.TaskSummary, 20),
+			entry.BeginTS.Format(timeOnlyFormat),
+			entry.EndTS.Format(timeOnlyFormat),
+		))
+	}
+
+	ctx := "overlaps " + strings.Join(descs, ", ")
+	if rejected {
+		ctx += "; overlaps are rejected"
+	}
+
+	return ctx
+}
@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/dhth/hours/internal/types"
+)
+
+const (
+	pomodoroTickInterval = time.Second
+	breakTaskSummary     = "break"
+	timeboxOverMsg       = "Timebox over; press f to finish the task log, or c to continue"
+)
+
+// PomodoroConfig determines the length of timeboxes (and the breaks after
+// them) in the TUI.
+type PomodoroConfig struct {
+	TimeboxDuration time.Duration
+	// BreakDuration can be zero, in which case no break follows a timebox
+	BreakDuration time.Duration
+	// RecordBreaks, if set, tracks breaks on a task called "break"
+	RecordBreaks bool
+	Alert        types.TimeboxAlert
+}
+
+type pomodoroPhase uint8
+
+const (
+	pomodoroOff pomodoroPhase = iota
+	pomodoroTimebox
+	pomodoroTimeboxOver
+	pomodoroBreak
+	pomodoroBreakOver
+)
+
+type pomodoroState struct {
+	phase  pomodoroPhase
+	endsAt time.Time
+	// breakRecorded is set if the break is being tracked on the break task
+	breakRecorded bool
+	// breakTaskID is the ID of the break task, once the break has been
+	// recorded on it
+	breakTaskID int
+	// tickID tells apart the ticks of the current timebox (or break) from
+	// those of earlier ones
+	tickID int
+}
+
+type pomodoroTickMsg struct {
+	id int
+}
+
+type breakStartedMsg struct {
+	taskID int
+	err    error
+}
+
+func tickPomodoro(id int) tea.Cmd {
+	return tea.Tick(pomodoroTickInterval, func(time.Time) tea.Msg {
+		return pomodoroTickMsg{id}
+	})
+}
+
+func (m *Model) startPomodoroPhase(phase pomodoroPhase, duration time.Duration) tea.Cmd {
+	m.pomodoro.phase = phase
+	m.pomodoro.endsAt = m.timeProvider.Now().Add(duration)
+	m.pomodoro.tickID++
+
+	return tickPomodoro(m.pomodoro.tickID)
+}
+
+func (m *Model) alertPomodoroPhaseOver(message string) tea.Cmd {
+	switch m.pomodoroCfg.Alert {
+	case types.TimeboxAlertOSC9:
+		return tea.Raw(fmt.Sprintf("\x1b]9;hours: %s\x07", message))
+	default:
+		return tea.Raw("\a")
+	}
+}
+
+// handleRequestToToggleTimebox starts a timebox for the active task (starting
+// to track the selected task first, if needed), or cancels the current one.
+func (m *Model) handleRequestToToggleTimebox() tea.Cmd {
+	switch m.pomodoro.phase {
+	case pomodoroTimebox, pomodoroTimeboxOver, pomodoroBreak:
+		m.pomodoro = pomodoroState{tickID: m.pomodoro.tickID}
+		m.message = infoMsg("Timebox cancelled")
+		return nil
+	}
+
+	if m.trackingActive {
+		return m.startPomodoroPhase(pomodoroTimebox, m.pomodoroCfg.TimeboxDuration)
+	}
+
+	if m.changesLocked {
+		return nil
+	}
+
+	trackCmd := m.getCmdToStartTracking()
+	if trackCmd == nil {
+		return nil
+	}
+
+	return tea.Batch(trackCmd, m.startPomodoroPhase(pomodoroTimebox, m.pomodoroCfg.TimeboxDuration))
+}
+
+func (m *Model) handleRequestToContinueTimebox() tea.Cmd {
+	if m.pomodoro.phase != pomodoroTimeboxOver || !m.trackingActive {
+		return nil
+	}
+
+	return m.startPomodoroPhase(pomodoroTimebox, m.pomodoroCfg.TimeboxDuration)
+}
+
+func (m *Model) handlePomodoroTickMsg(msg pomodoroTickMsg) tea.Cmd {
+	if msg.id != m.pomodoro.tickID {
+		return nil
+	}
+
+	now := m.timeProvider.Now()
+	if now.Before(m.pomodoro.endsAt) {
+		return tickPomodoro(msg.id)
+	}
+
+	switch m.pomodoro.phase {
+	case pomodoroTimebox:
+		m.pomodoro.phase = pomodoroTimeboxOver
+		m.message = infoMsg(timeboxOverMsg)
+		return m.alertPomodoroPhaseOver("timebox over")
+	case pomodoroBreak:
+		m.pomodoro.phase = pomodoroBreakOver
+		m.message = infoMsg("Break over")
+		cmds := []tea.Cmd{m.alertPomodoroPhaseOver("break over")}
+		// the break is only stopped if it's still being tracked; it's up to the
+		// user to stop tracking anything they moved on to
+		if m.pomodoro.breakRecorded && m.trackingActive && m.activeTaskID == m.pomodoro.breakTaskID {
+			m.activeTLEndTS = now.Truncate(time.Second)
+			cmds = append(cmds, toggleTracking(m.db, m.activeTaskID, m.activeTLBeginTS, m.activeTLEndTS, nil))
+		}
+		return tea.Batch(cmds...)
+	}
+
+	return nil
+}
+
+// handlePomodoroTrackingFinished starts a break once the task log that a
+// timebox was running for has been saved.
+func (m *Model) handlePomodoroTrackingFinished(finishedAt time.Time) tea.Cmd {
+	switch m.pomodoro.phase {
+	case pomodoroTimebox, pomodoroTimeboxOver:
+	case pomodoroBreak:
+		// a recorded break was stopped early
+		m.pomodoro = pomodoroState{tickID: m.pomodoro.tickID}
+		return nil
+	default:
+		return nil
+	}
+
+	if m.pomodoroCfg.BreakDuration <= 0 {
+		m.pomodoro = pomodoroState{tickID: m.pomodoro.tickID}
+		return nil
+	}
+
+	tickCmd := m.startPomodoroPhase(pomodoroBreak, m.pomodoroCfg.BreakDuration)
+	if !m.pomodoroCfg.RecordBreaks {
+		return tickCmd
+	}
+
+	m.pomodoro.breakRecorded = true
+	m.changesLocked = true
+	return tea.Batch(tickCmd, startBreak(m.db, finishedAt))
+}
+
+func (m *Model) handleBreakStartedMsg(msg breakStartedMsg) tea.Cmd {
+	m.changesLocked = false
+	if msg.err != nil {
+		m.pomodoro.breakRecorded = false
+		m.message = errMsg("Error recording break: " + msg.err.Error())
+		return nil
+	}
+
+	m.pomodoro.breakTaskID = msg.taskID
+
+	return fetchTasks(m.db, true)
+}
+
+func formatCountdown(d time.Duration) string {
+	d = max(d, 0).Round(time.Second)
+	mins := int(d / time.Minute)
+	secs := int((d % time.Minute) / time.Second)
+
+	return fmt.Sprintf("%02d:%02d", mins, secs)
+}
+
+// getPomodoroFooterMsg shows how much of the current timebox (or break) is
+// left.
+func (m Model) getPomodoroFooterMsg() string {
+	now := m.timeProvider.Now()
+	switch m.pomodoro.phase {
+	case pomodoroTimebox:
+		return m.style.timebox.Render(fmt.Sprintf("timebox: %s left", formatCountdown(m.pomodoro.endsAt.Sub(now))))
+	case pomodoroTimeboxOver:
+		return m.style.timeboxOver.Render("timebox over (f: finish, c: continue)")
+	case pomodoroBreak:
+		return m.style.timebox.Render(fmt.Sprintf("break: %s left", formatCountdown(m.pomodoro.endsAt.Sub(now))))
+	case pomodoroBreakOver:
+		return m.style.timeboxOver.Render("break over (P: next timebox)")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeboxEndsAfterItsDuration(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.trackingActive = true
+	cmd := m.handleRequestToToggleTimebox()
+	require.NotNil(t, cmd)
+	require.Equal(t, pomodoroTimebox, m.pomodoro.phase)
+	tick := pomodoroTickMsg{m.pomodoro.tickID}
+
+	// WHEN
+	m.timeProvider = types.TestTimeProvider{FixedTime: referenceTime.Add(10 * time.Minute)}
+	cmdBeforeEnd := m.handlePomodoroTickMsg(tick)
+	phaseBeforeEnd := m.pomodoro.phase
+
+	m.timeProvider = types.TestTimeProvider{FixedTime: referenceTime.Add(25 * time.Minute)}
+	cmdAtEnd := m.handlePomodoroTickMsg(tick)
+
+	// THEN
+	assert.NotNil(t, cmdBeforeEnd)
+	assert.Equal(t, pomodoroTimebox, phaseBeforeEnd)
+	assert.NotNil(t, cmdAtEnd)
+	assert.Equal(t, pomodoroTimeboxOver, m.pomodoro.phase)
+	assert.Equal(t, timeboxOverMsg, m.message.value)
+}
+
+func TestStaleTimeboxTicksAreIgnored(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.trackingActive = true
+	m.handleRequestToToggleTimebox()
+	staleTick := pomodoroTickMsg{m.pomodoro.tickID}
+	m.handleRequestToToggleTimebox()
+	m.handleRequestToToggleTimebox()
+
+	// WHEN
+	m.timeProvider = types.TestTimeProvider{FixedTime: referenceTime.Add(time.Hour)}
+	cmd := m.handlePomodoroTickMsg(staleTick)
+
+	// THEN
+	assert.Nil(t, cmd)
+	assert.Equal(t, pomodoroTimebox, m.pomodoro.phase)
+}
+
+func TestTogglingTimeboxCancelsIt(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.trackingActive = true
+	m.handleRequestToToggleTimebox()
+
+	// WHEN
+	cmd := m.handleRequestToToggleTimebox()
+
+	// THEN
+	assert.Nil(t, cmd)
+	assert.Equal(t, pomodoroOff, m.pomodoro.phase)
+	assert.Equal(t, "Timebox cancelled", m.message.value)
+}
+
+func TestContinuingTimeboxStartsANewOne(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.trackingActive = true
+	m.pomodoro = pomodoroState{phase: pomodoroTimeboxOver, endsAt: referenceTime}
+	m.timeProvider = types.TestTimeProvider{FixedTime: referenceTime.Add(2 * time.Minute)}
+
+	// WHEN
+	cmd := m.handleRequestToContinueTimebox()
+
+	// THEN
+	assert.NotNil(t, cmd)
+	assert.Equal(t, pomodoroTimebox, m.pomodoro.phase)
+	assert.Equal(t, referenceTime.Add(27*time.Minute), m.pomodoro.endsAt)
+}
+
+func TestFinishingTrackingDuringTimeboxStartsABreak(t *testing.T) {
+	testCases := []struct {
+		name          string
+		breakDuration time.Duration
+		phase         pomodoroPhase
+		expectedPhase pomodoroPhase
+	}{
+		{name: "timebox running", breakDuration: 5 * time.Minute, phase: pomodoroTimebox, expectedPhase: pomodoroBreak},
+		{name: "timebox over", breakDuration: 5 * time.Minute, phase: pomodoroTimeboxOver, expectedPhase: pomodoroBreak},
+		{name: "breaks turned off", breakDuration: 0, phase: pomodoroTimeboxOver, expectedPhase: pomodoroOff},
+		{name: "no timebox", breakDuration: 5 * time.Minute, phase: pomodoroOff, expectedPhase: pomodoroOff},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			m := createTestModel()
+			m.pomodoroCfg.BreakDuration = tt.breakDuration
+			m.pomodoro.phase = tt.phase
+
+			// WHEN
+			m.handlePomodoroTrackingFinished(referenceTime)
+
+			// THEN
+			assert.Equal(t, tt.expectedPhase, m.pomodoro.phase)
+			assert.False(t, m.pomodoro.breakRecorded)
+		})
+	}
+}
+
+func TestSwitchingDuringARecordedBreakEndsIt(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.pomodoroCfg.BreakDuration = 5 * time.Minute
+	m.pomodoroCfg.RecordBreaks = true
+	m.taskMap[1] = createTestTask(1, breakTaskSummary, true, true, m.timeProvider)
+	m.taskMap[2] = createTestTask(2, "Implement feature A", true, false, m.timeProvider)
+	m.pomodoro.phase = pomodoroTimeboxOver
+	m.handlePomodoroTrackingFinished(referenceTime)
+	m.handleBreakStartedMsg(breakStartedMsg{taskID: 1})
+	m.trackingActive = true
+	m.activeTaskID = 1
+	breakTick := pomodoroTickMsg{m.pomodoro.tickID}
+	require.True(t, m.pomodoro.breakRecorded)
+
+	// WHEN
+	m.handleActiveTLSwitchedMsg(activeTLSwitchedMsg{
+		lastActiveTaskID:      1,
+		currentlyActiveTaskID: 2,
+		ts:                    referenceTime.Add(time.Minute),
+	})
+	m.timeProvider = types.TestTimeProvider{FixedTime: referenceTime.Add(10 * time.Minute)}
+	tickCmd := m.handlePomodoroTickMsg(breakTick)
+
+	// THEN
+	assert.Equal(t, pomodoroOff, m.pomodoro.phase)
+	assert.False(t, m.pomodoro.breakRecorded)
+	assert.Nil(t, tickCmd)
+	assert.True(t, m.trackingActive)
+	assert.Equal(t, 2, m.activeTaskID)
+}
+
+func TestBreakOverOnlyStopsTrackingTheBreakTask(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.trackingActive = true
+	m.activeTaskID = 2
+	m.pomodoro = pomodoroState{phase: pomodoroBreak, endsAt: referenceTime, breakRecorded: true, breakTaskID: 1}
+
+	// WHEN
+	m.handlePomodoroTickMsg(pomodoroTickMsg{m.pomodoro.tickID})
+
+	// THEN
+	assert.Equal(t, pomodoroBreakOver, m.pomodoro.phase)
+	assert.True(t, m.activeTLEndTS.IsZero(), "the task log of another task shouldn't be finished")
+}
+
+func TestFormatCountdown(t *testing.T) {
+	assert.Equal(t, "25:00", formatCountdown(25*time.Minute))
+	assert.Equal(t, "04:05", formatCountdown(4*time.Minute+5*time.Second))
+	assert.Equal(t, "00:00", formatCountdown(-time.Second))
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+var errCouldntFetchProjects = errors.New("couldn't fetch projects")
+
+const projectsNameCharsBudget = 30
+
+func RenderProjects(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	outputFormat types.OutputFormat,
+) error {
+	projects, err := pers.FetchProjects(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchProjects, err.Error())
+	}
+
+	var output string
+	switch outputFormat {
+	case types.OutputFormatJSON:
+		if projects == nil {
+			projects = []domain.Project{}
+		}
+		output, err = marshalJSON(projects)
+	default:
+		output, err = getProjectsTable(projects, style, plain)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(writer, output)
+	return nil
+}
+
+func getProjectsTable(projects []domain.Project, style Style, plain bool) (string, error) {
+	rs := style.getReportStyles(plain)
+
+	data := make([][]string, len(projects))
+	for i, project := range projects {
+		rate := "-"
+		if project.Rate != nil {
+			rate = project.Rate.String()
+		}
+
+		row := []string{
+			fmt.Sprintf("%d", project.ID),
+			utils.RightPadTrim(project.Name, projectsNameCharsBudget, true),
+			utils.RightPadTrim(project.Client, projectsNameCharsBudget, true),
+			rate,
+		}
+
+		if !plain {
+			rowStyle := style.getDynamicStyle(project.Name)
+			for j, value := range row {
+				row[j] = rowStyle.Render(value)
+			}
+		}
+
+		data[i] = row
+	}
+
+	headerValues := []string{"ID", "Project", "Client", "Rate"}
+	headers := make([]string, len(headerValues))
+	for i, h := range headerValues {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+	b := bytes.Buffer{}
+	table := tablewriter.NewTable(
+		&b,
+		tablewriter.WithConfig(tablewriter.Config{
+			Header: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment:  tw.AlignCenter,
+					AutoWrap:   tw.WrapNone,
+					AutoFormat: tw.Off,
+				},
+			},
+			Row: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment: tw.AlignLeft,
+					AutoWrap:  tw.WrapNone,
+				},
+			},
+		}),
+		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{Symbols: rs.symbols(tw.StyleASCII)})),
+		tablewriter.WithHeader(headers),
+	)
+
+	if err := table.Bulk(data); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntAddDataToTable, err.Error())
+	}
+
+	if err := table.Render(); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntRenderTable, err.Error())
+	}
+
+	return b.String(), nil
+}
@@ -19,32 +19,78 @@ import (
 	"github.com/olekukonko/tablewriter/tw"
 )
 
-var errCouldntGenerateReport = errors.New("couldn't generate report")
+var (
+	errCouldntGenerateReport = errors.New("couldn't generate report")
+	errGroupByNotApplicable  = errors.New("reports can only be grouped by task, project, or client")
+)
 
 const (
 	reportTimeCharsBudget = 6
 )
 
+// reportDay holds the entries that make up a single day (ie, a column) of a
+// report.
+type reportDay[T any] struct {
+	Date      string `json:"date"`
+	SecsSpent int    `json:"secs_spent"`
+	Entries   []T    `json:"entries"`
+	start     time.Time
+}
+
 func RenderReport(db *sql.DB,
 	style Style,
 	writer io.Writer,
 	plain bool,
+	outputFormat types.OutputFormat,
 	dateRange types.DateRange,
 	period string,
-	taskStatus types.TaskStatus,
+	filter types.TaskFilter,
 	agg bool,
+	groupBy types.GroupBy,
 	interactive bool,
+	withComments bool,
 ) error {
+	// a task can have several tags, so the time spent on days wouldn't add up
+	if groupBy == types.GroupByTag {
+		return errGroupByNotApplicable
+	}
+
+	if interactive && outputFormat != types.OutputFormatTable {
+		return fmt.Errorf("%w with %s output", errInteractiveModeNotApplicable, outputFormat)
+	}
+
+	if withComments && outputFormat != types.OutputFormatHTML {
+		return errCommentsNotApplicable
+	}
+
 	var report string
 	var analyticsType recordsKind
 	var err error
 
-	if agg {
+	grouped := groupBy != types.GroupByTask
+
+	switch {
+	case outputFormat == types.OutputFormatJSON && grouped:
+		report, err = getReportGroupedJSON(db, dateRange.Start, dateRange.NumDays, filter, groupBy)
+	case outputFormat == types.OutputFormatJSON && agg:
+		report, err = getReportAggJSON(db, dateRange.Start, dateRange.NumDays, filter)
+	case outputFormat == types.OutputFormatJSON:
+		report, err = getReportJSON(db, dateRange.Start, dateRange.NumDays, filter)
+	case outputFormat == types.OutputFormatMarkdown || outputFormat == types.OutputFormatHTML:
+		var doc recordsDoc
+		doc, err = getReportDoc(db, dateRange, filter, agg, groupBy, withComments)
+		if err == nil {
+			report, err = doc.render(style, outputFormat)
+		}
+	case grouped:
+		analyticsType = reportGroupedRecords
+		report, err = getReportGrouped(db, style, dateRange.Start, dateRange.NumDays, filter, groupBy, plain)
+	case agg:
 		analyticsType = reportAggRecords
-		report, err = getReportAgg(db, style, dateRange.Start, dateRange.NumDays, taskStatus, plain)
-	} else {
+		report, err = getReportAgg(db, style, dateRange.Start, dateRange.NumDays, filter, plain)
+	default:
 		analyticsType = reportRecords
-		report, err = getReport(db, style, dateRange.Start, dateRange.NumDays, taskStatus, plain)
+		report, err = getReport(db, style, dateRange.Start, dateRange.NumDays, filter, plain)
 	}
 	if err != nil {
 		return fmt.Errorf("%w: %s", errCouldntGenerateReport, err.Error())
@@ -58,7 +104,8 @@ func RenderReport(db *sql.DB,
 			types.RealTimeProvider{},
 			dateRange,
 			period,
-			taskStatus,
+			filter,
+			groupBy,
 			plain,
 			report,
 		))
@@ -72,207 +119,270 @@ func RenderReport(db *sql.DB,
 	return nil
 }
 
-func getReport(db *sql.DB, style Style, start time.Time, numDays int, taskStatus types.TaskStatus, plain bool) (string, error) {
-	day := start
-	var nextDay time.Time
+func getReportData(db *sql.DB, start time.Time, numDays int, filter types.TaskFilter) ([]reportDay[domain.TaskLogEntry], error) {
+	return getReportDays(start,
+		numDays,
+		func(begin, end time.Time) ([]domain.TaskLogEntry, error) {
+			return pers.FetchTLEntriesBetweenTS(db, begin, end, filter, pers.NoLimit)
+		},
+		func(entry domain.TaskLogEntry) int { return entry.SecsSpent },
+	)
+}
 
-	var maxEntryForADay int
-	reportData := make(map[int][]domain.TaskLogEntry)
+func getReportAggData(db *sql.DB, start time.Time, numDays int, filter types.TaskFilter) ([]reportDay[domain.TaskReportEntry], error) {
+	return getReportDays(start,
+		numDays,
+		func(begin, end time.Time) ([]domain.TaskReportEntry, error) {
+			return pers.FetchReportBetweenTS(db, begin, end, filter, pers.NoLimit)
+		},
+		func(entry domain.TaskReportEntry) int { return entry.SecsSpent },
+	)
+}
+
+// getReportGroupedData returns the time spent on each day of a report, rolled
+// up by project or client.
+func getReportGroupedData(db *sql.DB, start time.Time, numDays int, filter types.TaskFilter, groupBy types.GroupBy) ([]reportDay[domain.GroupReportEntry], error) {
+	groups, err := pers.FetchTaskGroups(db, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return getReportDays(start,
+		numDays,
+		func(begin, end time.Time) ([]domain.GroupReportEntry, error) {
+			entries, err := pers.FetchReportBetweenTS(db, begin, end, filter, pers.NoLimit)
+			if err != nil {
+				return nil, err
+			}
+
+			return domain.RollUpReportEntries(entries, groups), nil
+		},
+		func(entry domain.GroupReportEntry) int { return entry.SecsSpent },
+	)
+}
+
+func getReportDays[T any](start time.Time,
+	numDays int,
+	fetch func(begin, end time.Time) ([]T, error),
+	secsSpent func(T) int,
+) ([]reportDay[T], error) {
+	days := make([]reportDay[T], numDays)
 
-	noEntriesFound := true
+	day := start
 	for i := range numDays {
-		nextDay = day.AddDate(0, 0, 1)
-		taskLogEntries, err := pers.FetchTLEntriesBetweenTS(db, day, nextDay, taskStatus, 100)
+		nextDay := day.AddDate(0, 0, 1)
+		entries, err := fetch(day, nextDay)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		if noEntriesFound && len(taskLogEntries) > 0 {
-			noEntriesFound = false
+
+		if entries == nil {
+			entries = []T{}
 		}
 
-		day = nextDay
-		reportData[i] = taskLogEntries
+		var totalSecs int
+		for _, entry := range entries {
+			totalSecs += secsSpent(entry)
+		}
 
-		if len(taskLogEntries) > maxEntryForADay {
-			maxEntryForADay = len(taskLogEntries)
+		days[i] = reportDay[T]{
+			Date:      day.Format(time.DateOnly),
+			SecsSpent: totalSecs,
+			Entries:   entries,
+			start:     day,
 		}
+		day = nextDay
 	}
 
-	if noEntriesFound {
-		maxEntryForADay = 1
+	return days, nil
+}
+
+func getReport(db *sql.DB, style Style, start time.Time, numDays int, filter types.TaskFilter, plain bool) (string, error) {
+	days, err := getReportData(db, start, numDays, filter)
+	if err != nil {
+		return "", err
 	}
 
-	data := make([][]string, maxEntryForADay)
-	totalSecsPerDay := make(map[int]int)
+	return getReportTable(days, style, plain, func(entry domain.TaskLogEntry) (string, int) {
+		return entry.TaskSummary, entry.SecsSpent
+	})
+}
 
-	for j := range numDays {
-		totalSecsPerDay[j] = 0
+func getReportAgg(db *sql.DB,
+	style Style,
+	start time.Time,
+	numDays int,
+	filter types.TaskFilter,
+	plain bool) (string,
+	error,
+) {
+	days, err := getReportAggData(db, start, numDays, filter)
+	if err != nil {
+		return "", err
 	}
 
-	rs := style.getReportStyles(plain)
+	return getReportTable(days, style, plain, func(entry domain.TaskReportEntry) (string, int) {
+		return entry.TaskSummary, entry.SecsSpent
+	})
+}
 
-	var summaryBudget int
-	switch numDays {
-	case 7:
-		summaryBudget = 8
-	case 6:
-		summaryBudget = 10
-	case 5:
-		summaryBudget = 14
-	default:
-		summaryBudget = 16
+func getReportGrouped(db *sql.DB,
+	style Style,
+	start time.Time,
+	numDays int,
+	filter types.TaskFilter,
+	groupBy types.GroupBy,
+	plain bool) (string,
+	error,
+) {
+	days, err := getReportGroupedData(db, start, numDays, filter, groupBy)
+	if err != nil {
+		return "", err
 	}
 
-	styleCache := make(map[string]lipgloss.Style)
-	for rowIndex := range maxEntryForADay {
-		row := make([]string, numDays)
-		for colIndex := range numDays {
-			if rowIndex >= len(reportData[colIndex]) {
-				row[colIndex] = fmt.Sprintf(
-					"%s  %s",
-					utils.RightPadTrim("", summaryBudget, false),
-					utils.RightPadTrim("", reportTimeCharsBudget, false),
-				)
-				continue
-			}
+	return getReportTable(days, style, plain, func(entry domain.GroupReportEntry) (string, int) {
+		return getGroupLabel(entry.Group), entry.SecsSpent
+	})
+}
 
-			tr := reportData[colIndex][rowIndex]
-			timeSpentStr := types.HumanizeDuration(tr.SecsSpent)
+func getReportJSON(db *sql.DB, start time.Time, numDays int, filter types.TaskFilter) (string, error) {
+	days, err := getReportData(db, start, numDays, filter)
+	if err != nil {
+		return "", err
+	}
 
-			if plain {
-				row[colIndex] = fmt.Sprintf(
-					"%s  %s",
-					utils.RightPadTrim(tr.TaskSummary, summaryBudget, false),
-					utils.RightPadTrim(timeSpentStr, reportTimeCharsBudget, false),
-				)
-			} else {
-				rowStyle, ok := styleCache[tr.TaskSummary]
+	return marshalJSON(days)
+}
 
-				if !ok {
-					rowStyle = style.getDynamicStyle(tr.TaskSummary)
-					styleCache[tr.TaskSummary] = rowStyle
-				}
+func getReportAggJSON(db *sql.DB, start time.Time, numDays int, filter types.TaskFilter) (string, error) {
+	days, err := getReportAggData(db, start, numDays, filter)
+	if err != nil {
+		return "", err
+	}
 
-				row[colIndex] = fmt.Sprintf(
-					"%s  %s",
-					rowStyle.Render(utils.RightPadTrim(tr.TaskSummary, summaryBudget, false)),
-					rowStyle.Render(utils.RightPadTrim(timeSpentStr, reportTimeCharsBudget, false)),
-				)
-			}
-			totalSecsPerDay[colIndex] += tr.SecsSpent
-		}
-		data[rowIndex] = row
+	return marshalJSON(days)
+}
+
+func getReportGroupedJSON(db *sql.DB, start time.Time, numDays int, filter types.TaskFilter, groupBy types.GroupBy) (string, error) {
+	days, err := getReportGroupedData(db, start, numDays, filter, groupBy)
+	if err != nil {
+		return "", err
 	}
 
-	totalTimePerDay := make([]string, numDays)
+	return marshalJSON(days)
+}
 
-	for i, ts := range totalSecsPerDay {
-		if ts != 0 {
-			totalTimePerDay[i] = rs.footerStyle.Render(types.HumanizeDuration(ts))
-		} else {
-			totalTimePerDay[i] = " "
+func getReportDoc(db *sql.DB,
+	dateRange types.DateRange,
+	filter types.TaskFilter,
+	agg bool,
+	groupBy types.GroupBy,
+	withComments bool,
+) (recordsDoc, error) {
+	var doc recordsDoc
+	var err error
+
+	switch {
+	case groupBy != types.GroupByTask:
+		var days []reportDay[domain.GroupReportEntry]
+		days, err = getReportGroupedData(db, dateRange.Start, dateRange.NumDays, filter, groupBy)
+		if err != nil {
+			return doc, err
+		}
+		title := fmt.Sprintf("Report (by %s)", groupBy)
+		doc = getReportDocFromDays(days, getRecordsTitle(title, &dateRange), func(entry domain.GroupReportEntry) (string, int) {
+			return getGroupLabel(entry.Group), entry.SecsSpent
+		})
+	case agg:
+		var days []reportDay[domain.TaskReportEntry]
+		days, err = getReportAggData(db, dateRange.Start, dateRange.NumDays, filter)
+		if err != nil {
+			return doc, err
+		}
+		doc = getReportDocFromDays(days, getRecordsTitle("Report (aggregated)", &dateRange), func(entry domain.TaskReportEntry) (string, int) {
+			return entry.TaskSummary, entry.SecsSpent
+		})
+	default:
+		var days []reportDay[domain.TaskLogEntry]
+		days, err = getReportData(db, dateRange.Start, dateRange.NumDays, filter)
+		if err != nil {
+			return doc, err
 		}
+		doc = getReportDocFromDays(days, getRecordsTitle("Report", &dateRange), func(entry domain.TaskLogEntry) (string, int) {
+			return entry.TaskSummary, entry.SecsSpent
+		})
 	}
 
-	headersValues := make([]string, numDays)
+	if withComments {
+		doc.withComments = true
+		doc.comments, err = fetchTLsWithComments(db, dateRange.Start, dateRange.End, filter)
+		if err != nil {
+			return doc, err
+		}
+	}
 
-	day = start
-	counter := 0
+	return doc, nil
+}
 
-	for counter < numDays {
-		headersValues[counter] = day.Format(dateFormat)
-		day = day.AddDate(0, 0, 1)
-		counter++
+func getReportDocFromDays[T any](days []reportDay[T], title string, entryDetails func(T) (string, int)) recordsDoc {
+	var maxEntryForADay int
+	for _, day := range days {
+		maxEntryForADay = max(maxEntryForADay, len(day.Entries))
 	}
 
-	headers := make([]string, numDays)
-	for i := range numDays {
-		headers[i] = rs.headerStyle.Render(headersValues[i])
+	doc := recordsDoc{
+		title:   title,
+		headers: make([]string, len(days)),
+		rows:    make([][]recordsCell, maxEntryForADay),
+		footer:  make([]string, len(days)),
 	}
 
-	b := bytes.Buffer{}
-	table := tablewriter.NewTable(
-		&b,
-		tablewriter.WithConfig(tablewriter.Config{
-			Header: tw.CellConfig{
-				Formatting: tw.CellFormatting{
-					Alignment:  tw.AlignCenter,
-					AutoWrap:   tw.WrapNone,
-					AutoFormat: tw.Off,
-				},
-			},
-			Row: tw.CellConfig{
-				Formatting: tw.CellFormatting{
-					Alignment: tw.AlignLeft,
-					AutoWrap:  tw.WrapNone,
-				},
-			},
-			Footer: tw.CellConfig{
-				Formatting: tw.CellFormatting{
-					Alignment:  tw.AlignCenter,
-					AutoWrap:   tw.WrapNone,
-					AutoFormat: tw.Off,
-				},
-			},
-		}),
-		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{Symbols: rs.symbols(tw.StyleASCII)})),
-		tablewriter.WithHeader(headers),
-		tablewriter.WithFooter(totalTimePerDay),
-	)
-
-	if err := table.Bulk(data); err != nil {
-		return "", fmt.Errorf("%w: %s", errCouldntAddDataToTable, err.Error())
+	for i, day := range days {
+		doc.headers[i] = day.start.Format(dateFormat)
+		if day.SecsSpent != 0 {
+			doc.footer[i] = types.HumanizeDuration(day.SecsSpent)
+		}
 	}
 
-	if err := table.Render(); err != nil {
-		return "", fmt.Errorf("%w: %s", errCouldntRenderTable, err.Error())
+	for rowIndex := range maxEntryForADay {
+		row := make([]recordsCell, len(days))
+		for colIndex, day := range days {
+			if rowIndex >= len(day.Entries) {
+				continue
+			}
+
+			summary, secsSpent := entryDetails(day.Entries[rowIndex])
+			row[colIndex] = recordsCell{
+				text:   summary,
+				detail: types.HumanizeDuration(secsSpent),
+				task:   summary,
+			}
+		}
+		doc.rows[rowIndex] = row
 	}
 
-	return b.String(), nil
+	return doc
 }
 
-func getReportAgg(db *sql.DB,
+func getReportTable[T any](days []reportDay[T],
 	style Style,
-	start time.Time,
-	numDays int,
-	taskStatus types.TaskStatus,
-	plain bool) (string,
-	error,
-) {
-	day := start
-	var nextDay time.Time
+	plain bool,
+	entryDetails func(T) (string, int),
+) (string, error) {
+	numDays := len(days)
 
 	var maxEntryForADay int
-	reportData := make(map[int][]domain.TaskReportEntry)
-
-	noEntriesFound := true
-	for i := range numDays {
-		nextDay = day.AddDate(0, 0, 1)
-		taskLogEntries, err := pers.FetchReportBetweenTS(db, day, nextDay, taskStatus, 100)
-		if err != nil {
-			return "", err
-		}
-		if noEntriesFound && len(taskLogEntries) > 0 {
-			noEntriesFound = false
-		}
-
-		day = nextDay
-		reportData[i] = taskLogEntries
-		if len(taskLogEntries) > maxEntryForADay {
-			maxEntryForADay = len(taskLogEntries)
+	for _, day := range days {
+		if len(day.Entries) > maxEntryForADay {
+			maxEntryForADay = len(day.Entries)
 		}
 	}
 
-	if noEntriesFound {
+	if maxEntryForADay == 0 {
 		maxEntryForADay = 1
 	}
 
 	data := make([][]string, maxEntryForADay)
-	totalSecsPerDay := make(map[int]int)
-
-	for j := range numDays {
-		totalSecsPerDay[j] = 0
-	}
 
 	rs := style.getReportStyles(plain)
 
@@ -292,7 +402,7 @@ func getReportAgg(db *sql.DB,
 	for rowIndex := range maxEntryForADay {
 		row := make([]string, numDays)
 		for colIndex := range numDays {
-			if rowIndex >= len(reportData[colIndex]) {
+			if rowIndex >= len(days[colIndex].Entries) {
 				row[colIndex] = fmt.Sprintf(
 					"%s  %s",
 					utils.RightPadTrim("", summaryBudget, false),
@@ -301,56 +411,41 @@ func getReportAgg(db *sql.DB,
 				continue
 			}
 
-			tr := reportData[colIndex][rowIndex]
-			timeSpentStr := types.HumanizeDuration(tr.SecsSpent)
+			summary, secsSpent := entryDetails(days[colIndex].Entries[rowIndex])
+			timeSpentStr := types.HumanizeDuration(secsSpent)
 
 			if plain {
 				row[colIndex] = fmt.Sprintf(
 					"%s  %s",
-					utils.RightPadTrim(tr.TaskSummary, summaryBudget, false),
+					utils.RightPadTrim(summary, summaryBudget, false),
 					utils.RightPadTrim(timeSpentStr, reportTimeCharsBudget, false),
 				)
 			} else {
-				rowStyle, ok := styleCache[tr.TaskSummary]
+				rowStyle, ok := styleCache[summary]
 				if !ok {
-					rowStyle = style.getDynamicStyle(tr.TaskSummary)
-					styleCache[tr.TaskSummary] = rowStyle
+					rowStyle = style.getDynamicStyle(summary)
+					styleCache[summary] = rowStyle
 				}
 
 				row[colIndex] = fmt.Sprintf(
 					"%s  %s",
-					rowStyle.Render(utils.RightPadTrim(tr.TaskSummary, summaryBudget, false)),
+					rowStyle.Render(utils.RightPadTrim(summary, summaryBudget, false)),
 					rowStyle.Render(utils.RightPadTrim(timeSpentStr, reportTimeCharsBudget, false)),
 				)
 			}
-			totalSecsPerDay[colIndex] += tr.SecsSpent
 		}
 		data[rowIndex] = row
 	}
 
 	totalTimePerDay := make([]string, numDays)
-	for i, ts := range totalSecsPerDay {
-		if ts != 0 {
-			totalTimePerDay[i] = rs.footerStyle.Render(types.HumanizeDuration(ts))
+	headers := make([]string, numDays)
+	for i, day := range days {
+		if day.SecsSpent != 0 {
+			totalTimePerDay[i] = rs.footerStyle.Render(types.HumanizeDuration(day.SecsSpent))
 		} else {
 			totalTimePerDay[i] = " "
 		}
-	}
-
-	headersValues := make([]string, numDays)
-
-	day = start
-	counter := 0
-
-	for counter < numDays {
-		headersValues[counter] = day.Format(dateFormat)
-		day = day.AddDate(0, 0, 1)
-		counter++
-	}
-
-	headers := make([]string, numDays)
-	for i := range numDays {
-		headers[i] = rs.headerStyle.Render(headersValues[i])
+		headers[i] = rs.headerStyle.Render(day.start.Format(dateFormat))
 	}
 
 	b := bytes.Buffer{}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite" // sqlite driver
+)
+
+func TestGetReportJSONIncludesEveryEntryOfADay(t *testing.T) {
+	// GIVEN
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, pers.InitDB(db))
+	require.NoError(t, pers.UpgradeDB(db, 1))
+
+	taskID, err := pers.InsertTask(db, "task")
+	require.NoError(t, err)
+
+	start := time.Date(2025, time.October, 24, 0, 0, 0, 0, time.Local)
+	numEntries := 150
+	for i := range numEntries {
+		beginTS := start.Add(time.Duration(2*i) * time.Minute)
+		_, err := pers.InsertManualTL(db, taskID, beginTS, beginTS.Add(time.Minute), nil, false)
+		require.NoError(t, err)
+	}
+
+	// WHEN
+	report, err := getReportJSON(db, start, 1, types.TaskFilter{Status: types.TaskStatusAny})
+
+	// THEN
+	require.NoError(t, err)
+	var days []struct {
+		SecsSpent int               `json:"secs_spent"`
+		Entries   []json.RawMessage `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(report), &days))
+	require.Len(t, days, 1)
+	assert.Len(t, days[0].Entries, numEntries)
+	assert.Equal(t, numEntries*60, days[0].SecsSpent)
+}
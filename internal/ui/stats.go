@@ -23,26 +23,72 @@ var errCouldntGenerateStats = errors.New("couldn't generate stats")
 const (
 	statsLogEntriesLimit = 10000
 	statsTimeCharsBudget = 6
+	untaggedLabel        = "(untagged)"
 )
 
+// statsEntry is a row in the stats table; its label is either a task's summary
+// or the name of a group of tasks.
+type statsEntry struct {
+	label      string
+	numEntries int
+	secsSpent  int
+}
+
 func RenderStats(db *sql.DB,
 	style Style,
 	writer io.Writer,
 	plain bool,
+	outputFormat types.OutputFormat,
 	dateRange *types.DateRange,
 	period string,
-	taskStatus types.TaskStatus,
+	filter types.TaskFilter,
+	groupBy types.GroupBy,
 	interactive bool,
+	withComments bool,
 ) error {
 	var stats string
 	var err error
 
+	if interactive && outputFormat != types.OutputFormatTable {
+		return fmt.Errorf("%w with %s output", errInteractiveModeNotApplicable, outputFormat)
+	}
+
 	if interactive && dateRange == nil {
 		return fmt.Errorf("%w when period=all", errInteractiveModeNotApplicable)
 	}
 
+	if withComments && outputFormat != types.OutputFormatHTML {
+		return errCommentsNotApplicable
+	}
+
+	if withComments && dateRange == nil {
+		return errCommentsNeedAPeriod
+	}
+
+	switch outputFormat {
+	case types.OutputFormatJSON:
+		stats, err = getStatsJSON(db, dateRange, filter, groupBy)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+		}
+
+		fmt.Fprint(writer, stats)
+		return nil
+	case types.OutputFormatMarkdown, types.OutputFormatHTML:
+		doc, err := getStatsDoc(db, dateRange, filter, groupBy, withComments)
+		if err == nil {
+			stats, err = doc.render(style, outputFormat)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
+		}
+
+		fmt.Fprint(writer, stats)
+		return nil
+	}
+
 	if dateRange == nil {
-		stats, err = getStats(db, style, dateRange, taskStatus, plain)
+		stats, err = getStats(db, style, dateRange, filter, groupBy, plain)
 		if err != nil {
 			return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
 		}
@@ -51,7 +97,7 @@ func RenderStats(db *sql.DB,
 		return nil
 	}
 
-	stats, err = getStats(db, style, dateRange, taskStatus, plain)
+	stats, err = getStats(db, style, dateRange, filter, groupBy, plain)
 	if err != nil {
 		return fmt.Errorf("%w: %s", errCouldntGenerateStats, err.Error())
 	}
@@ -64,7 +110,8 @@ func RenderStats(db *sql.DB,
 			types.RealTimeProvider{},
 			*dateRange,
 			period,
-			taskStatus,
+			filter,
+			groupBy,
 			plain,
 			stats,
 		))
@@ -78,22 +125,148 @@ func RenderStats(db *sql.DB,
 	return nil
 }
 
+func fetchStats(db *sql.DB, dateRange *types.DateRange, filter types.TaskFilter) ([]domain.TaskReportEntry, error) {
+	if dateRange == nil {
+		return pers.FetchStats(db, filter, statsLogEntriesLimit)
+	}
+
+	return pers.FetchStatsBetweenTS(db, dateRange.Start, dateRange.End, filter, statsLogEntriesLimit)
+}
+
+func fetchGroupStats(db *sql.DB, dateRange *types.DateRange, filter types.TaskFilter, groupBy types.GroupBy) ([]domain.GroupReportEntry, error) {
+	entries, err := fetchStats(db, dateRange, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := pers.FetchTaskGroups(db, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.RollUpReportEntries(entries, groups), nil
+}
+
+// getGroupLabel returns the label of a group of tasks; the only tasks that
+// don't belong to a group are the ones without tags.
+func getGroupLabel(group string) string {
+	if group == "" {
+		return untaggedLabel
+	}
+
+	return group
+}
+
+func fetchStatsEntries(db *sql.DB, dateRange *types.DateRange, filter types.TaskFilter, groupBy types.GroupBy) ([]statsEntry, error) {
+	var entries []statsEntry
+
+	if groupBy != types.GroupByTask {
+		groupEntries, err := fetchGroupStats(db, dateRange, filter, groupBy)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range groupEntries {
+			entries = append(entries, statsEntry{getGroupLabel(entry.Group), entry.NumEntries, entry.SecsSpent})
+		}
+
+		return entries, nil
+	}
+
+	taskEntries, err := fetchStats(db, dateRange, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range taskEntries {
+		entries = append(entries, statsEntry{entry.TaskSummary, entry.NumEntries, entry.SecsSpent})
+	}
+
+	return entries, nil
+}
+
+func getGroupHeader(groupBy types.GroupBy) string {
+	switch groupBy {
+	case types.GroupByTag:
+		return "Tag"
+	case types.GroupByProject:
+		return "Project"
+	case types.GroupByClient:
+		return "Client"
+	default:
+		return "Task"
+	}
+}
+
+func getStatsHeaders(groupBy types.GroupBy) []string {
+	return []string{getGroupHeader(groupBy), "#LogEntries", "TimeSpent"}
+}
+
+func getStatsJSON(db *sql.DB, dateRange *types.DateRange, filter types.TaskFilter, groupBy types.GroupBy) (string, error) {
+	if groupBy != types.GroupByTask {
+		entries, err := fetchGroupStats(db, dateRange, filter, groupBy)
+		if err != nil {
+			return "", err
+		}
+
+		if entries == nil {
+			entries = []domain.GroupReportEntry{}
+		}
+
+		return marshalJSON(entries)
+	}
+
+	entries, err := fetchStats(db, dateRange, filter)
+	if err != nil {
+		return "", err
+	}
+
+	if entries == nil {
+		entries = []domain.TaskReportEntry{}
+	}
+
+	return marshalJSON(entries)
+}
+
+func getStatsDoc(db *sql.DB, dateRange *types.DateRange, filter types.TaskFilter, groupBy types.GroupBy, withComments bool) (recordsDoc, error) {
+	doc := recordsDoc{
+		title:   getRecordsTitle("Stats", dateRange),
+		headers: getStatsHeaders(groupBy),
+	}
+
+	entries, err := fetchStatsEntries(db, dateRange, filter, groupBy)
+	if err != nil {
+		return doc, err
+	}
+
+	for _, entry := range entries {
+		doc.rows = append(doc.rows, []recordsCell{
+			{text: entry.label, task: entry.label},
+			{text: fmt.Sprintf("%d", entry.numEntries), task: entry.label},
+			{text: types.HumanizeDuration(entry.secsSpent), task: entry.label},
+		})
+	}
+
+	if withComments && dateRange != nil {
+		doc.withComments = true
+		doc.comments, err = fetchTLsWithComments(db, dateRange.Start, dateRange.End, filter)
+		if err != nil {
+			return doc, err
+		}
+	}
+
+	return doc, nil
+}
+
 func getStats(db *sql.DB,
 	style Style,
 	dateRange *types.DateRange,
-	taskStatus types.TaskStatus,
+	filter types.TaskFilter,
+	groupBy types.GroupBy,
 	plain bool) (string,
 	error,
 ) {
-	var entries []domain.TaskReportEntry
-	var err error
-
-	if dateRange == nil {
-		entries, err = pers.FetchStats(db, taskStatus, statsLogEntriesLimit)
-	} else {
-		entries, err = pers.FetchStatsBetweenTS(db, dateRange.Start, dateRange.End, taskStatus, statsLogEntriesLimit)
-	}
-
+	entries, err := fetchStatsEntries(db, dateRange, filter, groupBy)
 	if err != nil {
 		return "", err
 	}
@@ -120,29 +293,29 @@ func getStats(db *sql.DB,
 	styleCache := make(map[string]lipgloss.Style)
 
 	for i, entry := range entries {
-		timeSpentStr = types.HumanizeDuration(entry.SecsSpent)
+		timeSpentStr = types.HumanizeDuration(entry.secsSpent)
 
 		if plain {
 			data[i] = []string{
-				utils.RightPadTrim(entry.TaskSummary, 20, false),
-				fmt.Sprintf("%d", entry.NumEntries),
+				utils.RightPadTrim(entry.label, 20, false),
+				fmt.Sprintf("%d", entry.numEntries),
 				utils.RightPadTrim(timeSpentStr, statsTimeCharsBudget, false),
 			}
 		} else {
-			rowStyle, ok := styleCache[entry.TaskSummary]
+			rowStyle, ok := styleCache[entry.label]
 			if !ok {
-				rowStyle = style.getDynamicStyle(entry.TaskSummary)
-				styleCache[entry.TaskSummary] = rowStyle
+				rowStyle = style.getDynamicStyle(entry.label)
+				styleCache[entry.label] = rowStyle
 			}
 			data[i] = []string{
-				rowStyle.Render(utils.RightPadTrim(entry.TaskSummary, 20, false)),
-				rowStyle.Render(fmt.Sprintf("%d", entry.NumEntries)),
+				rowStyle.Render(utils.RightPadTrim(entry.label, 20, false)),
+				rowStyle.Render(fmt.Sprintf("%d", entry.numEntries)),
 				rowStyle.Render(utils.RightPadTrim(timeSpentStr, statsTimeCharsBudget, false)),
 			}
 		}
 	}
 
-	headerValues := []string{"Task", "#LogEntries", "TimeSpent"}
+	headerValues := getStatsHeaders(groupBy)
 	headers := make([]string, len(headerValues))
 	for i, h := range headerValues {
 		headers[i] = rs.headerStyle.Render(h)
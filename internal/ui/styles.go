@@ -28,6 +28,7 @@ type Style struct {
 	list                 lipgloss.Style
 	listItemDescColor    color.Color
 	listItemTitleColor   color.Color
+	movingTL             lipgloss.Style
 	recordsBorder        lipgloss.Style
 	recordsDateRange     lipgloss.Style
 	recordsFooter        lipgloss.Style
@@ -38,6 +39,8 @@ type Style struct {
 	taskLogEntryHeading  lipgloss.Style
 	trackedToday         lipgloss.Style
 	theme                theme.Theme
+	timebox              lipgloss.Style
+	timeboxOver          lipgloss.Style
 	titleForegroundColor color.Color
 	tlFormOkStyle        lipgloss.Style
 	tlFormWarnStyle      lipgloss.Style
@@ -93,6 +96,7 @@ func NewStyle(theme theme.Theme) Style {
 		list:                 baseList,
 		listItemDescColor:    lipgloss.Color(theme.ListItemDesc),
 		listItemTitleColor:   lipgloss.Color(theme.ListItemTitle),
+		movingTL:             footerItem.Foreground(lipgloss.Color(theme.TaskLogFormWarn)),
 		recordsBorder:        lipgloss.NewStyle().Foreground(lipgloss.Color(theme.RecordsBorder)),
 		recordsDateRange:     lipgloss.NewStyle().Foreground(lipgloss.Color(theme.RecordsDateRange)),
 		recordsFooter:        lipgloss.NewStyle().Foreground(lipgloss.Color(theme.RecordsFooter)),
@@ -103,6 +107,8 @@ func NewStyle(theme theme.Theme) Style {
 		taskLogEntryHeading:  baseHeading.Background(lipgloss.Color(theme.TaskLogEntry)),
 		trackedToday:         trackedToday,
 		theme:                theme,
+		timebox:              footerItem.Foreground(lipgloss.Color(theme.ActiveTaskBeginTime)),
+		timeboxOver:          footerItem.Foreground(lipgloss.Color(theme.TaskLogFormWarn)),
 		titleForegroundColor: lipgloss.Color(theme.TitleForeground),
 		tlFormOkStyle:        lipgloss.NewStyle().Foreground(lipgloss.Color(theme.TaskLogFormInfo)),
 		tlFormWarnStyle:      lipgloss.NewStyle().Foreground(lipgloss.Color(theme.TaskLogFormWarn)),
@@ -114,23 +120,26 @@ func NewStyle(theme theme.Theme) Style {
 }
 
 func (s *Style) getDynamicStyle(str string) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(s.getDynamicColor(str)))
+}
+
+// getDynamicColor picks one of the theme's task colors for a string, so that
+// the same task is always shown in the same color.
+func (s *Style) getDynamicColor(str string) string {
 	if len(s.theme.Tasks) == 0 {
-		return lipgloss.NewStyle().
-			Foreground(lipgloss.Color(fallbackTaskColor))
+		return fallbackTaskColor
 	}
 
 	h := fnv.New32()
 	_, err := h.Write([]byte(str))
 	if err != nil {
-		return lipgloss.NewStyle().
-			Foreground(lipgloss.Color(fallbackTaskColor))
+		return fallbackTaskColor
 	}
 
 	hash := h.Sum32()
 
-	color := s.theme.Tasks[hash%uint32(len(s.theme.Tasks))]
-	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color(color))
+	return s.theme.Tasks[hash%uint32(len(s.theme.Tasks))]
 }
 
 type reportStyles struct {
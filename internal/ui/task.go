@@ -2,8 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
 	"github.com/dhth/hours/internal/types"
 	"github.com/dhth/hours/internal/utils"
 	"github.com/dustin/go-humanize"
@@ -35,6 +37,29 @@ func (t *taskListItem) updateListDesc(timeProvider types.TimeProvider) {
 
 	lastUpdated := fmt.Sprintf("last updated: %s", humanize.RelTime(t.UpdatedAt, timeProvider.Now(), "ago", "from now"))
 	t.listDesc = fmt.Sprintf("%s %s", utils.RightPadTrim(lastUpdated, 60, true), timeSpent)
+	if t.Project != "" && t.Project != pers.UnassignedName {
+		t.listDesc += fmt.Sprintf("  (project: %s)", t.Project)
+	}
+	if len(t.Tags) > 0 {
+		t.listDesc += fmt.Sprintf("  (tags: %s)", strings.Join(t.Tags, ", "))
+	}
+	if t.BudgetSecs != nil {
+		t.listDesc += "  " + t.budgetProgress()
+	}
+}
+
+func (t *taskListItem) overBudget() bool {
+	return t.BudgetSecs != nil && t.SecsSpent > *t.BudgetSecs
+}
+
+// budgetProgress shows how much of a task's budget has been used up.
+func (t *taskListItem) budgetProgress() string {
+	budget := *t.BudgetSecs
+	if t.overBudget() {
+		return fmt.Sprintf("(over budget by %s; budget: %s)", types.HumanizeDuration(t.SecsSpent-budget), types.HumanizeDuration(budget))
+	}
+
+	return fmt.Sprintf("(budget: %s, %d%% used)", types.HumanizeDuration(budget), t.SecsSpent*100/budget)
 }
 
 func (t *taskListItem) Title() string {
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"image/color"
+	"io"
 
 	"charm.land/bubbles/v2/list"
 )
@@ -27,3 +28,29 @@ func newItemDelegate(titleColor, descColor, selectedColor color.Color) list.Defa
 
 	return d
 }
+
+// budgetDelegate highlights the descriptions of tasks that are over budget.
+type budgetDelegate struct {
+	list.DefaultDelegate
+	overBudget list.DefaultDelegate
+}
+
+func newBudgetDelegate(titleColor, descColor, selectedColor, overBudgetColor color.Color) budgetDelegate {
+	d := newItemDelegate(titleColor, descColor, selectedColor)
+
+	overBudget := d
+	overBudget.Styles.NormalDesc = overBudget.Styles.
+		NormalDesc.
+		Foreground(overBudgetColor)
+
+	return budgetDelegate{DefaultDelegate: d, overBudget: overBudget}
+}
+
+func (d budgetDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if task, ok := item.(*taskListItem); ok && task.overBudget() {
+		d.overBudget.Render(w, m, index, item)
+		return
+	}
+
+	d.DefaultDelegate.Render(w, m, index, item)
+}
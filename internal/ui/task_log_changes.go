@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+)
+
+func (m *Model) handleRequestToSplitTL() {
+	tl, ok := m.taskLogList.SelectedItem().(taskLogListItem)
+	if !ok {
+		return
+	}
+
+	// both parts need to be at least a minute long
+	midpoint := tl.BeginTS.Add(tl.EndTS.Sub(tl.BeginTS) / 2).Truncate(time.Minute)
+	if types.IsTaskLogDurationValid(tl.BeginTS, midpoint) != nil || types.IsTaskLogDurationValid(midpoint, tl.EndTS) != nil {
+		m.message = errMsg("Task log entry is too short to be split")
+		return
+	}
+
+	m.activeView = splitSavedTLView
+	m.tLInputs[entryBeginTS].SetValue(midpoint.Format(timeFormat))
+
+	m.blurTLTrackingInputs()
+	m.trackingFocussedField = entryBeginTS
+	m.tLInputs[entryBeginTS].Focus()
+}
+
+// tlSplit is a split of a task log entry that's waiting for the task the
+// second part is for to be picked.
+type tlSplit struct {
+	entry   domain.TaskLogEntry
+	splitTS time.Time
+}
+
+// handleSplitTSSubmitted lets the user pick the task the second part of the
+// selected task log entry is for from the task list; the entry's own task is
+// selected to begin with.
+func (m *Model) handleSplitTSSubmitted() {
+	tl, ok := m.taskLogList.SelectedItem().(taskLogListItem)
+	if !ok {
+		m.message = errMsg(genericErrorMsg)
+		return
+	}
+
+	splitTS, err := parseSplitTS(tl.TaskLogEntry, m.tLInputs[entryBeginTS].Value())
+	if err != nil {
+		return
+	}
+
+	m.blurTLTrackingInputs()
+	m.tLToSplit = &tlSplit{tl.TaskLogEntry, splitTS}
+	m.activeView = taskListView
+
+	for i, item := range m.activeTasksList.Items() {
+		if task, ok := item.(*taskListItem); ok && task.ID == tl.TaskID {
+			m.activeTasksList.Select(i)
+			break
+		}
+	}
+}
+
+func (m *Model) getCmdToSplitTL() tea.Cmd {
+	task, ok := m.activeTasksList.SelectedItem().(*taskListItem)
+	if !ok {
+		m.message = errMsg(msgCouldntSelectATask)
+		return nil
+	}
+
+	split := *m.tLToSplit
+	m.tLToSplit = nil
+	m.activeView = taskLogView
+
+	return splitTL(m.db, split.entry, split.splitTS, task.ID)
+}
+
+func (m *Model) cancelSplittingTL() {
+	m.tLToSplit = nil
+	m.activeView = taskLogView
+}
+
+func parseSplitTS(entry domain.TaskLogEntry, splitStr string) (time.Time, error) {
+	splitTS, err := time.ParseInLocation(timeFormat, splitStr, time.Local)
+	if err != nil {
+		return splitTS, err
+	}
+
+	err = types.IsTaskLogDurationValid(entry.BeginTS, splitTS)
+	if err != nil {
+		return splitTS, fmt.Errorf("first part: %w", err)
+	}
+
+	err = types.IsTaskLogDurationValid(splitTS, entry.EndTS)
+	if err != nil {
+		return splitTS, fmt.Errorf("second part: %w", err)
+	}
+
+	return splitTS, nil
+}
+
+func getSplitValidityContext(entry domain.TaskLogEntry, splitStr string) (string, tlFormValidity) {
+	splitTS, err := parseSplitTS(entry, splitStr)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err.Error()), tlSubmitErr
+	}
+
+	return fmt.Sprintf("You're splitting this entry into %s and %s",
+		types.HumanizeDuration(int(splitTS.Sub(entry.BeginTS).Seconds())),
+		types.HumanizeDuration(int(entry.EndTS.Sub(splitTS).Seconds())),
+	), tlSubmitOk
+}
+
+// getCmdToMergeTL merges the selected task log entry with the one before it
+// (ie, the one below it in the list).
+func (m *Model) getCmdToMergeTL() tea.Cmd {
+	tl, ok := m.taskLogList.SelectedItem().(taskLogListItem)
+	if !ok {
+		return nil
+	}
+
+	items := m.taskLogList.Items()
+	index := m.taskLogList.Index()
+	if index+1 >= len(items) {
+		m.message = errMsg("There's no earlier task log entry to merge this one with")
+		return nil
+	}
+
+	earlier, ok := items[index+1].(taskLogListItem)
+	if !ok {
+		m.message = errMsg(genericErrorMsg)
+		return nil
+	}
+
+	gap := tl.BeginTS.Sub(earlier.EndTS)
+	if gap > pers.MaxMergeGap {
+		m.message = errMsg(fmt.Sprintf("The gap of %s between these entries is longer than %s; they can't be merged",
+			types.HumanizeDuration(int(gap.Seconds())),
+			types.HumanizeDuration(int(pers.MaxMergeGap.Seconds())),
+		))
+		return nil
+	}
+
+	return mergeTLs(m.db, tl.TaskID, earlier.ID, tl.ID, gap)
+}
+
+// handleRequestToMoveTL lets the user pick the task to move the selected task
+// log entry to from the task list.
+func (m *Model) handleRequestToMoveTL() {
+	tl, ok := m.taskLogList.SelectedItem().(taskLogListItem)
+	if !ok {
+		return
+	}
+
+	m.tLToMove = &tl.TaskLogEntry
+	m.activeView = taskListView
+}
+
+func (m *Model) getCmdToMoveTL() tea.Cmd {
+	task, ok := m.activeTasksList.SelectedItem().(*taskListItem)
+	if !ok {
+		m.message = errMsg(msgCouldntSelectATask)
+		return nil
+	}
+
+	entry := *m.tLToMove
+	m.tLToMove = nil
+	m.activeView = taskLogView
+
+	return moveTL(m.db, entry, task.ID)
+}
+
+func (m *Model) cancelMovingTL() {
+	m.tLToMove = nil
+	m.activeView = taskLogView
+}
+
+func (m *Model) getCmdsToRefreshAfterTLChange(tlIDToFocusOn int, taskIDs ...int) []tea.Cmd {
+	var cmds []tea.Cmd
+	for _, taskID := range taskIDs {
+		if _, ok := m.taskMap[taskID]; ok {
+			cmds = append(cmds, fetchTaskTrackingData(m.db, taskID))
+		}
+	}
+	cmds = append(cmds, fetchTLS(m.db, &tlIDToFocusOn))
+
+	return cmds
+}
+
+func (m *Model) handleTLSplitMsg(msg tLSplitMsg) []tea.Cmd {
+	if m.changeFailed(msg.err) {
+		m.message = errMsg("Error splitting entry: " + msg.err.Error())
+		return nil
+	}
+
+	m.pushUndoableChange(msg.change)
+
+	return m.getCmdsToRefreshAfterTLChange(msg.newTLID, msg.entry.TaskID, msg.taskID)
+}
+
+func (m *Model) handleTLsMergedMsg(msg tLsMergedMsg) []tea.Cmd {
+	if m.changeFailed(msg.err) {
+		m.message = errMsg("Error merging entries: " + msg.err.Error())
+		return nil
+	}
+
+	m.pushUndoableChange(msg.change)
+	if msg.gap > 0 {
+		m.message = infoMsg(fmt.Sprintf("The gap of %s between the merged entries is recorded as a pause", types.HumanizeDuration(int(msg.gap.Seconds()))))
+	}
+
+	return m.getCmdsToRefreshAfterTLChange(msg.mergedID, msg.taskID)
+}
+
+func (m *Model) handleTLMovedMsg(msg tLMovedMsg) []tea.Cmd {
+	if m.changeFailed(msg.err) {
+		m.message = errMsg("Error moving entry: " + msg.err.Error())
+		return nil
+	}
+
+	m.pushUndoableChange(msg.change)
+
+	return m.getCmdsToRefreshAfterTLChange(msg.entry.ID, msg.entry.TaskID, msg.taskID)
+}
+
+func (m Model) getMovingTLFooterMsg() string {
+	switch {
+	case m.tLToMove != nil:
+		return m.style.movingTL.Render(fmt.Sprintf("moving entry #%d: pick a task (enter: move, esc: cancel)", m.tLToMove.ID))
+	case m.tLToSplit != nil:
+		return m.style.movingTL.Render(fmt.Sprintf("splitting entry #%d at %s: pick a task for the second part (enter: split, esc: cancel)",
+			m.tLToSplit.entry.ID,
+			m.tLToSplit.splitTS.Format(timeOnlyFormat),
+		))
+	default:
+		return ""
+	}
+}
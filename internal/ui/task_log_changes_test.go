@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"charm.land/bubbles/v2/list"
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSplitValidityContext(t *testing.T) {
+	entry := domain.TaskLogEntry{
+		BeginTS: time.Date(2025, 8, 16, 9, 0, 0, 0, time.Local),
+		EndTS:   time.Date(2025, 8, 16, 10, 30, 0, 0, time.Local),
+	}
+
+	testCases := []struct {
+		name             string
+		splitTS          string
+		expectedCtx      string
+		expectedValidity tlFormValidity
+	}{
+		{
+			name:             "within the entry",
+			splitTS:          "2025/08/16 10:00",
+			expectedCtx:      "You're splitting this entry into 1h and 30m",
+			expectedValidity: tlSubmitOk,
+		},
+		{
+			name:             "at the begin timestamp",
+			splitTS:          "2025/08/16 09:00",
+			expectedCtx:      "Error: first part: end time needs to be at least a minute after begin time",
+			expectedValidity: tlSubmitErr,
+		},
+		{
+			name:             "after the end timestamp",
+			splitTS:          "2025/08/16 11:00",
+			expectedCtx:      "Error: second part: end time is before begin time",
+			expectedValidity: tlSubmitErr,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCtx, gotValidity := getSplitValidityContext(entry, tt.splitTS)
+
+			assert.Equal(t, tt.expectedCtx, gotCtx)
+			assert.Equal(t, tt.expectedValidity, gotValidity)
+		})
+	}
+}
+
+func TestMergingNeedsAnEarlierEntry(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+	entry1 := createTestTaskLogEntry(2, 1, "Implement feature A", m.timeProvider)
+	entry2 := createTestTaskLogEntry(1, 1, "Implement feature A", m.timeProvider)
+	m.taskLogList.SetItems([]list.Item{entry1, entry2})
+
+	// WHEN
+	m.taskLogList.Select(0)
+	cmdWithEarlierEntry := m.getCmdToMergeTL()
+	m.taskLogList.Select(1)
+	cmdWithoutEarlierEntry := m.getCmdToMergeTL()
+
+	// THEN
+	assert.NotNil(t, cmdWithEarlierEntry)
+	assert.Nil(t, cmdWithoutEarlierEntry)
+	assert.Equal(t, userMsgErr, m.message.kind)
+}
+
+func TestMergingFailsForEntriesTooFarApart(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+	entry := createTestTaskLogEntry(2, 1, "Implement feature A", m.timeProvider)
+	earlier := createTestTaskLogEntry(1, 1, "Implement feature A", m.timeProvider)
+	earlier.BeginTS = entry.BeginTS.Add(-3 * time.Hour)
+	earlier.EndTS = entry.BeginTS.Add(-pers.MaxMergeGap - time.Minute)
+	m.taskLogList.SetItems([]list.Item{entry, earlier})
+
+	// WHEN
+	m.taskLogList.Select(0)
+	cmd := m.getCmdToMergeTL()
+
+	// THEN
+	assert.Nil(t, cmd)
+	assert.Equal(t, userMsgErr, m.message.kind)
+	assert.Contains(t, m.message.value, "The gap of 31m between these entries is longer than 30m")
+}
+
+func TestMergingAcrossAGapReportsThePause(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+
+	// WHEN
+	m.handleTLsMergedMsg(tLsMergedMsg{taskID: 1, mergedID: 1, gap: 15 * time.Minute})
+
+	// THEN
+	assert.Equal(t, infoMsg("The gap of 15m between the merged entries is recorded as a pause"), m.message)
+}
+
+func TestMovingTLCanBeCancelled(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+	entry := createTestTaskLogEntry(1, 1, "Implement feature A", m.timeProvider)
+	m.taskLogList.SetItems([]list.Item{entry})
+
+	// WHEN
+	m.handleRequestToMoveTL()
+	viewWhileMoving := m.activeView
+	require.NotNil(t, m.tLToMove)
+	shouldQuit := m.handleRequestToGoBackOrQuit()
+
+	// THEN
+	assert.Equal(t, taskListView, viewWhileMoving)
+	assert.False(t, shouldQuit)
+	assert.Nil(t, m.tLToMove)
+	assert.Equal(t, taskLogView, m.activeView)
+}
+
+func TestSplittingTLLetsTheTaskBePicked(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+	entry := createTestTaskLogEntry(1, 2, "Fix bug in module B", m.timeProvider)
+	m.taskLogList.SetItems([]list.Item{entry})
+	task := createTestTask(1, "Implement feature A", true, false, m.timeProvider)
+	otherTask := createTestTask(2, "Fix bug in module B", true, false, m.timeProvider)
+	m.activeTasksList.SetItems([]list.Item{task, otherTask})
+	m.handleRequestToSplitTL()
+
+	// WHEN
+	m.handleSplitTSSubmitted()
+
+	// THEN
+	require.NotNil(t, m.tLToSplit)
+	assert.Equal(t, taskListView, m.activeView)
+	assert.Equal(t, 1, m.activeTasksList.Index(), "the entry's own task should be selected")
+
+	// WHEN
+	m.activeTasksList.Select(0)
+	cmd := m.getCmdToSplitTL()
+
+	// THEN
+	assert.NotNil(t, cmd)
+	assert.Nil(t, m.tLToSplit)
+	assert.Equal(t, taskLogView, m.activeView)
+}
+
+func TestSplittingTLCanBeCancelled(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+	entry := createTestTaskLogEntry(1, 1, "Implement feature A", m.timeProvider)
+	m.taskLogList.SetItems([]list.Item{entry})
+	m.handleRequestToSplitTL()
+	m.handleSplitTSSubmitted()
+	require.NotNil(t, m.tLToSplit)
+
+	// WHEN
+	shouldQuit := m.handleRequestToGoBackOrQuit()
+
+	// THEN
+	assert.False(t, shouldQuit)
+	assert.Nil(t, m.tLToSplit)
+	assert.Equal(t, taskLogView, m.activeView)
+}
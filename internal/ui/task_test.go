@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetProgress(t *testing.T) {
+	budget := 36000
+	testCases := []struct {
+		name       string
+		secsSpent  int
+		expected   string
+		overBudget bool
+	}{
+		{name: "no time spent", secsSpent: 0, expected: "(budget: 10h, 0% used)"},
+		{name: "part of the budget used", secsSpent: 10800, expected: "(budget: 10h, 30% used)"},
+		{name: "budget used up exactly", secsSpent: 36000, expected: "(budget: 10h, 100% used)"},
+		{name: "over budget", secsSpent: 43200, expected: "(over budget by 2h; budget: 10h)", overBudget: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			item := taskListItem{Task: domain.Task{SecsSpent: tt.secsSpent, BudgetSecs: &budget}}
+
+			// WHEN
+			got := item.budgetProgress()
+
+			// THEN
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.overBudget, item.overBudget())
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+var errCouldntFetchTasks = errors.New("couldn't fetch tasks")
+
+const (
+	tasksLimit              = 10000
+	tasksSummaryCharsBudget = 40
+	tasksTimeCharsBudget    = 8
+	tasksProjectCharsBudget = 20
+	tasksTagsCharsBudget    = 24
+)
+
+func RenderTasks(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	outputFormat types.OutputFormat,
+	taskStatus types.TaskStatus,
+) error {
+	tasks, err := pers.FetchTasksWithStatus(db, taskStatus, tasksLimit)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchTasks, err.Error())
+	}
+
+	var output string
+	switch outputFormat {
+	case types.OutputFormatJSON:
+		output, err = getTasksJSON(tasks)
+	default:
+		output, err = getTasksTable(tasks, style, plain)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(writer, output)
+	return nil
+}
+
+func getTasksJSON(tasks []domain.Task) (string, error) {
+	if tasks == nil {
+		tasks = []domain.Task{}
+	}
+
+	for i := range tasks {
+		if tasks[i].Tags == nil {
+			tasks[i].Tags = []string{}
+		}
+	}
+
+	return marshalJSON(tasks)
+}
+
+func getTasksTable(tasks []domain.Task, style Style, plain bool) (string, error) {
+	var numEntriesInTable int
+	if len(tasks) == 0 {
+		numEntriesInTable = 1
+	} else {
+		numEntriesInTable = len(tasks)
+	}
+
+	data := make([][]string, numEntriesInTable)
+	if len(tasks) == 0 {
+		data[0] = []string{
+			"",
+			utils.RightPadTrim("", tasksSummaryCharsBudget, false),
+			"",
+			utils.RightPadTrim("", tasksTimeCharsBudget, false),
+			"",
+			"",
+			"",
+		}
+	}
+
+	rs := style.getReportStyles(plain)
+
+	for i, task := range tasks {
+		status := types.TSValueInactive
+		if task.Active {
+			status = types.TSValueActive
+		}
+
+		row := []string{
+			fmt.Sprintf("%d", task.ID),
+			utils.RightPadTrim(task.Summary, tasksSummaryCharsBudget, true),
+			status,
+			utils.RightPadTrim(types.HumanizeDuration(task.SecsSpent), tasksTimeCharsBudget, false),
+			task.UpdatedAt.Format(timeFormat),
+			utils.RightPadTrim(task.Project, tasksProjectCharsBudget, true),
+			utils.RightPadTrim(strings.Join(task.Tags, ","), tasksTagsCharsBudget, true),
+		}
+
+		if !plain {
+			rowStyle := style.getDynamicStyle(task.Summary)
+			for j, value := range row {
+				row[j] = rowStyle.Render(value)
+			}
+		}
+
+		data[i] = row
+	}
+
+	headerValues := []string{"ID", "Task", "Status", "TimeSpent", "UpdatedAt", "Project", "Tags"}
+	headers := make([]string, len(headerValues))
+	for i, h := range headerValues {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+	b := bytes.Buffer{}
+	table := tablewriter.NewTable(
+		&b,
+		tablewriter.WithConfig(tablewriter.Config{
+			Header: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment:  tw.AlignCenter,
+					AutoWrap:   tw.WrapNone,
+					AutoFormat: tw.Off,
+				},
+			},
+			Row: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment: tw.AlignLeft,
+					AutoWrap:  tw.WrapNone,
+				},
+			},
+		}),
+		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{Symbols: rs.symbols(tw.StyleASCII)})),
+		tablewriter.WithHeader(headers),
+	)
+
+	if err := table.Bulk(data); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntAddDataToTable, err.Error())
+	}
+
+	if err := table.Render(); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntRenderTable, err.Error())
+	}
+
+	return b.String(), nil
+}
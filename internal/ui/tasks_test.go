@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dhth/hours/internal/domain"
+	"github.com/dhth/hours/internal/ui/theme"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestTasks() []domain.Task {
+	return []domain.Task{
+		{
+			ID:        2,
+			Summary:   "write blog post",
+			CreatedAt: referenceTime.Add(-48 * time.Hour),
+			UpdatedAt: referenceTime,
+			SecsSpent: 5400,
+			Active:    true,
+			ProjectID: 2,
+			Project:   "blog",
+			Client:    "personal",
+			Rate:      &domain.Rate{Hundredths: 12050, Currency: "EUR"},
+			Billable:  true,
+			Tags:      []string{"blog", "writing"},
+		},
+		{
+			ID:        1,
+			Summary:   "a task with a summary long enough to be trimmed in the table",
+			CreatedAt: referenceTime.Add(-72 * time.Hour),
+			UpdatedAt: referenceTime.Add(-24 * time.Hour),
+			SecsSpent: 0,
+			Active:    false,
+			ProjectID: 1,
+			Project:   "unassigned",
+			Client:    "unassigned",
+		},
+	}
+}
+
+func TestGetTasksTablePlain(t *testing.T) {
+	// GIVEN
+	style := NewStyle(theme.Default())
+
+	// WHEN
+	result, err := getTasksTable(getTestTasks(), style, true)
+
+	// THEN
+	require.NoError(t, err)
+	snaps.MatchStandaloneSnapshot(t, result)
+}
+
+func TestGetTasksTableWithNoTasks(t *testing.T) {
+	// GIVEN
+	style := NewStyle(theme.Default())
+
+	// WHEN
+	result, err := getTasksTable(nil, style, true)
+
+	// THEN
+	require.NoError(t, err)
+	snaps.MatchStandaloneSnapshot(t, result)
+}
+
+func TestGetTasksJSON(t *testing.T) {
+	// GIVEN
+	// WHEN
+	result, err := getTasksJSON(getTestTasks())
+
+	// THEN
+	require.NoError(t, err)
+	snaps.MatchStandaloneSnapshot(t, result)
+}
+
+func TestGetTasksJSONWithNoTasks(t *testing.T) {
+	// GIVEN
+	// WHEN
+	result, err := getTasksJSON(nil)
+
+	// THEN
+	require.NoError(t, err)
+	snaps.MatchStandaloneSnapshot(t, result)
+}
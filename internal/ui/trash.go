@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/dhth/hours/internal/types"
+	"github.com/dhth/hours/internal/utils"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+var errCouldntFetchTrash = errors.New("couldn't fetch items in the trash")
+
+const emptyTrashMsg = "The trash is empty\n"
+
+// RenderTrash lists the tasks and task log entries in the trash.
+func RenderTrash(db *sql.DB,
+	style Style,
+	writer io.Writer,
+	plain bool,
+	outputFormat types.OutputFormat,
+) error {
+	items, err := pers.FetchTrash(db)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchTrash, err.Error())
+	}
+
+	var output string
+	switch {
+	case outputFormat == types.OutputFormatJSON:
+		if items == nil {
+			items = []pers.TrashedItem{}
+		}
+		output, err = marshalJSON(items)
+	case len(items) == 0:
+		output = emptyTrashMsg
+	default:
+		output, err = getTrashTable(items, style, plain)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errCouldntFetchTrash, err.Error())
+	}
+
+	fmt.Fprint(writer, output)
+	return nil
+}
+
+func getTrashTable(items []pers.TrashedItem, style Style, plain bool) (string, error) {
+	rs := style.getReportStyles(plain)
+
+	data := make([][]string, len(items))
+	for i, item := range items {
+		var period string
+		if item.BeginTS != nil && item.EndTS != nil {
+			period = fmt.Sprintf("%s  ...  %s", item.BeginTS.Format(timeFormat), item.EndTS.Format(timeFormat))
+		}
+
+		row := []string{
+			string(item.Kind),
+			fmt.Sprintf("%d", item.ID),
+			utils.RightPadTrim(item.TaskSummary, 20, false),
+			period,
+			types.HumanizeDuration(item.SecsSpent),
+			item.DeletedAt.Format(timeFormat),
+		}
+
+		if !plain {
+			rowStyle := style.getDynamicStyle(item.TaskSummary)
+			for j, value := range row {
+				row[j] = rowStyle.Render(value)
+			}
+		}
+
+		data[i] = row
+	}
+
+	headerValues := []string{"Kind", "ID", "Task", "Period", "TimeSpent", "DeletedAt"}
+	headers := make([]string, len(headerValues))
+	for i, h := range headerValues {
+		headers[i] = rs.headerStyle.Render(h)
+	}
+	b := bytes.Buffer{}
+	table := tablewriter.NewTable(
+		&b,
+		tablewriter.WithConfig(tablewriter.Config{
+			Header: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment:  tw.AlignCenter,
+					AutoWrap:   tw.WrapNone,
+					AutoFormat: tw.Off,
+				},
+			},
+			Row: tw.CellConfig{
+				Formatting: tw.CellFormatting{
+					Alignment: tw.AlignLeft,
+					AutoWrap:  tw.WrapNone,
+				},
+			},
+		}),
+		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{Symbols: rs.symbols(tw.StyleASCII)})),
+		tablewriter.WithHeader(headers),
+	)
+
+	if err := table.Bulk(data); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntAddDataToTable, err.Error())
+	}
+
+	if err := table.Render(); err != nil {
+		return "", fmt.Errorf("%w: %s", errCouldntRenderTable, err.Error())
+	}
+
+	return b.String(), nil
+}
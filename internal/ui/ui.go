@@ -16,7 +16,7 @@ var (
 	errCouldnCreateFramesDir      = errors.New("couldn't create frames directory")
 )
 
-func RenderUI(db *sql.DB, style Style, timeProvider types.TimeProvider) error {
+func RenderUI(db *sql.DB, style Style, timeProvider types.TimeProvider, pomodoroCfg PomodoroConfig, rejectOverlaps bool) error {
 	if len(os.Getenv("DEBUG")) > 0 {
 		f, err := tea.LogToFile("debug.log", "debug")
 		if err != nil {
@@ -44,6 +44,8 @@ func RenderUI(db *sql.DB, style Style, timeProvider types.TimeProvider) error {
 			db,
 			style,
 			timeProvider,
+			pomodoroCfg,
+			rejectOverlaps,
 			debug,
 			logFramesCfg,
 		),
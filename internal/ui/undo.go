@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	tea "charm.land/bubbletea/v2"
+	pers "github.com/dhth/hours/internal/persistence"
+)
+
+// only this many changes can be undone
+const undoHistoryLimit = 20
+
+var errChangeNotUndoable = errors.New("the change was made, but it can't be undone")
+
+// undoableChange holds the state of the task logs and tasks a change affected,
+// before and after it was made.
+type undoableChange struct {
+	// desc completes "Undid ..."/"Redid ..."
+	desc   string
+	before pers.Snapshot
+	after  pers.Snapshot
+}
+
+type changeUndoneMsg struct {
+	change undoableChange
+	redo   bool
+	err    error
+}
+
+// recordChange runs op between snapshots of the task logs and tasks it
+// affects; op returns the IDs of the task logs it creates, if any. If op
+// succeeds, but the state after it can't be captured, the error returned wraps
+// errChangeNotUndoable.
+func recordChange(db *sql.DB, desc string, tlIDs, taskIDs []int, op func() ([]int, error)) (*undoableChange, error) {
+	before, err := pers.TakeSnapshot(db, tlIDs, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	newTLIDs, err := op()
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := pers.TakeSnapshot(db, slices.Concat(tlIDs, newTLIDs), taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errChangeNotUndoable, err.Error())
+	}
+
+	return &undoableChange{desc, before, after}, nil
+}
+
+func undoChange(db *sql.DB, change undoableChange, redo bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if redo {
+			err = pers.RestoreSnapshot(db, change.before, change.after)
+		} else {
+			err = pers.RestoreSnapshot(db, change.after, change.before)
+		}
+		return changeUndoneMsg{change, redo, err}
+	}
+}
+
+func (m *Model) pushUndoableChange(change *undoableChange) {
+	if change == nil {
+		return
+	}
+
+	m.undoStack = append(m.undoStack, *change)
+	if len(m.undoStack) > undoHistoryLimit {
+		m.undoStack = m.undoStack[1:]
+	}
+	m.redoStack = nil
+}
+
+// changeFailed returns whether the error a change resulted in means that it
+// wasn't made; a change that was made, but can't be undone, is reported right
+// away.
+func (m *Model) changeFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, errChangeNotUndoable) {
+		m.message = errMsg(err.Error())
+		return false
+	}
+
+	return true
+}
+
+func (m *Model) getCmdToUndoChange(redo bool) tea.Cmd {
+	if m.changesLocked {
+		return nil
+	}
+
+	stack := &m.undoStack
+	if redo {
+		stack = &m.redoStack
+	}
+
+	if len(*stack) == 0 {
+		if redo {
+			m.message = errMsg("Nothing to redo")
+		} else {
+			m.message = errMsg("Nothing to undo")
+		}
+		return nil
+	}
+
+	change := (*stack)[len(*stack)-1]
+	*stack = (*stack)[:len(*stack)-1]
+	m.changesLocked = true
+
+	return undoChange(m.db, change, redo)
+}
+
+func (m *Model) handleChangeUndoneMsg(msg changeUndoneMsg) []tea.Cmd {
+	m.changesLocked = false
+
+	verb, done, restored := "undo", "Undid", msg.change.before
+	source, target := &m.undoStack, &m.redoStack
+	if msg.redo {
+		verb, done, restored = "redo", "Redid", msg.change.after
+		source, target = target, source
+	}
+
+	if msg.err != nil {
+		m.message = errMsg(fmt.Sprintf("Couldn't %s %s: %s", verb, msg.change.desc, msg.err.Error()))
+		// this can be tried again once nothing else is being tracked
+		if errors.Is(msg.err, pers.ErrAnotherTLActive) {
+			*source = append(*source, msg.change)
+		}
+		return nil
+	}
+
+	*target = append(*target, msg.change)
+	m.message = infoMsg(fmt.Sprintf("%s %s", done, msg.change.desc))
+
+	if msg.change.before.HasActiveTL() || msg.change.after.HasActiveTL() {
+		// the active task log is looked up again once tasks are fetched
+		m.lastTrackingChange = trackingFinished
+		m.trackingActive = false
+		m.activeTaskID = -1
+		m.activeTLComment = nil
+		m.activeTLPauses = nil
+		if !restored.HasActiveTL() {
+			m.pomodoro = pomodoroState{tickID: m.pomodoro.tickID}
+		}
+	}
+
+	return []tea.Cmd{
+		fetchTasks(m.db, true),
+		fetchTasks(m.db, false),
+		fetchTLS(m.db, nil),
+	}
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dhth/hours/internal/domain"
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushingUndoableChanges(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.redoStack = []undoableChange{{desc: "deleting task log entry #1"}}
+
+	// WHEN
+	for range undoHistoryLimit + 1 {
+		m.pushUndoableChange(&undoableChange{desc: "deleting task log entry #2"})
+	}
+	m.pushUndoableChange(nil)
+
+	// THEN
+	assert.Len(t, m.undoStack, undoHistoryLimit)
+	assert.Empty(t, m.redoStack)
+}
+
+func TestUndoingWithNothingToUndo(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+
+	// WHEN
+	cmd := m.getCmdToUndoChange(false)
+
+	// THEN
+	assert.Nil(t, cmd)
+	assert.Equal(t, errMsg("Nothing to undo"), m.message)
+	assert.False(t, m.changesLocked)
+}
+
+func TestUndoingAndRedoingAChange(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	change := undoableChange{desc: "deleting task log entry #1"}
+	m.pushUndoableChange(&change)
+
+	// WHEN
+	cmd := m.getCmdToUndoChange(false)
+
+	// THEN
+	require.NotNil(t, cmd)
+	assert.Empty(t, m.undoStack)
+	assert.True(t, m.changesLocked)
+
+	// WHEN
+	cmds := m.handleChangeUndoneMsg(changeUndoneMsg{change: change})
+
+	// THEN
+	assert.NotEmpty(t, cmds)
+	assert.False(t, m.changesLocked)
+	assert.Equal(t, infoMsg("Undid deleting task log entry #1"), m.message)
+	require.Len(t, m.redoStack, 1)
+
+	// WHEN
+	cmd = m.getCmdToUndoChange(true)
+	m.handleChangeUndoneMsg(changeUndoneMsg{change: change, redo: true})
+
+	// THEN
+	require.NotNil(t, cmd)
+	assert.Equal(t, infoMsg("Redid deleting task log entry #1"), m.message)
+	assert.Empty(t, m.redoStack)
+	assert.Len(t, m.undoStack, 1)
+}
+
+func TestFailingToUndoAChange(t *testing.T) {
+	testCases := []struct {
+		name              string
+		err               error
+		expectedUndoStack int
+	}{
+		{
+			name:              "change can be undone later",
+			err:               pers.ErrAnotherTLActive,
+			expectedUndoStack: 1,
+		},
+		{
+			name:              "change can't be undone anymore",
+			err:               pers.ErrSnapshotOutdated,
+			expectedUndoStack: 0,
+		},
+		{
+			name:              "unexpected error",
+			err:               errors.New("disk I/O error"),
+			expectedUndoStack: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			m := createTestModel()
+			change := undoableChange{desc: "discarding the active recording"}
+			m.pushUndoableChange(&change)
+			m.getCmdToUndoChange(false)
+
+			// WHEN
+			cmds := m.handleChangeUndoneMsg(changeUndoneMsg{change: change, err: tt.err})
+
+			// THEN
+			assert.Nil(t, cmds)
+			assert.Equal(t, errMsg("Couldn't undo discarding the active recording: "+tt.err.Error()), m.message)
+			assert.Len(t, m.undoStack, tt.expectedUndoStack)
+			assert.Empty(t, m.redoStack)
+		})
+	}
+}
+
+func TestHandlingAChangeThatCantBeUndone(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	err := fmt.Errorf("%w: %s", errChangeNotUndoable, "database is locked")
+	msg := tLDeletedMsg{entry: &domain.TaskLogEntry{ID: 1, TaskID: 1}, err: err}
+
+	// WHEN
+	cmds := m.handleTLDeleted(msg)
+
+	// THEN
+	assert.NotEmpty(t, cmds, "the task logs should still be refreshed")
+	assert.Equal(t, errMsg("the change was made, but it can't be undone: database is locked"), m.message)
+	assert.Empty(t, m.undoStack)
+}
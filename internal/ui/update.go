@@ -21,6 +21,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	model, cmd := m.processMessage(msg)
 	model.recalculateSecondsTrackedToday()
 
+	if overlapsCmd := model.getCmdToCheckTLOverlaps(); overlapsCmd != nil {
+		cmd = tea.Batch(cmd, overlapsCmd)
+	}
+
 	return model, cmd
 }
 
@@ -35,6 +39,9 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 		cmds = append(cmds, tickTimeTrackedToday(30*time.Second))
 	case tea.WindowSizeMsg:
 		m.handleWindowResizing(msg)
+	case tLOverlapsFetchedMsg:
+		m.handleTLOverlapsFetchedMsg(msg)
+		return m, nil
 	case tea.KeyPressMsg:
 		if msg.String() == ctrlC {
 			return m, tea.Quit
@@ -94,6 +101,10 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 				updateCmd = m.getCmdToFinishTrackingActiveTL()
 			case manualTasklogEntryView, editSavedTLView:
 				updateCmd = m.getCmdToCreateOrEditTL()
+			case splitSavedTLView:
+				// the key press is done with once the task list is shown
+				m.handleSplitTSSubmitted()
+				return m, tea.Batch(cmds...)
 			}
 			if updateCmd != nil {
 				cmds = append(cmds, updateCmd)
@@ -101,7 +112,7 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		case escape:
 			switch m.activeView {
-			case taskInputView, editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			case taskInputView, editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, splitSavedTLView:
 				m.handleEscapeInForms()
 				return m, tea.Batch(cmds...)
 			}
@@ -111,7 +122,7 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			m.goBackwardInView()
 		case "k":
 			switch m.activeView {
-			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, splitSavedTLView:
 				err := m.shiftTime(shiftBackward, shiftMinute)
 				if err != nil {
 					return m, tea.Batch(cmds...)
@@ -119,7 +130,7 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		case "j":
 			switch m.activeView {
-			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, splitSavedTLView:
 				err := m.shiftTime(shiftForward, shiftMinute)
 				if err != nil {
 					return m, tea.Batch(cmds...)
@@ -127,7 +138,7 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		case "K":
 			switch m.activeView {
-			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, splitSavedTLView:
 				err := m.shiftTime(shiftBackward, shiftFiveMinutes)
 				if err != nil {
 					return m, tea.Batch(cmds...)
@@ -135,7 +146,7 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		case "J":
 			switch m.activeView {
-			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, splitSavedTLView:
 				err := m.shiftTime(shiftForward, shiftFiveMinutes)
 				if err != nil {
 					return m, tea.Batch(cmds...)
@@ -143,7 +154,7 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		case "h":
 			switch m.activeView {
-			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, splitSavedTLView:
 				err := m.shiftTime(shiftBackward, shiftDay)
 				if err != nil {
 					return m, tea.Batch(cmds...)
@@ -154,7 +165,7 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		case "l":
 			switch m.activeView {
-			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+			case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, splitSavedTLView:
 				err := m.shiftTime(shiftForward, shiftDay)
 				if err != nil {
 					return m, tea.Batch(cmds...)
@@ -173,7 +184,7 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 		return m, tea.Batch(cmds...)
-	case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+	case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, splitSavedTLView:
 		for i := range m.tLInputs {
 			m.tLInputs[i], cmd = m.tLInputs[i].Update(msg)
 			cmds = append(cmds, cmd)
@@ -260,7 +271,38 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			if m.activeView == taskListView && m.trackingActive {
 				cmds = append(cmds, deleteActiveTL(m.db))
 			}
+		case "ctrl+z", "ctrl+y":
+			if m.activeView != taskListView && m.activeView != taskLogView && m.activeView != inactiveTaskListView {
+				break
+			}
+
+			undoCmd := m.getCmdToUndoChange(msg.String() == "ctrl+y")
+			if undoCmd != nil {
+				cmds = append(cmds, undoCmd)
+			}
+		case enter:
+			if m.activeView != taskListView {
+				break
+			}
+
+			switch {
+			case m.tLToMove != nil:
+				moveCmd := m.getCmdToMoveTL()
+				if moveCmd != nil {
+					cmds = append(cmds, moveCmd)
+				}
+			case m.tLToSplit != nil:
+				splitCmd := m.getCmdToSplitTL()
+				if splitCmd != nil {
+					cmds = append(cmds, splitCmd)
+				}
+			}
 		case "s":
+			if m.activeView == taskLogView {
+				m.handleRequestToSplitTL()
+				break
+			}
+
 			if m.activeView == taskListView {
 				switch m.lastTrackingChange {
 				case trackingFinished:
@@ -284,6 +326,31 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			if m.activeView == taskListView {
 				m.handleRequestToCreateTask()
 			}
+		case "p":
+			if m.activeView == taskListView {
+				m.handleRequestToFilterByProject()
+			}
+		case "P":
+			if m.activeView == taskListView {
+				pomodoroCmd := m.handleRequestToToggleTimebox()
+				if pomodoroCmd != nil {
+					cmds = append(cmds, pomodoroCmd)
+				}
+			}
+		case "c":
+			if m.activeView == taskListView {
+				pomodoroCmd := m.handleRequestToContinueTimebox()
+				if pomodoroCmd != nil {
+					cmds = append(cmds, pomodoroCmd)
+				}
+			}
+		case "ctrl+p":
+			if m.activeView == taskListView {
+				pauseCmd := m.getCmdToToggleActiveTLPause()
+				if pauseCmd != nil {
+					cmds = append(cmds, pauseCmd)
+				}
+			}
 		case "k":
 			m.handleRequestToScrollVPUp()
 		case "j":
@@ -292,6 +359,17 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			if m.activeView == taskLogView {
 				m.handleRequestToViewTLDetails()
 			}
+		case "m":
+			if m.activeView == taskLogView {
+				m.handleRequestToMoveTL()
+			}
+		case "M":
+			if m.activeView == taskLogView {
+				mergeCmd := m.getCmdToMergeTL()
+				if mergeCmd != nil {
+					cmds = append(cmds, mergeCmd)
+				}
+			}
 		case "?":
 			m.lastView = m.activeView
 			m.activeView = helpView
@@ -307,7 +385,9 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			m.message = errMsg(fmt.Sprintf("Error updating task: %s", msg.err))
 		} else {
 			msg.tsk.Summary = msg.summary
+			msg.tsk.Tags = msg.tags
 			msg.tsk.updateListTitle()
+			msg.tsk.updateListDesc(m.timeProvider)
 		}
 	case tasksFetchedMsg:
 		handleCmd := m.handleTasksFetchedMsg(msg)
@@ -315,11 +395,12 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			cmds = append(cmds, handleCmd)
 		}
 	case activeTLUpdatedMsg:
-		if msg.err != nil {
+		if m.changeFailed(msg.err) {
 			m.message = errMsg(msg.err.Error())
 		} else {
 			m.activeTLBeginTS = msg.beginTS
 			m.activeTLComment = msg.comment
+			m.pushUndoableChange(msg.change)
 		}
 	case manualTLInsertedMsg:
 		handleCmds := m.handleManualTLInsertedMsg(msg)
@@ -333,6 +414,24 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 		}
 	case tLsFetchedMsg:
 		m.handleTLSFetchedMsg(msg)
+		cmds = append(cmds, fetchGoalProgress(m.db, m.timeProvider.Now()))
+	case pomodoroTickMsg:
+		pomodoroCmd := m.handlePomodoroTickMsg(msg)
+		if pomodoroCmd != nil {
+			cmds = append(cmds, pomodoroCmd)
+		}
+	case breakStartedMsg:
+		handleCmd := m.handleBreakStartedMsg(msg)
+		if handleCmd != nil {
+			cmds = append(cmds, handleCmd)
+		}
+	case goalProgressFetchedMsg:
+		if msg.err != nil {
+			m.message = errMsg("Error fetching goals: " + msg.err.Error())
+		} else {
+			m.goals = msg.goals
+			m.weekTaskLogs = msg.weekEntries
+		}
 	case activeTaskFetchedMsg:
 		m.handleActiveTaskFetchedMsg(msg)
 	case trackingToggledMsg:
@@ -358,6 +457,21 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 			task.UpdatedAt = msg.updatedAt
 			task.updateListDesc(m.timeProvider)
 		}
+	case tLSplitMsg:
+		updateCmds := m.handleTLSplitMsg(msg)
+		if updateCmds != nil {
+			cmds = append(cmds, updateCmds...)
+		}
+	case tLsMergedMsg:
+		updateCmds := m.handleTLsMergedMsg(msg)
+		if updateCmds != nil {
+			cmds = append(cmds, updateCmds...)
+		}
+	case tLMovedMsg:
+		updateCmds := m.handleTLMovedMsg(msg)
+		if updateCmds != nil {
+			cmds = append(cmds, updateCmds...)
+		}
 	case tLDeletedMsg:
 		updateCmds := m.handleTLDeleted(msg)
 		if updateCmds != nil {
@@ -365,13 +479,21 @@ func (m Model) processMessage(msg tea.Msg) (Model, tea.Cmd) {
 		}
 	case activeTaskLogDeletedMsg:
 		m.handleActiveTLDeletedMsg(msg)
+	case activeTLPauseToggledMsg:
+		m.handleActiveTLPauseToggledMsg(msg)
 	case taskActiveStatusUpdatedMsg:
-		if msg.err != nil {
+		if m.changeFailed(msg.err) {
 			m.message = errMsg("Error updating task's active status: " + msg.err.Error())
 		} else {
+			m.pushUndoableChange(msg.change)
 			cmds = append(cmds, fetchTasks(m.db, true))
 			cmds = append(cmds, fetchTasks(m.db, false))
 		}
+	case changeUndoneMsg:
+		updateCmds := m.handleChangeUndoneMsg(msg)
+		if updateCmds != nil {
+			cmds = append(cmds, updateCmds...)
+		}
 	case hideHelpMsg:
 		m.showHelpIndicator = false
 	}
@@ -418,7 +540,7 @@ func (m recordsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				dr.NumDays = m.dateRange.NumDays
 				dr.End = dr.Start.AddDate(0, 0, m.dateRange.NumDays)
-				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, dr, m.taskStatus, m.plain))
+				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, dr, m.filter, m.groupBy, m.plain))
 				m.busy = true
 			}
 		case "right", "l":
@@ -439,7 +561,7 @@ func (m recordsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				dr.NumDays = m.dateRange.NumDays
 				dr.End = dr.Start.AddDate(0, 0, dr.NumDays)
-				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, dr, m.taskStatus, m.plain))
+				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, dr, m.filter, m.groupBy, m.plain))
 				m.busy = true
 			}
 		case "ctrl+t":
@@ -462,7 +584,7 @@ func (m recordsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				dr.NumDays = m.dateRange.NumDays
 				dr.End = dr.Start.AddDate(0, 0, dr.NumDays)
-				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, dr, m.taskStatus, m.plain))
+				cmds = append(cmds, getRecordsData(m.kind, m.db, m.style, dr, m.filter, m.groupBy, m.plain))
 				m.busy = true
 			}
 		}
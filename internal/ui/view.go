@@ -8,6 +8,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/dhth/hours/internal/domain"
 	"github.com/dhth/hours/internal/types"
 	"github.com/dhth/hours/internal/utils"
 )
@@ -46,7 +47,9 @@ func (m Model) View() tea.View {
 		task, ok := m.taskMap[m.activeTaskID]
 		if ok {
 			taskSummaryMsg = utils.Trim(task.Summary, 50)
-			if m.activeView != finishActiveTLView {
+			if pausedAt := domain.PausedAt(m.activeTLPauses); pausedAt != nil {
+				taskStartedSinceMsg = fmt.Sprintf("(paused since %s)", pausedAt.Format(timeOnlyFormat))
+			} else if m.activeView != finishActiveTLView {
 				taskStartedSinceMsg = fmt.Sprintf("(since %s)", m.activeTLBeginTS.Format(timeOnlyFormat))
 			}
 		}
@@ -62,6 +65,7 @@ func (m Model) View() tea.View {
 	formBeginTimeHelp := "Begin Time* (format: 2006/01/02 15:04)"
 	formEndTimeHelp := "End Time* (format: 2006/01/02 15:04)"
 	formTimeShiftHelp := "(j/k/J/K/h/l moves time)"
+	formSplitTimeHelp := "Split At* (format: 2006/01/02 15:04)"
 
 	var formCommentContext string
 	if m.tLCommentInput.Length() == 0 {
@@ -85,13 +89,43 @@ func (m Model) View() tea.View {
 		case tlSubmitErr:
 			submissionCtx = m.style.tlFormErrStyle.Render(durationCtx)
 		}
+
+		if submissionValidity != tlSubmitErr && len(m.tLOverlaps.entries) > 0 {
+			overlapsRejected := m.rejectOverlaps && m.activeView != finishActiveTLView
+			overlapsCtx := getOverlapsContext(m.tLOverlaps.entries, overlapsRejected)
+			if overlapsRejected {
+				submissionCtx += "; " + m.style.tlFormErrStyle.Render(overlapsCtx)
+			} else {
+				submissionCtx += "; " + m.style.tlFormWarnStyle.Render(overlapsCtx)
+			}
+		}
+	}
+
+	var splitEntryCtx string
+	if m.activeView == splitSavedTLView {
+		if tl, ok := m.taskLogList.SelectedItem().(taskLogListItem); ok {
+			splitEntryCtx = fmt.Sprintf("Splitting entry for %q (%s ... %s)",
+				utils.Trim(tl.TaskSummary, 20),
+				tl.BeginTS.Format(timeFormat),
+				tl.EndTS.Format(timeFormat),
+			)
+
+			var splitCtx string
+			splitCtx, submissionValidity = getSplitValidityContext(tl.TaskLogEntry, m.tLInputs[entryBeginTS].Value())
+			switch submissionValidity {
+			case tlSubmitErr:
+				submissionCtx = m.style.tlFormErrStyle.Render(splitCtx)
+			default:
+				submissionCtx = m.style.tlFormOkStyle.Render(splitCtx)
+			}
+		}
 	}
 
 	var formSubmitHelp string
 	switch m.activeView {
 	case taskInputView:
 		formSubmitHelp = "Press <ctrl+s>/<enter> to submit"
-	case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView:
+	case editActiveTLView, finishActiveTLView, manualTasklogEntryView, editSavedTLView, splitSavedTLView:
 		if submissionValidity != tlSubmitErr {
 			if m.trackingFocussedField == entryComment {
 				formSubmitHelp = m.style.formHelp.Render("Press <ctrl+s> to submit")
@@ -130,12 +164,15 @@ func (m Model) View() tea.View {
   %s
 
   %s
+
+  %s
 `,
 			m.style.taskEntryHeading.Render(formTitle),
 			m.taskInputs[summaryField].View(),
+			m.taskInputs[tagsField].View(),
 			m.style.formHelp.Render(formSubmitHelp),
 		)
-		for range m.terminalHeight - 9 {
+		for range m.terminalHeight - 11 {
 			content += "\n"
 		}
 	case finishActiveTLView:
@@ -263,6 +300,35 @@ func (m Model) View() tea.View {
 		for range m.terminalHeight - 34 {
 			content += "\n"
 		}
+	case splitSavedTLView:
+		content = fmt.Sprintf(
+			`
+  %s
+
+  %s
+
+  %s
+
+  %s
+
+  %s    %s
+
+  %s
+
+  %s
+`,
+			m.style.taskLogEntryHeading.Render(taskLogEntryViewHeading),
+			m.style.formContext.Render(splitEntryCtx),
+			m.style.formHelp.Render("Enter the timestamp to split the entry at; the task for the second part is picked next. Press esc to go back."),
+			m.style.formFieldName.Render(formSplitTimeHelp),
+			m.tLInputs[entryBeginTS].View(),
+			m.style.formHelp.Render(formTimeShiftHelp),
+			submissionCtx,
+			formSubmitHelp,
+		)
+		for range m.terminalHeight - 18 {
+			content += "\n"
+		}
 	case helpView:
 		if !m.helpVPReady {
 			content = "\n  Initializing..."
@@ -306,19 +372,26 @@ func (m Model) View() tea.View {
 	}
 
 	var trackedTodayMsg string
-	if m.secsTrackedToday >= 60 {
+	if m.goals.DailySecs != nil {
+		trackedTodayMsg = m.style.trackedToday.Render("tracked today: " + describeGoalProgress(m.secsTrackedToday, *m.goals.DailySecs))
+	} else if m.secsTrackedToday >= 60 {
 		trackedTodayMsg = m.style.trackedToday.Render(fmt.Sprintf(
 			"tracked today: %s",
 			types.HumanizeDuration(m.secsTrackedToday),
 		))
 	}
+	if m.goals.WeeklySecs != nil {
+		trackedTodayMsg += m.style.trackedToday.Render("this week: " + describeGoalProgress(m.secsTrackedThisWeek, *m.goals.WeeklySecs))
+	}
 
 	footer = fmt.Sprintf(
-		"%s%s%s%s",
+		"%s%s%s%s%s%s",
 		m.style.toolName.Render("hours"),
 		helpMsg,
 		trackedTodayMsg,
 		activeMsg,
+		m.getPomodoroFooterMsg(),
+		m.getMovingTLFooterMsg(),
 	)
 
 	if m.debug {
@@ -179,6 +179,66 @@ func TestTimeTrackedTodayInFooter(t *testing.T) {
 	snaps.MatchStandaloneSnapshot(t, result)
 }
 
+func TestGoalProgressInFooter(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+	dailyGoal := 6 * 60 * 60
+	weeklyGoal := 30 * 60 * 60
+	m.goals = domain.Goals{DailySecs: &dailyGoal, WeeklySecs: &weeklyGoal}
+	m.secsTrackedToday = 90 * 60
+	m.secsTrackedThisWeek = 31 * 60 * 60
+
+	entry1 := createTestTaskLogEntry(1, 1, "Implement feature A", m.timeProvider)
+	m.taskLogList.SetItems([]list.Item{entry1})
+
+	// WHEN
+	result := stripANSI(m.View().Content)
+
+	// THEN
+	snaps.MatchStandaloneSnapshot(t, result)
+}
+
+func TestTimeboxInFooter(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskListView
+	task := createTestTask(1, "Implement feature A", true, true, m.timeProvider)
+	m.activeTasksList.SetItems([]list.Item{task})
+	m.taskMap[task.ID] = task
+	m.tasksFetched = true
+	m.trackingActive = true
+	m.activeTaskID = task.ID
+	m.activeTLBeginTS = referenceTime.Add(-5 * time.Minute)
+	m.pomodoro = pomodoroState{phase: pomodoroTimebox, endsAt: referenceTime.Add(20*time.Minute + 30*time.Second)}
+
+	// WHEN
+	result := stripANSI(m.View().Content)
+
+	// THEN
+	snaps.MatchStandaloneSnapshot(t, result)
+}
+
+func TestPausedTrackingInFooter(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskListView
+	task := createTestTask(1, "Implement feature A", true, true, m.timeProvider)
+	m.activeTasksList.SetItems([]list.Item{task})
+	m.taskMap[task.ID] = task
+	m.tasksFetched = true
+	m.trackingActive = true
+	m.activeTaskID = task.ID
+	m.activeTLBeginTS = referenceTime.Add(-time.Hour)
+	m.activeTLPauses = []domain.TaskLogPause{{BeginTS: referenceTime.Add(-20 * time.Minute)}}
+
+	// WHEN
+	result := stripANSI(m.View().Content)
+
+	// THEN
+	snaps.MatchStandaloneSnapshot(t, result)
+}
+
 func TestCreateTaskViewWithNoInput(t *testing.T) {
 	// GIVEN
 	m := createTestModel()
@@ -268,6 +328,30 @@ func TestManualTasklogEntryView(t *testing.T) {
 	snaps.MatchStandaloneSnapshot(t, result)
 }
 
+func TestManualTasklogEntryViewWithOverlaps(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = manualTasklogEntryView
+	m.tasklogSaveType = tasklogInsert
+
+	m.tLInputs[entryBeginTS].SetValue("2025/08/17 09:00")
+	m.tLInputs[entryEndTS].SetValue("2025/08/17 10:30")
+	m.tLOverlaps.entries = []domain.TaskLogEntry{
+		{
+			ID:          1,
+			TaskSummary: "Implement feature A",
+			BeginTS:     time.Date(2025, 8, 17, 8, 30, 0, 0, time.Local),
+			EndTS:       time.Date(2025, 8, 17, 9, 15, 0, 0, time.Local),
+		},
+	}
+
+	// WHEN
+	result := stripANSI(m.View().Content)
+
+	// THEN
+	snaps.MatchStandaloneSnapshot(t, result)
+}
+
 func TestEditSavedTLView(t *testing.T) {
 	// GIVEN
 	m := createTestModel()
@@ -285,6 +369,40 @@ func TestEditSavedTLView(t *testing.T) {
 	snaps.MatchStandaloneSnapshot(t, result)
 }
 
+func TestSplitSavedTLView(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+	entry := createTestTaskLogEntry(1, 1, "Implement feature A", m.timeProvider)
+	m.taskLogList.SetItems([]list.Item{entry})
+	m.handleRequestToSplitTL()
+
+	// WHEN
+	result := stripANSI(m.View().Content)
+
+	// THEN
+	snaps.MatchStandaloneSnapshot(t, result)
+}
+
+func TestMovingTLInFooter(t *testing.T) {
+	// GIVEN
+	m := createTestModel()
+	m.activeView = taskLogView
+	entry := createTestTaskLogEntry(1, 1, "Implement feature A", m.timeProvider)
+	m.taskLogList.SetItems([]list.Item{entry})
+	task := createTestTask(2, "Fix bug in module B", true, false, m.timeProvider)
+	m.activeTasksList.SetItems([]list.Item{task})
+	m.taskMap[task.ID] = task
+	m.tasksFetched = true
+	m.handleRequestToMoveTL()
+
+	// WHEN
+	result := stripANSI(m.View().Content)
+
+	// THEN
+	snaps.MatchStandaloneSnapshot(t, result)
+}
+
 func TestHelpView(t *testing.T) {
 	// GIVEN
 	m := createTestModel()
@@ -455,7 +573,7 @@ func createTestModel() Model {
 	style := NewStyle(defaultTheme)
 
 	testTimeProvider := types.TestTimeProvider{FixedTime: referenceTime}
-	m := InitialModel(nil, style, testTimeProvider, false, logFramesConfig{})
+	m := InitialModel(nil, style, testTimeProvider, PomodoroConfig{TimeboxDuration: 25 * time.Minute, BreakDuration: 5 * time.Minute}, false, false, logFramesConfig{})
 
 	msg := tea.WindowSizeMsg{
 		Width:  96,
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActive(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	startTracking := []string{"start", "rust", "--at", "10:30", "--comment", "fix bug"}
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "json output when nothing is active", args: []string{"active", "--output", "json"}},
+		{name: "start tracking", args: startTracking},
+		{name: "template output", setup: [][]string{startTracking}, args: []string{"active", "--template", "{{task}} ({{time}})"}},
+		{name: "json output", setup: [][]string{startTracking}, args: []string{"active", "--output", "json"}},
+		{name: "incorrect output format", setup: [][]string{startTracking}, args: []string{"active", "--output", "yaml"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	withBudgets := [][]string{
+		{"task", "set-budget", "1", "10h"},
+		{"task", "set-budget", "2", "1h30m"},
+		{"task", "add", "write release notes", "--budget", "2h"},
+	}
+	withAnUnsetBudget := slices.Concat(withBudgets, [][]string{[]string{"task", "set-budget", "2", "none"}})
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "budget stats without budgets works", args: []string{"stats", "--budget", "--plain", "week"}},
+		{name: "set budget works", args: []string{"task", "set-budget", "1", "10h"}},
+		{name: "set a small budget works", args: []string{"task", "set-budget", "2", "1h30m"}},
+		{name: "set budget fails for incorrect duration", args: []string{"task", "set-budget", "1", "10"}},
+		{name: "set budget fails for too short a duration", args: []string{"task", "set-budget", "1", "30s"}},
+		{name: "set budget fails for unknown task", args: []string{"task", "set-budget", "999", "10h"}},
+		{name: "add task with budget works", args: []string{"task", "add", "write release notes", "--budget", "2h"}},
+		{name: "add task fails for incorrect budget", args: []string{"task", "add", "write changelog", "--budget", "two hours"}},
+		{name: "budget stats work", setup: withBudgets, args: []string{"stats", "--budget", "--plain", "week"}},
+		{name: "budget stats for all time work", setup: withBudgets, args: []string{"stats", "--budget", "--plain", "all"}},
+		{name: "budget stats as JSON work", setup: withBudgets, args: []string{"stats", "--budget", "--output", "json", "week"}},
+		{name: "budget stats as markdown work", setup: withBudgets, args: []string{"stats", "--budget", "--output", "markdown", "week"}},
+		{name: "budget stats fail when grouped by project", args: []string{"stats", "--budget", "--group-by", "project", "week"}},
+		{name: "unset budget works", setup: withBudgets, args: []string{"task", "set-budget", "2", "none"}},
+		{name: "budget stats don't show tasks with unset budgets", setup: withAnUnsetBudget, args: []string{"stats", "--budget", "--plain", "week"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
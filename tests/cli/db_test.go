@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite" // sqlite driver
+)
+
+func TestDBBackupAndRestore(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	backup := []string{"db", "backup"}
+	addTask := []string{"task", "add", "not in the backup"}
+	withBackups := []setupCmd{
+		{args: backup},
+		{args: backup, now: now.Add(time.Hour)},
+		{args: []string{"db", "backup", "--keep", "2"}, now: now.Add(2 * time.Hour)},
+	}
+	withTaskAdded := slices.Concat(withBackups, []setupCmd{{args: addTask}})
+	withDBRestored := slices.Concat(withTaskAdded, []setupCmd{
+		{args: []string{"db", "restore", "<TEMP_DIR>/hours-backups/hours-20251024T130000.db"}, now: now.Add(3 * time.Hour)},
+	})
+
+	testCases := []struct {
+		name  string
+		setup []setupCmd
+		args  []string
+		now   time.Time
+	}{
+		{name: "backup works", args: backup},
+		{name: "backup fails if the file exists", setup: withBackups[:1], args: backup},
+		{name: "another backup", setup: withBackups[:1], args: backup, now: now.Add(time.Hour)},
+		{name: "backup removes old backups", setup: withBackups[:2], args: []string{"db", "backup", "--keep", "2"}, now: now.Add(2 * time.Hour)},
+		{name: "backup works with a file path", args: []string{"db", "backup", "<TEMP_DIR>/snapshot.db"}},
+		{name: "backup fails with a file path and keep", args: []string{"db", "backup", "<TEMP_DIR>/other.db", "--keep", "2"}},
+		{name: "adding a task after backing up", setup: withBackups, args: addTask},
+		{name: "restore works", setup: withTaskAdded, args: []string{"db", "restore", "<TEMP_DIR>/hours-backups/hours-20251024T130000.db"}, now: now.Add(3 * time.Hour)},
+		{name: "restored database doesn't have the new task", setup: withDBRestored, args: addTask},
+		{name: "restore fails for a file that isn't an hours database", args: []string{"db", "restore", "<TEMP_DIR>/not-a-db.db"}},
+		{name: "restore fails for a missing file", args: []string{"db", "restore", "<TEMP_DIR>/absent.db"}},
+		{name: "restore fails for the live database", args: []string{"db", "restore", "<TEMP_DIR>/hours.db"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			fx.WriteFile(t, "not-a-db.db", "task,begin,end\n")
+			fx.runSetupCmds(t, now, tc.setup)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", cmp.Or(tc.now, now).Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+// createFirstReleaseDB creates a database the way the first release of hours
+// did, which needs migrating.
+func createFirstReleaseDB(t *testing.T, fx Fixture) {
+	t.Helper()
+
+	db, err := pers.GetDB(fx.Path("hours.db"))
+	require.NoError(t, err)
+	require.NoError(t, pers.InitDB(db))
+	require.NoError(t, db.Close())
+}
+
+func TestDBMigrate(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	dryRun := []string{"db", "migrate", "--dry-run"}
+	migrate := []string{"db", "migrate"}
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "dry run lists and checks pending migrations", args: dryRun},
+		{name: "dry run doesn't change the database", setup: [][]string{dryRun}, args: dryRun},
+		{name: "migrate works", args: migrate},
+		{name: "migrate reports when there are no pending migrations", setup: [][]string{migrate}, args: dryRun},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			createFirstReleaseDB(t, fx)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestDBMigrateBacksUpTheDatabaseOnce(t *testing.T) {
+	fx := NewFixture(t, testBinaryPath)
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	createFirstReleaseDB(t, fx)
+
+	fx.RunSetup(t, now,
+		[]string{"db", "migrate", "--dry-run"},
+		[]string{"db", "migrate"},
+		[]string{"db", "migrate"},
+	)
+
+	backups, err := filepath.Glob(fx.Path("hours-backups/hours-*-pre-migration-v1.db"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+}
+
+func TestDBMigrateFailsForMissingDB(t *testing.T) {
+	fx := NewFixture(t, testBinaryPath)
+	cmd := NewCmd([]string{"db", "migrate"})
+	cmd.UseDB()
+
+	result, err := fx.RunCmd(cmd)
+
+	require.NoError(t, err)
+	snaps.MatchStandaloneSnapshot(t, result)
+}
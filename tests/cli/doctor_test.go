@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	pers "github.com/dhth/hours/internal/persistence"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoctor(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	breakDB := func(t *testing.T, fx Fixture) {
+		t.Helper()
+
+		db, err := pers.GetDB(fx.Path("hours.db"))
+		require.NoError(t, err)
+		defer db.Close()
+
+		_, err = db.Exec(`UPDATE task SET secs_spent = secs_spent + 600 WHERE id = 1;`)
+		require.NoError(t, err)
+		_, err = db.Exec(`
+INSERT INTO task_log (task_id, begin_ts, end_ts, secs_spent, active)
+VALUES (999, ?, ?, 1800, false), (2, ?, ?, -1800, false);
+`,
+			now.Add(-4*time.Hour), now.Add(-3*time.Hour-30*time.Minute),
+			now.Add(-2*time.Hour), now.Add(-2*time.Hour-30*time.Minute),
+		)
+		require.NoError(t, err)
+	}
+
+	// doctor exits with an error as long as problems are left, so it can't be
+	// run with RunSetup
+	breakAndFixDB := func(t *testing.T, fx Fixture) {
+		t.Helper()
+
+		breakDB(t, fx)
+		cmd := NewCmd([]string{"doctor", "--fix"})
+		cmd.UseDB()
+		_, err := fx.RunCmd(cmd)
+		require.NoError(t, err)
+	}
+
+	testCases := []struct {
+		name  string
+		setup func(t *testing.T, fx Fixture)
+		args  []string
+	}{
+		{name: "doctor finds no problems", args: []string{"doctor"}},
+		{name: "doctor reports problems", setup: breakDB, args: []string{"doctor"}},
+		{name: "doctor fixes fixable problems", setup: breakDB, args: []string{"doctor", "--fix"}},
+		{name: "doctor reports problems that can't be fixed", setup: breakAndFixDB, args: []string{"doctor", "--fix"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			if tc.setup != nil {
+				tc.setup(t, fx)
+			}
+
+			cmd := NewCmd(tc.args)
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
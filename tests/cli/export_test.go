@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	fx := NewFixture(t, testBinaryPath)
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	_, err := fx.RunGen(42, now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{name: "csv", args: []string{"csv"}},
+		{name: "csv for date range", args: []string{"csv", "2025/10/22...2025/10/24"}},
+		{name: "tsv", args: []string{"tsv", "yest"}},
+		{name: "ics", args: []string{"ics"}},
+		{name: "csv for inactive tasks", args: []string{"csv", "--task-status", "inactive"}},
+		{name: "incorrect argument", args: []string{"csv", "blah"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewCmd([]string{"export"})
+			cmd.AddArgs(tc.args...)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
@@ -2,12 +2,14 @@ package cli
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -56,7 +58,12 @@ func NewFixture(t *testing.T, binPath string) Fixture {
 }
 
 func (f Fixture) RunCmd(cmd HoursCmd) (string, error) {
-	argsToUse := append([]string{}, cmd.args...)
+	// args can refer to files in the temp directory the same way the output
+	// does
+	argsToUse := make([]string, 0, len(cmd.args))
+	for _, arg := range cmd.args {
+		argsToUse = append(argsToUse, strings.ReplaceAll(arg, "<TEMP_DIR>", f.tempDir))
+	}
 	if cmd.useDB {
 		dbPath := filepath.Join(f.tempDir, "hours.db")
 		argsToUse = append(argsToUse, "--dbpath", dbPath)
@@ -98,13 +105,17 @@ func (f Fixture) RunCmd(cmd HoursCmd) (string, error) {
 		}
 	}
 
+	// paths in the temp directory change between runs
+	stdout := strings.ReplaceAll(stdoutBuf.String(), f.tempDir, "<TEMP_DIR>")
+	stderr := strings.ReplaceAll(stderrBuf.String(), f.tempDir, "<TEMP_DIR>")
+
 	output := fmt.Sprintf(`success: %t
 exit_code: %d
 ----- stdout -----
 %s
 ----- stderr -----
 %s
-`, success, exitCode, stdoutBuf.String(), stderrBuf.String())
+`, success, exitCode, stdout, stderr)
 
 	return output, nil
 }
@@ -117,3 +128,55 @@ func (f Fixture) RunGen(seed int64, now time.Time) (string, error) {
 
 	return f.RunCmd(cmd)
 }
+
+// RunSetup runs commands that a test depends on, and fails the test if any of
+// them doesn't succeed.
+func (f Fixture) RunSetup(t *testing.T, now time.Time, cmds ...[]string) {
+	t.Helper()
+
+	for _, args := range cmds {
+		cmd := NewCmd(args)
+		cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+		cmd.UseDB()
+
+		result, err := f.RunCmd(cmd)
+		if err != nil {
+			t.Fatalf("couldn't run setup command %q: %s", args, err.Error())
+		}
+		if !strings.HasPrefix(result, "success: true") {
+			t.Fatalf("setup command %q failed:\n%s", args, result)
+		}
+	}
+}
+
+// setupCmd is a command a test depends on, for tests where commands need to
+// run at different times.
+type setupCmd struct {
+	args []string
+	// now defaults to the test's
+	now time.Time
+}
+
+func (f Fixture) runSetupCmds(t *testing.T, now time.Time, cmds []setupCmd) {
+	t.Helper()
+
+	for _, cmd := range cmds {
+		f.RunSetup(t, cmp.Or(cmd.now, now), cmd.args)
+	}
+}
+
+func (f Fixture) Path(name string) string {
+	return filepath.Join(f.tempDir, name)
+}
+
+func (f Fixture) WriteFile(t *testing.T, name, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(f.tempDir, name)
+	err := os.WriteFile(path, []byte(contents), 0o644)
+	if err != nil {
+		t.Fatalf("couldn't write file %q: %s", name, err.Error())
+	}
+
+	return path
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoals(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	withGoals := [][]string{{"goals", "set", "--daily", "6h", "--weekly", "30h"}}
+	withoutAWeeklyGoal := slices.Concat(withGoals, [][]string{[]string{"goals", "set", "--weekly", "none"}})
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "goals fail without a daily goal", args: []string{"goals"}},
+		{name: "set goals fails without goals", args: []string{"goals", "set"}},
+		{name: "set goals fails for incorrect duration", args: []string{"goals", "set", "--daily", "6"}},
+		{name: "set goals fails for too large a daily goal", args: []string{"goals", "set", "--daily", "25h"}},
+		{name: "set goals works", args: []string{"goals", "set", "--daily", "6h", "--weekly", "30h"}},
+		{name: "goals work", setup: withGoals, args: []string{"goals", "--plain"}},
+		{name: "goals for a date range work", setup: withGoals, args: []string{"goals", "--plain", "2025/10/01...2025/10/10"}},
+		{name: "goals as JSON work", setup: withGoals, args: []string{"goals", "--output", "json", "3d"}},
+		{name: "unset weekly goal works", setup: withGoals, args: []string{"goals", "set", "--weekly", "none"}},
+		{name: "goals without a weekly goal work", setup: withoutAWeeklyGoal, args: []string{"goals", "--plain", "today"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
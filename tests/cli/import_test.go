@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImport(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	files := map[string]string{
+		"with-invalid-rows.csv": `task_summary,begin,end,comment
+rust,2025-10-20 09:00:00,2025-10-20 10:00:00,fix bug
+rust,2025-10-20 09:00:00,2025-10-20 09:00:30,
+rust,2025/10/20 11:00,2025-10-20 12:00:00,
+,2025-10-20 13:00:00,2025-10-20 14:00:00,
+`,
+		"valid.csv": `task_summary,begin,end,comment
+rust,2025-10-20 09:00:00,2025-10-20 10:00:00,fix bug
+python,2025-10-20 10:00:00,2025-10-20 11:30:00,"write script
+
+with a multi-line comment"
+python,2025-10-20 10:00:00,2025-10-20 11:30:00,
+clojure,2025-10-23 07:16:05,2025-10-23 08:04:05,
+`,
+		"custom-layout.tsv": "Project\tNotes\tStart\tStop\n" +
+			"go\treview PRs\t2025-10-21T09:00:00Z\t2025-10-21T10:15:00Z\n",
+	}
+	withInvalidRows := "<TEMP_DIR>/with-invalid-rows.csv"
+	valid := "<TEMP_DIR>/valid.csv"
+	customLayout := "<TEMP_DIR>/custom-layout.tsv"
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "dry run reports invalid rows", args: []string{"import", "csv", withInvalidRows, "--dry-run"}},
+		{name: "import fails when rows are invalid", args: []string{"import", "csv", withInvalidRows}},
+		{name: "dry run reports new tasks and duplicates", args: []string{"import", "csv", valid, "--dry-run"}},
+		{name: "import works", args: []string{"import", "csv", valid}},
+		{name: "importing again skips duplicates", setup: [][]string{{"import", "csv", valid}}, args: []string{"import", "csv", valid}},
+		{name: "log shows imported entries", setup: [][]string{{"import", "csv", valid}}, args: []string{"log", "2025/10/20", "--plain"}},
+		{
+			name: "import works with custom column mapping",
+			args: []string{
+				"import", "csv", customLayout,
+				"--delimiter", "tab",
+				"--task-col", "project",
+				"--begin-col", "start",
+				"--end-col", "stop",
+				"--comment-col", "notes",
+				"--time-format", time.RFC3339,
+			},
+		},
+		{name: "import fails for missing column", args: []string{"import", "csv", customLayout, "--delimiter", "tab"}},
+		{name: "import fails for invalid delimiter", args: []string{"import", "csv", valid, "--delimiter", ";;"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			for name, contents := range files {
+				fx.WriteFile(t, name, contents)
+			}
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestImportTimew(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	files := map[string]string{
+		"timew.json": `[
+{"id":3,"start":"20251023T080000Z","end":"20251023T093000Z","tags":["go","review"],"annotation":"look at PRs"},
+{"id":2,"start":"20251023T100000Z","end":"20251023T103000Z","tags":["rust"]},
+{"id":1,"start":"20251024T110000Z","tags":["go"],"annotation":"fix bug"}
+]`,
+		"timew-later.json": `[
+{"id":1,"start":"20251022T080000Z","end":"20251022T093000Z","tags":["go"]}
+]`,
+	}
+	export := "<TEMP_DIR>/timew.json"
+	laterExport := "<TEMP_DIR>/timew-later.json"
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "dry run", args: []string{"import", "timew", export, "--dry-run", "--task-from", "tags"}},
+		{name: "import works", args: []string{"import", "timew", export}},
+		{name: "active shows open interval", setup: [][]string{{"import", "timew", export}}, args: []string{"active"}},
+		{name: "log shows imported entries", setup: [][]string{{"import", "timew", export}}, args: []string{"log", "2025/10/23", "--plain"}},
+		{name: "importing again skips duplicates", setup: [][]string{{"import", "timew", export}}, args: []string{"import", "timew", export}},
+		{name: "import works while a task is being tracked", setup: [][]string{{"import", "timew", export}}, args: []string{"import", "timew", laterExport}},
+		{name: "import fails for incorrect task source", args: []string{"import", "timew", export, "--task-from", "tag"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			for name, contents := range files {
+				fx.WriteFile(t, name, contents)
+			}
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestImportToggl(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	files := map[string]string{
+		"toggl.csv": `User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount (USD)
+jane,jane@example.com,,hours,,review PRs,No,2025-10-21,09:00:00,2025-10-21,10:30:00,01:30:00,,
+jane,jane@example.com,,hours,,,No,2025-10-21,11:00:00,,,00:45:00,,
+`,
+		"toggl.json": `{"data": [
+{"id": 101, "project": "hours", "description": "fix bug", "start": "2025-10-22T09:00:00Z", "end": "2025-10-22T10:00:00Z", "dur": 3600000},
+{"id": 102, "project": "blog", "description": "", "start": "2025-10-22T11:00:00Z", "dur": 1800000}
+]}`,
+	}
+	csvExport := "<TEMP_DIR>/toggl.csv"
+	jsonExport := "<TEMP_DIR>/toggl.json"
+	withTogglImports := [][]string{{"import", "toggl", csvExport}, {"import", "toggl", jsonExport}}
+	withImportedEntryEdited := slices.Concat(withTogglImports, [][]string{{"log", "edit", "229", "--begin", "2025/10/21 08:30"}})
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "dry run for csv", args: []string{"import", "toggl", csvExport, "--dry-run"}},
+		{name: "import works for csv", args: []string{"import", "toggl", csvExport}},
+		{name: "import works for json", setup: [][]string{{"import", "toggl", csvExport}}, args: []string{"import", "toggl", jsonExport}},
+		{name: "editing an imported entry", setup: withTogglImports, args: []string{"log", "edit", "229", "--begin", "2025/10/21 08:30"}},
+		{name: "importing again skips entries imported before", setup: withImportedEntryEdited, args: []string{"import", "toggl", csvExport}},
+		{name: "importing json again skips entries imported before", setup: withImportedEntryEdited, args: []string{"import", "toggl", jsonExport}},
+		{name: "log shows imported entries", setup: withImportedEntryEdited, args: []string{"log", "2025/10/21...2025/10/22", "--plain"}},
+		{name: "import fails for incorrect format", args: []string{"import", "toggl", jsonExport, "--format", "xml"}},
+		{name: "import fails for mismatched format", args: []string{"import", "toggl", jsonExport, "--format", "csv"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			for name, contents := range files {
+				fx.WriteFile(t, name, contents)
+			}
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestImportICS(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	files := map[string]string{
+		"calendar.ics": `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//test//EN
+BEGIN:VEVENT
+UID:standup
+DTSTART:20251020T053000Z
+DTEND:20251020T054500Z
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR
+EXDATE:20251022T053000Z
+SUMMARY:Daily standup
+END:VEVENT
+BEGIN:VEVENT
+UID:one-on-one
+DTSTART:20251021T060000Z
+DTEND:20251021T064500Z
+SUMMARY:1:1 with Alex
+END:VEVENT
+BEGIN:VEVENT
+UID:offsite
+DTSTART;VALUE=DATE:20251023
+SUMMARY:Offsite
+END:VEVENT
+BEGIN:VEVENT
+UID:planning
+DTSTART:20251023T100000Z
+DTEND:20251023T110000Z
+SUMMARY:Sprint planning
+END:VEVENT
+BEGIN:VEVENT
+UID:retro
+DTSTART:20251024T113000Z
+DTEND:20251024T123000Z
+SUMMARY:Retro
+END:VEVENT
+END:VCALENDAR
+`,
+	}
+	calendar := "<TEMP_DIR>/calendar.ics"
+	period := "2025/10/20...2025/10/24"
+	icsImport := []string{"import", "ics", calendar, period, "--rule", "(?i)standup|planning=meetings", "--create-tasks"}
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "import fails without rules", args: []string{"import", "ics", calendar, period}},
+		{name: "import fails for invalid rule", args: []string{"import", "ics", calendar, period, "--rule", "standup"}},
+		{name: "dry run with rules", args: []string{"import", "ics", calendar, period, "--rule", "(?i)standup|planning=meetings", "--rule", "^1:1 with (.+)$=1:1s with $1", "--dry-run"}},
+		{name: "dry run with create tasks", args: []string{"import", "ics", calendar, period, "--create-tasks", "--dry-run"}},
+		{name: "import works", args: icsImport},
+		{name: "importing again skips events imported before", setup: [][]string{icsImport}, args: []string{"import", "ics", calendar, period, "--create-tasks"}},
+		{name: "log shows imported entries", setup: [][]string{icsImport}, args: []string{"log", period, "--plain"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			for name, contents := range files {
+				fx.WriteFile(t, name, contents)
+			}
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoice(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	addProject := []string{"project", "add", "website", "--client", "acme"}
+	withTasksInProject := [][]string{
+		addProject,
+		{"task", "set-project", "1", "website"},
+		{"task", "set-project", "4", "website"},
+		{"task", "set-project", "5", "website"},
+	}
+	withRates := slices.Concat(withTasksInProject, [][]string{
+		{"project", "set-rate", "website", "120.5", "--currency", "eur"},
+		{"task", "set-rate", "4", "99", "--currency", "USD"},
+		{"task", "non-billable", "5"},
+	})
+	withRatesChanged := slices.Concat(withRates, [][]string{
+		{"task", "set-rate", "4", "none"},
+		{"task", "billable", "5"},
+	})
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "add project", args: addProject},
+		{name: "move a task to the project", setup: [][]string{addProject}, args: []string{"task", "set-project", "1", "website"}},
+		{name: "move another task to the project", setup: [][]string{addProject}, args: []string{"task", "set-project", "4", "website"}},
+		{name: "move a third task to the project", setup: [][]string{addProject}, args: []string{"task", "set-project", "5", "website"}},
+		{name: "invoice fails if tasks have no rate", setup: withTasksInProject, args: []string{"invoice", "--client", "acme", "week"}},
+		{name: "set project rate fails without currency", setup: [][]string{addProject}, args: []string{"project", "set-rate", "website", "120"}},
+		{name: "set project rate fails for incorrect rate", setup: [][]string{addProject}, args: []string{"project", "set-rate", "website", "12,5", "--currency", "EUR"}},
+		{name: "set project rate works", setup: [][]string{addProject}, args: []string{"project", "set-rate", "website", "120.5", "--currency", "eur"}},
+		{name: "set task rate works", args: []string{"task", "set-rate", "4", "99", "--currency", "USD"}},
+		{name: "mark task as non-billable works", args: []string{"task", "non-billable", "5"}},
+		{name: "list projects shows rates", setup: withRates, args: []string{"project", "list", "--plain"}},
+		{name: "invoice works", setup: withRates, args: []string{"invoice", "--client", "ACME", "week"}},
+		{name: "invoice with rounding works", setup: withRates, args: []string{"invoice", "--client", "acme", "--round-to", "15m", "--rounding", "up", "week"}},
+		{name: "invoice as JSON works", setup: withRates, args: []string{"invoice", "--client", "acme", "--output", "json", "week"}},
+		{name: "invoice for a period with no entries works", setup: withRates, args: []string{"invoice", "--client", "acme", "2025/09/01"}},
+		{name: "unset task rate works", setup: [][]string{{"task", "set-rate", "4", "99", "--currency", "USD"}}, args: []string{"task", "set-rate", "4", "none"}},
+		{name: "mark task as billable works", setup: [][]string{{"task", "non-billable", "5"}}, args: []string{"task", "billable", "5"}},
+		{name: "invoice uses project rate once task rate is unset", setup: withRatesChanged, args: []string{"invoice", "--client", "acme", "week"}},
+		{name: "invoice fails without client", args: []string{"invoice", "week"}},
+		{name: "invoice fails for unknown client", setup: withRates, args: []string{"invoice", "--client", "globex", "week"}},
+		{name: "invoice fails for table output", setup: withRates, args: []string{"invoice", "--client", "acme", "--output", "table", "week"}},
+		{name: "invoice fails for incorrect rounding", setup: withRates, args: []string{"invoice", "--client", "acme", "--rounding", "sideways", "week"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"slices"
 	"testing"
 	"time"
 
@@ -44,3 +45,238 @@ func TestLog(t *testing.T) {
 		})
 	}
 }
+
+func TestLogEntryManagement(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	addTask := []string{"task", "add", "write blog post"}
+	addEntry := []string{"log", "add", "write", "--begin", "09:00", "--end", "10:30", "--comment", "outline"}
+	addAnotherEntry := []string{"log", "add", "1", "--begin", "2025/10/24 10:45", "--end", "2025/10/24 11:15"}
+	editEnd := []string{"log", "edit", "1", "--end", "10:00"}
+	withEntries := [][]string{addTask, addEntry, addAnotherEntry}
+	withCommentEdited := slices.Concat(withEntries, [][]string{editEnd, {"log", "edit", "2", "--comment", "proofread"}})
+	withEdits := slices.Concat(withCommentEdited, [][]string{{"log", "edit", "1", "--comment", ""}})
+	withEntryRemoved := slices.Concat(withEdits, [][]string{{"log", "rm", "2"}})
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "add task", args: addTask},
+		{name: "add works", setup: withEntries[:1], args: addEntry},
+		{name: "add works with full timestamps", setup: withEntries[:2], args: addAnotherEntry},
+		{name: "add fails for end before begin", setup: withEntries, args: []string{"log", "add", "1", "--begin", "10:00", "--end", "09:00"}},
+		{name: "add fails for short duration", setup: withEntries, args: []string{"log", "add", "1", "--begin", "10:00", "--end", "10:00"}},
+		{name: "add fails for invalid begin", setup: withEntries, args: []string{"log", "add", "1", "--begin", "10", "--end", "11:00"}},
+		{name: "add fails for missing end", setup: withEntries, args: []string{"log", "add", "1", "--begin", "10:00"}},
+		{name: "add fails for unknown task", setup: withEntries, args: []string{"log", "add", "deploy", "--begin", "10:00", "--end", "11:00"}},
+		{name: "edit works", setup: withEntries, args: editEnd},
+		{name: "edit works for comment", setup: withEntries, args: []string{"log", "edit", "2", "--comment", "proofread"}},
+		{name: "edit can clear comment", setup: withCommentEdited, args: []string{"log", "edit", "1", "--comment", ""}},
+		{name: "edit fails when nothing is provided", setup: withEdits, args: []string{"log", "edit", "1"}},
+		{name: "edit fails for invalid duration", setup: withEdits, args: []string{"log", "edit", "1", "--begin", "10:30"}},
+		{name: "edit fails for unknown entry", setup: withEdits, args: []string{"log", "edit", "10", "--end", "10:00"}},
+		{name: "edit fails for invalid ID", setup: withEdits, args: []string{"log", "edit", "abc", "--end", "10:00"}},
+		{name: "log shows entries with IDs", setup: withEdits, args: []string{"log", "--plain"}},
+		{name: "rm works", setup: withEdits, args: []string{"log", "rm", "2"}},
+		{name: "rm fails for deleted entry", setup: withEntryRemoved, args: []string{"log", "rm", "2"}},
+		{name: "stats reflect changes", setup: withEntryRemoved, args: []string{"stats", "all", "--plain"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestLogJSON(t *testing.T) {
+	fx := NewFixture(t, testBinaryPath)
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	_, err := fx.RunGen(42, now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		period string
+	}{
+		{name: "today", period: "today"},
+		{name: "no entries", period: "2025/09/01"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewCmd([]string{"log", "--output", "json"})
+			cmd.AddArgs(tc.period)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestLogDocuments(t *testing.T) {
+	fx := NewFixture(t, testBinaryPath)
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	_, err := fx.RunGen(42, now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{name: "markdown", args: []string{"today", "--output", "markdown"}},
+		{name: "html with comments", args: []string{"today", "--output", "html", "--with-comments"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewCmd([]string{"log"})
+			cmd.AddArgs(tc.args...)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestLogOverlaps(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	addTask := []string{"task", "add", "write blog post"}
+	addAnotherTask := []string{"task", "add", "review PRs"}
+	addEntry := []string{"log", "add", "write", "--begin", "09:00", "--end", "10:30"}
+	addOverlappingEntry := []string{"log", "add", "review", "--begin", "10:00", "--end", "11:00"}
+	addEntryAfterwards := []string{"log", "add", "review", "--begin", "11:30", "--end", "12:00"}
+	editOverlappingEntry := []string{"log", "edit", "2", "--end", "11:45"}
+	start := []string{"start", "write", "--at", "11:50"}
+	addOverlappingActiveEntry := []string{"log", "add", "write", "--begin", "11:40", "--end", "11:55"}
+	withOverlap := [][]string{addTask, addAnotherTask, addEntry, addOverlappingEntry}
+	withOverlapResolved := slices.Concat(withOverlap, [][]string{{"log", "edit", "1", "--begin", "08:45", "--end", "10:00", "--reject-overlaps"}})
+	withEntryAfterwards := slices.Concat(withOverlapResolved, [][]string{addEntryAfterwards})
+	withNewOverlap := slices.Concat(withEntryAfterwards, [][]string{editOverlappingEntry})
+	withTracking := slices.Concat(withNewOverlap, [][]string{start})
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "add task", args: addTask},
+		{name: "add another task", setup: withOverlap[:1], args: addAnotherTask},
+		{name: "add works", setup: withOverlap[:2], args: addEntry},
+		{name: "add warns about overlaps", setup: withOverlap[:3], args: addOverlappingEntry},
+		{name: "add rejects overlaps when asked to", setup: withOverlap, args: []string{"log", "add", "review", "--begin", "08:30", "--end", "09:30", "--reject-overlaps"}},
+		{name: "edit rejects overlaps when asked to", setup: withOverlap, args: []string{"log", "edit", "1", "--end", "10:45", "--reject-overlaps"}},
+		{name: "edit leaves out the entry being edited", setup: withOverlap, args: []string{"log", "edit", "1", "--begin", "08:45", "--end", "10:00", "--reject-overlaps"}},
+		{name: "overlaps shows no overlaps", setup: withOverlapResolved, args: []string{"log", "overlaps", "today", "--plain"}},
+		{name: "add works with overlaps", setup: withOverlapResolved, args: addEntryAfterwards},
+		{name: "edit warns about overlaps", setup: withEntryAfterwards, args: editOverlappingEntry},
+		{name: "overlaps works", setup: withNewOverlap, args: []string{"log", "overlaps", "today", "--plain"}},
+		{name: "overlaps works for json output", setup: withNewOverlap, args: []string{"log", "overlaps", "today", "--output", "json"}},
+		{name: "overlaps fails for incorrect argument", args: []string{"log", "overlaps", "blah"}},
+		{name: "start works", setup: withNewOverlap, args: start},
+		{name: "add warns about overlapping the active entry", setup: withTracking, args: addOverlappingActiveEntry},
+		{name: "add rejects overlapping the active entry when asked to", setup: slices.Concat(withTracking, [][]string{addOverlappingActiveEntry}), args: []string{"log", "add", "write", "--begin", "11:52", "--end", "11:58", "--reject-overlaps"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestLogSplitMergeMove(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	addTask := []string{"task", "add", "write blog post"}
+	addAnotherTask := []string{"task", "add", "review PRs"}
+	addEntry := []string{"log", "add", "write", "--begin", "09:00", "--end", "11:00", "--comment", "outline"}
+	split := []string{"log", "split", "1", "--at", "10:00"}
+	splitForAnotherTask := []string{"log", "split", "2", "--at", "10:30", "--task", "review"}
+	addEntryAfterAGap := []string{"log", "add", "write", "--begin", "11:15", "--end", "11:45"}
+	move := []string{"log", "move", "3", "write"}
+	merge := []string{"log", "merge", "3", "2"}
+	mergeAcrossAGap := []string{"log", "merge", "2", "4"}
+	addEntryAfterALongGap := []string{"log", "add", "write", "--begin", "08:00", "--end", "08:20"}
+	withEntry := [][]string{addTask, addAnotherTask, addEntry}
+	withSplit := slices.Concat(withEntry, [][]string{split})
+	withSplits := slices.Concat(withSplit, [][]string{splitForAnotherTask})
+	withGap := slices.Concat(withSplits, [][]string{addEntryAfterAGap})
+	withMove := slices.Concat(withGap, [][]string{move})
+	withMerge := slices.Concat(withMove, [][]string{merge})
+	withMergeAcrossAGap := slices.Concat(withMerge, [][]string{mergeAcrossAGap})
+	withLongGap := slices.Concat(withMergeAcrossAGap, [][]string{addEntryAfterALongGap})
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "add task", args: addTask},
+		{name: "add another task", setup: withEntry[:1], args: addAnotherTask},
+		{name: "add entry", setup: withEntry[:2], args: addEntry},
+		{name: "split fails outside the entry", setup: withEntry, args: []string{"log", "split", "1", "--at", "11:30"}},
+		{name: "split fails for unknown task", setup: withEntry, args: []string{"log", "split", "1", "--at", "10:00", "--task", "deploy"}},
+		{name: "split works", setup: withEntry, args: split},
+		{name: "split works for another task", setup: withSplit, args: splitForAnotherTask},
+		{name: "merge fails for different tasks", setup: withSplits, args: []string{"log", "merge", "2", "3"}},
+		{name: "add entry after a gap", setup: withSplits, args: addEntryAfterAGap},
+		{name: "merge fails for entries that aren't adjacent", setup: withGap, args: []string{"log", "merge", "2", "4"}},
+		{name: "move fails for the same task", setup: withGap, args: []string{"log", "move", "3", "review"}},
+		{name: "move works", setup: withGap, args: move},
+		{name: "merge works", setup: withMove, args: merge},
+		{name: "merge works across a gap", setup: withMerge, args: mergeAcrossAGap},
+		{name: "merge fails for unknown entry", setup: withMergeAcrossAGap, args: []string{"log", "merge", "2", "3"}},
+		{name: "add entry after a long gap", setup: withMergeAcrossAGap, args: addEntryAfterALongGap},
+		{name: "merge fails for entries too far apart", setup: withLongGap, args: []string{"log", "merge", "1", "5"}},
+		{name: "log shows entries", setup: withLongGap, args: []string{"log", "--plain"}},
+		{name: "stats reflect changes", setup: withLongGap, args: []string{"stats", "all", "--plain"}},
+		{name: "time spent on tasks stays consistent", setup: withLongGap, args: []string{"doctor"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjects(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	addWebsite := []string{"project", "add", "website", "--client", "acme"}
+	addResearch := []string{"project", "add", "research"}
+	withProjects := [][]string{
+		addWebsite,
+		addResearch,
+		{"task", "add", "fix login bug", "--project", "Website", "--tag", "bugs"},
+		{"task", "set-project", "1", "website"},
+		{"task", "set-project", "2", "research"},
+	}
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "add project works", args: addWebsite},
+		{name: "add project for another client works", setup: [][]string{addWebsite}, args: addResearch},
+		{name: "add project fails if it already exists", setup: [][]string{addWebsite}, args: []string{"project", "add", "website", "--client", "globex"}},
+		{name: "list projects works", setup: [][]string{addWebsite, addResearch}, args: []string{"project", "list", "--plain"}},
+		{name: "list projects as JSON works", setup: [][]string{addWebsite, addResearch}, args: []string{"project", "list", "--output", "json"}},
+		{name: "add task with project works", setup: [][]string{addWebsite}, args: []string{"task", "add", "fix login bug", "--project", "Website", "--tag", "bugs"}},
+		{name: "add task fails for unknown project", args: []string{"task", "add", "fix login bug", "--project", "unknown"}},
+		{name: "set project works", setup: [][]string{addWebsite}, args: []string{"task", "set-project", "1", "website"}},
+		{name: "set project works for another task", setup: [][]string{addResearch}, args: []string{"task", "set-project", "2", "research"}},
+		{name: "set project fails for unknown task", setup: [][]string{addWebsite}, args: []string{"task", "set-project", "99", "website"}},
+		{name: "stats by project", setup: withProjects, args: []string{"stats", "--plain", "--group-by", "project", "week"}},
+		{name: "stats by client as JSON", setup: withProjects, args: []string{"stats", "--output", "json", "--group-by", "client", "today"}},
+		{name: "report by project", setup: withProjects, args: []string{"report", "--plain", "--group-by", "project", "today"}},
+		{name: "report by client as JSON", setup: withProjects, args: []string{"report", "--output", "json", "--group-by", "client", "today"}},
+		{name: "report fails for grouping by tag", args: []string{"report", "--group-by", "tag", "today"}},
+		{name: "stats fails for incorrect group by", args: []string{"stats", "--group-by", "week", "today"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
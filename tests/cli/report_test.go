@@ -45,3 +45,68 @@ func TestReport(t *testing.T) {
 		})
 	}
 }
+
+func TestReportJSON(t *testing.T) {
+	fx := NewFixture(t, testBinaryPath)
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	_, err := fx.RunGen(42, now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{name: "3d", args: []string{"3d"}},
+		{name: "aggregated", args: []string{"3d", "--agg"}},
+		{name: "interactive mode", args: []string{"3d", "--interactive"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewCmd([]string{"report", "--output", "json"})
+			cmd.AddArgs(tc.args...)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestReportDocuments(t *testing.T) {
+	fx := NewFixture(t, testBinaryPath)
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	_, err := fx.RunGen(42, now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{name: "markdown", args: []string{"3d", "--output", "markdown"}},
+		{name: "markdown aggregated", args: []string{"3d", "--agg", "--output", "markdown"}},
+		{name: "html", args: []string{"yest", "--output", "html"}},
+		{name: "html with comments", args: []string{"yest", "--output", "html", "--with-comments"}},
+		{name: "comments with markdown", args: []string{"3d", "--output", "markdown", "--with-comments"}},
+		{name: "interactive mode", args: []string{"3d", "--output", "html", "--interactive"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewCmd([]string{"report"})
+			cmd.AddArgs(tc.args...)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
@@ -45,3 +45,65 @@ func TestStats(t *testing.T) {
 		})
 	}
 }
+
+func TestStatsJSON(t *testing.T) {
+	fx := NewFixture(t, testBinaryPath)
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	_, err := fx.RunGen(42, now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		period string
+	}{
+		{name: "today", period: "today"},
+		{name: "all", period: "all"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewCmd([]string{"stats", "--output", "json"})
+			cmd.AddArgs(tc.period)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestStatsDocuments(t *testing.T) {
+	fx := NewFixture(t, testBinaryPath)
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+
+	_, err := fx.RunGen(42, now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{name: "markdown", args: []string{"week", "--output", "markdown"}},
+		{name: "html", args: []string{"all", "--output", "html"}},
+		{name: "html with comments", args: []string{"today", "--output", "html", "--with-comments"}},
+		{name: "comments for all time", args: []string{"all", "--output", "html", "--with-comments"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewCmd([]string{"stats"})
+			cmd.AddArgs(tc.args...)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	withTags := [][]string{
+		{"task", "add", "write blog post", "--tag", "Writing,personal"},
+		{"task", "tag", "1", "work", "client:acme"},
+		{"task", "tag", "2", "work,personal"},
+		{"task", "untag", "2", "personal"},
+	}
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "add task with tags works", args: []string{"task", "add", "write blog post", "--tag", "Writing,personal"}},
+		{name: "add task fails for incorrect tag", args: []string{"task", "add", "deep work", "--tag", "deep work"}},
+		{name: "tag works", args: []string{"task", "tag", "1", "work", "client:acme"}},
+		{name: "tag works for another task", args: []string{"task", "tag", "2", "work,personal"}},
+		{name: "tag fails for unknown task", args: []string{"task", "tag", "99", "work"}},
+		{name: "untag works", setup: [][]string{{"task", "tag", "2", "work,personal"}}, args: []string{"task", "untag", "2", "personal"}},
+		{name: "stats by tag", setup: withTags, args: []string{"stats", "--plain", "--group-by", "tag", "week"}},
+		{name: "stats by tag as JSON", setup: withTags, args: []string{"stats", "--output", "json", "--group-by", "tag", "today"}},
+		{name: "stats with a tag", setup: withTags, args: []string{"stats", "--plain", "--tag", "work", "week"}},
+		{name: "stats by tag with an excluded tag", setup: withTags, args: []string{"stats", "--plain", "--group-by", "tag", "--exclude-tag", "client:acme", "week"}},
+		{name: "log with a tag", setup: withTags, args: []string{"log", "--plain", "--tag", "client:acme", "week"}},
+		{name: "report with an excluded tag", setup: withTags, args: []string{"report", "--plain", "--agg", "--exclude-tag", "work", "today"}},
+		{name: "report fails for incorrect tag", args: []string{"report", "--tag", "-work"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	addTask := []string{"task", "add", "write blog post"}
+	addAnotherTask := []string{"task", "add", "  review PRs  "}
+	withTasks := [][]string{addTask, addAnotherTask}
+	withAnInactiveTask := slices.Concat(withTasks, [][]string{[]string{"task", "deactivate", "review"}})
+	withChanges := slices.Concat(withAnInactiveTask, [][]string{
+		{"task", "rename", "write", "write blog post on sqlite"},
+		{"task", "activate", "2"},
+		{"start", "1", "--at", "11:00"},
+	})
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "add works", args: addTask},
+		{name: "add works for another task", setup: [][]string{addTask}, args: addAnotherTask},
+		{name: "add fails for empty summary", args: []string{"task", "add", "  "}},
+		{name: "rename works", setup: [][]string{addTask}, args: []string{"task", "rename", "write", "write blog post on sqlite"}},
+		{name: "rename fails for unknown task", setup: withTasks, args: []string{"task", "rename", "5", "deploy"}},
+		{name: "deactivate works", setup: withTasks, args: []string{"task", "deactivate", "review"}},
+		{name: "deactivate fails for inactive task", setup: withAnInactiveTask, args: []string{"task", "deactivate", "review"}},
+		{name: "activate works", setup: withAnInactiveTask, args: []string{"task", "activate", "2"}},
+		{name: "start tracking", setup: withTasks, args: []string{"start", "1", "--at", "11:00"}},
+		{name: "deactivate fails for tracked task", setup: [][]string{addTask, {"start", "1", "--at", "11:00"}}, args: []string{"task", "deactivate", "1"}},
+		{name: "list works for inactive tasks when there are none", setup: withChanges, args: []string{"task", "list", "--plain", "--task-status", "inactive"}},
+		{name: "list fails for incorrect output format", args: []string{"task", "list", "--output", "yaml"}},
+		{name: "list fails for incorrect task status", args: []string{"task", "list", "--task-status", "done"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
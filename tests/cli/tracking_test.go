@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracking(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	start := []string{"start", "ru", "--at", "10:00", "--comment", "fix bug"}
+	switchTask := []string{"switch", "4", "--at", "11:00", "--comment", "review"}
+	stop := []string{"stop", "--at", "11:30"}
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "stop fails when nothing is active", args: []string{"stop"}},
+		{name: "switch fails when nothing is active", args: []string{"switch", "rust"}},
+		{name: "start fails for unknown task", args: []string{"start", "python"}},
+		{name: "start fails for ambiguous task", args: []string{"start", "clojure"}},
+		{name: "start fails for ambiguous prefix", args: []string{"start", "cl"}},
+		{name: "start fails for begin in the future", args: []string{"start", "rust", "--at", "13:00"}},
+		{name: "start fails for invalid timestamp", args: []string{"start", "rust", "--at", "9am"}},
+		{name: "start works with prefix", args: start},
+		{name: "active shows started task", setup: [][]string{start}, args: []string{"active"}},
+		{name: "start fails when a task is active", setup: [][]string{start}, args: []string{"start", "4"}},
+		{name: "switch fails for same task", setup: [][]string{start}, args: []string{"switch", "rust"}},
+		{name: "switch fails for timestamp before begin", setup: [][]string{start}, args: []string{"switch", "4", "--at", "09:00"}},
+		{name: "switch works with ID", setup: [][]string{start}, args: switchTask},
+		{name: "stop fails for end in the future", setup: [][]string{start, switchTask}, args: []string{"stop", "--at", "12:30"}},
+		{name: "stop works", setup: [][]string{start, switchTask}, args: stop},
+		{name: "log shows tracked entries", setup: [][]string{start, switchTask, stop}, args: []string{"log", "--plain"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
+
+func TestPausingTracking(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	start := []string{"start", "rust", "--at", "09:00"}
+	pause := []string{"pause", "--at", "10:00"}
+	resume := []string{"resume", "--at", "10:30"}
+	withPause := [][]string{start, pause}
+	withTrackingStopped := [][]string{start, pause, resume, {"stop", "--at", "11:30"}}
+	withTrackingRestarted := slices.Concat(withTrackingStopped, [][]string{{"start", "rust", "--at", "11:30"}})
+	withLongPause := slices.Concat(withTrackingRestarted, [][]string{{"pause", "--at", "11:30"}})
+	withLongPauseResumed := slices.Concat(withLongPause, [][]string{{"resume", "--at", "11:59"}})
+
+	testCases := []struct {
+		name  string
+		setup [][]string
+		args  []string
+	}{
+		{name: "pause fails when nothing is active", args: []string{"pause"}},
+		{name: "start works", args: start},
+		{name: "resume fails when not paused", setup: [][]string{start}, args: []string{"resume"}},
+		{name: "pause fails for timestamp before begin", setup: [][]string{start}, args: []string{"pause", "--at", "08:30"}},
+		{name: "pause works", setup: [][]string{start}, args: pause},
+		{name: "pause fails when already paused", setup: withPause, args: []string{"pause"}},
+		{name: "active shows paused task", setup: withPause, args: []string{"active", "-t", "{{task}} ({{time}})"}},
+		{name: "active shows paused task as json", setup: withPause, args: []string{"active", "--output", "json"}},
+		{name: "resume fails for timestamp before pause", setup: withPause, args: []string{"resume", "--at", "09:30"}},
+		{name: "resume works", setup: withPause, args: resume},
+		{name: "stop leaves out paused time", setup: [][]string{start, pause, resume}, args: []string{"stop", "--at", "11:30"}},
+		{name: "start works again", setup: withTrackingStopped, args: []string{"start", "rust", "--at", "11:30"}},
+		{name: "pause works again", setup: withTrackingRestarted, args: []string{"pause", "--at", "11:30"}},
+		{name: "resume works after a long pause", setup: withLongPause, args: []string{"resume", "--at", "11:59"}},
+		{name: "stop fails when time left out of pauses is too short", setup: withLongPauseResumed, args: []string{"stop", "--at", "11:59"}},
+		{name: "active shows task is still being tracked", setup: withLongPauseResumed, args: []string{"active", "--output", "json"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			_, err := fx.RunGen(42, now)
+			require.NoError(t, err)
+			fx.RunSetup(t, now, tc.setup...)
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", now.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash(t *testing.T) {
+	now := time.Date(2025, time.October, 24, 12, 0, 0, 0, time.UTC)
+	later := now.AddDate(0, 0, 10)
+
+	addTask := []string{"task", "add", "write blog post"}
+	addAnotherTask := []string{"task", "add", "review pr"}
+	addEntry := []string{"log", "add", "write", "--begin", "09:00", "--end", "10:30"}
+	addAnotherEntry := []string{"log", "add", "write", "--begin", "10:45", "--end", "11:15"}
+	addEntryForAnotherTask := []string{"log", "add", "review", "--begin", "08:00", "--end", "08:45"}
+	rmEntry := []string{"log", "rm", "1"}
+	rmTask := []string{"task", "rm", "review"}
+	restoreEntry := []string{"trash", "restore", "log", "1"}
+	purgeOldItems := []string{"trash", "purge", "--older-than", "5"}
+	restoreTask := []string{"trash", "restore", "task", "2"}
+	rmTaskAgain := []string{"task", "rm", "2"}
+	purge := []string{"trash", "purge"}
+
+	withEntries := []setupCmd{{args: addTask}, {args: addAnotherTask}, {args: addEntry}, {args: addAnotherEntry}, {args: addEntryForAnotherTask}}
+	withEntryInTrash := slices.Concat(withEntries, []setupCmd{{args: rmEntry}})
+	withItemsInTrash := slices.Concat(withEntryInTrash, []setupCmd{{args: rmTask, now: later}})
+	withEntryRestored := slices.Concat(withItemsInTrash, []setupCmd{{args: restoreEntry}})
+	withEntryInTrashAgain := slices.Concat(withEntryRestored, []setupCmd{{args: rmEntry}})
+	withOldItemsPurged := slices.Concat(withEntryInTrashAgain, []setupCmd{{args: purgeOldItems, now: later}})
+	withTaskRestored := slices.Concat(withOldItemsPurged, []setupCmd{{args: restoreTask}})
+	withTaskInTrashAgain := slices.Concat(withTaskRestored, []setupCmd{{args: rmTaskAgain}})
+	withTrashPurged := slices.Concat(withTaskInTrashAgain, []setupCmd{{args: purge}})
+
+	testCases := []struct {
+		name  string
+		setup []setupCmd
+		args  []string
+		now   time.Time
+	}{
+		{name: "add task", args: addTask},
+		{name: "add another task", setup: withEntries[:1], args: addAnotherTask},
+		{name: "add entry", setup: withEntries[:2], args: addEntry},
+		{name: "add another entry", setup: withEntries[:3], args: addAnotherEntry},
+		{name: "add entry for another task", setup: withEntries[:4], args: addEntryForAnotherTask},
+		{name: "list shows an empty trash", setup: withEntries, args: []string{"trash", "list"}},
+		{name: "rm moves entry to the trash", setup: withEntries, args: rmEntry},
+		{name: "task rm moves task to the trash", setup: withEntryInTrash, args: rmTask, now: later},
+		{name: "list works", setup: withItemsInTrash, args: []string{"trash", "list", "--plain"}, now: later},
+		{name: "list works with json output", setup: withItemsInTrash, args: []string{"trash", "list", "--output", "json"}},
+		{name: "trashed items are left out of stats", setup: withItemsInTrash, args: []string{"stats", "all", "--plain"}},
+		{name: "trashed items are left out of log", setup: withItemsInTrash, args: []string{"log", "today", "--plain"}},
+		{name: "trashed tasks can't be updated", setup: withItemsInTrash, args: []string{"task", "rename", "review", "read pr"}},
+		{name: "trashed tasks can't be tracked", setup: withItemsInTrash, args: []string{"start", "2"}},
+		{name: "restore fails for invalid kind", setup: withItemsInTrash, args: []string{"trash", "restore", "entry", "1"}},
+		{name: "restore fails for item not in the trash", setup: withItemsInTrash, args: []string{"trash", "restore", "log", "2"}},
+		{name: "restore works for entry", setup: withItemsInTrash, args: restoreEntry},
+		{name: "restored entry counts again", setup: withEntryRestored, args: []string{"stats", "all", "--plain"}},
+		{name: "rm moves entry to the trash again", setup: withEntryRestored, args: rmEntry},
+		{name: "purge keeps recently deleted items", setup: withEntryInTrashAgain, args: purgeOldItems, now: later},
+		{name: "list shows remaining items", setup: withOldItemsPurged, args: []string{"trash", "list", "--plain"}, now: later},
+		{name: "restore works for task", setup: withOldItemsPurged, args: restoreTask},
+		{name: "task rm moves task to the trash again", setup: withTaskRestored, args: rmTaskAgain},
+		{name: "task rm fails for unknown task", setup: withEntries, args: []string{"task", "rm", "deploy"}},
+		{name: "purge fails for negative days", args: []string{"trash", "purge", "--older-than", "-1"}},
+		{name: "purge works", setup: withTaskInTrashAgain, args: purge},
+		{name: "list shows an empty trash after purge", setup: withTrashPurged, args: []string{"trash", "list"}},
+		{name: "doctor finds no problems", setup: withTrashPurged, args: []string{"doctor"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fx := NewFixture(t, testBinaryPath)
+			fx.runSetupCmds(t, now, tc.setup)
+
+			stepNow := now
+			if !tc.now.IsZero() {
+				stepNow = tc.now
+			}
+
+			cmd := NewCmd(tc.args)
+			cmd.SetEnv("HOURS_NOW", stepNow.Format(time.RFC3339))
+			cmd.UseDB()
+
+			result, runErr := fx.RunCmd(cmd)
+
+			require.NoError(t, runErr)
+			snaps.MatchStandaloneSnapshot(t, result)
+		})
+	}
+}